@@ -303,6 +303,35 @@ func detectStaticField(grokStatics []parser.ExtraField) []string {
 	return ret
 }
 
+// detectDissectAndDecodeFields returns the fields set by the dissect patterns and the decoders of a node, with their statics
+func detectDissectAndDecodeFields(node parser.Node) []string {
+	ret := []string{}
+
+	if node.Dissect.RunTimeDissect != nil {
+		for _, capturedField := range node.Dissect.RunTimeDissect.Names() {
+			ret = append(ret, "evt.Parsed."+capturedField)
+		}
+	}
+
+	for _, field := range node.Decode.Fields {
+		if field.Parsed != "" {
+			ret = append(ret, "evt.Parsed."+field.Parsed)
+		}
+
+		if field.Meta != "" {
+			ret = append(ret, "evt.Meta."+field.Meta)
+		}
+	}
+
+	for _, statics := range [][]parser.ExtraField{node.Dissect.Statics, node.Decode.Statics} {
+		if len(statics) > 0 {
+			ret = append(ret, detectStaticField(statics)...)
+		}
+	}
+
+	return ret
+}
+
 func detectNode(node parser.Node, parserCTX parser.UnixParserCtx) []string {
 	ret := make([]string, 0)
 
@@ -328,6 +357,12 @@ func detectNode(node parser.Node, parserCTX parser.UnixParserCtx) []string {
 		}
 	}
 
+	for _, fieldName := range detectDissectAndDecodeFields(node) {
+		if !slices.Contains(ret, fieldName) {
+			ret = append(ret, fieldName)
+		}
+	}
+
 	if len(node.Grok.Statics) > 0 {
 		staticsField := detectStaticField(node.Grok.Statics)
 		for _, staticField := range staticsField {
@@ -375,6 +410,12 @@ func detectSubNode(node parser.Node, parserCTX parser.UnixParserCtx) []string {
 			}
 		}
 
+		for _, fieldName := range detectDissectAndDecodeFields(subnode) {
+			if !slices.Contains(ret, fieldName) {
+				ret = append(ret, fieldName)
+			}
+		}
+
 		if len(subnode.Grok.Statics) > 0 {
 			staticsField := detectStaticField(subnode.Grok.Statics)
 			for _, staticField := range staticsField {
@@ -7,16 +7,20 @@ import (
 	"io"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/go-openapi/strfmt"
+	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/spf13/cobra"
 
 	"github.com/crowdsecurity/crowdsec/cmd/crowdsec-cli/args"
+	"github.com/crowdsecurity/crowdsec/cmd/crowdsec-cli/cstable"
 	"github.com/crowdsecurity/crowdsec/cmd/crowdsec-cli/require"
 	"github.com/crowdsecurity/crowdsec/pkg/apiclient"
 	"github.com/crowdsecurity/crowdsec/pkg/csconfig"
 	"github.com/crowdsecurity/crowdsec/pkg/cwhub"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent"
 	"github.com/crowdsecurity/crowdsec/pkg/models"
 )
 
@@ -70,9 +74,46 @@ func (cli *cliLapi) Status(ctx context.Context, out io.Writer, hub *cwhub.Hub) e
 
 	fmt.Fprintf(out, "You can successfully interact with Local API (LAPI)\n")
 
+	// on a LAPI machine, show which instances run the background jobs
+	if cfg.DbConfig == nil {
+		return nil
+	}
+
+	if err := require.DB(cfg); err != nil {
+		return err
+	}
+
+	db, err := require.DBClient(ctx, cfg.DbConfig)
+	if err != nil {
+		return err
+	}
+
+	leases, err := db.ListLeases(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to list the leader roles: %w", err)
+	}
+
+	cli.leaderRoles(out, leases)
+
 	return nil
 }
 
+func (cli *cliLapi) leaderRoles(out io.Writer, leases []*ent.Lock) {
+	if len(leases) == 0 {
+		fmt.Fprintf(out, "No leader election: each Local API instance runs its own background jobs\n")
+		return
+	}
+
+	t := cstable.NewLight(out, cli.cfg().Cscli.Color).Writer
+	t.AppendHeader(table.Row{"Role", "Instance", "Since", "Lease Expires"})
+
+	for _, lease := range leases {
+		t.AppendRow(table.Row{lease.Name, lease.Owner, lease.CreatedAt.Format(time.RFC3339), lease.ExpiresAt.Format(time.RFC3339)})
+	}
+
+	fmt.Fprintln(out, t.Render())
+}
+
 // prepareAPIURL checks/fixes a LAPI connection url (http, https or socket) and returns an URL struct
 func prepareAPIURL(clientCfg *csconfig.LocalApiClientCfg, apiURL string) (*url.URL, error) {
 	if apiURL == "" {
@@ -99,7 +140,7 @@ func prepareAPIURL(clientCfg *csconfig.LocalApiClientCfg, apiURL string) (*url.U
 func (cli *cliLapi) newStatusCmd() *cobra.Command {
 	cmdLapiStatus := &cobra.Command{
 		Use:               "status",
-		Short:             "Check authentication to Local API (LAPI)",
+		Short:             "Check authentication to Local API (LAPI), and show which instances hold the leader roles",
 		Args:              args.NoArgs,
 		DisableAutoGenTag: true,
 		RunE: func(cmd *cobra.Command, _ []string) error {
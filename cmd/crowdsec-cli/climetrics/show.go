@@ -64,7 +64,9 @@ func expandAlias(args []string) []string {
 	for _, section := range args {
 		switch section {
 		case "engine":
-			ret = append(ret, "acquisition", "parsers", "scenarios", "stash", "whitelists")
+			ret = append(ret, "acquisition", "parsers", "parser-timing", "scenarios", "scenario-pressure", "scenario-shadow", "stash", "whitelists")
+		case "parsers":
+			ret = append(ret, "parsers", "parser-timing")
 		case "lapi":
 			ret = append(ret, "alerts", "decisions", "lapi", "lapi-bouncer", "lapi-decisions", "lapi-machine")
 		case "appsec":
@@ -0,0 +1,87 @@
+package climetrics
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/crowdsecurity/go-cs-lib/maptools"
+
+	"github.com/crowdsecurity/crowdsec/cmd/crowdsec-cli/cstable"
+)
+
+// the global limit is exposed with an empty scenario name
+const globalPressureLabel = "(all scenarios)"
+
+type statBucketPressure map[string]map[string]int
+
+func (s statBucketPressure) Description() (string, string) {
+	return "Scenario Pressure Metrics",
+		`Compares the number of buckets of the scenarios to their limit (max_buckets), and to the global limit of the agent. ` +
+			`Evicted buckets were discarded to make room for new ones once a limit was reached.`
+}
+
+func (s statBucketPressure) Process(bucket, metric string, val int) {
+	if _, ok := s[bucket]; !ok {
+		s[bucket] = make(map[string]int)
+	}
+
+	s[bucket][metric] += val
+}
+
+func (s statBucketPressure) Table(out io.Writer, wantColor string, noUnit bool, showEmpty bool) {
+	t := cstable.New(out, wantColor).Writer
+	t.AppendHeader(table.Row{"Scenario", "Current Count", "Limit", "Usage", "Evicted"})
+
+	total := 0
+	for name, stats := range s {
+		if name != "" {
+			total += stats["curr_count"]
+		}
+	}
+
+	numRows := 0
+
+	for _, name := range maptools.SortedKeys(s) {
+		stats := s[name]
+
+		count := stats["curr_count"]
+		label := name
+
+		if name == "" {
+			count = total
+			label = globalPressureLabel
+		}
+
+		limit := stats["limit"]
+		evicted := stats["evicted"]
+
+		// scenarios that are not limited and never evicted anything are under no pressure
+		if limit == 0 && evicted == 0 {
+			continue
+		}
+
+		row := table.Row{label, formatNumber(int64(count), !noUnit), "-", "-", "-"}
+
+		if limit > 0 {
+			row[2] = formatNumber(int64(limit), !noUnit)
+			row[3] = strconv.Itoa(count*100/limit) + "%"
+		}
+
+		if evicted > 0 {
+			row[4] = formatNumber(int64(evicted), !noUnit)
+		}
+
+		t.AppendRow(row)
+
+		numRows++
+	}
+
+	if numRows > 0 || showEmpty {
+		title, _ := s.Description()
+		t.SetTitle(title)
+		fmt.Fprintln(out, t.Render())
+	}
+}
@@ -0,0 +1,115 @@
+package climetrics
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/crowdsecurity/go-cs-lib/maptools"
+
+	"github.com/crowdsecurity/crowdsec/cmd/crowdsec-cli/cstable"
+)
+
+// timing is the content of a histogram: number of observations and total time in seconds
+type timing struct {
+	Count int     `json:"count"`
+	Sum   float64 `json:"sum"`
+}
+
+func (t timing) average(noUnit bool) string {
+	if t.Count == 0 {
+		return "-"
+	}
+
+	avg := t.Sum / float64(t.Count)
+
+	if noUnit {
+		return strconv.FormatFloat(avg, 'f', -1, 64)
+	}
+
+	d := time.Duration(avg * float64(time.Second))
+
+	switch {
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond).String()
+	case d >= time.Microsecond:
+		return d.Round(10 * time.Nanosecond).String()
+	default:
+		return d.String()
+	}
+}
+
+type statParserTiming struct {
+	// node -> step -> timing
+	Nodes map[string]map[string]timing `json:"nodes"`
+	// stage -> timing
+	Stages map[string]timing `json:"stages"`
+}
+
+func (s statParserTiming) Description() (string, string) {
+	return "Parser Timing Metrics",
+		`Average time spent by each parser node matching its pattern (grok, dissect or decode), ` +
+			`evaluating its expressions and running its enrichment methods, and by each stage processing an event. ` +
+			`The time of a node does not include the one of its children. ` +
+			`Only available when prometheus.parser_timing is enabled in the crowdsec configuration.`
+}
+
+func (s statParserTiming) ProcessNode(name, step string, count int, sum float64) {
+	if _, ok := s.Nodes[name]; !ok {
+		s.Nodes[name] = make(map[string]timing)
+	}
+
+	t := s.Nodes[name][step]
+	t.Count += count
+	t.Sum += sum
+	s.Nodes[name][step] = t
+}
+
+func (s statParserTiming) ProcessStage(stage string, count int, sum float64) {
+	t := s.Stages[stage]
+	t.Count += count
+	t.Sum += sum
+	s.Stages[stage] = t
+}
+
+func (s statParserTiming) Table(out io.Writer, wantColor string, noUnit bool, showEmpty bool) {
+	title, _ := s.Description()
+
+	t := cstable.New(out, wantColor).Writer
+	t.AppendHeader(table.Row{"Parsers", "Grok", "Expr", "Enrich"})
+
+	for _, name := range maptools.SortedKeys(s.Nodes) {
+		steps := s.Nodes[name]
+
+		t.AppendRow(table.Row{
+			name,
+			steps["grok"].average(noUnit),
+			steps["expr"].average(noUnit),
+			steps["enrich"].average(noUnit),
+		})
+	}
+
+	if len(s.Nodes) > 0 || showEmpty {
+		t.SetTitle(title)
+		fmt.Fprintln(out, t.Render())
+	}
+
+	t = cstable.New(out, wantColor).Writer
+	t.AppendHeader(table.Row{"Stage", "Events", "Average"})
+
+	for _, stage := range maptools.SortedKeys(s.Stages) {
+		t.AppendRow(table.Row{
+			stage,
+			formatNumber(int64(s.Stages[stage].Count), !noUnit),
+			s.Stages[stage].average(noUnit),
+		})
+	}
+
+	if len(s.Stages) > 0 || showEmpty {
+		t.SetTitle("Parser Stage Timing Metrics")
+		fmt.Fprintln(out, t.Render())
+	}
+}
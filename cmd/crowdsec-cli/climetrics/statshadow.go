@@ -0,0 +1,63 @@
+package climetrics
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/crowdsecurity/go-cs-lib/maptools"
+
+	"github.com/crowdsecurity/crowdsec/cmd/crowdsec-cli/cstable"
+)
+
+// active scenario -> shadow scenario -> result -> count
+type statShadow map[string]map[string]map[string]int
+
+func (s statShadow) Description() (string, string) {
+	return "Shadow Scenario Metrics",
+		`Compares the overflows of the scenarios with the ones of their shadow version (shadow_of), by source. ` +
+			`"Both" is the number of overflows that happened in both versions, the others only happened in one of them. ` +
+			`The overflows of the shadow scenarios are never sent as alerts.`
+}
+
+func (s statShadow) Process(name, shadow, result string, val int) {
+	if _, ok := s[name]; !ok {
+		s[name] = make(map[string]map[string]int)
+	}
+
+	if _, ok := s[name][shadow]; !ok {
+		s[name][shadow] = make(map[string]int)
+	}
+
+	s[name][shadow][result] += val
+}
+
+func (s statShadow) Table(out io.Writer, wantColor string, noUnit bool, showEmpty bool) {
+	t := cstable.New(out, wantColor).Writer
+	t.AppendHeader(table.Row{"Scenario", "Shadow", "Both", "Only Active", "Only Shadow"})
+
+	numRows := 0
+
+	for _, name := range maptools.SortedKeys(s) {
+		for _, shadow := range maptools.SortedKeys(s[name]) {
+			stats := s[name][shadow]
+
+			t.AppendRow(table.Row{
+				name,
+				shadow,
+				formatNumber(int64(stats["both"]), !noUnit),
+				formatNumber(int64(stats["only_active"]), !noUnit),
+				formatNumber(int64(stats["only_shadow"]), !noUnit),
+			})
+
+			numRows++
+		}
+	}
+
+	if numRows > 0 || showEmpty {
+		title, _ := s.Description()
+		t.SetTitle(title)
+		fmt.Fprintln(out, t.Render())
+	}
+}
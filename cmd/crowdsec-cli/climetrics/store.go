@@ -29,20 +29,23 @@ type metricStore map[string]metricSection
 
 func NewMetricStore() metricStore {
 	return metricStore{
-		"acquisition":    statAcquis{},
-		"alerts":         statAlert{},
-		"bouncers":       &statBouncer{},
-		"appsec-engine":  statAppsecEngine{},
-		"appsec-rule":    statAppsecRule{},
-		"decisions":      statDecision{},
-		"lapi":           statLapi{},
-		"lapi-bouncer":   statLapiBouncer{},
-		"lapi-decisions": statLapiDecision{},
-		"lapi-machine":   statLapiMachine{},
-		"parsers":        statParser{},
-		"scenarios":      statBucket{},
-		"stash":          statStash{},
-		"whitelists":     statWhitelist{},
+		"acquisition":       statAcquis{},
+		"alerts":            statAlert{},
+		"bouncers":          &statBouncer{},
+		"appsec-engine":     statAppsecEngine{},
+		"appsec-rule":       statAppsecRule{},
+		"decisions":         statDecision{},
+		"lapi":              statLapi{},
+		"lapi-bouncer":      statLapiBouncer{},
+		"lapi-decisions":    statLapiDecision{},
+		"lapi-machine":      statLapiMachine{},
+		"parsers":           statParser{},
+		"parser-timing":     statParserTiming{Nodes: map[string]map[string]timing{}, Stages: map[string]timing{}},
+		"scenarios":         statBucket{},
+		"scenario-pressure": statBucketPressure{},
+		"scenario-shadow":   statShadow{},
+		"stash":             statStash{},
+		"whitelists":        statWhitelist{},
 	}
 }
 
@@ -102,7 +105,10 @@ func (ms metricStore) processPrometheusMetrics(result []*prom2json.Family) {
 	mLapiDecision := ms["lapi-decisions"].(statLapiDecision)
 	mLapiMachine := ms["lapi-machine"].(statLapiMachine)
 	mParser := ms["parsers"].(statParser)
+	mParserTiming := ms["parser-timing"].(statParserTiming)
 	mBucket := ms["scenarios"].(statBucket)
+	mPressure := ms["scenario-pressure"].(statBucketPressure)
+	mShadow := ms["scenario-shadow"].(statShadow)
 	mStash := ms["stash"].(statStash)
 	mWhitelist := ms["whitelists"].(statWhitelist)
 
@@ -114,6 +120,11 @@ func (ms metricStore) processPrometheusMetrics(result []*prom2json.Family) {
 		log.Tracef("round %d", idx)
 
 		for _, m := range fam.Metrics {
+			if histogram, ok := m.(prom2json.Histogram); ok {
+				processHistogram(fam.Name, histogram, mParserTiming)
+				continue
+			}
+
 			metric, ok := m.(prom2json.Metric)
 			if !ok {
 				log.Debugf("failed to convert metric to prom2json.Metric")
@@ -165,6 +176,13 @@ func (ms metricStore) processPrometheusMetrics(result []*prom2json.Family) {
 				mBucket.Process(name, "instantiation", ival)
 			case "cs_buckets":
 				mBucket.Process(name, "curr_count", ival)
+				mPressure.Process(name, "curr_count", ival)
+			case "cs_buckets_limit":
+				mPressure.Process(name, "limit", ival)
+			case "cs_bucket_evicted_total":
+				mPressure.Process(name, "evicted", ival)
+			case "cs_scenario_shadow_total":
+				mShadow.Process(name, metric.Labels["shadow"], metric.Labels["result"], ival)
 			case "cs_bucket_overflowed_total":
 				mBucket.Process(name, "overflow", ival)
 			case "cs_bucket_poured_total":
@@ -236,6 +254,30 @@ func (ms metricStore) processPrometheusMetrics(result []*prom2json.Family) {
 	}
 }
 
+// processHistogram collects the count and sum of the histograms we display
+func processHistogram(famName string, histogram prom2json.Histogram, mParserTiming statParserTiming) {
+	count, err := strconv.Atoi(histogram.Count)
+	if err != nil {
+		log.Errorf("Unexpected int value %s : %s", histogram.Count, err)
+		return
+	}
+
+	sum, err := strconv.ParseFloat(histogram.Sum, 64)
+	if err != nil {
+		log.Errorf("Unexpected float value %s : %s", histogram.Sum, err)
+		return
+	}
+
+	switch famName {
+	case "cs_node_step_seconds":
+		mParserTiming.ProcessNode(histogram.Labels["name"], histogram.Labels["step"], count, sum)
+	case "cs_stage_seconds":
+		mParserTiming.ProcessStage(histogram.Labels["stage"], count, sum)
+	default:
+		log.Debugf("unknown: %+v", famName)
+	}
+}
+
 func (ms metricStore) Format(out io.Writer, wantColor string, sections []string, outputFormat string, noUnit bool) error {
 	// copy only the sections we want
 	want := map[string]metricSection{}
@@ -0,0 +1,275 @@
+package cliprofiles
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/crowdsecurity/crowdsec/cmd/crowdsec-cli/args"
+	"github.com/crowdsecurity/crowdsec/cmd/crowdsec-cli/require"
+	v1 "github.com/crowdsecurity/crowdsec/pkg/apiserver/controllers/v1"
+	"github.com/crowdsecurity/crowdsec/pkg/csconfig"
+	"github.com/crowdsecurity/crowdsec/pkg/csprofiles"
+	"github.com/crowdsecurity/crowdsec/pkg/database"
+	"github.com/crowdsecurity/crowdsec/pkg/models"
+)
+
+type configGetter = func() *csconfig.Config
+
+type cliProfiles struct {
+	cfg configGetter
+}
+
+func New(cfg configGetter) *cliProfiles {
+	return &cliProfiles{
+		cfg: cfg,
+	}
+}
+
+func (cli *cliProfiles) NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "profiles [action]",
+		Short:             "Test the profiles of the Local API",
+		DisableAutoGenTag: true,
+		PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
+			return require.LAPI(cli.cfg())
+		},
+	}
+
+	cmd.AddCommand(cli.newTestCmd())
+
+	return cmd
+}
+
+type testOpts struct {
+	profilesPath string
+	file         string
+	since        string
+	scenario     string
+	limit        int
+	changedOnly  bool
+}
+
+// loadProfiles compiles the profiles from the given file, or the ones of the Local API
+func (cli *cliProfiles) loadProfiles(profilesPath string) ([]*csprofiles.Runtime, error) {
+	profilesCfg := cli.cfg().API.Server.Profiles
+
+	if profilesPath != "" {
+		serverCfg := csconfig.LocalApiServerCfg{ProfilesPath: profilesPath}
+		if err := serverCfg.LoadProfiles(); err != nil {
+			return nil, err
+		}
+
+		profilesCfg = serverCfg.Profiles
+	}
+
+	profiles, err := csprofiles.NewProfile(profilesCfg)
+	if err != nil {
+		return nil, fmt.Errorf("while compiling profiles: %w", err)
+	}
+
+	return profiles, nil
+}
+
+func alertsFromFile(path string) ([]*replayedAlert, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := []*models.Alert{}
+
+	if err := json.Unmarshal(content, &alerts); err != nil {
+		return nil, fmt.Errorf("while parsing %s: %w", path, err)
+	}
+
+	ret := make([]*replayedAlert, 0, len(alerts))
+
+	for _, alert := range alerts {
+		ret = append(ret, newReplayedAlert(alert, summarizeDecisions(alert.Decisions)))
+	}
+
+	return ret, nil
+}
+
+func alertsFromDB(ctx context.Context, db *database.Client, opts testOpts) ([]*replayedAlert, error) {
+	filter := map[string][]string{
+		"limit": {strconv.Itoa(opts.limit)},
+	}
+
+	if opts.since != "" {
+		filter["since"] = []string{opts.since}
+	}
+
+	if opts.scenario != "" {
+		filter["scenario"] = []string{opts.scenario}
+	}
+
+	alerts, err := db.QueryAlertWithFilter(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list alerts: %w", err)
+	}
+
+	ret := make([]*replayedAlert, 0, len(alerts))
+
+	for _, alert := range alerts {
+		ret = append(ret, replayedAlertFromDB(alert, v1.FormatOneAlert(alert)))
+	}
+
+	return ret, nil
+}
+
+func (cli *cliProfiles) test(ctx context.Context, out io.Writer, opts testOpts) error {
+	cfg := cli.cfg()
+
+	profiles, err := cli.loadProfiles(opts.profilesPath)
+	if err != nil {
+		return err
+	}
+
+	// the escalation policies count the previous offenses in the database
+	var db *database.Client
+
+	if cfg.DbConfig != nil {
+		db, err = require.DBClient(ctx, cfg.DbConfig)
+		if err != nil {
+			if opts.file == "" {
+				return err
+			}
+
+			log.Warningf("the escalation policies won't be applied: %s", err)
+		}
+	}
+
+	if db == nil && opts.file == "" {
+		return errors.New("no database configuration to read the alerts from, use --file")
+	}
+
+	for _, profile := range profiles {
+		profile.DBClient = db
+	}
+
+	var alerts []*replayedAlert
+
+	if opts.file != "" {
+		alerts, err = alertsFromFile(opts.file)
+	} else {
+		alerts, err = alertsFromDB(ctx, db, opts)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	results := []*replayResult{}
+	changed := 0
+
+	for _, alert := range alerts {
+		result := evaluate(profiles, alert)
+		if len(result.Diff) > 0 {
+			changed++
+		} else if opts.changedOnly {
+			continue
+		}
+
+		results = append(results, result)
+	}
+
+	switch cfg.Cscli.Output {
+	case "human":
+		for _, result := range results {
+			printResult(out, result)
+		}
+
+		fmt.Fprintf(out, "%d alerts replayed, %d with different decisions\n", len(alerts), changed)
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+
+		if err := enc.Encode(results); err != nil {
+			return errors.New("failed to serialize")
+		}
+	default:
+		return fmt.Errorf("output format '%s' not supported for this command", cfg.Cscli.Output)
+	}
+
+	return nil
+}
+
+func printResult(out io.Writer, result *replayResult) {
+	fmt.Fprintf(out, "Alert %d: %s on %s (%s)\n", result.AlertID, result.Scenario, result.Source, result.CreatedAt)
+
+	if len(result.Profiles) == 0 {
+		fmt.Fprintf(out, "  no matching profile\n")
+	} else {
+		fmt.Fprintf(out, "  profiles: %s\n", strings.Join(result.Profiles, ", "))
+	}
+
+	if len(result.Notifications) > 0 {
+		fmt.Fprintf(out, "  notifications: %s\n", strings.Join(result.Notifications, ", "))
+	}
+
+	for _, err := range result.Errors {
+		fmt.Fprintf(out, "  error: %s\n", err)
+	}
+
+	for _, decision := range result.Decisions {
+		fmt.Fprintf(out, "  decision: %s\n", decision)
+	}
+
+	for _, change := range result.Diff {
+		line := "  " + change.String()
+
+		switch change.Change {
+		case "+":
+			line = color.GreenString(line)
+		case "-":
+			line = color.RedString(line)
+		default:
+			line = color.YellowString(line)
+		}
+
+		fmt.Fprintln(out, line)
+	}
+
+	fmt.Fprintln(out)
+}
+
+func (cli *cliProfiles) newTestCmd() *cobra.Command {
+	opts := testOpts{}
+
+	cmd := &cobra.Command{
+		Use:   "test",
+		Short: "Evaluate the profiles against past alerts",
+		Long: `Replay the alerts stored in the database, or read from a JSON file, through the profiles.
+For each alert, show the profiles that match, the decisions and notifications they would produce,
+and the difference with the decisions that were actually taken.`,
+		Example: `cscli profiles test
+cscli profiles test --profiles /etc/crowdsec/profiles.yaml.new --since 7d --changed-only
+cscli profiles test --file alerts.json -o json`,
+		Args:              args.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cli.test(cmd.Context(), color.Output, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.profilesPath, "profiles", "", "profiles file to test (default: the profiles of the Local API)")
+	flags.StringVarP(&opts.file, "file", "f", "", "JSON file with a list of alerts, instead of the database")
+	flags.StringVar(&opts.since, "since", "", "restrict to alerts newer than since (ie. 4h, 30d)")
+	flags.StringVarP(&opts.scenario, "scenario", "s", "", "restrict to alerts of the scenario (ie. crowdsecurity/ssh-bf)")
+	flags.IntVarP(&opts.limit, "limit", "l", 50, "maximum number of alerts to replay (0 for all)")
+	flags.BoolVar(&opts.changedOnly, "changed-only", false, "only show the alerts that would get different decisions")
+
+	return cmd
+}
@@ -0,0 +1,269 @@
+package cliprofiles
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/crowdsecurity/crowdsec/pkg/csprofiles"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent"
+	"github.com/crowdsecurity/crowdsec/pkg/models"
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+type decisionSummary struct {
+	Type     string `json:"type"`
+	Scope    string `json:"scope"`
+	Value    string `json:"value"`
+	Duration string `json:"duration"`
+	// the decision was deleted before its end, its duration is not known
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+func (d decisionSummary) key() string {
+	return d.Type + " " + d.Scope + ":" + d.Value
+}
+
+func (d decisionSummary) String() string {
+	if d.Deleted {
+		return d.key() + " (deleted)"
+	}
+
+	return d.key() + " " + d.Duration
+}
+
+// decisionChange is a difference between what the profiles would decide and what was decided:
+// "+" for a new decision, "-" for a decision that would not be taken, "~" for a different duration
+type decisionChange struct {
+	Change string `json:"change"`
+	decisionSummary
+	PreviousDuration string `json:"previous_duration,omitempty"`
+}
+
+func (c decisionChange) String() string {
+	if c.Change == "~" {
+		return fmt.Sprintf("~ %s %s -> %s", c.key(), c.PreviousDuration, c.Duration)
+	}
+
+	return c.Change + " " + c.decisionSummary.String()
+}
+
+// replayedAlert is an alert to evaluate again, with the decisions it actually got
+type replayedAlert struct {
+	alert  *models.Alert
+	actual []decisionSummary
+}
+
+type replayResult struct {
+	AlertID       int64             `json:"alert_id"`
+	Scenario      string            `json:"scenario"`
+	Source        string            `json:"source"`
+	CreatedAt     string            `json:"created_at"`
+	Profiles      []string          `json:"profiles"`
+	Notifications []string          `json:"notifications"`
+	Decisions     []decisionSummary `json:"decisions"`
+	Actual        []decisionSummary `json:"actual"`
+	Diff          []decisionChange  `json:"diff"`
+	Errors        []string          `json:"errors,omitempty"`
+}
+
+// normalizeDuration allows to compare "4h" from a profile with "4h0m0s" from the database
+func normalizeDuration(duration string) string {
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return duration
+	}
+
+	return d.String()
+}
+
+func summarizeDecisions(decisions []*models.Decision) []decisionSummary {
+	ret := []decisionSummary{}
+
+	for _, d := range decisions {
+		ret = append(ret, decisionSummary{
+			Type:     ptrValue(d.Type),
+			Scope:    ptrValue(d.Scope),
+			Value:    ptrValue(d.Value),
+			Duration: normalizeDuration(ptrValue(d.Duration)),
+		})
+	}
+
+	return ret
+}
+
+func ptrValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
+
+// generatedByProfiles returns true if the decisions were taken by the profiles,
+// the other ones (cscli decisions add, console...) were given with the alert
+func generatedByProfiles(decisions []*models.Decision) bool {
+	for _, d := range decisions {
+		origin := ptrValue(d.Origin)
+		if origin == types.CrowdSecOrigin || strings.HasPrefix(origin, types.CrowdSecOrigin+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newReplayedAlert prepares an alert as it was received by the Local API: without the decisions
+// generated by the profiles
+func newReplayedAlert(alert *models.Alert, actual []decisionSummary) *replayedAlert {
+	input := *alert
+	if generatedByProfiles(alert.Decisions) {
+		input.Decisions = nil
+	}
+
+	return &replayedAlert{
+		alert:  &input,
+		actual: actual,
+	}
+}
+
+// replayedAlertFromDB uses the original durations of the decisions: the API only gives the time left
+func replayedAlertFromDB(alert *ent.Alert, formatted *models.Alert) *replayedAlert {
+	actual := []decisionSummary{}
+
+	for _, d := range alert.Edges.Decisions {
+		summary := decisionSummary{
+			Type:  d.Type,
+			Scope: d.Scope,
+			Value: d.Value,
+		}
+
+		switch {
+		case deletedEarly(d):
+			summary.Deleted = true
+		case d.Until != nil:
+			summary.Duration = d.Until.Sub(alert.StoppedAt).Round(time.Second).String()
+		}
+
+		actual = append(actual, summary)
+	}
+
+	return newReplayedAlert(formatted, actual)
+}
+
+// deletedEarly tells if a decision was deleted before its end: the end of the decision is then
+// the time of the deletion, the decisions are not updated otherwise
+func deletedEarly(d *ent.Decision) bool {
+	return d.UpdatedAt.Sub(d.CreatedAt) > time.Second
+}
+
+// evaluate runs the alert through the profiles, the same way the Local API does when it receives the alert
+func evaluate(profiles []*csprofiles.Runtime, replayed *replayedAlert) *replayResult {
+	alert := replayed.alert
+
+	result := &replayResult{
+		AlertID:       alert.ID,
+		Scenario:      ptrValue(alert.Scenario),
+		CreatedAt:     alert.CreatedAt,
+		Profiles:      []string{},
+		Notifications: []string{},
+		Actual:        replayed.actual,
+	}
+
+	if alert.Source != nil {
+		result.Source = ptrValue(alert.Source.Scope) + " " + ptrValue(alert.Source.Value)
+	}
+
+	// the decisions were given with the alert, the profiles only send the notifications
+	manual := len(alert.Decisions) != 0
+
+	for _, profile := range profiles {
+		profileDecisions, matched, err := profile.EvaluateProfile(alert)
+		forceBreak := false
+
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %s", profile.Cfg.Name, err))
+
+			switch {
+			case manual:
+				continue
+			case profile.Cfg.OnError == "apply":
+				matched = true
+			case profile.Cfg.OnError == "break":
+				forceBreak = true
+			case profile.Cfg.OnError == "continue", profile.Cfg.OnError == "ignore":
+			default:
+				result.Errors = append(result.Errors, "the alert would be rejected")
+				result.Decisions = []decisionSummary{}
+				result.Diff = diffDecisions(result.Decisions, result.Actual)
+
+				return result
+			}
+		}
+
+		if !matched {
+			continue
+		}
+
+		result.Profiles = append(result.Profiles, profile.Cfg.Name)
+		result.Notifications = append(result.Notifications, profile.Cfg.Notifications...)
+
+		if len(alert.Decisions) == 0 {
+			alert.Decisions = append(alert.Decisions, profileDecisions...)
+		}
+
+		if profile.Cfg.OnSuccess == "break" || forceBreak {
+			break
+		}
+	}
+
+	if manual {
+		// kept as they were given
+		result.Decisions = result.Actual
+	} else {
+		result.Decisions = summarizeDecisions(alert.Decisions)
+	}
+
+	result.Diff = diffDecisions(result.Decisions, result.Actual)
+
+	return result
+}
+
+func diffDecisions(expected []decisionSummary, actual []decisionSummary) []decisionChange {
+	diff := []decisionChange{}
+
+	previous := map[string]decisionSummary{}
+	for _, d := range actual {
+		previous[d.key()] = d
+	}
+
+	seen := map[string]bool{}
+
+	for _, d := range expected {
+		seen[d.key()] = true
+
+		old, ok := previous[d.key()]
+
+		switch {
+		case !ok:
+			diff = append(diff, decisionChange{Change: "+", decisionSummary: d})
+		case old.Deleted:
+			// the duration it had is not known
+		case old.Duration != d.Duration:
+			diff = append(diff, decisionChange{Change: "~", decisionSummary: d, PreviousDuration: old.Duration})
+		}
+	}
+
+	for _, d := range actual {
+		if !seen[d.key()] {
+			diff = append(diff, decisionChange{Change: "-", decisionSummary: d})
+		}
+	}
+
+	slices.SortStableFunc(diff, func(a, b decisionChange) int {
+		return strings.Compare(a.key(), b.key())
+	})
+
+	return diff
+}
@@ -0,0 +1,161 @@
+package cliprofiles
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/crowdsecurity/go-cs-lib/ptr"
+
+	"github.com/crowdsecurity/crowdsec/pkg/csconfig"
+	"github.com/crowdsecurity/crowdsec/pkg/csprofiles"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent"
+	"github.com/crowdsecurity/crowdsec/pkg/models"
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+func testAlert(scope string, value string, decisions ...*models.Decision) *models.Alert {
+	return &models.Alert{
+		ID:          1,
+		Scenario:    ptr.Of("crowdsecurity/ssh-bf"),
+		Remediation: true,
+		Source:      &models.Source{Scope: ptr.Of(scope), Value: ptr.Of(value)},
+		Decisions:   decisions,
+	}
+}
+
+func testDecision(origin string, decisionType string, value string, duration string) *models.Decision {
+	return &models.Decision{
+		Origin:   ptr.Of(origin),
+		Type:     ptr.Of(decisionType),
+		Scope:    ptr.Of(types.Ip),
+		Value:    ptr.Of(value),
+		Duration: ptr.Of(duration),
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	profiles, err := csprofiles.NewProfile([]*csconfig.ProfileCfg{
+		{
+			Name:          "ip",
+			Filters:       []string{`Alert.Remediation == true && Alert.GetScope() == "Ip"`},
+			Decisions:     []models.Decision{{Type: ptr.Of("ban"), Duration: ptr.Of("24h")}},
+			Notifications: []string{"slack_default"},
+			OnSuccess:     "break",
+		},
+		{
+			Name:    "never reached",
+			Filters: []string{"1==1"},
+		},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		alert         *models.Alert
+		expectedDiff  []string
+		expectedNotif []string
+		expected      []string
+	}{
+		{
+			name:          "same decision",
+			alert:         testAlert(types.Ip, "1.2.3.4", testDecision(types.CrowdSecOrigin, "ban", "1.2.3.4", "24h0m0s")),
+			expected:      []string{"ban Ip:1.2.3.4 24h0m0s"},
+			expectedNotif: []string{"slack_default"},
+			expectedDiff:  []string{},
+		},
+		{
+			name:          "longer decision",
+			alert:         testAlert(types.Ip, "1.2.3.4", testDecision(types.CrowdSecOrigin, "ban", "1.2.3.4", "4h")),
+			expected:      []string{"ban Ip:1.2.3.4 24h0m0s"},
+			expectedNotif: []string{"slack_default"},
+			expectedDiff:  []string{"~ ban Ip:1.2.3.4 4h0m0s -> 24h0m0s"},
+		},
+		{
+			name:          "other type",
+			alert:         testAlert(types.Ip, "1.2.3.4", testDecision(types.CrowdSecOrigin+"/captcha", "captcha", "1.2.3.4", "4h")),
+			expected:      []string{"ban Ip:1.2.3.4 24h0m0s"},
+			expectedNotif: []string{"slack_default"},
+			expectedDiff:  []string{"+ ban Ip:1.2.3.4 24h0m0s", "- captcha Ip:1.2.3.4 4h0m0s"},
+		},
+		{
+			name:          "no decision before",
+			alert:         testAlert(types.Ip, "1.2.3.4"),
+			expected:      []string{"ban Ip:1.2.3.4 24h0m0s"},
+			expectedNotif: []string{"slack_default"},
+			expectedDiff:  []string{"+ ban Ip:1.2.3.4 24h0m0s"},
+		},
+		{
+			name:          "no profile",
+			alert:         testAlert(types.Range, "1.2.3.0/24", testDecision(types.CrowdSecOrigin, "ban", "1.2.3.0/24", "4h")),
+			expected:      []string{},
+			expectedNotif: []string{},
+			expectedDiff:  []string{"- ban Ip:1.2.3.0/24 4h0m0s"},
+		},
+		{
+			name:          "manual decision",
+			alert:         testAlert(types.Ip, "1.2.3.4", testDecision(types.CscliOrigin, "ban", "1.2.3.4", "1h")),
+			expected:      []string{"ban Ip:1.2.3.4 1h0m0s"},
+			expectedNotif: []string{"slack_default"},
+			expectedDiff:  []string{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result := evaluate(profiles, newReplayedAlert(tc.alert, summarizeDecisions(tc.alert.Decisions)))
+
+			decisions := []string{}
+			for _, d := range result.Decisions {
+				decisions = append(decisions, d.String())
+			}
+
+			diff := []string{}
+			for _, c := range result.Diff {
+				diff = append(diff, c.String())
+			}
+
+			assert.Equal(t, tc.expected, decisions)
+			assert.Equal(t, tc.expectedNotif, result.Notifications)
+			assert.Equal(t, tc.expectedDiff, diff)
+			assert.Empty(t, result.Errors)
+		})
+	}
+}
+
+func TestReplayedAlertFromDB(t *testing.T) {
+	stoppedAt := time.Now().UTC().Add(-2 * time.Hour)
+	until := stoppedAt.Add(4 * time.Hour)
+	deletedAt := stoppedAt.Add(time.Hour)
+
+	alert := &ent.Alert{
+		StoppedAt: stoppedAt,
+		Edges: ent.AlertEdges{
+			Decisions: []*ent.Decision{
+				{CreatedAt: stoppedAt, UpdatedAt: stoppedAt, Until: &until, Type: "ban", Scope: types.Ip, Value: "1.2.3.4"},
+				// deleted with cscli, its end is the time of the deletion
+				{CreatedAt: stoppedAt, UpdatedAt: deletedAt, Until: &deletedAt, Type: "ban", Scope: types.Ip, Value: "1.2.3.5"},
+			},
+		},
+	}
+
+	replayed := replayedAlertFromDB(alert, testAlert(types.Ip, "1.2.3.4"))
+
+	actual := []string{}
+	for _, d := range replayed.actual {
+		actual = append(actual, d.String())
+	}
+
+	assert.Equal(t, []string{"ban Ip:1.2.3.4 4h0m0s", "ban Ip:1.2.3.5 (deleted)"}, actual)
+
+	// the duration of a deleted decision is not compared
+	diff := diffDecisions([]decisionSummary{
+		{Type: "ban", Scope: types.Ip, Value: "1.2.3.4", Duration: "24h0m0s"},
+		{Type: "ban", Scope: types.Ip, Value: "1.2.3.5", Duration: "24h0m0s"},
+	}, replayed.actual)
+
+	require.Len(t, diff, 1)
+	assert.Equal(t, "~ ban Ip:1.2.3.4 4h0m0s -> 24h0m0s", diff[0].String())
+}
@@ -113,6 +113,29 @@ func runCrowdsec(cConfig *csconfig.Config, parsers *parser.Parsers, hub *cwhub.H
 			}
 		}
 
+		if useBucketsState(cConfig) {
+			if err := leaky.RestoreBucketsState(cConfig.Crowdsec.BucketsState.Path, buckets, holders); err != nil {
+				log.Errorf("unable to restore buckets state: %s", err)
+			}
+
+			if interval := *cConfig.Crowdsec.BucketsState.Interval; interval > 0 {
+				bucketsTomb.Go(func() error {
+					defer trace.CatchPanic("crowdsec/saveBucketsState")
+
+					return runSaveBucketsState(cConfig.Crowdsec.BucketsState.Path, interval)
+				})
+			}
+		}
+
+		// in forensic mode, the overflows are matched against the time of the logs by the garbage collector
+		if buckets.Shadows.Enabled() && !cConfig.Crowdsec.BucketsGCEnabled {
+			bucketsTomb.Go(func() error {
+				defer trace.CatchPanic("crowdsec/expireShadows")
+
+				return runExpireShadows()
+			})
+		}
+
 		for range cConfig.Crowdsec.BucketsRoutinesCount {
 			bucketsTomb.Go(func() error {
 				defer trace.CatchPanic("crowdsec/runPour")
@@ -180,6 +203,18 @@ func runCrowdsec(cConfig *csconfig.Config, parsers *parser.Parsers, hub *cwhub.H
 
 	log.Info("Starting processing data")
 
+	// the datasources added to acquisition_dir are started even if the current ones only read files once
+	if cConfig.Crowdsec.AcquisitionDirPath != "" && !flags.haveTimeMachine() {
+		acquisTomb.Go(func() error {
+			// the changes are not applied, but the acquisition keeps running
+			if err := acquisition.WatchAcquisitionDir(context.TODO(), cConfig.Crowdsec, cConfig.Prometheus, inputLineChan, &acquisTomb); err != nil {
+				log.Errorf("acquisition files won't be reloaded: %s", err)
+			}
+
+			return nil
+		})
+	}
+
 	if err := acquisition.StartAcquisition(context.TODO(), dataSources, inputLineChan, &acquisTomb); err != nil {
 		return fmt.Errorf("starting acquisition error: %w", err)
 	}
@@ -187,6 +222,12 @@ func runCrowdsec(cConfig *csconfig.Config, parsers *parser.Parsers, hub *cwhub.H
 	return nil
 }
 
+// useBucketsState tells whether the buckets are restored at startup and saved while running.
+// A replay of logs (-dsn, -file) has its own buckets, and must not overwrite the state of the live agent.
+func useBucketsState(cConfig *csconfig.Config) bool {
+	return cConfig.Crowdsec != nil && cConfig.Crowdsec.BucketsState != nil && !flags.haveTimeMachine()
+}
+
 // serveCrowdsec wraps the log processor service
 func serveCrowdsec(parsers *parser.Parsers, cConfig *csconfig.Config, hub *cwhub.Hub, datasources []acquisition.DataSource, agentReady chan bool) {
 	crowdsecTomb.Go(func() error {
@@ -210,7 +251,7 @@ func serveCrowdsec(parsers *parser.Parsers, cConfig *csconfig.Config, hub *cwhub
 		waitOnTomb()
 		log.Debugf("Shutting down crowdsec routines")
 
-		if err := ShutdownCrowdsecRoutines(); err != nil {
+		if err := ShutdownCrowdsecRoutines(cConfig); err != nil {
 			return fmt.Errorf("unable to shutdown crowdsec routines: %w", err)
 		}
 
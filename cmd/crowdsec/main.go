@@ -18,6 +18,7 @@ import (
 	"github.com/crowdsecurity/go-cs-lib/trace"
 
 	"github.com/crowdsecurity/crowdsec/pkg/acquisition"
+	"github.com/crowdsecurity/crowdsec/pkg/apiclient"
 	"github.com/crowdsecurity/crowdsec/pkg/csconfig"
 	"github.com/crowdsecurity/crowdsec/pkg/csplugin"
 	"github.com/crowdsecurity/crowdsec/pkg/cwhub"
@@ -90,6 +91,18 @@ func LoadBuckets(cConfig *csconfig.Config, hub *cwhub.Hub) error {
 
 	buckets = leakybucket.NewBuckets()
 
+	if cConfig.Crowdsec.BucketsSharedState == "lapi" {
+		log.Info("Shared buckets use the local API to aggregate their state")
+
+		buckets.SharedState = NewLAPIStateBackend(apiclient.GetLAPIClient)
+	}
+
+	if limits := cConfig.Crowdsec.BucketsLimits; limits != nil {
+		if err = buckets.Limits.SetLimits(limits.MaxBuckets, limits.MaxBucketsPerScenario, limits.EvictionPolicy); err != nil {
+			return err
+		}
+	}
+
 	scenarios := hub.GetInstalledByType(cwhub.SCENARIOS, false)
 
 	log.Infof("Loading %d scenario files", len(scenarios))
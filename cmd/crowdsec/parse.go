@@ -39,6 +39,9 @@ func runParse(input chan types.Event, output chan types.Event, parserCTX parser.
 			if err != nil {
 				log.Errorf("failed parsing: %v", err)
 			}
+			if event.Line.Ack != nil {
+				event.Line.Ack()
+			}
 			elapsed := time.Since(startParsing)
 			globalParsingHistogram.With(prometheus.Labels{"source": event.Line.Src, "type": event.Line.Module}).Observe(elapsed.Seconds())
 			if !parsed.Process {
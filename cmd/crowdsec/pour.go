@@ -67,3 +67,37 @@ func runPour(input chan types.Event, holders []leaky.BucketFactory, buckets *lea
 		}
 	}
 }
+
+// runSaveBucketsState periodically saves the state of the live buckets, so that
+// they survive an unclean shutdown of the agent.
+func runSaveBucketsState(path string, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-bucketsTomb.Dying():
+			return nil
+		case <-ticker.C:
+			if err := leaky.SaveBucketsState(path, buckets); err != nil {
+				log.Errorf("unable to save buckets state: %s", err)
+			}
+		}
+	}
+}
+
+// runExpireShadows periodically counts the overflows of the shadow scenarios (and of the
+// scenarios they shadow) that were not matched by the other version as divergences.
+func runExpireShadows() error {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-bucketsTomb.Dying():
+			return nil
+		case <-ticker.C:
+			buckets.Shadows.Expire(time.Now().UTC())
+		}
+	}
+}
@@ -31,7 +31,7 @@ func debugHandler(sig os.Signal, cConfig *csconfig.Config) error {
 	)
 
 	// stop goroutines
-	if err = ShutdownCrowdsecRoutines(); err != nil {
+	if err = ShutdownCrowdsecRoutines(cConfig); err != nil {
 		log.Warningf("Failed to shut down routines: %s", err)
 	}
 
@@ -125,7 +125,7 @@ func reloadHandler(sig os.Signal) (*csconfig.Config, error) {
 	return cConfig, nil
 }
 
-func ShutdownCrowdsecRoutines() error {
+func ShutdownCrowdsecRoutines(cConfig *csconfig.Config) error {
 	var reterr error
 
 	log.Debugf("Shutting down crowdsec sub-routines")
@@ -152,6 +152,16 @@ func ShutdownCrowdsecRoutines() error {
 
 	log.Debugf("parsers is done")
 	time.Sleep(1 * time.Second) // ugly workaround for now to ensure PourItemtoholders are finished
+
+	// save the buckets while they are still alive, they will be restored at the next start
+	if useBucketsState(cConfig) && buckets != nil {
+		if err := leaky.SaveBucketsState(cConfig.Crowdsec.BucketsState.Path, buckets); err != nil {
+			log.Errorf("unable to save buckets state: %s", err)
+		} else {
+			log.Infof("Buckets state saved to %s", cConfig.Crowdsec.BucketsState.Path)
+		}
+	}
+
 	bucketsTomb.Kill(nil)
 
 	if err := bucketsTomb.Wait(); err != nil {
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/crowdsecurity/crowdsec/pkg/apiclient"
+)
+
+// how long we wait for the LAPI before falling back to the local bucket
+const lapiStateTimeout = 2 * time.Second
+
+// LAPIStateBackend shares the bucket levels through the local API, so that all the
+// agents registered on the same LAPI aggregate their pours.
+type LAPIStateBackend struct {
+	// the client is fetched on first use, as it's initialized after the scenarios are loaded
+	getClient func() (*apiclient.ApiClient, error)
+}
+
+func NewLAPIStateBackend(getClient func() (*apiclient.ApiClient, error)) *LAPIStateBackend {
+	return &LAPIStateBackend{getClient: getClient}
+}
+
+func (l *LAPIStateBackend) Pour(ctx context.Context, key string, count int, leakspeed time.Duration) (float64, error) {
+	client, err := l.getClient()
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, lapiStateTimeout)
+	defer cancel()
+
+	resp, _, err := client.Buckets.Pour(ctx, apiclient.BucketPourRequest{
+		Key:       key,
+		Count:     count,
+		Leakspeed: leakspeed.String(),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("pouring in shared bucket: %w", err)
+	}
+
+	return resp.Level, nil
+}
+
+func (l *LAPIStateBackend) Reset(ctx context.Context, key string) error {
+	client, err := l.getClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, lapiStateTimeout)
+	defer cancel()
+
+	if _, err := client.Buckets.Reset(ctx, key); err != nil {
+		return fmt.Errorf("resetting shared bucket: %w", err)
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package acquisition
 
 import (
 	"context"
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"io"
@@ -59,6 +60,7 @@ var (
 	// We declare everything here so we can tell if they are unsupported, or excluded from the build
 	AcquisitionSources = map[string]func() DataSource{}
 	transformRuntimes  = map[string]*vm.Program{}
+	configHashes       = map[string]string{}
 )
 
 func GetDataSourceIface(dataSourceType string) (DataSource, error) {
@@ -215,8 +217,25 @@ func GetMetricsLevelFromPromCfg(prom *csconfig.PrometheusCfg) int {
 	return configuration.METRICS_FULL
 }
 
+// configHash identifies the content of a datasource configuration, to tell which ones changed on reload
+func configHash(sub configuration.DataSourceCommonCfg) (string, error) {
+	sub.UniqueId = ""
+
+	out, err := yaml.Marshal(sub)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(out)), nil
+}
+
 // LoadAcquisitionFromFile unmarshals the configuration item and checks its availability
 func LoadAcquisitionFromFile(config *csconfig.CrowdsecServiceCfg, prom *csconfig.PrometheusCfg) ([]DataSource, error) {
+	return loadAcquisitionFromFile(config, prom, nil)
+}
+
+// loadAcquisitionFromFile skips the items for which reuse() returns true, they are not configured.
+func loadAcquisitionFromFile(config *csconfig.CrowdsecServiceCfg, prom *csconfig.PrometheusCfg, reuse func(hash string) bool) ([]DataSource, error) {
 	var sources []DataSource
 
 	metrics_level := GetMetricsLevelFromPromCfg(prom)
@@ -292,6 +311,16 @@ func LoadAcquisitionFromFile(config *csconfig.CrowdsecServiceCfg, prom *csconfig
 				return nil, fmt.Errorf("in file %s (position %d) - %w", acquisFile, idx, err)
 			}
 
+			hash, err := configHash(sub)
+			if err != nil {
+				return nil, fmt.Errorf("in file %s (position %d) - %w", acquisFile, idx, err)
+			}
+
+			if reuse != nil && reuse(hash) {
+				log.Debugf("datasource %s in %s (position %d) did not change", sub.Source, acquisFile, idx)
+				continue
+			}
+
 			uniqueId := uuid.NewString()
 			sub.UniqueId = uniqueId
 
@@ -315,6 +344,31 @@ func LoadAcquisitionFromFile(config *csconfig.CrowdsecServiceCfg, prom *csconfig
 				transformRuntimes[uniqueId] = vm
 			}
 
+			if sub.Multiline != nil {
+				rt, err := compileMultiline(sub.Multiline)
+				if err != nil {
+					return nil, fmt.Errorf("while configuring multiline for datasource %s in %s (position %d): %w", sub.Source, acquisFile, idx, err)
+				}
+
+				multilineRuntimes[uniqueId] = rt
+			}
+
+			if sub.RateLimit != nil {
+				name := sub.Name
+				if name == "" {
+					name = sub.Source
+				}
+
+				rt, err := compileRateLimit(name, sub.RateLimit)
+				if err != nil {
+					return nil, fmt.Errorf("while configuring rate limit for datasource %s in %s (position %d): %w", sub.Source, acquisFile, idx, err)
+				}
+
+				rateLimitRuntimes[uniqueId] = rt
+			}
+
+			configHashes[uniqueId] = hash
+
 			sources = append(sources, src)
 		}
 	}
@@ -370,7 +424,12 @@ func transform(transformChan chan types.Event, output chan types.Event, acquisTo
 		case <-acquisTomb.Dying():
 			logger.Debugf("transformer is dying")
 			return
-		case evt := <-transformChan:
+		case evt, ok := <-transformChan:
+			if !ok {
+				logger.Debugf("transformer input is closed")
+				return
+			}
+
 			logger.Tracef("Received event %s", evt.Line.Raw)
 
 			out, err := expr.Run(transformRuntime, map[string]interface{}{"evt": &evt})
@@ -416,56 +475,141 @@ func transform(transformChan chan types.Event, output chan types.Event, acquisTo
 	}
 }
 
-func StartAcquisition(ctx context.Context, sources []DataSource, output chan types.Event, acquisTomb *tomb.Tomb) error {
-	// Don't wait if we have no sources, as it will hang forever
-	if len(sources) == 0 {
-		return nil
-	}
+// startSource runs a datasource and the stages of its pipeline, each datasource has its own tomb
+// so it can be stopped on reload. The caller must hold sourcesMu.
+func startSource(ctx context.Context, subsrc DataSource, output chan types.Event, acquisTomb *tomb.Tomb) {
+	srcTomb := &tomb.Tomb{}
+	outChan := output
 
-	for i := range sources {
-		subsrc := sources[i] // ensure its a copy
-		log.Debugf("starting one source %d/%d ->> %T", i, len(sources), subsrc)
+	log.Debugf("datasource %s UUID: %s", subsrc.GetName(), subsrc.GetUuid())
 
-		acquisTomb.Go(func() error {
-			defer trace.CatchPanic("crowdsec/acquis")
+	if transformRuntime, ok := transformRuntimes[subsrc.GetUuid()]; ok {
+		log.Infof("transform expression found for datasource %s", subsrc.GetName())
 
-			var err error
-
-			outChan := output
+		transformChan := make(chan types.Event)
+		outChan = transformChan
+		transformLogger := log.WithFields(log.Fields{
+			"component":  "transform",
+			"datasource": subsrc.GetName(),
+		})
 
-			log.Debugf("datasource %s UUID: %s", subsrc.GetName(), subsrc.GetUuid())
+		srcTomb.Go(func() error {
+			transform(transformChan, output, srcTomb, transformRuntime, transformLogger)
+			return nil
+		})
+	}
 
-			if transformRuntime, ok := transformRuntimes[subsrc.GetUuid()]; ok {
-				log.Infof("transform expression found for datasource %s", subsrc.GetName())
+	if rateLimitRuntime, ok := rateLimitRuntimes[subsrc.GetUuid()]; ok {
+		log.Infof("rate limit found for datasource %s", subsrc.GetName())
 
-				transformChan := make(chan types.Event)
-				outChan = transformChan
-				transformLogger := log.WithFields(log.Fields{
-					"component":  "transform",
-					"datasource": subsrc.GetName(),
-				})
+		rateLimitOut := outChan
+		rateLimitChan := make(chan types.Event)
+		outChan = rateLimitChan
+		rateLimitLogger := log.WithFields(log.Fields{
+			"component":  "ratelimit",
+			"datasource": subsrc.GetName(),
+		})
 
-				acquisTomb.Go(func() error {
-					transform(outChan, output, acquisTomb, transformRuntime, transformLogger)
-					return nil
-				})
+		srcTomb.Go(func() error {
+			rateLimit(rateLimitChan, rateLimitOut, srcTomb, rateLimitRuntime, rateLimitLogger)
+			// let the next stage return
+			if rateLimitOut != output {
+				close(rateLimitOut)
 			}
 
-			if subsrc.GetMode() == configuration.TAIL_MODE {
-				err = subsrc.StreamingAcquisition(ctx, outChan, acquisTomb)
-			} else {
-				err = subsrc.OneShotAcquisition(ctx, outChan, acquisTomb)
-			}
+			return nil
+		})
+	}
 
-			if err != nil {
-				// if one of the acqusition returns an error, we kill the others to properly shutdown
-				acquisTomb.Kill(err)
+	if multilineRuntime, ok := multilineRuntimes[subsrc.GetUuid()]; ok {
+		log.Infof("multiline configuration found for datasource %s", subsrc.GetName())
+
+		multilineOut := outChan
+		multilineChan := make(chan types.Event)
+		outChan = multilineChan
+		multilineLogger := log.WithFields(log.Fields{
+			"component":  "multiline",
+			"datasource": subsrc.GetName(),
+		})
+
+		srcTomb.Go(func() error {
+			multiline(multilineChan, multilineOut, srcTomb, multilineRuntime, multilineLogger)
+			// let the next stage return
+			if multilineOut != output {
+				close(multilineOut)
 			}
 
 			return nil
 		})
 	}
 
+	srcTomb.Go(func() error {
+		defer trace.CatchPanic("crowdsec/acquis")
+
+		if subsrc.GetMode() == configuration.TAIL_MODE {
+			return subsrc.StreamingAcquisition(ctx, outChan, srcTomb)
+		}
+
+		err := subsrc.OneShotAcquisition(ctx, outChan, srcTomb)
+		if outChan != output {
+			// nothing more to read, the stages flush their pending events and return
+			close(outChan)
+		}
+
+		return err
+	})
+
+	stop := make(chan struct{})
+	runningSources[subsrc.GetUuid()] = &runningSource{
+		source: subsrc,
+		tomb:   srcTomb,
+		stop:   stop,
+	}
+
+	acquisTomb.Go(func() error {
+		defer trace.CatchPanic("crowdsec/acquis")
+
+		select {
+		case <-acquisTomb.Dying():
+			srcTomb.Kill(nil)
+		case <-stop:
+			// stopped on reload, the errors don't concern the other datasources
+			srcTomb.Kill(nil)
+
+			if err := srcTomb.Wait(); err != nil {
+				log.Warningf("datasource %s stopped: %s", subsrc.GetName(), err)
+			}
+
+			return nil
+		case <-srcTomb.Dying():
+		}
+
+		if err := srcTomb.Wait(); err != nil {
+			// if one of the acqusition returns an error, we kill the others to properly shutdown
+			acquisTomb.Kill(err)
+		}
+
+		return nil
+	})
+}
+
+func StartAcquisition(ctx context.Context, sources []DataSource, output chan types.Event, acquisTomb *tomb.Tomb) error {
+	// Don't wait if we have no sources, as it will hang forever
+	if len(sources) == 0 {
+		return nil
+	}
+
+	sourcesMu.Lock()
+
+	runningSources = make(map[string]*runningSource)
+
+	for i := range sources {
+		log.Debugf("starting one source %d/%d ->> %T", i, len(sources), sources[i])
+		startSource(ctx, sources[i], output, acquisTomb)
+	}
+
+	sourcesMu.Unlock()
+
 	/*return only when acquisition is over (cat) or never (tail)*/
 	err := acquisTomb.Wait()
 
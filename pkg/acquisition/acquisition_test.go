@@ -290,6 +290,34 @@ func TestLoadAcquisitionFromFile(t *testing.T) {
 			},
 			ExpectedLen: 1,
 		},
+		{
+			TestName: "multiline",
+			Config: csconfig.CrowdsecServiceCfg{
+				AcquisitionFiles: []string{"test_files/multiline.yaml"},
+			},
+			ExpectedLen: 1,
+		},
+		{
+			TestName: "bad_multiline",
+			Config: csconfig.CrowdsecServiceCfg{
+				AcquisitionFiles: []string{"test_files/bad_multiline.yaml"},
+			},
+			ExpectedError: "while configuring multiline for datasource mock in test_files/bad_multiline.yaml (position 0): invalid start_pattern",
+		},
+		{
+			TestName: "rate_limit",
+			Config: csconfig.CrowdsecServiceCfg{
+				AcquisitionFiles: []string{"test_files/rate_limit.yaml"},
+			},
+			ExpectedLen: 1,
+		},
+		{
+			TestName: "bad_rate_limit",
+			Config: csconfig.CrowdsecServiceCfg{
+				AcquisitionFiles: []string{"test_files/bad_rate_limit.yaml"},
+			},
+			ExpectedError: "while configuring rate limit for datasource mock in test_files/bad_rate_limit.yaml (position 0): unknown policy 'wait', must be block or drop",
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.TestName, func(t *testing.T) {
@@ -309,6 +337,13 @@ func TestLoadAcquisitionFromFile(t *testing.T) {
 				assert.Equal(t, "${NON_EXISTING}", mock.Labels["non_existing"])
 				assert.Equal(t, log.InfoLevel, mock.logger.Logger.Level)
 			}
+
+			if tc.TestName == "multiline" {
+				mock := dss[0].Dump().(*MockSource)
+				require.NotNil(t, mock.Multiline)
+				assert.Equal(t, 2*time.Second, mock.Multiline.FlushTimeout)
+				assert.Equal(t, 100, mock.Multiline.MaxLines)
+			}
 		})
 	}
 }
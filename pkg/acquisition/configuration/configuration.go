@@ -1,6 +1,8 @@
 package configuration
 
 import (
+	"time"
+
 	log "github.com/sirupsen/logrus"
 )
 
@@ -13,9 +15,37 @@ type DataSourceCommonCfg struct {
 	UseTimeMachine bool                   `yaml:"use_time_machine,omitempty"`
 	UniqueId       string                 `yaml:"unique_id,omitempty"`
 	TransformExpr  string                 `yaml:"transform,omitempty"`
+	Multiline      *MultilineCfg          `yaml:"multiline,omitempty"`
+	RateLimit      *RateLimitCfg          `yaml:"rate_limit,omitempty"`
 	Config         map[string]interface{} `yaml:",inline"` // to keep the datasource-specific configuration directives
 }
 
+// MultilineCfg describes how consecutive lines from the same source are joined into a single event.
+// A line matching StartPattern begins a new event, a line matching ContinuationPattern is appended
+// to the current one. If both are set, a line matching neither begins a new event.
+type MultilineCfg struct {
+	StartPattern        string        `yaml:"start_pattern,omitempty"`
+	ContinuationPattern string        `yaml:"continuation_pattern,omitempty"`
+	MaxLines            int           `yaml:"max_lines,omitempty"`     // flush the event once it has this many lines
+	FlushTimeout        time.Duration `yaml:"flush_timeout,omitempty"` // flush the event if no line has been received for this long
+}
+
+// RateLimitCfg limits the events a datasource sends to the parsers.
+// With the "block" policy (default), the datasource waits when it goes over the rate or when the parsers
+// are behind. With the "drop" policy, these events are discarded instead.
+type RateLimitCfg struct {
+	EventsPerSecond float64 `yaml:"events_per_second,omitempty"`
+	Burst           int     `yaml:"burst,omitempty"`
+	SampleRatio     float64 `yaml:"sample_ratio,omitempty"` // fraction of the events that are kept, all of them if 0
+	Policy          string  `yaml:"policy,omitempty"`
+	QueueSize       int     `yaml:"queue_size,omitempty"` // events waiting for the parsers before they're dropped
+}
+
+const (
+	RATE_LIMIT_BLOCK = "block"
+	RATE_LIMIT_DROP  = "drop"
+)
+
 const (
 	TAIL_MODE   = "tail"
 	CAT_MODE    = "cat"
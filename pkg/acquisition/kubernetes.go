@@ -0,0 +1,12 @@
+//go:build !no_datasource_kubernetes
+
+package acquisition
+
+import (
+	kubernetesacquisition "github.com/crowdsecurity/crowdsec/pkg/acquisition/modules/kubernetes"
+)
+
+//nolint:gochecknoinits
+func init() {
+	registerDataSource("kubernetes", func() DataSource { return &kubernetesacquisition.KubernetesSource{} })
+}
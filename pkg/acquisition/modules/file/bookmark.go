@@ -0,0 +1,398 @@
+package fileacquisition
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nxadm/tail"
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/tomb.v2"
+
+	"github.com/crowdsecurity/crowdsec/pkg/acquisition/configuration"
+	"github.com/crowdsecurity/crowdsec/pkg/csconfig"
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+const (
+	// StartEnd reads only the lines written after crowdsec started (default)
+	StartEnd = "end"
+	// StartBeginning reads the files from their first line
+	StartBeginning = "beginning"
+	// StartResume reads the files from the position saved in the bookmarks, or from the end if there is none
+	StartResume = "resume"
+)
+
+// fingerprintSize is the maximum number of bytes used to identify a file
+const fingerprintSize = 256
+
+// how often the read positions are written to disk
+const bookmarkSaveInterval = 5 * time.Second
+
+// bookmark is the position of the last line read in a file
+type bookmark struct {
+	Inode  uint64 `json:"inode"`
+	Offset int64  `json:"offset"`
+	// Fingerprint is a hash of the first line of the file, to recognize it after
+	// rotation when the inode can't be used (compressed files, windows)
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// bookmarkStore keeps the bookmarks of a datasource, by file name, and saves them to disk
+type bookmarkStore struct {
+	path      string
+	mu        sync.Mutex
+	bookmarks map[string]bookmark
+	dirty     bool
+}
+
+func newBookmarkStore(path string) (*bookmarkStore, error) {
+	s := &bookmarkStore{
+		path:      path,
+		bookmarks: make(map[string]bookmark),
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not read bookmarks: %w", err)
+	}
+
+	if len(content) == 0 {
+		return s, nil
+	}
+
+	if err := json.Unmarshal(content, &s.bookmarks); err != nil {
+		return nil, fmt.Errorf("could not parse bookmarks %s: %w", path, err)
+	}
+
+	return s, nil
+}
+
+func (s *bookmarkStore) get(filename string) (bookmark, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.bookmarks[filename]
+
+	return b, ok
+}
+
+func (s *bookmarkStore) set(filename string, b bookmark) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.bookmarks[filename] = b
+	s.dirty = true
+}
+
+// save writes the bookmarks if they changed since the last save. The file is replaced
+// atomically so that a crash never leaves truncated bookmarks.
+func (s *bookmarkStore) save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.dirty {
+		return nil
+	}
+
+	content, err := json.Marshal(s.bookmarks)
+	if err != nil {
+		return fmt.Errorf("could not serialize bookmarks: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("could not save bookmarks: %w", err)
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("could not save bookmarks: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("could not save bookmarks: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("could not save bookmarks: %w", err)
+	}
+
+	s.dirty = false
+
+	return nil
+}
+
+// openMaybeGz returns a reader on the content of a file, decompressed if it's a .gz file
+func openMaybeGz(filename string) (io.ReadCloser, error) {
+	fd, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasSuffix(filename, ".gz") {
+		return fd, nil
+	}
+
+	gz, err := gzip.NewReader(fd)
+	if err != nil {
+		fd.Close()
+		return nil, err
+	}
+
+	return struct {
+		io.Reader
+		io.Closer
+	}{gz, fd}, nil
+}
+
+// fingerprint returns a hash of the first line of a file (or of its first bytes if the line is
+// long), or an empty string if the first line is not complete yet
+func fingerprint(filename string) string {
+	r, err := openMaybeGz(filename)
+	if err != nil {
+		return ""
+	}
+	defer r.Close()
+
+	head, err := bufio.NewReaderSize(r, fingerprintSize).Peek(fingerprintSize)
+	if idx := slices.Index(head, '\n'); idx >= 0 {
+		head = head[:idx+1]
+	} else if err != nil {
+		// no newline in a short file, the first line is still being written
+		return ""
+	}
+
+	sum := sha256.Sum256(head)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// identify returns the bookmark of the beginning of a file
+func identify(filename string) bookmark {
+	b := bookmark{
+		Fingerprint: fingerprint(filename),
+	}
+
+	if fi, err := os.Stat(filename); err == nil {
+		b.Inode = inode(fi)
+	}
+
+	return b
+}
+
+// sameFile returns true if the bookmark was taken on the file described by current
+func (b bookmark) sameFile(current bookmark) bool {
+	if b.Inode != 0 && current.Inode != 0 && b.Inode != current.Inode {
+		return false
+	}
+
+	if b.Fingerprint != "" && current.Fingerprint != "" && b.Fingerprint != current.Fingerprint {
+		return false
+	}
+
+	// at least one of them must have matched
+	return (b.Inode != 0 && b.Inode == current.Inode) || (b.Fingerprint != "" && b.Fingerprint == current.Fingerprint)
+}
+
+// findRotated looks for the file that was bookmarked among the rotated versions of filename
+// (filename.1, filename.1.gz, filename-20240101.gz...). A file renamed by the rotation keeps
+// its inode, a file that was copied (copytruncate) or compressed is recognized by its fingerprint.
+func findRotated(filename string, b bookmark) string {
+	candidates, err := filepath.Glob(filename + "?*")
+	if err != nil {
+		return ""
+	}
+
+	for _, candidate := range candidates {
+		if !strings.HasSuffix(candidate, ".gz") {
+			fi, err := os.Stat(candidate)
+			if err != nil || fi.IsDir() || fi.Size() < b.Offset {
+				continue
+			}
+
+			if b.Inode != 0 && inode(fi) == b.Inode {
+				return candidate
+			}
+		}
+
+		if b.Fingerprint != "" && fingerprint(candidate) == b.Fingerprint {
+			return candidate
+		}
+	}
+
+	return ""
+}
+
+// defaultBookmarkPath returns a bookmark file in the data directory, named after the files
+// of the datasource so that it's the same across restarts
+func defaultBookmarkPath(filenames []string) (string, error) {
+	cfg := csconfig.GetConfig()
+	if cfg.ConfigPaths == nil || cfg.ConfigPaths.DataDir == "" {
+		return "", errors.New("no data directory to save the read positions, bookmark_path must be set")
+	}
+
+	sorted := slices.Clone(filenames)
+	slices.Sort(sorted)
+	sum := sha256.Sum256([]byte(strings.Join(sorted, "\n")))
+
+	return filepath.Join(cfg.ConfigPaths.DataDir, "file-bookmarks-"+hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+// startPosition is where a file is read from when the datasource starts, and the rotated
+// file to read first when the file was rotated while crowdsec was stopped
+type startPosition struct {
+	location      tail.SeekInfo
+	rotated       string
+	rotatedOffset int64
+}
+
+func (f *FileSource) startPosition(filename string) startPosition {
+	fromStart := startPosition{location: tail.SeekInfo{Offset: 0, Whence: io.SeekStart}}
+	fromEnd := startPosition{location: tail.SeekInfo{Offset: 0, Whence: io.SeekEnd}}
+
+	switch {
+	case f.config.StartPosition == StartBeginning:
+		return fromStart
+	case f.config.StartPosition != StartResume || f.bookmarks == nil:
+		return fromEnd
+	}
+
+	logger := f.logger.WithField("file", filename)
+
+	b, ok := f.bookmarks.get(filename)
+	if !ok {
+		logger.Debug("No read position saved, reading from the end")
+		return fromEnd
+	}
+
+	current := identify(filename)
+
+	if b.sameFile(current) {
+		fi, err := os.Stat(filename)
+		if err == nil && fi.Size() < b.Offset {
+			logger.Infof("File was truncated, reading from the beginning")
+			return fromStart
+		}
+
+		logger.Infof("Resuming at offset %d", b.Offset)
+
+		return startPosition{location: tail.SeekInfo{Offset: b.Offset, Whence: io.SeekStart}}
+	}
+
+	rotated := findRotated(filename, b)
+	if rotated == "" && b.Inode != 0 && b.Inode == current.Inode {
+		logger.Infof("File was truncated, reading from the beginning")
+		return fromStart
+	}
+
+	if rotated == "" {
+		logger.Warning("File was rotated and the previous file can't be found, the lines written to it while crowdsec was stopped are lost")
+		return fromStart
+	}
+
+	logger.Infof("File was rotated, reading the end of %s first", rotated)
+
+	fromStart.rotated = rotated
+	fromStart.rotatedOffset = b.Offset
+
+	return fromStart
+}
+
+// readRotated sends the lines of a rotated file after offset, as if they came from filename
+func (f *FileSource) readRotated(filename string, rotated string, offset int64, out chan types.Event, t *tomb.Tomb) error {
+	logger := f.logger.WithField("rotated", rotated)
+
+	r, err := openMaybeGz(rotated)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	if _, err = io.CopyN(io.Discard, r, offset); err != nil {
+		return fmt.Errorf("could not seek to offset %d: %w", offset, err)
+	}
+
+	scanner := bufio.NewScanner(r)
+
+	if f.config.MaxBufferSize > 0 {
+		buf := make([]byte, 0, 64*1024)
+		scanner.Buffer(buf, f.config.MaxBufferSize)
+	}
+
+	src := filename
+	if f.metricsLevel == configuration.METRICS_AGGREGATE {
+		src = filepath.Base(filename)
+	}
+
+	count := 0
+
+	for scanner.Scan() {
+		if scanner.Text() == "" {
+			continue
+		}
+
+		if f.metricsLevel != configuration.METRICS_NONE {
+			linesRead.With(prometheus.Labels{"source": filename}).Inc()
+		}
+
+		evt := types.MakeEvent(f.config.UseTimeMachine, types.LOG, true)
+		evt.Line = types.Line{
+			Raw:     trimLine(scanner.Text()),
+			Labels:  f.config.Labels,
+			Time:    time.Now().UTC(),
+			Src:     src,
+			Process: true,
+			Module:  f.GetName(),
+		}
+
+		select {
+		case out <- evt:
+			count++
+		case <-t.Dying():
+			return nil
+		}
+	}
+
+	logger.Infof("Read %d lines written before the rotation", count)
+
+	return scanner.Err()
+}
+
+// saveBookmarks periodically writes the read positions, and one last time when the datasource stops
+func (f *FileSource) saveBookmarks(t *tomb.Tomb) error {
+	ticker := time.NewTicker(bookmarkSaveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := f.bookmarks.save(); err != nil {
+				f.logger.Error(err)
+			}
+		case <-t.Dying():
+			if err := f.bookmarks.save(); err != nil {
+				f.logger.Error(err)
+			}
+
+			return nil
+		}
+	}
+}
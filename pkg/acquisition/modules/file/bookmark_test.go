@@ -0,0 +1,317 @@
+package fileacquisition
+
+import (
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/tomb.v2"
+
+	"github.com/crowdsecurity/go-cs-lib/cstest"
+
+	"github.com/crowdsecurity/crowdsec/pkg/acquisition/configuration"
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+func appendLines(t *testing.T, filename string, lines ...string) {
+	t.Helper()
+
+	fd, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+
+	for _, line := range lines {
+		_, err = fmt.Fprintln(fd, line)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, fd.Close())
+}
+
+func gzipFile(t *testing.T, src string, dst string) {
+	t.Helper()
+
+	content, err := os.ReadFile(src)
+	require.NoError(t, err)
+
+	fd, err := os.Create(dst)
+	require.NoError(t, err)
+
+	gz := gzip.NewWriter(fd)
+	_, err = gz.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	require.NoError(t, fd.Close())
+	require.NoError(t, os.Remove(src))
+}
+
+func TestBookmarkStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bookmarks.json")
+
+	s, err := newBookmarkStore(path)
+	require.NoError(t, err)
+
+	_, ok := s.get("/var/log/auth.log")
+	assert.False(t, ok)
+
+	// nothing to save
+	require.NoError(t, s.save())
+	assert.NoFileExists(t, path)
+
+	s.set("/var/log/auth.log", bookmark{Inode: 42, Offset: 1234, Fingerprint: "abcd"})
+	require.NoError(t, s.save())
+
+	s, err = newBookmarkStore(path)
+	require.NoError(t, err)
+
+	b, ok := s.get("/var/log/auth.log")
+	require.True(t, ok)
+	assert.Equal(t, bookmark{Inode: 42, Offset: 1234, Fingerprint: "abcd"}, b)
+
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+
+	_, err = newBookmarkStore(path)
+	cstest.RequireErrorContains(t, err, "could not parse bookmarks")
+}
+
+func TestFingerprint(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "test.log")
+
+	// the first line is not complete
+	require.NoError(t, os.WriteFile(filename, []byte("first"), 0o644))
+	assert.Empty(t, fingerprint(filename))
+
+	appendLines(t, filename, " line", "second line")
+
+	fp := fingerprint(filename)
+	assert.NotEmpty(t, fp)
+
+	// only the first line counts
+	appendLines(t, filename, "third line")
+	assert.Equal(t, fp, fingerprint(filename))
+
+	// same content, compressed
+	gzipFile(t, filename, filename+".1.gz")
+	assert.Equal(t, fp, fingerprint(filename+".1.gz"))
+
+	assert.Empty(t, fingerprint(filepath.Join(dir, "missing.log")))
+}
+
+func TestSameFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		saved    bookmark
+		current  bookmark
+		expected bool
+	}{
+		{"same inode and fingerprint", bookmark{Inode: 1, Fingerprint: "a"}, bookmark{Inode: 1, Fingerprint: "a"}, true},
+		{"same inode, empty file", bookmark{Inode: 1, Fingerprint: "a"}, bookmark{Inode: 1}, true},
+		{"same inode, new content", bookmark{Inode: 1, Fingerprint: "a"}, bookmark{Inode: 1, Fingerprint: "b"}, false},
+		{"other inode", bookmark{Inode: 1, Fingerprint: "a"}, bookmark{Inode: 2, Fingerprint: "a"}, false},
+		{"no inode, same fingerprint", bookmark{Fingerprint: "a"}, bookmark{Fingerprint: "a"}, true},
+		{"no inode, other fingerprint", bookmark{Fingerprint: "a"}, bookmark{Fingerprint: "b"}, false},
+		{"nothing to compare", bookmark{}, bookmark{}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.saved.sameFile(tc.current))
+		})
+	}
+}
+
+func TestBadStartPosition(t *testing.T) {
+	tests := []struct {
+		config      string
+		expectedErr string
+	}{
+		{
+			config:      "filename: /tmp/test.log\nstart_position: middle",
+			expectedErr: "unsupported start_position middle, must be one of end, beginning or resume",
+		},
+		{
+			config:      "filename: /tmp/test.log\nbookmark_path: /tmp/bookmarks.json",
+			expectedErr: "bookmark_path requires start_position: resume",
+		},
+		{
+			// no data directory in the tests
+			config:      "filename: /tmp/test.log\nstart_position: resume",
+			expectedErr: "bookmark_path must be set",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.expectedErr, func(t *testing.T) {
+			f := FileSource{}
+			err := f.Configure([]byte(tc.config), log.WithField("type", "file"), configuration.METRICS_NONE)
+			cstest.RequireErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
+
+// runResume starts a file datasource with start_position: resume, and returns the lines
+// it reads until it's idle. The lines are acknowledged as if they were parsed.
+func runResume(t *testing.T, filename string, bookmarks string, write func()) []string {
+	t.Helper()
+
+	return runResumeAck(t, filename, bookmarks, write, true)
+}
+
+func runResumeAck(t *testing.T, filename string, bookmarks string, write func(), ack bool) []string {
+	t.Helper()
+
+	config := fmt.Sprintf("filename: %s\nstart_position: resume\nbookmark_path: %s\npoll_without_inotify: true", filename, bookmarks)
+
+	f := FileSource{}
+	require.NoError(t, f.Configure([]byte(config), log.WithField("type", "file"), configuration.METRICS_NONE))
+
+	out := make(chan types.Event)
+	tmb := tomb.Tomb{}
+
+	require.NoError(t, f.StreamingAcquisition(t.Context(), out, &tmb))
+
+	if write != nil {
+		// let the tail open the file before writing to it
+		time.Sleep(500 * time.Millisecond)
+		write()
+	}
+
+	lines := []string{}
+
+	for {
+		select {
+		case evt := <-out:
+			lines = append(lines, evt.Line.Raw)
+
+			// the end of a rotated file is read once, without a bookmark
+			if ack && evt.Line.Ack != nil {
+				evt.Line.Ack()
+			}
+
+			continue
+		case <-time.After(2 * time.Second):
+		}
+
+		break
+	}
+
+	tmb.Kill(nil)
+	require.NoError(t, tmb.Wait())
+
+	return lines
+}
+
+func TestResume(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "test.log")
+	bookmarks := filepath.Join(dir, "bookmarks.json")
+
+	appendLines(t, filename, "old 1", "old 2")
+
+	// no bookmark yet: start from the end
+	lines := runResume(t, filename, bookmarks, func() {
+		appendLines(t, filename, "live 1", "live 2")
+	})
+	assert.Equal(t, []string{"live 1", "live 2"}, lines)
+	assert.FileExists(t, bookmarks)
+
+	// lines written while stopped are read on restart
+	appendLines(t, filename, "stopped 1", "stopped 2")
+
+	lines = runResume(t, filename, bookmarks, nil)
+	assert.Equal(t, []string{"stopped 1", "stopped 2"}, lines)
+
+	// rotated while stopped: the end of the rotated file is read first
+	appendLines(t, filename, "before rotation")
+	require.NoError(t, os.Rename(filename, filename+".1"))
+	appendLines(t, filename, "after rotation")
+
+	lines = runResume(t, filename, bookmarks, nil)
+	assert.Equal(t, []string{"before rotation", "after rotation"}, lines)
+
+	// rotated and compressed while stopped
+	appendLines(t, filename, "before compression")
+	require.NoError(t, os.Rename(filename, filename+".2"))
+	gzipFile(t, filename+".2", filename+".2.gz")
+	appendLines(t, filename, "after compression")
+
+	lines = runResume(t, filename, bookmarks, nil)
+	assert.Equal(t, []string{"before compression", "after compression"}, lines)
+
+	// truncated while stopped: read from the beginning
+	require.NoError(t, os.Truncate(filename, 0))
+	appendLines(t, filename, "truncated")
+
+	lines = runResume(t, filename, bookmarks, nil)
+	assert.Equal(t, []string{"truncated"}, lines)
+}
+
+func TestResumeCopyTruncate(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "test.log")
+	bookmarks := filepath.Join(dir, "bookmarks.json")
+
+	appendLines(t, filename, "before copytruncate 1", "before copytruncate 2")
+
+	// rotated with copytruncate while running: the copy has the identity the file had
+	lines := runResume(t, filename, bookmarks, func() {
+		appendLines(t, filename, "live 1")
+		time.Sleep(500 * time.Millisecond)
+
+		content, err := os.ReadFile(filename)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filename+".1", content, 0o644))
+		require.NoError(t, os.Truncate(filename, 0))
+
+		// let the tail notice the truncation
+		time.Sleep(500 * time.Millisecond)
+		appendLines(t, filename, "after copytruncate")
+	})
+	assert.Equal(t, []string{"live 1", "after copytruncate"}, lines)
+
+	s, err := newBookmarkStore(bookmarks)
+	require.NoError(t, err)
+
+	b, ok := s.get(filename)
+	require.True(t, ok)
+	assert.Equal(t, fingerprint(filename), b.Fingerprint)
+
+	// on restart, the bookmark must not be mistaken for the rotated copy
+	appendLines(t, filename, "stopped 1")
+
+	lines = runResume(t, filename, bookmarks, nil)
+	assert.Equal(t, []string{"stopped 1"}, lines)
+}
+
+func TestResumeNotAcked(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "test.log")
+	bookmarks := filepath.Join(dir, "bookmarks.json")
+
+	appendLines(t, filename, "old 1")
+
+	lines := runResume(t, filename, bookmarks, func() {
+		appendLines(t, filename, "live 1")
+	})
+	assert.Equal(t, []string{"live 1"}, lines)
+
+	// the lines that were sent but not parsed are read again on restart
+	appendLines(t, filename, "stopped 1")
+
+	lines = runResumeAck(t, filename, bookmarks, func() {
+		appendLines(t, filename, "live 2")
+	}, false)
+	assert.Equal(t, []string{"stopped 1", "live 2"}, lines)
+
+	lines = runResume(t, filename, bookmarks, nil)
+	assert.Equal(t, []string{"stopped 1", "live 2"}, lines)
+
+	lines = runResume(t, filename, bookmarks, nil)
+	assert.Empty(t, lines)
+}
@@ -40,9 +40,11 @@ type FileConfiguration struct {
 	Filenames                         []string
 	ExcludeRegexps                    []string `yaml:"exclude_regexps"`
 	Filename                          string
-	ForceInotify                      bool  `yaml:"force_inotify"`
-	MaxBufferSize                     int   `yaml:"max_buffer_size"`
-	PollWithoutInotify                *bool `yaml:"poll_without_inotify"`
+	ForceInotify                      bool   `yaml:"force_inotify"`
+	MaxBufferSize                     int    `yaml:"max_buffer_size"`
+	PollWithoutInotify                *bool  `yaml:"poll_without_inotify"`
+	StartPosition                     string `yaml:"start_position"` // end, beginning or resume, only in tail mode
+	BookmarkPath                      string `yaml:"bookmark_path"`  // where the read positions are saved with start_position: resume
 	configuration.DataSourceCommonCfg `yaml:",inline"`
 }
 
@@ -56,6 +58,7 @@ type FileSource struct {
 	files              []string
 	exclude_regexps    []*regexp.Regexp
 	tailMapMutex       *sync.RWMutex
+	bookmarks          *bookmarkStore
 }
 
 func (f *FileSource) GetUuid() string {
@@ -90,6 +93,20 @@ func (f *FileSource) UnmarshalConfig(yamlConfig []byte) error {
 		return fmt.Errorf("unsupported mode %s for file source", f.config.Mode)
 	}
 
+	if f.config.StartPosition == "" {
+		f.config.StartPosition = StartEnd
+	}
+
+	switch f.config.StartPosition {
+	case StartEnd, StartBeginning, StartResume:
+	default:
+		return fmt.Errorf("unsupported start_position %s, must be one of %s, %s or %s", f.config.StartPosition, StartEnd, StartBeginning, StartResume)
+	}
+
+	if f.config.BookmarkPath != "" && f.config.StartPosition != StartResume {
+		return fmt.Errorf("bookmark_path requires start_position: %s", StartResume)
+	}
+
 	for _, exclude := range f.config.ExcludeRegexps {
 		re, err := regexp.Compile(exclude)
 		if err != nil {
@@ -122,6 +139,27 @@ func (f *FileSource) Configure(yamlConfig []byte, logger *log.Entry, metricsLeve
 
 	f.logger.Tracef("Actual FileAcquisition Configuration %+v", f.config)
 
+	// the whole files are read in cat mode, there is nothing to resume
+	if f.config.StartPosition == StartResume && f.config.Mode == configuration.CAT_MODE {
+		f.logger.Warningf("start_position: %s is only supported in %s mode, ignoring it", StartResume, configuration.TAIL_MODE)
+	}
+
+	if f.config.StartPosition == StartResume && f.config.Mode == configuration.TAIL_MODE {
+		if f.config.BookmarkPath == "" {
+			f.config.BookmarkPath, err = defaultBookmarkPath(f.config.Filenames)
+			if err != nil {
+				return err
+			}
+		}
+
+		f.logger.Infof("Read positions will be saved to %s", f.config.BookmarkPath)
+
+		f.bookmarks, err = newBookmarkStore(f.config.BookmarkPath)
+		if err != nil {
+			return err
+		}
+	}
+
 	for _, pattern := range f.config.Filenames {
 		if f.config.ForceInotify {
 			directory := filepath.Dir(pattern)
@@ -327,6 +365,13 @@ func (f *FileSource) StreamingAcquisition(ctx context.Context, out chan types.Ev
 		return f.monitorNewFiles(out, t)
 	})
 
+	if f.bookmarks != nil {
+		t.Go(func() error {
+			defer trace.CatchPanic("crowdsec/acquis/file/bookmarks")
+			return f.saveBookmarks(t)
+		})
+	}
+
 	for _, file := range f.files {
 		// before opening the file, check if we need to specifically avoid it. (XXX)
 		skip := false
@@ -396,7 +441,9 @@ func (f *FileSource) StreamingAcquisition(ctx context.Context, out chan types.Ev
 			f.logger.Warnf("File %s is a symlink, but inotify polling is enabled. Crowdsec will not be able to detect rotation. Consider setting poll_without_inotify to true in your configuration", file)
 		}
 
-		tail, err := tail.TailFile(file, tail.Config{ReOpen: true, Follow: true, Poll: pollFile, Location: &tail.SeekInfo{Offset: 0, Whence: io.SeekEnd}, Logger: log.NewEntry(log.StandardLogger())})
+		start := f.startPosition(file)
+
+		tail, err := tail.TailFile(file, tail.Config{ReOpen: true, Follow: true, Poll: pollFile, Location: &start.location, Logger: log.NewEntry(log.StandardLogger())})
 		if err != nil {
 			f.logger.Errorf("Could not start tailing file %s : %s", file, err)
 			continue
@@ -407,6 +454,13 @@ func (f *FileSource) StreamingAcquisition(ctx context.Context, out chan types.Ev
 		f.tailMapMutex.Unlock()
 		t.Go(func() error {
 			defer trace.CatchPanic("crowdsec/acquis/file/live/fsnotify")
+
+			if start.rotated != "" {
+				if err := f.readRotated(file, start.rotated, start.rotatedOffset, out, t); err != nil {
+					f.logger.Errorf("Could not read the end of %s : %s", start.rotated, err)
+				}
+			}
+
 			return f.tailFile(out, t, tail)
 		})
 	}
@@ -565,6 +619,9 @@ func (f *FileSource) tailFile(out chan types.Event, t *tomb.Tomb, tail *tail.Tai
 	logger := f.logger.WithField("tail", tail.Filename)
 	logger.Debug("-> start tailing")
 
+	// read position of the file, for the bookmarks
+	var position bookmark
+
 	for {
 		select {
 		case <-t.Dying():
@@ -622,6 +679,20 @@ func (f *FileSource) tailFile(out chan types.Event, t *tomb.Tomb, tail *tail.Tai
 			// we're tailing, it must be real time logs
 			logger.Debugf("pushing %+v", l)
 
+			if f.bookmarks != nil {
+				// the tail starts over at line 1 when the file is rotated or truncated, and the offset
+				// goes backward when it's truncated (copytruncate): the file is not the same anymore
+				if line.Num == 1 || position.Fingerprint == "" || line.SeekInfo.Offset < position.Offset {
+					position = identify(tail.Filename)
+				}
+
+				position.Offset = line.SeekInfo.Offset
+				// move the bookmark once the line is parsed, not when it's sent:
+				// the lines still in the pipeline are read again after a restart
+				ack := position
+				l.Ack = func() { f.bookmarks.set(tail.Filename, ack) }
+			}
+
 			evt := types.MakeEvent(f.config.UseTimeMachine, types.LOG, true)
 			evt.Line = l
 			out <- evt
@@ -0,0 +1,132 @@
+package journalctlacquisition
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/tomb.v2"
+
+	"github.com/crowdsecurity/crowdsec/pkg/csconfig"
+)
+
+// how often the cursor is written to disk
+const cursorSaveInterval = 5 * time.Second
+
+// cursorStore keeps the cursor of the last journal entry that was read, and saves it to disk
+type cursorStore struct {
+	path   string
+	mu     sync.Mutex
+	cursor string
+	dirty  bool
+}
+
+func newCursorStore(path string) (*cursorStore, error) {
+	s := &cursorStore{
+		path: path,
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not read journal cursor: %w", err)
+	}
+
+	s.cursor = strings.TrimSpace(string(content))
+
+	return s, nil
+}
+
+func (s *cursorStore) get() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.cursor
+}
+
+func (s *cursorStore) set(cursor string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cursor = cursor
+	s.dirty = true
+}
+
+// save writes the cursor if it changed since the last save. The file is replaced
+// atomically so that a crash never leaves a truncated cursor.
+func (s *cursorStore) save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.dirty {
+		return nil
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("could not save journal cursor: %w", err)
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(s.cursor + "\n"); err != nil {
+		tmp.Close()
+		return fmt.Errorf("could not save journal cursor: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("could not save journal cursor: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("could not save journal cursor: %w", err)
+	}
+
+	s.dirty = false
+
+	return nil
+}
+
+// defaultCursorPath returns a cursor file in the data directory, named after the filters
+// of the datasource so that it's the same across restarts. It returns an empty string
+// if there is no data directory.
+func defaultCursorPath(filters []string) string {
+	cfg := csconfig.GetConfig()
+	if cfg.ConfigPaths == nil || cfg.ConfigPaths.DataDir == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(filters, "\n")))
+
+	return filepath.Join(cfg.ConfigPaths.DataDir, "journalctl-cursor-"+hex.EncodeToString(sum[:8]))
+}
+
+// saveCursor periodically writes the cursor, and one last time when the datasource stops
+func (j *JournalCtlSource) saveCursor(t *tomb.Tomb) error {
+	ticker := time.NewTicker(cursorSaveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := j.cursor.save(); err != nil {
+				j.logger.Error(err)
+			}
+		case <-t.Dying():
+			if err := j.cursor.save(); err != nil {
+				j.logger.Error(err)
+			}
+
+			return nil
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package journalctlacquisition
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// journalEntry is an entry of the journal, as written by `journalctl --output json`
+type journalEntry struct {
+	// the fields of the entry: a string, or a list of strings when a field has several values
+	Fields map[string]any
+	Cursor string
+	// Time is the time journalctl shows for the entry
+	Time time.Time
+}
+
+// fieldValue converts the JSON value of a field: a string, an array of bytes for binary
+// or non UTF-8 values, null for values that are too large, or an array of them
+func fieldValue(raw json.RawMessage) any {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+
+	var b []byte
+	if err := json.Unmarshal(raw, &b); err == nil {
+		return string(b)
+	}
+
+	var values []json.RawMessage
+	if err := json.Unmarshal(raw, &values); err == nil {
+		ret := make([]string, 0, len(values))
+
+		for _, v := range values {
+			if s, ok := fieldValue(v).(string); ok {
+				ret = append(ret, s)
+			}
+		}
+
+		return ret
+	}
+
+	return ""
+}
+
+func parseEntry(line string) (journalEntry, error) {
+	var raw map[string]json.RawMessage
+
+	if err := json.Unmarshal([]byte(line), &raw); err != nil {
+		return journalEntry{}, err
+	}
+
+	if raw == nil {
+		return journalEntry{}, errors.New("not a journal entry")
+	}
+
+	entry := journalEntry{
+		Fields: make(map[string]any, len(raw)),
+	}
+
+	for k, v := range raw {
+		entry.Fields[k] = fieldValue(v)
+	}
+
+	entry.Cursor = entry.field("__CURSOR")
+
+	// journalctl shows the time the entry was sent, if it's known, rather than received
+	for _, name := range []string{"_SOURCE_REALTIME_TIMESTAMP", "__REALTIME_TIMESTAMP"} {
+		if usec, err := strconv.ParseInt(entry.field(name), 10, 64); err == nil && usec > 0 {
+			entry.Time = time.UnixMicro(usec)
+			break
+		}
+	}
+
+	return entry, nil
+}
+
+// field returns the value of a field, or its first value if it has several
+func (e journalEntry) field(name string) string {
+	switch v := e.Fields[name].(type) {
+	case string:
+		return v
+	case []string:
+		if len(v) > 0 {
+			return v[0]
+		}
+	}
+
+	return ""
+}
+
+// lastField returns the last value of a field, which is the one journalctl shows, and whether it's set
+func (e journalEntry) lastField(name string) (string, bool) {
+	switch v := e.Fields[name].(type) {
+	case string:
+		return v, true
+	case []string:
+		if len(v) > 0 {
+			return v[len(v)-1], true
+		}
+	}
+
+	return "", false
+}
+
+// printThreshold is the length from which journalctl doesn't show the header fields
+const printThreshold = 300
+
+// printable tells if a header field is shown by journalctl
+func printable(s string) bool {
+	return len(s) < printThreshold && isPrintable(s)
+}
+
+// isPrintable tells if s is valid UTF-8 without control characters, except tabs and newlines
+func isPrintable(s string) bool {
+	if !utf8.ValidString(s) {
+		return false
+	}
+
+	for _, r := range s {
+		if (r < ' ' && r != '\t' && r != '\n') || (r >= 0x7f && r <= 0x9f) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// stripTabANSI replaces the tabs with 8 spaces, and removes the color sequences and the
+// carriage returns before a newline or at the end of the message, like journalctl does
+func stripTabANSI(s string) string {
+	var sb strings.Builder
+
+	carriageReturns := 0
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		if c == '\r' {
+			carriageReturns++
+			continue
+		}
+
+		if c == '\n' {
+			carriageReturns = 0
+		}
+
+		for ; carriageReturns > 0; carriageReturns-- {
+			sb.WriteByte('\r')
+		}
+
+		switch {
+		case c == '\t':
+			sb.WriteString("        ")
+		case c == 0x1b && i+1 < len(s) && s[i+1] == '[':
+			// SGR sequence: digits and semicolons, ended by 'm'. journalctl accepts NUL bytes in it too.
+			end := i + 2
+			for end < len(s) && strings.IndexByte("0123456789;\x00", s[end]) >= 0 {
+				end++
+			}
+
+			if end < len(s) && s[end] == 'm' {
+				i = end
+			} else {
+				sb.WriteByte(c)
+			}
+		case c == 0x1b && i+1 < len(s) && s[i+1] == ']':
+			// OSC sequence, ended by BEL
+			end := i + 2
+			for end < len(s) && s[end] >= 32 && s[end] <= 126 {
+				end++
+			}
+
+			if end < len(s) && s[end] == '\a' {
+				i = end
+			} else {
+				sb.WriteByte(c)
+			}
+		case c == 0x1b && i+1 < len(s):
+			// not a sequence, the next character is kept as is
+			sb.WriteByte(c)
+			sb.WriteByte(s[i+1])
+			i++
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return sb.String()
+}
+
+// formatBytes formats a size like journalctl does for the binary messages: 11B, 1.4K, 2.0M...
+func formatBytes(size uint64) string {
+	const units = "KMGTPE"
+
+	if size < 1024 {
+		return strconv.FormatUint(size, 10) + "B"
+	}
+
+	unit := 0
+	factor := uint64(1024)
+
+	for unit < len(units)-1 && size/factor >= 1024 {
+		unit++
+		factor *= 1024
+	}
+
+	return fmt.Sprintf("%d.%d%c", size/factor, (size/(factor/1024)*10/1024)%10, units[unit])
+}
+
+// short formats the entry like `journalctl --output short`, which is what the parsers expect:
+// `Nov 22 11:22:19 hostname sshd[1480]: message`. The continuation lines of a multiline message
+// are indented and returned as separate lines, as they were read from journalctl. An entry
+// without a message or a time is not shown by journalctl, and has no line.
+func (e journalEntry) short() []string {
+	message, ok := e.lastField("MESSAGE")
+	if !ok || e.Time.IsZero() {
+		return nil
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(e.Time.Local().Format("Jan 02 15:04:05"))
+
+	if host, ok := e.lastField("_HOSTNAME"); ok && printable(host) {
+		sb.WriteString(" " + host)
+	}
+
+	identifier, ok := e.lastField("SYSLOG_IDENTIFIER")
+	if !ok || !printable(identifier) {
+		identifier, ok = e.lastField("_COMM")
+	}
+
+	if !ok || !printable(identifier) {
+		identifier = "unknown"
+	}
+
+	sb.WriteString(" " + identifier)
+
+	pid, ok := e.lastField("_PID")
+	if !ok || !printable(pid) {
+		pid, ok = e.lastField("SYSLOG_PID")
+	}
+
+	if ok && printable(pid) {
+		sb.WriteString("[" + pid + "]")
+	}
+
+	sb.WriteString(": ")
+
+	prefix := sb.Len()
+
+	message = stripTabANSI(message)
+	if !isPrintable(message) {
+		return []string{sb.String() + "[" + formatBytes(uint64(len(message))) + " blob data]"}
+	}
+
+	// a trailing newline doesn't make an empty line
+	lines := strings.Split(strings.TrimSuffix(message, "\n"), "\n")
+
+	ret := make([]string, len(lines))
+	ret[0] = sb.String() + lines[0]
+
+	for i := 1; i < len(lines); i++ {
+		ret[i] = strings.Repeat(" ", prefix) + lines[i]
+	}
+
+	return ret
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/url"
 	"os/exec"
+	"slices"
 	"strings"
 	"time"
 
@@ -24,6 +25,7 @@ import (
 type JournalCtlConfiguration struct {
 	configuration.DataSourceCommonCfg `yaml:",inline"`
 	Filters                           []string `yaml:"journalctl_filter"`
+	CursorPath                        string   `yaml:"cursor_path"` // where the journal cursor is saved in tail mode
 }
 
 type JournalCtlSource struct {
@@ -32,13 +34,20 @@ type JournalCtlSource struct {
 	logger       *log.Entry
 	src          string
 	args         []string
+	cursor       *cursorStore
 }
 
 const journalctlCmd string = "journalctl"
 
+// cursorSeekError is written by journalctl when it can't follow the journal from a cursor
+const cursorSeekError = "Failed to seek to cursor"
+
 var (
+	// without --all, the fields of 4096 bytes or more are null
+	journalctlArgsOutput   = []string{"--output", "json", "--all"}
 	journalctlArgsOneShot  = []string{}
 	journalctlArgstreaming = []string{"--follow", "-n", "0"}
+	journalctlArgsResume   = []string{"--follow", "--after-cursor"}
 )
 
 var linesRead = prometheus.NewCounterVec(
@@ -48,10 +57,15 @@ var linesRead = prometheus.NewCounterVec(
 	},
 	[]string{"source"})
 
-func readLine(scanner *bufio.Scanner, out chan string, errChan chan error) error {
+func readLine(ctx context.Context, scanner *bufio.Scanner, out chan string, errChan chan error) error {
 	for scanner.Scan() {
 		txt := scanner.Text()
-		out <- txt
+		select {
+		case out <- txt:
+		case <-ctx.Done():
+			// the command was stopped, nobody reads the output anymore
+			return nil
+		}
 	}
 
 	if errChan != nil && scanner.Err() != nil {
@@ -68,10 +82,12 @@ func readLine(scanner *bufio.Scanner, out chan string, errChan chan error) error
 	return nil
 }
 
-func (j *JournalCtlSource) runJournalCtl(ctx context.Context, out chan types.Event, t *tomb.Tomb) error {
-	ctx, cancel := context.WithCancel(ctx)
+func (j *JournalCtlSource) runJournalCtl(parentCtx context.Context, out chan types.Event, t *tomb.Tomb) error {
+	ctx, cancel := context.WithCancel(parentCtx)
 
-	cmd := exec.CommandContext(ctx, journalctlCmd, j.args...)
+	resumeCursor := j.resumeCursor()
+
+	cmd := exec.CommandContext(ctx, journalctlCmd, j.commandArgs()...)
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
@@ -117,14 +133,34 @@ func (j *JournalCtlSource) runJournalCtl(ctx context.Context, out chan types.Eve
 	}
 
 	t.Go(func() error {
-		return readLine(stdoutscanner, stdoutChan, errChan)
+		return readLine(ctx, stdoutscanner, stdoutChan, errChan)
 	})
 
+	stderrDone := make(chan error, 1)
+
 	t.Go(func() error {
 		// looks like journalctl closes stderr quite early, so ignore its status (but not its output)
-		return readLine(stderrScanner, stderrChan, nil)
+		return readLine(ctx, stderrScanner, stderrChan, stderrDone)
 	})
 
+	readEntries := false
+
+	// a saved cursor can be invalid, or refer to entries that were rotated or vacuumed:
+	// journalctl exits before reading anything, and would fail the same way after every
+	// restart. Forget the cursor and follow the journal from the current entry instead.
+	resumeFailed := func(reason string) bool {
+		if resumeCursor == "" || readEntries || !strings.HasPrefix(reason, cursorSeekError) {
+			return false
+		}
+
+		logger.Warningf("could not resume after cursor %s (%s), reading new entries only", resumeCursor, reason)
+		cancel()
+		cmd.Wait() // avoid zombie process
+		j.cursor.set("")
+
+		return true
+	}
+
 	for {
 		select {
 		case <-t.Dying():
@@ -134,27 +170,53 @@ func (j *JournalCtlSource) runJournalCtl(ctx context.Context, out chan types.Eve
 
 			return nil
 		case stdoutLine := <-stdoutChan:
-			l := types.Line{}
-			l.Raw = stdoutLine
-			logger.Debugf("getting one line : %s", l.Raw)
-			l.Labels = j.config.Labels
-			l.Time = time.Now().UTC()
-			l.Src = j.src
-			l.Process = true
-			l.Module = j.GetName()
-
-			if j.metricsLevel != configuration.METRICS_NONE {
-				linesRead.With(prometheus.Labels{"source": j.src}).Inc()
+			entry, err := parseEntry(stdoutLine)
+			if err != nil {
+				logger.Warningf("could not parse journal entry '%s' : %s", stdoutLine, err)
+				continue
+			}
+
+			// the lines the parsers got when reading `journalctl --output short`
+			for _, raw := range entry.short() {
+				l := types.Line{}
+				l.Raw = raw
+				logger.Debugf("getting one line : %s", l.Raw)
+				l.Labels = j.config.Labels
+				l.Time = time.Now().UTC()
+				l.Src = j.src
+				l.Process = true
+				l.Module = j.GetName()
+
+				if j.metricsLevel != configuration.METRICS_NONE {
+					linesRead.With(prometheus.Labels{"source": j.src}).Inc()
+				}
+
+				evt := types.MakeEvent(j.config.UseTimeMachine, types.LOG, true)
+				evt.Line = l
+				evt.Unmarshaled["journald"] = entry.Fields
+				out <- evt
 			}
 
-			evt := types.MakeEvent(j.config.UseTimeMachine, types.LOG, true)
-			evt.Line = l
-			out <- evt
+			readEntries = true
+
+			if j.cursor != nil && entry.Cursor != "" {
+				j.cursor.set(entry.Cursor)
+			}
 		case stderrLine := <-stderrChan:
+			if resumeFailed(stderrLine) {
+				return j.runJournalCtl(parentCtx, out, t)
+			}
+
 			logger.Warnf("Got stderr message : %s", stderrLine)
 			err := fmt.Errorf("journalctl error : %s", stderrLine)
 			t.Kill(err)
 		case errScanner, ok := <-errChan:
+			if !ok && resumeCursor != "" && !readEntries && stderrDone != nil {
+				// journalctl exited without any entry, its error can still be on stderr
+				errChan = nil
+				continue
+			}
+
 			if !ok {
 				logger.Debugf("errChan is closed, quitting")
 				t.Kill(nil)
@@ -163,6 +225,17 @@ func (j *JournalCtlSource) runJournalCtl(ctx context.Context, out chan types.Eve
 			if errScanner != nil {
 				t.Kill(errScanner)
 			}
+		case _, ok := <-stderrDone:
+			if ok {
+				continue
+			}
+
+			stderrDone = nil
+
+			if errChan == nil {
+				logger.Debugf("journalctl exited, quitting")
+				t.Kill(nil)
+			}
 		}
 	}
 }
@@ -191,25 +264,43 @@ func (j *JournalCtlSource) UnmarshalConfig(yamlConfig []byte) error {
 		j.config.Mode = configuration.TAIL_MODE
 	}
 
-	var args []string
-	if j.config.Mode == configuration.TAIL_MODE {
-		args = journalctlArgstreaming
-	} else {
-		args = journalctlArgsOneShot
-	}
-
 	if len(j.config.Filters) == 0 {
 		return errors.New("journalctl_filter is required")
 	}
 
-	args = append(args, j.config.Filters...)
-
-	j.args = args
+	j.args = j.config.Filters
 	j.src = "journalctl-%s" + strings.Join(j.config.Filters, ".")
 
 	return nil
 }
 
+// commandArgs returns the arguments of journalctl: in tail mode, it follows the journal
+// from the saved cursor if there is one, from the current entry otherwise
+func (j *JournalCtlSource) commandArgs() []string {
+	args := slices.Clone(journalctlArgsOutput)
+
+	switch cursor := j.resumeCursor(); {
+	case j.config.Mode != configuration.TAIL_MODE:
+		args = append(args, journalctlArgsOneShot...)
+	case cursor != "":
+		args = append(args, journalctlArgsResume...)
+		args = append(args, cursor)
+	default:
+		args = append(args, journalctlArgstreaming...)
+	}
+
+	return append(args, j.args...)
+}
+
+// resumeCursor returns the cursor the journal is followed from in tail mode, if any
+func (j *JournalCtlSource) resumeCursor() string {
+	if j.config.Mode != configuration.TAIL_MODE || j.cursor == nil {
+		return ""
+	}
+
+	return j.cursor.get()
+}
+
 func (j *JournalCtlSource) Configure(yamlConfig []byte, logger *log.Entry, metricsLevel int) error {
 	j.logger = logger
 	j.metricsLevel = metricsLevel
@@ -219,6 +310,28 @@ func (j *JournalCtlSource) Configure(yamlConfig []byte, logger *log.Entry, metri
 		return err
 	}
 
+	if j.config.Mode != configuration.TAIL_MODE {
+		return nil
+	}
+
+	if j.config.CursorPath == "" {
+		j.config.CursorPath = defaultCursorPath(j.config.Filters)
+	}
+
+	if j.config.CursorPath == "" {
+		j.logger.Warning("no data directory, the journal cursor will not be saved")
+		return nil
+	}
+
+	j.cursor, err = newCursorStore(j.config.CursorPath)
+	if err != nil {
+		return err
+	}
+
+	if cursor := j.cursor.get(); cursor != "" {
+		j.logger.Infof("Resuming after cursor %s", cursor)
+	}
+
 	return nil
 }
 
@@ -294,6 +407,13 @@ func (j *JournalCtlSource) StreamingAcquisition(ctx context.Context, out chan ty
 		return j.runJournalCtl(ctx, out, t)
 	})
 
+	if j.cursor != nil {
+		t.Go(func() error {
+			defer trace.CatchPanic("crowdsec/acquis/journalctl/cursor")
+			return j.saveCursor(t)
+		})
+	}
+
 	return nil
 }
 
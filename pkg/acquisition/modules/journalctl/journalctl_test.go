@@ -1,10 +1,12 @@
 package journalctlacquisition
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -140,7 +142,7 @@ journalctl_filter:
 			expectedErr:    "",
 			expectedOutput: "",
 			logLevel:       log.WarnLevel,
-			expectedLines:  14,
+			expectedLines:  13,
 		},
 	}
 	for _, ts := range tests {
@@ -212,7 +214,7 @@ journalctl_filter:
 			expectedErr:    "",
 			expectedOutput: "",
 			logLevel:       log.WarnLevel,
-			expectedLines:  14,
+			expectedLines:  13,
 		},
 	}
 	for _, ts := range tests {
@@ -296,3 +298,313 @@ func TestMain(m *testing.M) {
 
 	os.Exit(m.Run())
 }
+
+func TestParseEntry(t *testing.T) {
+	tests := []struct {
+		name          string
+		line          string
+		expectedErr   string
+		expectedShort []string
+		expectedField map[string]any
+	}{
+		{
+			name:          "syslog entry",
+			line:          `{"__CURSOR":"s=1;i=2","__REALTIME_TIMESTAMP":"1606040539000000","_HOSTNAME":"zeroed","SYSLOG_IDENTIFIER":"sshd","_PID":"1480","_SYSTEMD_UNIT":"ssh.service","MESSAGE":"Invalid user wqeqwe from 127.0.0.1 port 55818"}`,
+			expectedShort: []string{time.UnixMicro(1606040539000000).Local().Format("Jan 02 15:04:05") + " zeroed sshd[1480]: Invalid user wqeqwe from 127.0.0.1 port 55818"},
+			expectedField: map[string]any{"_SYSTEMD_UNIT": "ssh.service", "_PID": "1480"},
+		},
+		{
+			name: "no timestamp",
+			line: `{"_HOSTNAME":"zeroed","_COMM":"kernel","MESSAGE":"hello"}`,
+		},
+		{
+			name:          "binary message and multiple values",
+			line:          `{"__REALTIME_TIMESTAMP":"1606040539000000","MESSAGE":[104,105],"TAG":["a","b"]}`,
+			expectedShort: []string{time.UnixMicro(1606040539000000).Local().Format("Jan 02 15:04:05") + " unknown: hi"},
+			expectedField: map[string]any{"MESSAGE": "hi", "TAG": []string{"a", "b"}},
+		},
+		{
+			name:        "not json",
+			line:        `-- No entries --`,
+			expectedErr: "invalid character",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			entry, err := parseEntry(tc.line)
+			cstest.RequireErrorContains(t, err, tc.expectedErr)
+
+			if tc.expectedErr != "" {
+				return
+			}
+
+			assert.Equal(t, tc.expectedShort, entry.short())
+
+			for k, v := range tc.expectedField {
+				assert.Equal(t, v, entry.Fields[k])
+			}
+		})
+	}
+}
+
+// The expected lines were written by journalctl 252 with --output short, for the same entries
+func TestShort(t *testing.T) {
+	ts := time.Date(2026, 10, 18, 2, 36, 33, 0, time.Local)
+	header := "Oct 18 02:36:33 vm "
+
+	tests := []struct {
+		name     string
+		fields   string
+		expected []string
+	}{
+		{
+			name:     "plain",
+			fields:   `"SYSLOG_IDENTIFIER":"sshd","_PID":"1279","SYSLOG_PID":"42","MESSAGE":"plain message"`,
+			expected: []string{header + "sshd[1279]: plain message"},
+		},
+		{
+			name:     "no identifier, no pid",
+			fields:   `"_COMM":"python3","SYSLOG_PID":"42","MESSAGE":"no identifier"`,
+			expected: []string{header + "python3[42]: no identifier"},
+		},
+		{
+			name:     "no identifier nor command",
+			fields:   `"MESSAGE":"hi"`,
+			expected: []string{header + "unknown: hi"},
+		},
+		{
+			name:     "empty identifier",
+			fields:   `"SYSLOG_IDENTIFIER":"","_PID":"1809","MESSAGE":"hi"`,
+			expected: []string{header + "[1809]: hi"},
+		},
+		{
+			name:     "identifier too long",
+			fields:   `"SYSLOG_IDENTIFIER":"` + strings.Repeat("i", 300) + `","_COMM":"python3","_PID":"2206","MESSAGE":"t300"`,
+			expected: []string{header + "python3[2206]: t300"},
+		},
+		{
+			name:     "last value",
+			fields:   `"SYSLOG_IDENTIFIER":["a","b"],"_PID":"1279","MESSAGE":["m1","m2"]`,
+			expected: []string{header + "b[1279]: m2"},
+		},
+		{
+			name:   "multiline",
+			fields: `"SYSLOG_IDENTIFIER":"multi","_PID":"1279","MESSAGE":"first line\nsecond line\n\n\tfourth line\n"`,
+			expected: []string{
+				header + "multi[1279]: first line",
+				"                                second line",
+				"                                ",
+				"                                        fourth line",
+			},
+		},
+		{
+			name:     "empty message",
+			fields:   `"SYSLOG_IDENTIFIER":"empty","_PID":"1809","MESSAGE":""`,
+			expected: []string{header + "empty[1809]: "},
+		},
+		{
+			name:   "carriage returns",
+			fields: `"SYSLOG_IDENTIFIER":"crs","_PID":"1809","MESSAGE":"a\r\r\nb\r"`,
+			expected: []string{
+				header + "crs[1809]: a",
+				"                              b",
+			},
+		},
+		{
+			name:     "colors and title",
+			fields:   `"SYSLOG_IDENTIFIER":"ansi","_PID":"1279","MESSAGE":"\u001b[31mred\u001b[0m text \u001b]0;title\u0007 tab\there"`,
+			expected: []string{header + "ansi[1279]: red text  tab        here"},
+		},
+		{
+			name:     "other escape sequence",
+			fields:   `"SYSLOG_IDENTIFIER":"csi","_PID":"1809","MESSAGE":"x\u001b[2Ky\u001b[?25lz"`,
+			expected: []string{header + "csi[1809]: [13B blob data]"},
+		},
+		{
+			name:     "carriage return",
+			fields:   `"SYSLOG_IDENTIFIER":"cr","_PID":"1809","MESSAGE":"a\rb"`,
+			expected: []string{header + "cr[1809]: [3B blob data]"},
+		},
+		{
+			name:     "binary",
+			fields:   `"SYSLOG_IDENTIFIER":"blob","_PID":"1279","MESSAGE":[98,105,110,0,97,114,121]`,
+			expected: []string{header + "blob[1279]: [7B blob data]"},
+		},
+		{
+			name:     "binary with a tab",
+			fields:   `"SYSLOG_IDENTIFIER":"tabblob","_PID":"1809","MESSAGE":"a\tb\u0001"`,
+			expected: []string{header + "tabblob[1809]: [11B blob data]"},
+		},
+		{
+			name:     "large binary",
+			fields:   `"SYSLOG_IDENTIFIER":"k15","_PID":"1809","MESSAGE":"` + strings.Repeat("x", 1500) + `\u0001"`,
+			expected: []string{header + "k15[1809]: [1.4K blob data]"},
+		},
+		{
+			name:   "no message",
+			fields: `"SYSLOG_IDENTIFIER":"nomessage","_PID":"1279"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			line := fmt.Sprintf(`{"__REALTIME_TIMESTAMP":"%d","_HOSTNAME":"vm",%s}`, ts.UnixMicro(), tc.fields)
+
+			entry, err := parseEntry(line)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, entry.short())
+		})
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "0B", formatBytes(0))
+	assert.Equal(t, "1023B", formatBytes(1023))
+	assert.Equal(t, "1.0K", formatBytes(1024))
+	assert.Equal(t, "2.9K", formatBytes(3001))
+	assert.Equal(t, "1.0M", formatBytes(1024*1024))
+	assert.Equal(t, "1.5G", formatBytes(3*1024*1024*1024/2))
+}
+
+func TestCursor(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping test on windows")
+	}
+
+	cursorPath := filepath.Join(t.TempDir(), "cursor")
+
+	// the last two entries of the test journal were not read yet
+	require.NoError(t, os.WriteFile(cursorPath, []byte("s=0123456789abcdef;i=b\n"), 0o600))
+
+	config := `
+source: journalctl
+mode: tail
+cursor_path: ` + cursorPath + `
+journalctl_filter:
+ - _SYSTEMD_UNIT=ssh.service`
+
+	j := JournalCtlSource{}
+	require.NoError(t, j.Configure([]byte(config), log.WithField("type", "journalctl"), configuration.METRICS_NONE))
+	assert.Equal(t, []string{"--output", "json", "--all", "--follow", "--after-cursor", "s=0123456789abcdef;i=b", "_SYSTEMD_UNIT=ssh.service"}, j.commandArgs())
+
+	tmb := tomb.Tomb{}
+	out := make(chan types.Event)
+
+	require.NoError(t, j.StreamingAcquisition(t.Context(), out, &tmb))
+
+	events := []types.Event{}
+
+	for len(events) < 2 {
+		select {
+		case evt := <-out:
+			events = append(events, evt)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected 2 events, got %d", len(events))
+		}
+	}
+
+	// the cursor is recorded once the event is sent
+	require.Eventually(t, func() bool {
+		return j.cursor.get() == "s=0123456789abcdef;i=d"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	tmb.Kill(nil)
+	require.NoError(t, tmb.Wait())
+
+	assert.Contains(t, events[0].Line.Raw, "zeroed sshd[1791]: Invalid user wqeqwe5 from 127.0.0.1 port 55834")
+
+	fields, ok := events[0].Unmarshaled["journald"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "ssh.service", fields["_SYSTEMD_UNIT"])
+	assert.Equal(t, "1791", fields["_PID"])
+
+	content, err := os.ReadFile(cursorPath)
+	require.NoError(t, err)
+	assert.Equal(t, "s=0123456789abcdef;i=d\n", string(content))
+
+	// without a saved cursor, only the new entries are read
+	j = JournalCtlSource{}
+	require.NoError(t, j.Configure([]byte(strings.ReplaceAll(config, cursorPath, cursorPath+".new")), log.WithField("type", "journalctl"), configuration.METRICS_NONE))
+	assert.Equal(t, []string{"--output", "json", "--all", "--follow", "-n", "0", "_SYSTEMD_UNIT=ssh.service"}, j.commandArgs())
+}
+
+func TestInvalidCursor(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping test on windows")
+	}
+
+	cursorPath := filepath.Join(t.TempDir(), "cursor")
+
+	// a cursor from another journal, that journalctl refuses
+	require.NoError(t, os.WriteFile(cursorPath, []byte("s=fedcba9876543210;i=2a\n"), 0o600))
+
+	config := `
+source: journalctl
+mode: tail
+cursor_path: ` + cursorPath + `
+journalctl_filter:
+ - _SYSTEMD_UNIT=ssh.service`
+
+	logger, hook := test.NewNullLogger()
+	logger.SetLevel(log.WarnLevel)
+
+	j := JournalCtlSource{}
+	require.NoError(t, j.Configure([]byte(config), logger.WithField("type", "journalctl"), configuration.METRICS_NONE))
+
+	tmb := tomb.Tomb{}
+	out := make(chan types.Event)
+
+	require.NoError(t, j.StreamingAcquisition(t.Context(), out, &tmb))
+
+	require.Eventually(t, func() bool {
+		return j.cursor.get() == ""
+	}, 5*time.Second, 50*time.Millisecond)
+
+	// the datasource keeps following the journal, without the cursor
+	time.Sleep(500 * time.Millisecond)
+	assert.True(t, tmb.Alive())
+	assert.Equal(t, []string{"--output", "json", "--all", "--follow", "-n", "0", "_SYSTEMD_UNIT=ssh.service"}, j.commandArgs())
+
+	tmb.Kill(nil)
+	require.NoError(t, tmb.Wait())
+
+	require.NotNil(t, hook.LastEntry())
+	assert.Contains(t, hook.LastEntry().Message, "could not resume after cursor s=fedcba9876543210;i=2a (Failed to seek to cursor: Invalid argument)")
+
+	content, err := os.ReadFile(cursorPath)
+	require.NoError(t, err)
+	assert.Equal(t, "\n", string(content))
+}
+
+func TestResumeError(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping test on windows")
+	}
+
+	cursorPath := filepath.Join(t.TempDir(), "cursor")
+
+	// journalctl fails for another reason than the cursor
+	require.NoError(t, os.WriteFile(cursorPath, []byte("s=unreadable;i=2a\n"), 0o600))
+
+	config := `
+source: journalctl
+mode: tail
+cursor_path: ` + cursorPath + `
+journalctl_filter:
+ - _SYSTEMD_UNIT=ssh.service`
+
+	j := JournalCtlSource{}
+	require.NoError(t, j.Configure([]byte(config), log.WithField("type", "journalctl"), configuration.METRICS_NONE))
+
+	tmb := tomb.Tomb{}
+	out := make(chan types.Event)
+
+	require.NoError(t, j.StreamingAcquisition(t.Context(), out, &tmb))
+
+	err := tmb.Wait()
+	cstest.RequireErrorContains(t, err, "journalctl error : Failed to iterate through journal: Bad message")
+
+	// the cursor is kept
+	assert.Equal(t, "s=unreadable;i=2a", j.cursor.get())
+}
@@ -0,0 +1,653 @@
+package kubernetesacquisition
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/tomb.v2"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/crowdsecurity/go-cs-lib/trace"
+
+	"github.com/crowdsecurity/crowdsec/pkg/acquisition/configuration"
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+const dataSourceName = "kubernetes"
+
+const (
+	// the informers send all the pods again at this interval, which restarts the log streams
+	// that were closed while their container is still running
+	resyncPeriod = 30 * time.Second
+	// the longer lines are truncated
+	maxLineSize = 1024 * 1024
+)
+
+var linesRead = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "cs_kubernetessource_hits_total",
+		Help: "Total lines that were read from pod logs",
+	},
+	[]string{"source"})
+
+type KubernetesConfiguration struct {
+	// KubeConfig is the path to a kubeconfig file, the in-cluster configuration is used if empty
+	KubeConfig string `yaml:"kubeconfig"`
+	// Namespaces to watch, all of them if empty
+	Namespaces    []string `yaml:"namespaces"`
+	LabelSelector string   `yaml:"label_selector"`
+	// NodeName restricts the pods to the ones scheduled on a node, when running as a daemonset
+	NodeName string `yaml:"node_name"`
+	// Containers to read in each pod, all of them if empty
+	Containers []string `yaml:"containers"`
+	// Since reads the logs written before crowdsec started, up to this duration
+	Since time.Duration `yaml:"since"`
+
+	configuration.DataSourceCommonCfg `yaml:",inline"`
+}
+
+type KubernetesSource struct {
+	metricsLevel int
+	Config       KubernetesConfiguration
+	logger       *log.Entry
+	client       kubernetes.Interface
+	selector     labels.Selector
+	// logs written before this time are not read
+	startTime time.Time
+
+	mu sync.Mutex
+	// running log streams, by namespace/pod/container
+	tails map[string]*tail
+	// time of the last line read, by namespace/pod/container
+	lastSeen map[string]time.Time
+	wg       sync.WaitGroup
+}
+
+type tail struct {
+	containerID string
+	cancel      context.CancelFunc
+}
+
+func (k *KubernetesSource) GetUuid() string {
+	return k.Config.UniqueId
+}
+
+func (k *KubernetesSource) UnmarshalConfig(yamlConfig []byte) error {
+	k.Config = KubernetesConfiguration{}
+
+	err := yaml.Unmarshal(yamlConfig, &k.Config)
+	if err != nil {
+		return fmt.Errorf("cannot parse %s datasource configuration: %w", dataSourceName, err)
+	}
+
+	if k.Config.Mode == "" {
+		k.Config.Mode = configuration.TAIL_MODE
+	}
+
+	return nil
+}
+
+func (kc *KubernetesConfiguration) Validate() error {
+	if kc.Mode != configuration.TAIL_MODE && kc.Mode != configuration.CAT_MODE {
+		return fmt.Errorf("unsupported mode %s for %s datasource", kc.Mode, dataSourceName)
+	}
+
+	if kc.Since < 0 {
+		return errors.New("since must be positive")
+	}
+
+	if _, err := labels.Parse(kc.LabelSelector); err != nil {
+		return fmt.Errorf("invalid label_selector: %w", err)
+	}
+
+	return nil
+}
+
+func (k *KubernetesSource) Configure(yamlConfig []byte, logger *log.Entry, metricsLevel int) error {
+	k.logger = logger
+	k.metricsLevel = metricsLevel
+
+	err := k.UnmarshalConfig(yamlConfig)
+	if err != nil {
+		return err
+	}
+
+	return k.configure()
+}
+
+func (k *KubernetesSource) configure() error {
+	if err := k.Config.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	// already validated
+	k.selector, _ = labels.Parse(k.Config.LabelSelector)
+
+	if len(k.Config.Namespaces) == 0 {
+		k.Config.Namespaces = []string{metav1.NamespaceAll}
+	}
+
+	k.tails = make(map[string]*tail)
+	k.lastSeen = make(map[string]time.Time)
+
+	// the tests provide a fake client
+	if k.client != nil {
+		return nil
+	}
+
+	restConfig, err := clientcmd.BuildConfigFromFlags("", k.Config.KubeConfig)
+	if err != nil {
+		return fmt.Errorf("cannot load kubernetes client configuration: %w", err)
+	}
+
+	k.client, err = kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("cannot create kubernetes client: %w", err)
+	}
+
+	return nil
+}
+
+// ConfigureByDSN reads the logs of the pods of a namespace (or all of them):
+// kubernetes://[namespace]?[label_selector=app%3Dnginx][&container=name][&node_name=node][&kubeconfig=path][&since=1h]
+func (k *KubernetesSource) ConfigureByDSN(dsn string, labels map[string]string, logger *log.Entry, uuid string) error {
+	k.logger = logger
+	k.Config = KubernetesConfiguration{}
+	k.Config.Mode = configuration.CAT_MODE
+	k.Config.Labels = labels
+	k.Config.UniqueId = uuid
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return fmt.Errorf("while parsing dsn '%s': %w", dsn, err)
+	}
+
+	if u.Scheme != dataSourceName {
+		return fmt.Errorf("invalid DSN %s for %s source, must start with %s://", dsn, dataSourceName, dataSourceName)
+	}
+
+	if u.Host != "" {
+		k.Config.Namespaces = []string{u.Host}
+	}
+
+	params := u.Query()
+
+	k.Config.LabelSelector = params.Get("label_selector")
+	k.Config.Containers = params["container"]
+	k.Config.NodeName = params.Get("node_name")
+	k.Config.KubeConfig = params.Get("kubeconfig")
+
+	if since := params.Get("since"); since != "" {
+		k.Config.Since, err = time.ParseDuration(since)
+		if err != nil {
+			return fmt.Errorf("invalid since in dsn: %w", err)
+		}
+	}
+
+	if logLevel := params.Get("log_level"); logLevel != "" {
+		level, err := log.ParseLevel(logLevel)
+		if err != nil {
+			return fmt.Errorf("invalid log_level in dsn: %w", err)
+		}
+
+		k.Config.LogLevel = &level
+		k.logger.Logger.SetLevel(level)
+	}
+
+	return k.configure()
+}
+
+func (k *KubernetesSource) GetMode() string {
+	return k.Config.Mode
+}
+
+func (k *KubernetesSource) GetName() string {
+	return dataSourceName
+}
+
+func (k *KubernetesSource) CanRun() error {
+	return nil
+}
+
+func (k *KubernetesSource) GetMetrics() []prometheus.Collector {
+	return []prometheus.Collector{linesRead}
+}
+
+func (k *KubernetesSource) GetAggregMetrics() []prometheus.Collector {
+	return []prometheus.Collector{linesRead}
+}
+
+func (k *KubernetesSource) Dump() interface{} {
+	return k
+}
+
+// matches returns true if the pod must be read. The fake clientset used by the tests doesn't
+// apply the selectors to the watches, so they're checked here too.
+func (k *KubernetesSource) matches(pod *corev1.Pod) bool {
+	if !k.selector.Matches(labels.Set(pod.Labels)) {
+		return false
+	}
+
+	if k.Config.NodeName != "" && pod.Spec.NodeName != k.Config.NodeName {
+		return false
+	}
+
+	return true
+}
+
+func (k *KubernetesSource) wantContainer(name string) bool {
+	return len(k.Config.Containers) == 0 || slices.Contains(k.Config.Containers, name)
+}
+
+func (k *KubernetesSource) listOptions(options *metav1.ListOptions) {
+	options.LabelSelector = k.Config.LabelSelector
+
+	if k.Config.NodeName != "" {
+		options.FieldSelector = fields.OneTermEqualSelector("spec.nodeName", k.Config.NodeName).String()
+	}
+}
+
+func containerKey(pod *corev1.Pod, container string) string {
+	return pod.Namespace + "/" + pod.Name + "/" + container
+}
+
+// parseLine splits the timestamp the api server adds in front of each line
+func parseLine(line string) (time.Time, string) {
+	ts, msg, found := strings.Cut(line, " ")
+	if !found {
+		return time.Time{}, line
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return time.Time{}, line
+	}
+
+	return t, msg
+}
+
+// readLine returns the next line of a log stream, without its end of line. A line longer than
+// maxLineSize is truncated, the rest of it is skipped.
+func readLine(r *bufio.Reader) (string, bool, error) {
+	var line []byte
+
+	truncated := false
+
+	for {
+		chunk, err := r.ReadSlice('\n')
+
+		if room := maxLineSize - len(line); len(chunk) > room {
+			chunk = chunk[:room]
+			truncated = true
+		}
+
+		line = append(line, chunk...)
+
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+
+		// the last line can have no end of line
+		if err != nil && (!errors.Is(err, io.EOF) || len(line) == 0) {
+			return "", false, err
+		}
+
+		break
+	}
+
+	line = bytes.TrimSuffix(line, []byte("\n"))
+	line = bytes.TrimSuffix(line, []byte("\r"))
+
+	return string(line), truncated, nil
+}
+
+func (k *KubernetesSource) makeEvent(pod *corev1.Pod, container string, ts time.Time, msg string) types.Event {
+	src := containerKey(pod, container)
+
+	lineLabels := make(map[string]string, len(k.Config.Labels)+3)
+	maps.Copy(lineLabels, k.Config.Labels)
+	lineLabels["namespace"] = pod.Namespace
+	lineLabels["pod"] = pod.Name
+	lineLabels["container"] = container
+
+	if ts.IsZero() {
+		ts = time.Now().UTC()
+	}
+
+	evt := types.MakeEvent(k.Config.UseTimeMachine, types.LOG, true)
+	evt.Line = types.Line{
+		Raw:     msg,
+		Labels:  lineLabels,
+		Time:    ts,
+		Src:     src,
+		Process: true,
+		Module:  k.GetName(),
+	}
+	evt.Unmarshaled["kubernetes"] = map[string]any{
+		"namespace": pod.Namespace,
+		"pod":       pod.Name,
+		"container": container,
+		"node":      pod.Spec.NodeName,
+		"labels":    pod.Labels,
+	}
+
+	switch k.metricsLevel {
+	case configuration.METRICS_FULL:
+		linesRead.With(prometheus.Labels{"source": src}).Inc()
+	case configuration.METRICS_AGGREGATE:
+		linesRead.With(prometheus.Labels{"source": pod.Namespace}).Inc()
+	}
+
+	return evt
+}
+
+// readLogs sends the log lines of a container until the stream is closed, and returns the time of the
+// last line that was sent
+func (k *KubernetesSource) readLogs(ctx context.Context, pod *corev1.Pod, container string, follow bool, since time.Time, out chan types.Event) (time.Time, error) {
+	options := &corev1.PodLogOptions{
+		Container:  container,
+		Follow:     follow,
+		Timestamps: true,
+	}
+
+	if !since.IsZero() {
+		options.SinceTime = &metav1.Time{Time: since}
+	}
+
+	stream, err := k.client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, options).Stream(ctx)
+	if err != nil {
+		return since, fmt.Errorf("while opening the logs of %s: %w", containerKey(pod, container), err)
+	}
+	defer stream.Close()
+
+	last := since
+
+	reader := bufio.NewReaderSize(stream, 64*1024)
+
+	for {
+		line, truncated, err := readLine(reader)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return last, nil
+			}
+
+			return last, fmt.Errorf("while reading the logs of %s: %w", containerKey(pod, container), err)
+		}
+
+		if truncated {
+			k.logger.Warningf("a line of %s is longer than %d bytes, it was truncated", containerKey(pod, container), maxLineSize)
+		}
+
+		ts, msg := parseLine(line)
+
+		// SinceTime has a one second precision, skip what was already read
+		if !ts.IsZero() && !since.IsZero() && !ts.After(since) {
+			continue
+		}
+
+		select {
+		case out <- k.makeEvent(pod, container, ts, msg):
+		case <-ctx.Done():
+			return last, nil
+		}
+
+		if !ts.IsZero() {
+			last = ts
+		}
+	}
+
+	return last, nil
+}
+
+// syncPod starts reading the containers of a pod that are running, and stops reading the
+// ones that were replaced
+func (k *KubernetesSource) syncPod(ctx context.Context, pod *corev1.Pod, out chan types.Event) {
+	if !k.matches(pod) {
+		k.stopPod(pod)
+		return
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	for _, status := range pod.Status.ContainerStatuses {
+		if !k.wantContainer(status.Name) || status.State.Running == nil || status.ContainerID == "" {
+			continue
+		}
+
+		key := containerKey(pod, status.Name)
+
+		if t, ok := k.tails[key]; ok {
+			if t.containerID == status.ContainerID {
+				continue
+			}
+
+			k.logger.Debugf("container %s was restarted", key)
+			t.cancel()
+		}
+
+		since, ok := k.lastSeen[key]
+		if !ok {
+			since = k.startTime
+		}
+
+		tailCtx, cancel := context.WithCancel(ctx)
+		t := &tail{containerID: status.ContainerID, cancel: cancel}
+		k.tails[key] = t
+
+		k.logger.Infof("reading logs of %s", key)
+
+		k.wg.Add(1)
+
+		go func(pod *corev1.Pod, container string) {
+			defer trace.CatchPanic("crowdsec/acquis/kubernetes/tail")
+			defer k.wg.Done()
+			defer cancel()
+
+			last, err := k.readLogs(tailCtx, pod, container, true, since, out)
+			if err != nil {
+				k.logger.Error(err)
+			}
+
+			k.mu.Lock()
+			defer k.mu.Unlock()
+
+			// the stream is restarted at the next resync if the container is still running
+			if k.tails[key] == t {
+				delete(k.tails, key)
+
+				if !last.IsZero() {
+					k.lastSeen[key] = last
+				}
+			}
+
+			k.logger.Debugf("stopped reading logs of %s", key)
+		}(pod, status.Name)
+	}
+}
+
+// stopPod stops reading the containers of a pod that was deleted or doesn't match anymore
+func (k *KubernetesSource) stopPod(pod *corev1.Pod) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	prefix := pod.Namespace + "/" + pod.Name + "/"
+
+	for key, t := range k.tails {
+		if strings.HasPrefix(key, prefix) {
+			k.logger.Infof("stop reading logs of %s", key)
+			t.cancel()
+			delete(k.tails, key)
+		}
+	}
+
+	for key := range k.lastSeen {
+		if strings.HasPrefix(key, prefix) {
+			delete(k.lastSeen, key)
+		}
+	}
+}
+
+func podFromObject(obj any) (*corev1.Pod, bool) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	pod, ok := obj.(*corev1.Pod)
+
+	return pod, ok
+}
+
+func (k *KubernetesSource) RunWatcher(ctx context.Context, out chan types.Event, t *tomb.Tomb) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj any) {
+			if pod, ok := podFromObject(obj); ok {
+				k.syncPod(ctx, pod, out)
+			}
+		},
+		UpdateFunc: func(_, obj any) {
+			if pod, ok := podFromObject(obj); ok {
+				k.syncPod(ctx, pod, out)
+			}
+		},
+		DeleteFunc: func(obj any) {
+			if pod, ok := podFromObject(obj); ok {
+				k.stopPod(pod)
+			}
+		},
+	}
+
+	factories := make([]informers.SharedInformerFactory, 0, len(k.Config.Namespaces))
+
+	for _, namespace := range k.Config.Namespaces {
+		factory := informers.NewSharedInformerFactoryWithOptions(k.client, resyncPeriod,
+			informers.WithNamespace(namespace),
+			informers.WithTweakListOptions(k.listOptions))
+
+		if _, err := factory.Core().V1().Pods().Informer().AddEventHandler(handler); err != nil {
+			return fmt.Errorf("while watching pods: %w", err)
+		}
+
+		factory.Start(t.Dying())
+		factories = append(factories, factory)
+	}
+
+	for _, factory := range factories {
+		for typ, synced := range factory.WaitForCacheSync(t.Dying()) {
+			if !synced {
+				return fmt.Errorf("cannot list %s", typ)
+			}
+		}
+	}
+
+	k.logger.Debugf("pods of namespaces %v are listed", k.Config.Namespaces)
+
+	<-t.Dying()
+	k.logger.Infof("%s datasource stopping", dataSourceName)
+
+	cancel()
+
+	for _, factory := range factories {
+		factory.Shutdown()
+	}
+
+	k.wg.Wait()
+
+	return nil
+}
+
+func (k *KubernetesSource) StreamingAcquisition(ctx context.Context, out chan types.Event, t *tomb.Tomb) error {
+	k.logger.Infof("watching pods of namespaces %v with selector '%s'", k.Config.Namespaces, k.selector)
+
+	k.startTime = time.Now().Add(-k.Config.Since)
+
+	t.Go(func() error {
+		defer trace.CatchPanic("crowdsec/acquis/kubernetes/live")
+		return k.RunWatcher(ctx, out, t)
+	})
+
+	return nil
+}
+
+func (k *KubernetesSource) OneShotAcquisition(ctx context.Context, out chan types.Event, t *tomb.Tomb) error {
+	defer trace.CatchPanic("crowdsec/acquis/kubernetes/oneshot")
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-t.Dying():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	var since time.Time
+
+	if k.Config.Since > 0 {
+		since = time.Now().Add(-k.Config.Since)
+	}
+
+	for _, namespace := range k.Config.Namespaces {
+		options := metav1.ListOptions{}
+		k.listOptions(&options)
+
+		pods, err := k.client.CoreV1().Pods(namespace).List(ctx, options)
+		if err != nil {
+			return fmt.Errorf("while listing pods: %w", err)
+		}
+
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if !k.matches(pod) {
+				continue
+			}
+
+			for _, status := range pod.Status.ContainerStatuses {
+				if !k.wantContainer(status.Name) || status.ContainerID == "" {
+					continue
+				}
+
+				k.logger.Debugf("reading logs of %s", containerKey(pod, status.Name))
+
+				if _, err := k.readLogs(ctx, pod, status.Name, false, since, out); err != nil {
+					k.logger.Error(err)
+				}
+
+				if ctx.Err() != nil {
+					return nil
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,380 @@
+package kubernetesacquisition
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/tomb.v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/crowdsecurity/go-cs-lib/cstest"
+
+	"github.com/crowdsecurity/crowdsec/pkg/acquisition/configuration"
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+func TestConfigure(t *testing.T) {
+	tests := []struct {
+		config      string
+		expectedErr string
+	}{
+		{
+			config:      `mode: batch`,
+			expectedErr: "invalid configuration: unsupported mode batch for kubernetes datasource",
+		},
+		{
+			config:      `since: -1h`,
+			expectedErr: "invalid configuration: since must be positive",
+		},
+		{
+			config:      `label_selector: "app in (web"`,
+			expectedErr: "invalid configuration: invalid label_selector",
+		},
+		{
+			config:      `foobar: [`,
+			expectedErr: "cannot parse kubernetes datasource configuration",
+		},
+		{
+			config: `
+namespaces: [default]
+label_selector: app=web
+containers: [nginx]
+since: 1h`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.expectedErr, func(t *testing.T) {
+			k := KubernetesSource{client: fake.NewSimpleClientset()}
+			err := k.Configure([]byte(tc.config), log.WithField("type", "kubernetes"), configuration.METRICS_NONE)
+			cstest.RequireErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestConfigureByDSN(t *testing.T) {
+	tests := []struct {
+		dsn         string
+		expected    KubernetesConfiguration
+		expectedErr string
+	}{
+		{
+			dsn:         "foobar://default",
+			expectedErr: "invalid DSN foobar://default for kubernetes source, must start with kubernetes://",
+		},
+		{
+			dsn:         "kubernetes://default?since=foobar",
+			expectedErr: "invalid since in dsn",
+		},
+		{
+			dsn:         "kubernetes://default?log_level=foobar",
+			expectedErr: "invalid log_level in dsn",
+		},
+		{
+			dsn: "kubernetes://",
+			expected: KubernetesConfiguration{
+				Namespaces: []string{""},
+			},
+		},
+		{
+			dsn: "kubernetes://default?label_selector=app%3Dweb&container=nginx&container=php&node_name=node-1&since=1h",
+			expected: KubernetesConfiguration{
+				Namespaces:    []string{"default"},
+				LabelSelector: "app=web",
+				Containers:    []string{"nginx", "php"},
+				NodeName:      "node-1",
+				Since:         time.Hour,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.dsn, func(t *testing.T) {
+			k := KubernetesSource{client: fake.NewSimpleClientset()}
+			err := k.ConfigureByDSN(tc.dsn, map[string]string{"type": "nginx"}, log.WithField("type", "kubernetes"), "")
+			cstest.RequireErrorContains(t, err, tc.expectedErr)
+
+			if tc.expectedErr != "" {
+				return
+			}
+
+			tc.expected.Mode = configuration.CAT_MODE
+			tc.expected.Labels = map[string]string{"type": "nginx"}
+			assert.Equal(t, tc.expected, k.Config)
+		})
+	}
+}
+
+func TestParseLine(t *testing.T) {
+	ts, msg := parseLine("2024-01-02T03:04:05.123456789Z GET /index.html 200")
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 123456789, time.UTC), ts)
+	assert.Equal(t, "GET /index.html 200", msg)
+
+	ts, msg = parseLine("no timestamp here")
+	assert.True(t, ts.IsZero())
+	assert.Equal(t, "no timestamp here", msg)
+
+	ts, msg = parseLine("single")
+	assert.True(t, ts.IsZero())
+	assert.Equal(t, "single", msg)
+}
+
+func TestReadLine(t *testing.T) {
+	long := strings.Repeat("x", maxLineSize+10)
+	input := "first\r\n2024-01-02T03:04:05Z " + long + "\nafter\nno end of line"
+
+	r := bufio.NewReaderSize(strings.NewReader(input), 4096)
+
+	line, truncated, err := readLine(r)
+	require.NoError(t, err)
+	assert.False(t, truncated)
+	assert.Equal(t, "first", line)
+
+	// the timestamp of a long line is still read, so the stream can resume after it
+	line, truncated, err = readLine(r)
+	require.NoError(t, err)
+	assert.True(t, truncated)
+	assert.Len(t, line, maxLineSize)
+
+	ts, _ := parseLine(line)
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), ts)
+
+	line, truncated, err = readLine(r)
+	require.NoError(t, err)
+	assert.False(t, truncated)
+	assert.Equal(t, "after", line)
+
+	line, _, err = readLine(r)
+	require.NoError(t, err)
+	assert.Equal(t, "no end of line", line)
+
+	_, _, err = readLine(r)
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func newPod(namespace string, name string, podLabels map[string]string, containers ...string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+			Labels:    podLabels,
+		},
+		Spec: corev1.PodSpec{
+			NodeName: "node-1",
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+		},
+	}
+
+	for _, container := range containers {
+		pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: container})
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{
+			Name:        container,
+			ContainerID: "containerd://" + name + "-" + container,
+			State:       corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+		})
+	}
+
+	return pod
+}
+
+// logRequests returns the options of the log requests that were sent, by namespace
+func logRequests(client *fake.Clientset) map[string][]*corev1.PodLogOptions {
+	ret := map[string][]*corev1.PodLogOptions{}
+
+	for _, action := range client.Actions() {
+		if action.GetSubresource() != "log" {
+			continue
+		}
+
+		generic, ok := action.(k8stesting.GenericAction)
+		if !ok {
+			continue
+		}
+
+		opts, ok := generic.GetValue().(*corev1.PodLogOptions)
+		if !ok {
+			continue
+		}
+
+		ret[action.GetNamespace()] = append(ret[action.GetNamespace()], opts)
+	}
+
+	return ret
+}
+
+func TestOneShot(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		newPod("default", "web", map[string]string{"app": "web"}, "nginx", "sidecar"),
+		newPod("default", "db", map[string]string{"app": "db"}, "postgres"),
+		newPod("kube-system", "web", map[string]string{"app": "web"}, "nginx"),
+	)
+
+	k := KubernetesSource{client: client}
+	require.NoError(t, k.ConfigureByDSN("kubernetes://default?label_selector=app%3Dweb&container=nginx&since=1h",
+		map[string]string{"type": "nginx"}, log.WithField("type", "kubernetes"), ""))
+
+	out := make(chan types.Event, 10)
+	require.NoError(t, k.OneShotAcquisition(t.Context(), out, &tomb.Tomb{}))
+	close(out)
+
+	events := []types.Event{}
+	for evt := range out {
+		events = append(events, evt)
+	}
+
+	require.Len(t, events, 1)
+
+	evt := events[0]
+	assert.Equal(t, "fake logs", evt.Line.Raw)
+	assert.Equal(t, "default/web/nginx", evt.Line.Src)
+	assert.Equal(t, "kubernetes", evt.Line.Module)
+	assert.Equal(t, map[string]string{
+		"type":      "nginx",
+		"namespace": "default",
+		"pod":       "web",
+		"container": "nginx",
+	}, evt.Line.Labels)
+	assert.Equal(t, "node-1", evt.Unmarshaled["kubernetes"].(map[string]any)["node"])
+
+	requests := logRequests(client)["default"]
+	require.Len(t, requests, 1)
+	assert.Equal(t, "nginx", requests[0].Container)
+	assert.False(t, requests[0].Follow)
+	assert.True(t, requests[0].Timestamps)
+	require.NotNil(t, requests[0].SinceTime)
+	assert.WithinDuration(t, time.Now().Add(-time.Hour), requests[0].SinceTime.Time, time.Minute)
+}
+
+// newWatchedClient returns a fake client, and a channel that is closed once the pods are watched:
+// the fake client doesn't send the pods created between the list and the watch
+func newWatchedClient(objects ...runtime.Object) (*fake.Clientset, chan struct{}) {
+	client := fake.NewSimpleClientset(objects...)
+	watching := make(chan struct{})
+
+	var once sync.Once
+
+	client.PrependWatchReactor("pods", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		w, err := client.Tracker().Watch(action.GetResource(), action.GetNamespace())
+		if err != nil {
+			return false, nil, err
+		}
+
+		once.Do(func() { close(watching) })
+
+		return true, w, nil
+	})
+
+	return client, watching
+}
+
+func readEvent(t *testing.T, out chan types.Event) types.Event {
+	t.Helper()
+
+	select {
+	case evt := <-out:
+		return evt
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for an event")
+	}
+
+	return types.Event{}
+}
+
+func TestStreaming(t *testing.T) {
+	ctx := t.Context()
+
+	pending := newPod("default", "api", map[string]string{"app": "web"}, "app")
+	pending.Status.Phase = corev1.PodPending
+	pending.Status.ContainerStatuses[0].State = corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{}}
+	pending.Status.ContainerStatuses[0].ContainerID = ""
+
+	client, watching := newWatchedClient(
+		newPod("default", "web", map[string]string{"app": "web"}, "nginx"),
+		newPod("default", "db", map[string]string{"app": "db"}, "postgres"),
+		pending,
+	)
+
+	k := KubernetesSource{client: client}
+	require.NoError(t, k.Configure([]byte(`
+namespaces: [default]
+label_selector: app=web
+labels:
+  type: nginx`), log.WithField("type", "kubernetes"), configuration.METRICS_NONE))
+
+	out := make(chan types.Event)
+	tmb := tomb.Tomb{}
+	require.NoError(t, k.StreamingAcquisition(ctx, out, &tmb))
+
+	// existing pod
+	evt := readEvent(t, out)
+	assert.Equal(t, "default/web/nginx", evt.Line.Src)
+
+	<-watching
+
+	// new pod
+	_, err := client.CoreV1().Pods("default").Create(ctx, newPod("default", "web-2", map[string]string{"app": "web"}, "nginx"), metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	evt = readEvent(t, out)
+	assert.Equal(t, "default/web-2/nginx", evt.Line.Src)
+	assert.Equal(t, "web-2", evt.Line.Labels["pod"])
+
+	// pending pod starts running
+	running := newPod("default", "api", map[string]string{"app": "web"}, "app")
+	_, err = client.CoreV1().Pods("default").UpdateStatus(ctx, running, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	evt = readEvent(t, out)
+	assert.Equal(t, "default/api/app", evt.Line.Src)
+
+	// restarted container
+	restarted := newPod("default", "web", map[string]string{"app": "web"}, "nginx")
+	restarted.Status.ContainerStatuses[0].ContainerID = "containerd://restarted"
+	_, err = client.CoreV1().Pods("default").UpdateStatus(ctx, restarted, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	evt = readEvent(t, out)
+	assert.Equal(t, "default/web/nginx", evt.Line.Src)
+
+	// deleted pods and pods that don't match are not read anymore
+	require.NoError(t, client.CoreV1().Pods("default").Delete(ctx, "web-2", metav1.DeleteOptions{}))
+
+	_, err = client.CoreV1().Pods("default").Create(ctx, newPod("default", "cache", map[string]string{"app": "cache"}, "redis"), metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	select {
+	case evt := <-out:
+		t.Fatalf("unexpected event from %s", evt.Line.Src)
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	for _, requests := range logRequests(client) {
+		for _, opts := range requests {
+			assert.True(t, opts.Follow)
+			assert.NotEqual(t, "postgres", opts.Container)
+			assert.NotEqual(t, "redis", opts.Container)
+		}
+	}
+
+	tmb.Kill(nil)
+	require.NoError(t, tmb.Wait())
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	assert.Empty(t, k.tails)
+}
@@ -0,0 +1,512 @@
+package natsacquisition
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/tomb.v2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/crowdsecurity/go-cs-lib/trace"
+
+	"github.com/crowdsecurity/crowdsec/pkg/acquisition/configuration"
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+const dataSourceName = "nats"
+
+var linesRead = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "cs_natssource_hits_total",
+		Help: "Total lines that were read from subject",
+	},
+	[]string{"subject"})
+
+type NatsConfiguration struct {
+	// URL of the server, or a comma separated list of servers
+	URL      string   `yaml:"url"`
+	Subjects []string `yaml:"subjects"`
+	// QueueGroup shares the messages between the subscribers of the same group (core nats only)
+	QueueGroup string           `yaml:"queue_group"`
+	JetStream  *JetStreamConfig `yaml:"jetstream"`
+	// SubjectLabels are added to the labels of the messages whose subject matches
+	SubjectLabels []SubjectLabels `yaml:"subject_labels"`
+	TLS           *TLSConfig      `yaml:"tls"`
+	Auth          *AuthConfig     `yaml:"auth"`
+	Timeout       time.Duration   `yaml:"timeout"`
+
+	configuration.DataSourceCommonCfg `yaml:",inline"`
+}
+
+type JetStreamConfig struct {
+	// Stream is found from the subjects if it's not set
+	Stream        string        `yaml:"stream"`
+	Durable       string        `yaml:"durable"`
+	DeliverPolicy string        `yaml:"deliver_policy"`
+	AckWait       time.Duration `yaml:"ack_wait"`
+	MaxAckPending int           `yaml:"max_ack_pending"`
+}
+
+type SubjectLabels struct {
+	Subject string            `yaml:"subject"`
+	Labels  map[string]string `yaml:"labels"`
+}
+
+type TLSConfig struct {
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+	ClientCert         string `yaml:"client_cert"`
+	ClientKey          string `yaml:"client_key"`
+	CaCert             string `yaml:"ca_cert"`
+}
+
+type AuthConfig struct {
+	CredsFile string `yaml:"creds_file"`
+	NkeyFile  string `yaml:"nkey_file"`
+	Username  string `yaml:"username"`
+	Password  string `yaml:"password"`
+	Token     string `yaml:"token"`
+}
+
+type NatsSource struct {
+	metricsLevel int
+	Config       NatsConfiguration
+	logger       *log.Entry
+	conn         *nats.Conn
+}
+
+var deliverPolicies = map[string]jetstream.DeliverPolicy{
+	"all":  jetstream.DeliverAllPolicy,
+	"last": jetstream.DeliverLastPolicy,
+	"new":  jetstream.DeliverNewPolicy,
+}
+
+func (n *NatsSource) GetUuid() string {
+	return n.Config.UniqueId
+}
+
+func (n *NatsSource) UnmarshalConfig(yamlConfig []byte) error {
+	n.Config = NatsConfiguration{}
+
+	err := yaml.Unmarshal(yamlConfig, &n.Config)
+	if err != nil {
+		return fmt.Errorf("cannot parse %s datasource configuration: %w", dataSourceName, err)
+	}
+
+	if n.Config.Mode == "" {
+		n.Config.Mode = configuration.TAIL_MODE
+	}
+
+	if n.Config.Timeout == 0 {
+		n.Config.Timeout = 10 * time.Second
+	}
+
+	if n.Config.JetStream != nil && n.Config.JetStream.DeliverPolicy == "" {
+		n.Config.JetStream.DeliverPolicy = "all"
+	}
+
+	return nil
+}
+
+func (nc *NatsConfiguration) Validate() error {
+	if nc.URL == "" {
+		return errors.New("url is required")
+	}
+
+	if len(nc.Subjects) == 0 {
+		return errors.New("at least one subject is required")
+	}
+
+	for _, subject := range nc.Subjects {
+		if !validSubject(subject) {
+			return fmt.Errorf("invalid subject '%s'", subject)
+		}
+	}
+
+	for _, sl := range nc.SubjectLabels {
+		if !validSubject(sl.Subject) {
+			return fmt.Errorf("invalid subject '%s' in subject_labels", sl.Subject)
+		}
+	}
+
+	if js := nc.JetStream; js != nil {
+		if nc.QueueGroup != "" {
+			return errors.New("queue_group can't be used with jetstream, use the same durable consumer instead")
+		}
+
+		if js.Durable == "" {
+			return errors.New("jetstream.durable is required")
+		}
+
+		if strings.ContainsAny(js.Durable, " \t.*>/\\") {
+			return fmt.Errorf("invalid durable name '%s'", js.Durable)
+		}
+
+		if _, ok := deliverPolicies[js.DeliverPolicy]; !ok {
+			return fmt.Errorf("unsupported deliver_policy %s, must be one of all, last or new", js.DeliverPolicy)
+		}
+
+		if js.AckWait < 0 || js.MaxAckPending < 0 {
+			return errors.New("ack_wait and max_ack_pending must be positive")
+		}
+	}
+
+	if nc.TLS != nil && (nc.TLS.ClientCert == "") != (nc.TLS.ClientKey == "") {
+		return errors.New("client_cert and client_key must be set together")
+	}
+
+	if nc.Mode != configuration.TAIL_MODE {
+		return fmt.Errorf("unsupported mode %s for %s datasource", nc.Mode, dataSourceName)
+	}
+
+	return nil
+}
+
+func (n *NatsSource) Configure(yamlConfig []byte, logger *log.Entry, metricsLevel int) error {
+	n.logger = logger
+	n.metricsLevel = metricsLevel
+
+	err := n.UnmarshalConfig(yamlConfig)
+	if err != nil {
+		return err
+	}
+
+	if err := n.Config.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return nil
+}
+
+func (n *NatsSource) ConfigureByDSN(string, map[string]string, *log.Entry, string) error {
+	return fmt.Errorf("%s datasource does not support command-line acquisition", dataSourceName)
+}
+
+func (n *NatsSource) GetMode() string {
+	return n.Config.Mode
+}
+
+func (n *NatsSource) GetName() string {
+	return dataSourceName
+}
+
+func (n *NatsSource) OneShotAcquisition(_ context.Context, _ chan types.Event, _ *tomb.Tomb) error {
+	return fmt.Errorf("%s datasource does not support one-shot acquisition", dataSourceName)
+}
+
+func (n *NatsSource) CanRun() error {
+	return nil
+}
+
+func (n *NatsSource) GetMetrics() []prometheus.Collector {
+	return []prometheus.Collector{linesRead}
+}
+
+func (n *NatsSource) GetAggregMetrics() []prometheus.Collector {
+	return []prometheus.Collector{linesRead}
+}
+
+func (n *NatsSource) Dump() interface{} {
+	return n
+}
+
+func (nc *NatsConfiguration) NewTLSConfig() (*tls.Config, error) {
+	tlsConfig := tls.Config{
+		InsecureSkipVerify: nc.TLS.InsecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	if nc.TLS.ClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(nc.TLS.ClientCert, nc.TLS.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client cert/key: %w", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if nc.TLS.CaCert != "" {
+		caCert, err := os.ReadFile(nc.TLS.CaCert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca cert: %w", err)
+		}
+
+		caCertPool, err := x509.SystemCertPool()
+		if err != nil {
+			return nil, fmt.Errorf("unable to load system CA certificates: %w", err)
+		}
+
+		if caCertPool == nil {
+			caCertPool = x509.NewCertPool()
+		}
+
+		caCertPool.AppendCertsFromPEM(caCert)
+		tlsConfig.RootCAs = caCertPool
+	}
+
+	return &tlsConfig, nil
+}
+
+// connectOptions returns the nats options for TLS and authentication
+func (nc *NatsConfiguration) connectOptions(logger *log.Entry) ([]nats.Option, error) {
+	opts := []nats.Option{
+		nats.Name("crowdsec"),
+		nats.Timeout(nc.Timeout),
+		// keep trying if the server is not up yet, and forever once connected
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				logger.Warningf("disconnected from nats: %s", err)
+			}
+		}),
+		nats.ReconnectHandler(func(conn *nats.Conn) {
+			logger.Infof("reconnected to nats server %s", conn.ConnectedUrl())
+		}),
+		nats.ErrorHandler(func(_ *nats.Conn, _ *nats.Subscription, err error) {
+			logger.Errorf("nats error: %s", err)
+		}),
+	}
+
+	if nc.TLS != nil {
+		tlsConfig, err := nc.NewTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, nats.Secure(tlsConfig))
+	}
+
+	if auth := nc.Auth; auth != nil {
+		if auth.CredsFile != "" {
+			opts = append(opts, nats.UserCredentials(auth.CredsFile))
+		}
+
+		if auth.NkeyFile != "" {
+			opt, err := nats.NkeyOptionFromSeed(auth.NkeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load nkey: %w", err)
+			}
+
+			opts = append(opts, opt)
+		}
+
+		if auth.Username != "" {
+			opts = append(opts, nats.UserInfo(auth.Username, auth.Password))
+		}
+
+		if auth.Token != "" {
+			opts = append(opts, nats.Token(auth.Token))
+		}
+	}
+
+	return opts, nil
+}
+
+// labels returns the labels of a message: the labels of the datasource, with those of the
+// matching subject_labels on top, in the order of the configuration
+func (n *NatsSource) labels(subject string) map[string]string {
+	var labels map[string]string
+
+	for _, sl := range n.Config.SubjectLabels {
+		if !subjectMatches(sl.Subject, subject) {
+			continue
+		}
+
+		if labels == nil {
+			labels = maps.Clone(n.Config.Labels)
+			if labels == nil {
+				labels = make(map[string]string)
+			}
+		}
+
+		maps.Copy(labels, sl.Labels)
+	}
+
+	if labels == nil {
+		return n.Config.Labels
+	}
+
+	return labels
+}
+
+// sendMessage sends a message to the parsers, and returns false if the datasource is stopping
+func (n *NatsSource) sendMessage(pattern string, subject string, data []byte, ts time.Time, ack func(), out chan types.Event, t *tomb.Tomb) bool {
+	n.logger.Tracef("got message on '%s': %s", subject, string(data))
+
+	if n.metricsLevel != configuration.METRICS_NONE {
+		metricSubject := subject
+		if n.metricsLevel == configuration.METRICS_AGGREGATE {
+			// wildcards can match a lot of subjects
+			metricSubject = pattern
+		}
+
+		linesRead.With(prometheus.Labels{"subject": metricSubject}).Inc()
+	}
+
+	evt := types.MakeEvent(n.Config.UseTimeMachine, types.LOG, true)
+	evt.Line = types.Line{
+		Raw:     string(data),
+		Labels:  n.labels(subject),
+		Time:    ts,
+		Src:     subject,
+		Process: true,
+		Module:  n.GetName(),
+		Ack:     ack,
+	}
+
+	select {
+	case out <- evt:
+		return true
+	case <-t.Dying():
+		return false
+	}
+}
+
+// subscribe reads the subjects with core nats: messages published while crowdsec is not
+// connected are lost
+func (n *NatsSource) subscribe(out chan types.Event, t *tomb.Tomb) error {
+	for _, pattern := range n.Config.Subjects {
+		handler := func(msg *nats.Msg) {
+			n.sendMessage(pattern, msg.Subject, msg.Data, time.Now().UTC(), nil, out, t)
+		}
+
+		var (
+			sub *nats.Subscription
+			err error
+		)
+
+		if n.Config.QueueGroup != "" {
+			sub, err = n.conn.QueueSubscribe(pattern, n.Config.QueueGroup, handler)
+		} else {
+			sub, err = n.conn.Subscribe(pattern, handler)
+		}
+
+		if err != nil {
+			return fmt.Errorf("while subscribing to '%s': %w", pattern, err)
+		}
+
+		n.logger.Infof("subscribed to '%s'", sub.Subject)
+	}
+
+	return nil
+}
+
+// consume reads the subjects with a durable jetstream consumer. The messages are acknowledged
+// once parsed, those that were not are delivered again after ack_wait.
+func (n *NatsSource) consume(ctx context.Context, out chan types.Event, t *tomb.Tomb) (jetstream.ConsumeContext, error) {
+	jsConfig := n.Config.JetStream
+
+	js, err := jetstream.New(n.conn)
+	if err != nil {
+		return nil, fmt.Errorf("while creating jetstream context: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, n.Config.Timeout)
+	defer cancel()
+
+	stream := jsConfig.Stream
+	if stream == "" {
+		stream, err = js.StreamNameBySubject(ctx, n.Config.Subjects[0])
+		if err != nil {
+			return nil, fmt.Errorf("while looking for the stream of subject '%s': %w", n.Config.Subjects[0], err)
+		}
+	}
+
+	consumer, err := js.CreateOrUpdateConsumer(ctx, stream, jetstream.ConsumerConfig{
+		Durable:        jsConfig.Durable,
+		FilterSubjects: n.Config.Subjects,
+		DeliverPolicy:  deliverPolicies[jsConfig.DeliverPolicy],
+		AckPolicy:      jetstream.AckExplicitPolicy,
+		AckWait:        jsConfig.AckWait,
+		MaxAckPending:  jsConfig.MaxAckPending,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("while creating consumer '%s' on stream '%s': %w", jsConfig.Durable, stream, err)
+	}
+
+	cc, err := consumer.Consume(func(msg jetstream.Msg) {
+		ts := time.Now().UTC()
+		if meta, err := msg.Metadata(); err == nil {
+			ts = meta.Timestamp.UTC()
+		}
+
+		pattern := msg.Subject()
+
+		for _, p := range n.Config.Subjects {
+			if subjectMatches(p, msg.Subject()) {
+				pattern = p
+				break
+			}
+		}
+
+		ack := func() {
+			if err := msg.Ack(); err != nil {
+				n.logger.Errorf("while acknowledging message on '%s': %s", msg.Subject(), err)
+			}
+		}
+
+		n.sendMessage(pattern, msg.Subject(), msg.Data(), ts, ack, out, t)
+	}, jetstream.ConsumeErrHandler(func(_ jetstream.ConsumeContext, err error) {
+		n.logger.Errorf("jetstream consumer error: %s", err)
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("while consuming from stream '%s': %w", stream, err)
+	}
+
+	n.logger.Infof("consuming stream '%s' with durable consumer '%s'", stream, jsConfig.Durable)
+
+	return cc, nil
+}
+
+func (n *NatsSource) RunReader(ctx context.Context, out chan types.Event, t *tomb.Tomb) error {
+	opts, err := n.Config.connectOptions(n.logger)
+	if err != nil {
+		return err
+	}
+
+	n.conn, err = nats.Connect(n.Config.URL, opts...)
+	if err != nil {
+		return fmt.Errorf("while connecting to %s: %w", n.Config.URL, err)
+	}
+
+	defer n.conn.Close()
+
+	if n.Config.JetStream != nil {
+		cc, err := n.consume(ctx, out, t)
+		if err != nil {
+			return err
+		}
+
+		defer cc.Stop()
+	} else if err := n.subscribe(out, t); err != nil {
+		return err
+	}
+
+	<-t.Dying()
+
+	n.logger.Infof("%s datasource stopping", dataSourceName)
+
+	return nil
+}
+
+func (n *NatsSource) StreamingAcquisition(ctx context.Context, out chan types.Event, t *tomb.Tomb) error {
+	n.logger.Infof("start reader on '%s' with subjects %v", n.Config.URL, n.Config.Subjects)
+
+	t.Go(func() error {
+		defer trace.CatchPanic("crowdsec/acquis/nats/live")
+		return n.RunReader(ctx, out, t)
+	})
+
+	return nil
+}
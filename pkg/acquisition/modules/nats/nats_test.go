@@ -0,0 +1,591 @@
+package natsacquisition
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/tomb.v2"
+
+	"github.com/crowdsecurity/go-cs-lib/cstest"
+
+	"github.com/crowdsecurity/crowdsec/pkg/acquisition/configuration"
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+func TestConfigure(t *testing.T) {
+	tests := []struct {
+		config      string
+		expectedErr string
+	}{
+		{
+			config:      `foobar: bla`,
+			expectedErr: "invalid configuration: url is required",
+		},
+		{
+			config:      `url: nats://127.0.0.1:4222`,
+			expectedErr: "invalid configuration: at least one subject is required",
+		},
+		{
+			config: `
+url: nats://127.0.0.1:4222
+subjects: ["logs.>.nginx"]`,
+			expectedErr: "invalid configuration: invalid subject 'logs.>.nginx'",
+		},
+		{
+			config: `
+url: nats://127.0.0.1:4222
+subjects: ["logs.>"]
+subject_labels:
+  - subject: "logs..nginx"
+    labels:
+      type: nginx`,
+			expectedErr: "invalid configuration: invalid subject 'logs..nginx' in subject_labels",
+		},
+		{
+			config: `
+url: nats://127.0.0.1:4222
+subjects: ["logs.>"]
+jetstream:
+  stream: LOGS`,
+			expectedErr: "invalid configuration: jetstream.durable is required",
+		},
+		{
+			config: `
+url: nats://127.0.0.1:4222
+subjects: ["logs.>"]
+jetstream:
+  durable: crowdsec.1`,
+			expectedErr: "invalid configuration: invalid durable name 'crowdsec.1'",
+		},
+		{
+			config: `
+url: nats://127.0.0.1:4222
+subjects: ["logs.>"]
+jetstream:
+  durable: crowdsec
+  deliver_policy: first`,
+			expectedErr: "invalid configuration: unsupported deliver_policy first, must be one of all, last or new",
+		},
+		{
+			config: `
+url: nats://127.0.0.1:4222
+subjects: ["logs.>"]
+queue_group: crowdsec
+jetstream:
+  durable: crowdsec`,
+			expectedErr: "invalid configuration: queue_group can't be used with jetstream",
+		},
+		{
+			config: `
+url: nats://127.0.0.1:4222
+subjects: ["logs.>"]
+tls:
+  client_cert: cert.pem`,
+			expectedErr: "invalid configuration: client_cert and client_key must be set together",
+		},
+		{
+			config: `
+url: nats://127.0.0.1:4222
+subjects: ["logs.>"]
+mode: cat`,
+			expectedErr: "invalid configuration: unsupported mode cat for nats datasource",
+		},
+		{
+			config: `
+url: nats://127.0.0.1:4222
+subjects: ["logs.>", "access.*"]
+jetstream:
+  durable: crowdsec
+  ack_wait: 30s`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.expectedErr, func(t *testing.T) {
+			n := NatsSource{}
+			err := n.Configure([]byte(tc.config), log.WithField("type", "nats"), configuration.METRICS_NONE)
+			cstest.RequireErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestSubjectMatches(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		subject  string
+		expected bool
+	}{
+		{"logs.nginx", "logs.nginx", true},
+		{"logs.nginx", "logs.apache", false},
+		{"logs.nginx", "logs.nginx.access", false},
+		{"logs.*", "logs.nginx", true},
+		{"logs.*", "logs.nginx.access", false},
+		{"logs.*.access", "logs.nginx.access", true},
+		{"logs.>", "logs.nginx", true},
+		{"logs.>", "logs.nginx.access", true},
+		{"logs.>", "logs", false},
+		{">", "logs", true},
+		{"*.access", "logs.nginx.access", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.pattern+" "+tc.subject, func(t *testing.T) {
+			assert.Equal(t, tc.expected, subjectMatches(tc.pattern, tc.subject))
+		})
+	}
+}
+
+// runServer starts an embedded nats server with jetstream
+func runServer(t *testing.T, opts *server.Options) *server.Server {
+	t.Helper()
+
+	if opts == nil {
+		opts = &server.Options{}
+	}
+
+	opts.Host = "127.0.0.1"
+	opts.Port = -1
+	opts.JetStream = true
+	opts.StoreDir = t.TempDir()
+	opts.NoLog = true
+	opts.NoSigs = true
+
+	s, err := server.NewServer(opts)
+	require.NoError(t, err)
+
+	go s.Start()
+
+	if !s.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server did not start")
+	}
+
+	t.Cleanup(s.Shutdown)
+
+	return s
+}
+
+func runSource(t *testing.T, config string, metricsLevel int) (chan types.Event, *prometheus.Registry, *tomb.Tomb) {
+	t.Helper()
+
+	linesRead.Reset()
+
+	n := &NatsSource{}
+	require.NoError(t, n.Configure([]byte(config), log.WithField("type", "nats"), metricsLevel))
+
+	tmb := &tomb.Tomb{}
+	out := make(chan types.Event)
+	require.NoError(t, n.StreamingAcquisition(t.Context(), out, tmb))
+
+	registry := prometheus.NewPedanticRegistry()
+	for _, metric := range n.GetMetrics() {
+		require.NoError(t, registry.Register(metric))
+	}
+
+	return out, registry, tmb
+}
+
+func stopSource(t *testing.T, tmb *tomb.Tomb) {
+	t.Helper()
+
+	tmb.Kill(nil)
+	require.NoError(t, tmb.Wait())
+}
+
+func receiveEvents(t *testing.T, out chan types.Event, count int) []types.Event {
+	t.Helper()
+
+	events := []types.Event{}
+
+	for range count {
+		select {
+		case evt := <-out:
+			events = append(events, evt)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected %d events, got %d", count, len(events))
+		}
+	}
+
+	return events
+}
+
+func assertNoEvent(t *testing.T, out chan types.Event) {
+	t.Helper()
+
+	select {
+	case evt := <-out:
+		t.Fatalf("unexpected event: %s", evt.Line.Raw)
+	case <-time.After(500 * time.Millisecond):
+	}
+}
+
+func assertMetrics(t *testing.T, reg *prometheus.Registry, expected map[string]int) {
+	t.Helper()
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+
+	actual := map[string]int{}
+
+	for _, family := range families {
+		if family.GetName() != "cs_natssource_hits_total" {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			actual[metric.GetLabel()[0].GetValue()] = int(metric.GetCounter().GetValue())
+		}
+	}
+
+	assert.Equal(t, expected, actual)
+}
+
+// waitForSubscriptions waits until the datasource subscribed to the subjects,
+// core nats messages published before that are lost
+func waitForSubscriptions(t *testing.T, s *server.Server, count int) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		connz, err := s.Connz(nil)
+		if err != nil {
+			return false
+		}
+
+		subs := 0
+
+		for _, conn := range connz.Conns {
+			if conn.Name == "crowdsec" {
+				subs += int(conn.NumSubs)
+			}
+		}
+
+		return subs >= count
+	}, 5*time.Second, 20*time.Millisecond)
+}
+
+func TestCoreNats(t *testing.T) {
+	s := runServer(t, nil)
+
+	out, reg, tmb := runSource(t, fmt.Sprintf(`
+url: %s
+subjects: ["logs.>", "access.*"]
+labels:
+  type: syslog
+subject_labels:
+  - subject: "logs.nginx.>"
+    labels:
+      type: nginx
+      program: nginx
+  - subject: "logs.*.error"
+    labels:
+      program: nginx-error`, s.ClientURL()), configuration.METRICS_FULL)
+	defer stopSource(t, tmb)
+
+	waitForSubscriptions(t, s, 2)
+
+	nc, err := nats.Connect(s.ClientURL())
+	require.NoError(t, err)
+
+	defer nc.Close()
+
+	require.NoError(t, nc.Publish("logs.nginx.access", []byte("access line")))
+	require.NoError(t, nc.Publish("logs.nginx.error", []byte("error line")))
+	require.NoError(t, nc.Publish("access.apache", []byte("apache line")))
+	require.NoError(t, nc.Publish("other", []byte("not subscribed")))
+	require.NoError(t, nc.Flush())
+
+	// the subscriptions are served concurrently, index the lines by subject
+	bySubject := map[string]types.Line{}
+
+	for _, evt := range receiveEvents(t, out, 3) {
+		bySubject[evt.Line.Src] = evt.Line
+	}
+
+	assertNoEvent(t, out)
+
+	line := bySubject["logs.nginx.access"]
+	assert.Equal(t, "access line", line.Raw)
+	assert.Equal(t, "nats", line.Module)
+	assert.Equal(t, map[string]string{"type": "nginx", "program": "nginx"}, line.Labels)
+	assert.Nil(t, line.Ack)
+
+	// the matching subject_labels are applied in order
+	assert.Equal(t, map[string]string{"type": "nginx", "program": "nginx-error"}, bySubject["logs.nginx.error"].Labels)
+
+	line = bySubject["access.apache"]
+	assert.Equal(t, "apache line", line.Raw)
+	assert.Equal(t, map[string]string{"type": "syslog"}, line.Labels)
+
+	assertMetrics(t, reg, map[string]int{"logs.nginx.access": 1, "logs.nginx.error": 1, "access.apache": 1})
+}
+
+func TestQueueGroup(t *testing.T) {
+	s := runServer(t, nil)
+
+	config := fmt.Sprintf(`
+url: %s
+subjects: ["logs.>"]
+queue_group: crowdsec
+labels:
+  type: syslog`, s.ClientURL())
+
+	out1, reg, tmb1 := runSource(t, config, configuration.METRICS_AGGREGATE)
+	defer stopSource(t, tmb1)
+
+	out2, _, tmb2 := runSource(t, config, configuration.METRICS_AGGREGATE)
+	defer stopSource(t, tmb2)
+
+	waitForSubscriptions(t, s, 2)
+
+	nc, err := nats.Connect(s.ClientURL())
+	require.NoError(t, err)
+
+	defer nc.Close()
+
+	for i := range 10 {
+		require.NoError(t, nc.Publish(fmt.Sprintf("logs.app%d", i), []byte("line")))
+	}
+
+	require.NoError(t, nc.Flush())
+
+	// each message is delivered to only one member of the group
+	count := 0
+
+	for {
+		select {
+		case <-out1:
+			count++
+			continue
+		case <-out2:
+			count++
+			continue
+		case <-time.After(500 * time.Millisecond):
+		}
+
+		break
+	}
+
+	assert.Equal(t, 10, count)
+
+	// aggregated metrics use the subscription instead of the subject
+	assertMetrics(t, reg, map[string]int{"logs.>": 10})
+}
+
+func TestJetStream(t *testing.T) {
+	s := runServer(t, nil)
+
+	nc, err := nats.Connect(s.ClientURL())
+	require.NoError(t, err)
+
+	defer nc.Close()
+
+	js, err := jetstream.New(nc)
+	require.NoError(t, err)
+
+	_, err = js.CreateStream(t.Context(), jetstream.StreamConfig{
+		Name:     "LOGS",
+		Subjects: []string{"logs.>"},
+	})
+	require.NoError(t, err)
+
+	// published before crowdsec starts: delivered by the durable consumer
+	_, err = js.Publish(t.Context(), "logs.nginx", []byte("line 1"))
+	require.NoError(t, err)
+	_, err = js.Publish(t.Context(), "logs.nginx", []byte("line 2"))
+	require.NoError(t, err)
+
+	config := fmt.Sprintf(`
+url: %s
+subjects: ["logs.>"]
+labels:
+  type: nginx
+jetstream:
+  durable: crowdsec
+  ack_wait: 1s`, s.ClientURL())
+
+	out, _, tmb := runSource(t, config, configuration.METRICS_NONE)
+
+	events := receiveEvents(t, out, 2)
+	assert.Equal(t, "line 1", events[0].Line.Raw)
+	assert.Equal(t, "line 2", events[1].Line.Raw)
+	assert.Equal(t, "logs.nginx", events[0].Line.Src)
+	require.NotNil(t, events[0].Line.Ack)
+
+	// only the first line is parsed
+	events[0].Line.Ack()
+
+	stopSource(t, tmb)
+
+	_, err = js.Publish(t.Context(), "logs.nginx", []byte("line 3"))
+	require.NoError(t, err)
+
+	// the durable consumer resumes: the line that was not acknowledged is delivered again
+	out, _, tmb = runSource(t, config, configuration.METRICS_NONE)
+	defer stopSource(t, tmb)
+
+	events = receiveEvents(t, out, 2)
+
+	raw := []string{events[0].Line.Raw, events[1].Line.Raw}
+	assert.ElementsMatch(t, []string{"line 2", "line 3"}, raw)
+
+	for _, evt := range events {
+		evt.Line.Ack()
+	}
+
+	assertNoEvent(t, out)
+
+	consumer, err := js.Consumer(t.Context(), "LOGS", "crowdsec")
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		info, err := consumer.Info(t.Context())
+		return err == nil && info.NumAckPending == 0 && info.NumPending == 0
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestJetStreamNoStream(t *testing.T) {
+	s := runServer(t, nil)
+
+	n := &NatsSource{}
+	require.NoError(t, n.Configure([]byte(fmt.Sprintf(`
+url: %s
+subjects: ["logs.>"]
+jetstream:
+  durable: crowdsec`, s.ClientURL())), log.WithField("type", "nats"), configuration.METRICS_NONE))
+
+	tmb := &tomb.Tomb{}
+	require.NoError(t, n.StreamingAcquisition(t.Context(), make(chan types.Event), tmb))
+
+	err := tmb.Wait()
+	cstest.RequireErrorContains(t, err, "while looking for the stream of subject 'logs.>'")
+}
+
+func TestAuth(t *testing.T) {
+	s := runServer(t, &server.Options{Authorization: "secret"})
+
+	for _, tc := range []struct {
+		token    string
+		expected bool
+	}{
+		{"wrong", false},
+		{"secret", true},
+	} {
+		t.Run(tc.token, func(t *testing.T) {
+			out, _, tmb := runSource(t, fmt.Sprintf(`
+url: %s
+subjects: ["logs.>"]
+auth:
+  token: %s`, s.ClientURL(), tc.token), configuration.METRICS_NONE)
+			defer stopSource(t, tmb)
+
+			nc, err := nats.Connect(s.ClientURL(), nats.Token("secret"))
+			require.NoError(t, err)
+
+			defer nc.Close()
+
+			if !tc.expected {
+				// give the datasource the time to fail
+				time.Sleep(500 * time.Millisecond)
+				require.NoError(t, nc.Publish("logs.app", []byte("line")))
+				assertNoEvent(t, out)
+
+				return
+			}
+
+			waitForSubscriptions(t, s, 1)
+			require.NoError(t, nc.Publish("logs.app", []byte("line")))
+			receiveEvents(t, out, 1)
+		})
+	}
+}
+
+// newTestCert creates a certificate for 127.0.0.1 signed by parent, or a CA if parent is nil
+func newTestCert(t *testing.T, dir string, name string, parent *tls.Certificate) (tls.Certificate, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	signer, signerKey := template, any(key)
+
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		signer, signerKey = parent.Leaf, parent.PrivateKey
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+
+	leaf, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, name+".pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, certFile
+}
+
+func TestTLS(t *testing.T) {
+	dir := t.TempDir()
+
+	ca, caFile := newTestCert(t, dir, "ca", nil)
+	serverCert, _ := newTestCert(t, dir, "server", &ca)
+
+	s := runServer(t, &server.Options{
+		TLS:       true,
+		TLSConfig: &tls.Config{Certificates: []tls.Certificate{serverCert}, MinVersion: tls.VersionTLS12},
+	})
+
+	out, _, tmb := runSource(t, fmt.Sprintf(`
+url: %s
+subjects: ["logs.>"]
+tls:
+  ca_cert: %s`, s.ClientURL(), caFile), configuration.METRICS_NONE)
+	defer stopSource(t, tmb)
+
+	waitForSubscriptions(t, s, 1)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Leaf)
+
+	nc, err := nats.Connect(s.ClientURL(), nats.Secure(&tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}))
+	require.NoError(t, err)
+
+	defer nc.Close()
+
+	require.NoError(t, nc.Publish("logs.app", []byte("tls line")))
+
+	events := receiveEvents(t, out, 1)
+	assert.Equal(t, "tls line", events[0].Line.Raw)
+}
@@ -0,0 +1,49 @@
+package natsacquisition
+
+import "strings"
+
+// validSubject checks a subject or a subscription pattern: non-empty tokens separated
+// by dots, '*' as a whole token and '>' only as the last token
+func validSubject(subject string) bool {
+	if subject == "" || strings.ContainsAny(subject, " \t\r\n") {
+		return false
+	}
+
+	tokens := strings.Split(subject, ".")
+
+	for i, token := range tokens {
+		switch {
+		case token == "":
+			return false
+		case token == ">" && i != len(tokens)-1:
+			return false
+		case token != "*" && token != ">" && strings.ContainsAny(token, "*>"):
+			return false
+		}
+	}
+
+	return true
+}
+
+// subjectMatches returns true if the subject matches the pattern, which can contain the
+// nats wildcards: '*' matches one token, '>' matches one or more tokens at the end
+func subjectMatches(pattern string, subject string) bool {
+	patternTokens := strings.Split(pattern, ".")
+	subjectTokens := strings.Split(subject, ".")
+
+	for i, token := range patternTokens {
+		if token == ">" {
+			return len(subjectTokens) > i
+		}
+
+		if i >= len(subjectTokens) {
+			return false
+		}
+
+		if token != "*" && token != subjectTokens[i] {
+			return false
+		}
+	}
+
+	return len(patternTokens) == len(subjectTokens)
+}
@@ -0,0 +1,163 @@
+package otlpacquisition
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"maps"
+	"strconv"
+	"time"
+
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+// anyValue converts an OTLP value to its go equivalent
+func anyValue(v *commonpb.AnyValue) any {
+	switch val := v.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return val.StringValue
+	case *commonpb.AnyValue_BoolValue:
+		return val.BoolValue
+	case *commonpb.AnyValue_IntValue:
+		return val.IntValue
+	case *commonpb.AnyValue_DoubleValue:
+		return val.DoubleValue
+	case *commonpb.AnyValue_BytesValue:
+		return val.BytesValue
+	case *commonpb.AnyValue_ArrayValue:
+		ret := make([]any, 0, len(val.ArrayValue.GetValues()))
+		for _, item := range val.ArrayValue.GetValues() {
+			ret = append(ret, anyValue(item))
+		}
+
+		return ret
+	case *commonpb.AnyValue_KvlistValue:
+		return attributes(val.KvlistValue.GetValues())
+	}
+
+	return nil
+}
+
+// stringValue converts an OTLP value to a string: strings and scalars as is, the rest in JSON
+func stringValue(v *commonpb.AnyValue) string {
+	switch val := v.GetValue().(type) {
+	case nil:
+		return ""
+	case *commonpb.AnyValue_StringValue:
+		return val.StringValue
+	case *commonpb.AnyValue_BoolValue:
+		return strconv.FormatBool(val.BoolValue)
+	case *commonpb.AnyValue_IntValue:
+		return strconv.FormatInt(val.IntValue, 10)
+	case *commonpb.AnyValue_DoubleValue:
+		return strconv.FormatFloat(val.DoubleValue, 'f', -1, 64)
+	}
+
+	b, err := json.Marshal(anyValue(v))
+	if err != nil {
+		return ""
+	}
+
+	return string(b)
+}
+
+func attributes(kvs []*commonpb.KeyValue) map[string]any {
+	ret := make(map[string]any, len(kvs))
+
+	for _, kv := range kvs {
+		ret[kv.GetKey()] = anyValue(kv.GetValue())
+	}
+
+	return ret
+}
+
+// findAttribute looks for an attribute in the log record first, then in the resource
+func findAttribute(key string, lists ...[]*commonpb.KeyValue) (*commonpb.AnyValue, bool) {
+	for _, kvs := range lists {
+		for _, kv := range kvs {
+			if kv.GetKey() == key {
+				return kv.GetValue(), true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+func recordTime(record *logspb.LogRecord) time.Time {
+	ts := record.GetTimeUnixNano()
+	if ts == 0 {
+		ts = record.GetObservedTimeUnixNano()
+	}
+
+	if ts == 0 {
+		return time.Now().UTC()
+	}
+
+	return time.Unix(0, int64(ts)).UTC()
+}
+
+// makeEvents converts the log records of an export request to events. The body of a record is
+// the raw line, its attributes and those of the resource and scope are in Unmarshaled["otlp"].
+func (o *OtlpSource) makeEvents(req *collogspb.ExportLogsServiceRequest, src string) []types.Event {
+	events := []types.Event{}
+
+	for _, resourceLogs := range req.GetResourceLogs() {
+		resourceAttrs := resourceLogs.GetResource().GetAttributes()
+		resource := attributes(resourceAttrs)
+
+		for _, scopeLogs := range resourceLogs.GetScopeLogs() {
+			scope := map[string]any{
+				"name":       scopeLogs.GetScope().GetName(),
+				"version":    scopeLogs.GetScope().GetVersion(),
+				"attributes": attributes(scopeLogs.GetScope().GetAttributes()),
+			}
+
+			for _, record := range scopeLogs.GetLogRecords() {
+				labels := o.Config.Labels
+
+				if len(o.Config.LabelAttributes) > 0 {
+					labels = maps.Clone(o.Config.Labels)
+					if labels == nil {
+						labels = make(map[string]string)
+					}
+
+					for _, key := range o.Config.LabelAttributes {
+						if v, ok := findAttribute(key, record.GetAttributes(), resourceAttrs); ok {
+							labels[key] = stringValue(v)
+						}
+					}
+				}
+
+				ts := recordTime(record)
+
+				evt := types.MakeEvent(o.Config.UseTimeMachine, types.LOG, true)
+				evt.Line = types.Line{
+					Raw:     stringValue(record.GetBody()),
+					Labels:  labels,
+					Time:    ts,
+					Src:     src,
+					Process: true,
+					Module:  o.GetName(),
+				}
+				evt.Unmarshaled["otlp"] = map[string]any{
+					"resource":        resource,
+					"scope":           scope,
+					"attributes":      attributes(record.GetAttributes()),
+					"severity_number": int32(record.GetSeverityNumber()),
+					"severity_text":   record.GetSeverityText(),
+					"timestamp":       ts.Format(time.RFC3339Nano),
+					"trace_id":        hex.EncodeToString(record.GetTraceId()),
+					"span_id":         hex.EncodeToString(record.GetSpanId()),
+				}
+
+				events = append(events, evt)
+			}
+		}
+	}
+
+	return events
+}
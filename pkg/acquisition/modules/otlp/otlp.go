@@ -0,0 +1,515 @@
+package otlpacquisition
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	_ "google.golang.org/grpc/encoding/gzip" // register the gzip decompressor
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/tomb.v2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/crowdsecurity/go-cs-lib/ptr"
+	"github.com/crowdsecurity/go-cs-lib/trace"
+
+	"github.com/crowdsecurity/crowdsec/pkg/acquisition/configuration"
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+var dataSourceName = "otlp"
+
+const (
+	protocolHTTP = "http"
+	protocolGRPC = "grpc"
+
+	contentTypeProtobuf = "application/x-protobuf"
+	contentTypeJSON     = "application/json"
+
+	// the largest request accepted by default, once decompressed
+	defaultMaxBodySize int64 = 10 * 1024 * 1024
+	// how long an exporter has to send the headers of a request, and the whole request by default
+	readHeaderTimeout = 10 * time.Second
+	defaultTimeout    = 30 * time.Second
+)
+
+var linesRead = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "cs_otlpsource_hits_total",
+		Help: "Total log records that were received by the OTLP source",
+	},
+	[]string{"protocol", "src"})
+
+type OtlpConfiguration struct {
+	HTTPListenAddr string `yaml:"http_listen_addr"`
+	GRPCListenAddr string `yaml:"grpc_listen_addr"`
+	// Path of the OTLP/HTTP endpoint
+	Path string `yaml:"path"`
+	// Headers that the exporters must send, as HTTP headers or gRPC metadata
+	Headers     map[string]string `yaml:"headers"`
+	TLS         *TLSConfig        `yaml:"tls"`
+	MaxBodySize *int64            `yaml:"max_body_size"`
+	// Timeout is the time an exporter has to send an OTLP/HTTP request
+	Timeout *time.Duration `yaml:"timeout"`
+	// LabelAttributes are the log or resource attributes copied to the labels of the events
+	LabelAttributes                   []string `yaml:"label_attributes"`
+	configuration.DataSourceCommonCfg `yaml:",inline"`
+}
+
+type TLSConfig struct {
+	ServerCert string `yaml:"server_cert"`
+	ServerKey  string `yaml:"server_key"`
+	// CaCert enables mTLS: the exporters must present a certificate signed by this CA
+	CaCert string `yaml:"ca_cert"`
+}
+
+type OtlpSource struct {
+	collogspb.UnimplementedLogsServiceServer
+
+	metricsLevel int
+	Config       OtlpConfiguration
+	logger       *log.Entry
+	out          chan types.Event
+	HTTPServer   *http.Server
+	GRPCServer   *grpc.Server
+}
+
+func (o *OtlpSource) GetUuid() string {
+	return o.Config.UniqueId
+}
+
+func (o *OtlpSource) UnmarshalConfig(yamlConfig []byte) error {
+	o.Config = OtlpConfiguration{}
+
+	err := yaml.Unmarshal(yamlConfig, &o.Config)
+	if err != nil {
+		return fmt.Errorf("cannot parse %s datasource configuration: %w", dataSourceName, err)
+	}
+
+	if o.Config.Mode == "" {
+		o.Config.Mode = configuration.TAIL_MODE
+	}
+
+	return nil
+}
+
+func (oc *OtlpConfiguration) Validate() error {
+	if oc.HTTPListenAddr == "" && oc.GRPCListenAddr == "" {
+		return errors.New("http_listen_addr or grpc_listen_addr is required")
+	}
+
+	if oc.Path == "" {
+		oc.Path = "/v1/logs"
+	}
+
+	if oc.Path[0] != '/' {
+		return errors.New("path must start with /")
+	}
+
+	if oc.TLS != nil {
+		if oc.TLS.ServerCert == "" {
+			return errors.New("server_cert is required")
+		}
+
+		if oc.TLS.ServerKey == "" {
+			return errors.New("server_key is required")
+		}
+	}
+
+	if oc.MaxBodySize == nil {
+		oc.MaxBodySize = ptr.Of(defaultMaxBodySize)
+	}
+
+	if *oc.MaxBodySize <= 0 {
+		return errors.New("max_body_size must be positive")
+	}
+
+	if oc.Timeout == nil {
+		oc.Timeout = ptr.Of(defaultTimeout)
+	}
+
+	if *oc.Timeout <= 0 {
+		return errors.New("timeout must be positive")
+	}
+
+	if oc.Mode != configuration.TAIL_MODE {
+		return fmt.Errorf("unsupported mode %s for %s datasource", oc.Mode, dataSourceName)
+	}
+
+	return nil
+}
+
+func (o *OtlpSource) Configure(yamlConfig []byte, logger *log.Entry, metricsLevel int) error {
+	o.logger = logger
+	o.metricsLevel = metricsLevel
+
+	err := o.UnmarshalConfig(yamlConfig)
+	if err != nil {
+		return err
+	}
+
+	if err := o.Config.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return nil
+}
+
+func (o *OtlpSource) ConfigureByDSN(string, map[string]string, *log.Entry, string) error {
+	return fmt.Errorf("%s datasource does not support command-line acquisition", dataSourceName)
+}
+
+func (o *OtlpSource) GetMode() string {
+	return o.Config.Mode
+}
+
+func (o *OtlpSource) GetName() string {
+	return dataSourceName
+}
+
+func (o *OtlpSource) OneShotAcquisition(ctx context.Context, out chan types.Event, t *tomb.Tomb) error {
+	return fmt.Errorf("%s datasource does not support one-shot acquisition", dataSourceName)
+}
+
+func (o *OtlpSource) CanRun() error {
+	return nil
+}
+
+func (o *OtlpSource) GetMetrics() []prometheus.Collector {
+	return []prometheus.Collector{linesRead}
+}
+
+func (o *OtlpSource) GetAggregMetrics() []prometheus.Collector {
+	return []prometheus.Collector{linesRead}
+}
+
+func (o *OtlpSource) Dump() interface{} {
+	return o
+}
+
+func (oc *OtlpConfiguration) NewTLSConfig() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(oc.TLS.ServerCert, oc.TLS.ServerKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server cert/key: %w", err)
+	}
+
+	tlsConfig := tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if oc.TLS.CaCert != "" {
+		caCert, err := os.ReadFile(oc.TLS.CaCert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca cert: %w", err)
+		}
+
+		caCertPool := x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no certificate found in %s", oc.TLS.CaCert)
+		}
+
+		tlsConfig.ClientCAs = caCertPool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return &tlsConfig, nil
+}
+
+// authorize checks the headers (or gRPC metadata) sent by the exporter. The values
+// are compared in constant time, as they usually hold a token.
+func (oc *OtlpConfiguration) authorize(get func(string) string) error {
+	for key, value := range oc.Headers {
+		if subtle.ConstantTimeCompare([]byte(get(key)), []byte(value)) != 1 {
+			return errors.New("invalid headers")
+		}
+	}
+
+	return nil
+}
+
+// sendRecords converts the log records of an export request to events
+func (o *OtlpSource) sendRecords(ctx context.Context, req *collogspb.ExportLogsServiceRequest, protocol string, srcHost string) error {
+	src := srcHost
+	lineSrc := srcHost
+
+	if o.metricsLevel == configuration.METRICS_AGGREGATE {
+		src = ""
+
+		lineSrc = o.Config.GRPCListenAddr
+		if protocol == protocolHTTP {
+			lineSrc = o.Config.Path
+		}
+	}
+
+	for _, evt := range o.makeEvents(req, lineSrc) {
+		if o.metricsLevel != configuration.METRICS_NONE {
+			linesRead.With(prometheus.Labels{"protocol": protocol, "src": src}).Inc()
+		}
+
+		o.logger.Tracef("line to send: %+v", evt.Line)
+
+		select {
+		case o.out <- evt:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// Export implements the OTLP/gRPC logs service
+func (o *OtlpSource) Export(ctx context.Context, req *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if err := o.Config.authorize(func(key string) string {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+
+		return ""
+	}); err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	srcHost := ""
+
+	if p, ok := peer.FromContext(ctx); ok {
+		srcHost, _, _ = net.SplitHostPort(p.Addr.String())
+	}
+
+	if err := o.sendRecords(ctx, req, protocolGRPC, srcHost); err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	return &collogspb.ExportLogsServiceResponse{}, nil
+}
+
+// decodeRequest reads an OTLP/HTTP request, encoded in protobuf or JSON
+func (o *OtlpSource) decodeRequest(r *http.Request) (*collogspb.ExportLogsServiceRequest, bool, int, error) {
+	isJSON := false
+
+	mediaType, _, _ := strings.Cut(r.Header.Get("Content-Type"), ";")
+
+	switch strings.TrimSpace(mediaType) {
+	case contentTypeProtobuf:
+	case contentTypeJSON:
+		isJSON = true
+	default:
+		return nil, false, http.StatusUnsupportedMediaType, fmt.Errorf("unsupported content type '%s'", mediaType)
+	}
+
+	var reader io.Reader = r.Body
+
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, isJSON, http.StatusBadRequest, fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+		defer gz.Close()
+
+		reader = gz
+	}
+
+	// the limit applies to the decompressed body as well
+	reader = io.LimitReader(reader, *o.Config.MaxBodySize+1)
+
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, isJSON, http.StatusBadRequest, fmt.Errorf("failed to read body: %w", err)
+	}
+
+	if int64(len(body)) > *o.Config.MaxBodySize {
+		return nil, isJSON, http.StatusRequestEntityTooLarge, fmt.Errorf("body size exceeds max body size: %d", *o.Config.MaxBodySize)
+	}
+
+	req := &collogspb.ExportLogsServiceRequest{}
+
+	if isJSON {
+		err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(body, req)
+	} else {
+		err = proto.Unmarshal(body, req)
+	}
+
+	if err != nil {
+		return nil, isJSON, http.StatusBadRequest, fmt.Errorf("failed to decode: %w", err)
+	}
+
+	return req, isJSON, http.StatusOK, nil
+}
+
+func (o *OtlpSource) handleHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := o.Config.authorize(r.Header.Get); err != nil {
+		o.logger.Errorf("failed to authorize request from '%s': %s", r.RemoteAddr, err)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+
+		return
+	}
+
+	defer r.Body.Close()
+
+	req, isJSON, code, err := o.decodeRequest(r)
+	if err != nil {
+		o.logger.Errorf("failed to process request from '%s': %s", r.RemoteAddr, err)
+		http.Error(w, err.Error(), code)
+
+		return
+	}
+
+	srcHost, _, _ := net.SplitHostPort(r.RemoteAddr)
+
+	if err := o.sendRecords(r.Context(), req, protocolHTTP, srcHost); err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	var (
+		resp        []byte
+		contentType = contentTypeProtobuf
+	)
+
+	if isJSON {
+		contentType = contentTypeJSON
+		resp, err = protojson.Marshal(&collogspb.ExportLogsServiceResponse{})
+	} else {
+		resp, err = proto.Marshal(&collogspb.ExportLogsServiceResponse{})
+	}
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(resp)
+}
+
+func (o *OtlpSource) RunServer(out chan types.Event, t *tomb.Tomb) error {
+	o.out = out
+
+	var tlsConfig *tls.Config
+
+	if o.Config.TLS != nil {
+		var err error
+
+		tlsConfig, err = o.Config.NewTLSConfig()
+		if err != nil {
+			return fmt.Errorf("failed to create tls config: %w", err)
+		}
+	}
+
+	if o.Config.HTTPListenAddr != "" {
+		mux := http.NewServeMux()
+		mux.HandleFunc(o.Config.Path, o.handleHTTP)
+
+		o.HTTPServer = &http.Server{
+			Addr:              o.Config.HTTPListenAddr,
+			Handler:           mux,
+			TLSConfig:         tlsConfig,
+			ReadHeaderTimeout: readHeaderTimeout,
+			ReadTimeout:       *o.Config.Timeout,
+		}
+
+		t.Go(func() error {
+			defer trace.CatchPanic("crowdsec/acquis/otlp/http")
+
+			var err error
+
+			if tlsConfig != nil {
+				o.logger.Infof("start OTLP/HTTP server with TLS on %s", o.Config.HTTPListenAddr)
+				err = o.HTTPServer.ListenAndServeTLS("", "")
+			} else {
+				o.logger.Infof("start OTLP/HTTP server on %s", o.Config.HTTPListenAddr)
+				err = o.HTTPServer.ListenAndServe()
+			}
+
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return fmt.Errorf("OTLP/HTTP server failed: %w", err)
+			}
+
+			return nil
+		})
+	}
+
+	if o.Config.GRPCListenAddr != "" {
+		opts := []grpc.ServerOption{}
+		if tlsConfig != nil {
+			opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+		}
+
+		// the limit applies to the decompressed messages
+		opts = append(opts, grpc.MaxRecvMsgSize(int(*o.Config.MaxBodySize)))
+
+		o.GRPCServer = grpc.NewServer(opts...)
+		collogspb.RegisterLogsServiceServer(o.GRPCServer, o)
+
+		listener, err := net.Listen("tcp", o.Config.GRPCListenAddr)
+		if err != nil {
+			return fmt.Errorf("OTLP/gRPC server failed: %w", err)
+		}
+
+		t.Go(func() error {
+			defer trace.CatchPanic("crowdsec/acquis/otlp/grpc")
+
+			o.logger.Infof("start OTLP/gRPC server on %s", o.Config.GRPCListenAddr)
+
+			if err := o.GRPCServer.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+				return fmt.Errorf("OTLP/gRPC server failed: %w", err)
+			}
+
+			return nil
+		})
+	}
+
+	<-t.Dying()
+
+	o.logger.Infof("%s datasource stopping", dataSourceName)
+
+	if o.GRPCServer != nil {
+		o.GRPCServer.Stop()
+	}
+
+	if o.HTTPServer != nil {
+		if err := o.HTTPServer.Close(); err != nil {
+			return fmt.Errorf("while closing %s server: %w", dataSourceName, err)
+		}
+	}
+
+	return nil
+}
+
+func (o *OtlpSource) StreamingAcquisition(ctx context.Context, out chan types.Event, t *tomb.Tomb) error {
+	t.Go(func() error {
+		defer trace.CatchPanic("crowdsec/acquis/otlp/live")
+		return o.RunServer(out, t)
+	})
+
+	return nil
+}
@@ -0,0 +1,655 @@
+package otlpacquisition
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/tomb.v2"
+
+	"github.com/crowdsecurity/go-cs-lib/cstest"
+
+	"github.com/crowdsecurity/crowdsec/pkg/acquisition/configuration"
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+const (
+	testHTTPAddr = "127.0.0.1:4318"
+	testGRPCAddr = "127.0.0.1:4317"
+)
+
+func TestConfigure(t *testing.T) {
+	tests := []struct {
+		config      string
+		expectedErr string
+	}{
+		{
+			config:      `foobar: bla`,
+			expectedErr: "invalid configuration: http_listen_addr or grpc_listen_addr is required",
+		},
+		{
+			config: `
+http_listen_addr: 127.0.0.1:4318
+path: v1/logs`,
+			expectedErr: "invalid configuration: path must start with /",
+		},
+		{
+			config: `
+http_listen_addr: 127.0.0.1:4318
+tls:
+  server_key: key.pem`,
+			expectedErr: "invalid configuration: server_cert is required",
+		},
+		{
+			config: `
+grpc_listen_addr: 127.0.0.1:4317
+tls:
+  server_cert: cert.pem`,
+			expectedErr: "invalid configuration: server_key is required",
+		},
+		{
+			config: `
+http_listen_addr: 127.0.0.1:4318
+max_body_size: 0`,
+			expectedErr: "invalid configuration: max_body_size must be positive",
+		},
+		{
+			config: `
+http_listen_addr: 127.0.0.1:4318
+timeout: -1s`,
+			expectedErr: "invalid configuration: timeout must be positive",
+		},
+		{
+			config: `
+grpc_listen_addr: 127.0.0.1:4317
+mode: cat`,
+			expectedErr: "invalid configuration: unsupported mode cat for otlp datasource",
+		},
+		{
+			config: `
+http_listen_addr: 127.0.0.1:4318
+grpc_listen_addr: 127.0.0.1:4317`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.expectedErr, func(t *testing.T) {
+			o := OtlpSource{}
+			err := o.Configure([]byte(tc.config), log.WithField("type", "otlp"), configuration.METRICS_NONE)
+			cstest.RequireErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestConfigureByDSN(t *testing.T) {
+	o := OtlpSource{}
+	err := o.ConfigureByDSN("otlp://localhost:4317", map[string]string{}, log.WithField("type", "otlp"), "")
+	cstest.RequireErrorContains(t, err, "otlp datasource does not support command-line acquisition")
+}
+
+func setupAndRunOtlpSource(t *testing.T, config string, metricsLevel int) (chan types.Event, *prometheus.Registry, *tomb.Tomb) {
+	t.Helper()
+
+	linesRead.Reset()
+
+	o := &OtlpSource{}
+	require.NoError(t, o.Configure([]byte(config), log.WithField("type", "otlp"), metricsLevel))
+
+	tmb := &tomb.Tomb{}
+	out := make(chan types.Event)
+	require.NoError(t, o.StreamingAcquisition(t.Context(), out, tmb))
+
+	registry := prometheus.NewPedanticRegistry()
+	for _, metric := range o.GetMetrics() {
+		require.NoError(t, registry.Register(metric))
+	}
+
+	t.Cleanup(func() {
+		tmb.Kill(nil)
+		require.NoError(t, tmb.Wait())
+	})
+
+	// wait for the servers to listen
+	for _, addr := range []string{o.Config.HTTPListenAddr, o.Config.GRPCListenAddr} {
+		if addr == "" {
+			continue
+		}
+
+		require.Eventually(t, func() bool {
+			conn, err := net.Dial("tcp", addr)
+			if err != nil {
+				return false
+			}
+
+			conn.Close()
+
+			return true
+		}, 5*time.Second, 50*time.Millisecond)
+	}
+
+	return out, registry, tmb
+}
+
+func stringAttr(key string, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}}}
+}
+
+func testRequest(bodies ...string) *collogspb.ExportLogsServiceRequest {
+	records := make([]*logspb.LogRecord, 0, len(bodies))
+
+	for _, body := range bodies {
+		records = append(records, &logspb.LogRecord{
+			TimeUnixNano:   uint64(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC).UnixNano()),
+			SeverityNumber: logspb.SeverityNumber_SEVERITY_NUMBER_WARN,
+			SeverityText:   "WARN",
+			Body:           &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: body}},
+			Attributes:     []*commonpb.KeyValue{stringAttr("log.file.name", "auth.log")},
+			TraceId:        []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		})
+	}
+
+	return &collogspb.ExportLogsServiceRequest{
+		ResourceLogs: []*logspb.ResourceLogs{
+			{
+				Resource: &resourcepb.Resource{
+					Attributes: []*commonpb.KeyValue{
+						stringAttr("service.name", "sshd"),
+						stringAttr("host.name", "web-1"),
+					},
+				},
+				ScopeLogs: []*logspb.ScopeLogs{
+					{
+						Scope:      &commonpb.InstrumentationScope{Name: "filelog"},
+						LogRecords: records,
+					},
+				},
+			},
+		},
+	}
+}
+
+func receiveEvents(t *testing.T, out chan types.Event, count int) []types.Event {
+	t.Helper()
+
+	events := []types.Event{}
+
+	for range count {
+		select {
+		case evt := <-out:
+			events = append(events, evt)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("expected %d events, got %d", count, len(events))
+		}
+	}
+
+	return events
+}
+
+func assertMetrics(t *testing.T, reg *prometheus.Registry, protocol string, src string, expected int) {
+	t.Helper()
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != "cs_otlpsource_hits_total" {
+			continue
+		}
+
+		require.Len(t, family.GetMetric(), 1)
+
+		metric := family.GetMetric()[0]
+		assert.InDelta(t, float64(expected), metric.GetCounter().GetValue(), 0.000001)
+
+		labels := metric.GetLabel()
+		require.Len(t, labels, 2)
+		assert.Equal(t, "protocol", labels[0].GetName())
+		assert.Equal(t, protocol, labels[0].GetValue())
+		assert.Equal(t, "src", labels[1].GetName())
+		assert.Equal(t, src, labels[1].GetValue())
+
+		return
+	}
+
+	if expected > 0 {
+		t.Fatalf("expected metric cs_otlpsource_hits_total not found")
+	}
+}
+
+func postHTTP(t *testing.T, client *http.Client, url string, contentType string, body []byte, headers map[string]string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, url, bytes.NewReader(body))
+	require.NoError(t, err)
+
+	req.Header.Set("Content-Type", contentType)
+
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+
+	resp.Body.Close()
+
+	return resp
+}
+
+func TestGRPC(t *testing.T) {
+	out, reg, _ := setupAndRunOtlpSource(t, `
+grpc_listen_addr: `+testGRPCAddr+`
+labels:
+  type: syslog
+label_attributes:
+  - service.name
+  - log.file.name
+  - missing`, configuration.METRICS_FULL)
+
+	conn, err := grpc.NewClient(testGRPCAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	client := collogspb.NewLogsServiceClient(conn)
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		_, err := client.Export(t.Context(), testRequest("line 1", "line 2"))
+		assert.NoError(t, err)
+	}()
+
+	events := receiveEvents(t, out, 2)
+	<-done
+
+	assert.Equal(t, "line 1", events[0].Line.Raw)
+	assert.Equal(t, "line 2", events[1].Line.Raw)
+	assert.Equal(t, "127.0.0.1", events[0].Line.Src)
+	assert.Equal(t, "otlp", events[0].Line.Module)
+	assert.Equal(t, map[string]string{"type": "syslog", "service.name": "sshd", "log.file.name": "auth.log"}, events[0].Line.Labels)
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), events[0].Line.Time)
+
+	otlp, ok := events[0].Unmarshaled["otlp"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, map[string]any{"service.name": "sshd", "host.name": "web-1"}, otlp["resource"])
+	assert.Equal(t, map[string]any{"log.file.name": "auth.log"}, otlp["attributes"])
+	assert.Equal(t, "filelog", otlp["scope"].(map[string]any)["name"])
+	assert.Equal(t, "WARN", otlp["severity_text"])
+	assert.Equal(t, int32(13), otlp["severity_number"])
+	assert.Equal(t, "0102030405060708090a0b0c0d0e0f10", otlp["trace_id"])
+	assert.Empty(t, otlp["span_id"])
+
+	assertMetrics(t, reg, "grpc", "127.0.0.1", 2)
+}
+
+func TestGRPCHeaders(t *testing.T) {
+	out, _, _ := setupAndRunOtlpSource(t, `
+grpc_listen_addr: `+testGRPCAddr+`
+headers:
+  x-api-key: secret`, configuration.METRICS_NONE)
+
+	conn, err := grpc.NewClient(testGRPCAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	client := collogspb.NewLogsServiceClient(conn)
+
+	_, err = client.Export(t.Context(), testRequest("line"))
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(t.Context(), "x-api-key", "secret")
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		_, err := client.Export(ctx, testRequest("line"))
+		assert.NoError(t, err)
+	}()
+
+	events := receiveEvents(t, out, 1)
+	<-done
+	assert.Equal(t, "line", events[0].Line.Raw)
+}
+
+func TestHTTP(t *testing.T) {
+	out, reg, _ := setupAndRunOtlpSource(t, `
+http_listen_addr: `+testHTTPAddr+`
+headers:
+  x-api-key: secret`, configuration.METRICS_AGGREGATE)
+
+	url := "http://" + testHTTPAddr + "/v1/logs"
+	headers := map[string]string{"x-api-key": "secret"}
+
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+
+	body, err := proto.Marshal(testRequest("protobuf line"))
+	require.NoError(t, err)
+
+	resp = postHTTP(t, http.DefaultClient, url, contentTypeProtobuf, body, nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp = postHTTP(t, http.DefaultClient, url, "text/plain", body, headers)
+	assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+
+	resp = postHTTP(t, http.DefaultClient, url, contentTypeJSON, []byte("{"), headers)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		resp := postHTTP(t, http.DefaultClient, url, contentTypeProtobuf, body, headers)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, contentTypeProtobuf, resp.Header.Get("Content-Type"))
+	}()
+
+	events := receiveEvents(t, out, 1)
+	<-done
+	assert.Equal(t, "protobuf line", events[0].Line.Raw)
+	// aggregated metrics: the source is the path
+	assert.Equal(t, "/v1/logs", events[0].Line.Src)
+
+	body, err = protojson.Marshal(testRequest("json line"))
+	require.NoError(t, err)
+
+	var gz bytes.Buffer
+
+	w := gzip.NewWriter(&gz)
+	_, err = w.Write(body)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	done = make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		resp := postHTTP(t, http.DefaultClient, url, contentTypeJSON, gz.Bytes(), map[string]string{"x-api-key": "secret", "Content-Encoding": "gzip"})
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, contentTypeJSON, resp.Header.Get("Content-Type"))
+	}()
+
+	events = receiveEvents(t, out, 1)
+	<-done
+	assert.Equal(t, "json line", events[0].Line.Raw)
+
+	assertMetrics(t, reg, "http", "", 2)
+}
+
+func TestHTTPMaxBodySize(t *testing.T) {
+	_, _, _ = setupAndRunOtlpSource(t, `
+http_listen_addr: `+testHTTPAddr+`
+max_body_size: 10`, configuration.METRICS_NONE)
+
+	body, err := proto.Marshal(testRequest("a line that is too long"))
+	require.NoError(t, err)
+
+	resp := postHTTP(t, http.DefaultClient, "http://"+testHTTPAddr+"/v1/logs", contentTypeProtobuf, body, nil)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+}
+
+func TestHTTPDefaultMaxBodySize(t *testing.T) {
+	_, _, _ = setupAndRunOtlpSource(t, `
+http_listen_addr: `+testHTTPAddr, configuration.METRICS_NONE)
+
+	// a small compressed body that expands beyond the default limit
+	var gz bytes.Buffer
+
+	w := gzip.NewWriter(&gz)
+	_, err := w.Write(make([]byte, defaultMaxBodySize+1))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	resp := postHTTP(t, http.DefaultClient, "http://"+testHTTPAddr+"/v1/logs", contentTypeProtobuf, gz.Bytes(), map[string]string{"Content-Encoding": "gzip"})
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+}
+
+func TestHTTPTimeout(t *testing.T) {
+	_, _, _ = setupAndRunOtlpSource(t, `
+http_listen_addr: `+testHTTPAddr+`
+timeout: 500ms`, configuration.METRICS_NONE)
+
+	conn, err := net.Dial("tcp", testHTTPAddr)
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	// the body never comes
+	_, err = fmt.Fprintf(conn, "POST /v1/logs HTTP/1.1\r\nHost: %s\r\nContent-Type: %s\r\nContent-Length: 100\r\n\r\n", testHTTPAddr, contentTypeProtobuf)
+	require.NoError(t, err)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestStringValue(t *testing.T) {
+	tests := []struct {
+		value    *commonpb.AnyValue
+		expected string
+	}{
+		{nil, ""},
+		{&commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "foo"}}, "foo"},
+		{&commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: 42}}, "42"},
+		{&commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: 1.5}}, "1.5"},
+		{&commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: true}}, "true"},
+		{
+			&commonpb.AnyValue{Value: &commonpb.AnyValue_KvlistValue{KvlistValue: &commonpb.KeyValueList{
+				Values: []*commonpb.KeyValue{stringAttr("msg", "hello")},
+			}}},
+			`{"msg":"hello"}`,
+		},
+		{
+			&commonpb.AnyValue{Value: &commonpb.AnyValue_ArrayValue{ArrayValue: &commonpb.ArrayValue{
+				Values: []*commonpb.AnyValue{{Value: &commonpb.AnyValue_IntValue{IntValue: 1}}},
+			}}},
+			`[1]`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.expected, func(t *testing.T) {
+			assert.Equal(t, tc.expected, stringValue(tc.value))
+		})
+	}
+}
+
+// writePEM writes a certificate or a key in a temporary directory
+func writePEM(t *testing.T, dir string, name string, blockType string, der []byte) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+
+	return path
+}
+
+type testCert struct {
+	cert     *x509.Certificate
+	key      *ecdsa.PrivateKey
+	certFile string
+	keyFile  string
+}
+
+// newTestCert creates a certificate signed by parent, or a self-signed CA if parent is nil
+func newTestCert(t *testing.T, dir string, name string, parent *testCert) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	signer, signerKey := template, key
+
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return &testCert{
+		cert:     cert,
+		key:      key,
+		certFile: writePEM(t, dir, name+".pem", "CERTIFICATE", der),
+		keyFile:  writePEM(t, dir, name+"-key.pem", "EC PRIVATE KEY", keyDer),
+	}
+}
+
+func TestMTLS(t *testing.T) {
+	dir := t.TempDir()
+
+	ca := newTestCert(t, dir, "ca", nil)
+	server := newTestCert(t, dir, "server", ca)
+	client := newTestCert(t, dir, "client", ca)
+	otherCA := newTestCert(t, dir, "other-ca", nil)
+	intruder := newTestCert(t, dir, "intruder", otherCA)
+
+	out, _, _ := setupAndRunOtlpSource(t, fmt.Sprintf(`
+http_listen_addr: %s
+grpc_listen_addr: %s
+tls:
+  server_cert: %s
+  server_key: %s
+  ca_cert: %s`, testHTTPAddr, testGRPCAddr, server.certFile, server.keyFile, ca.certFile), configuration.METRICS_NONE)
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(ca.cert)
+
+	clientTLS := func(c *testCert) *tls.Config {
+		cfg := &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12}
+
+		if c != nil {
+			pair, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+			require.NoError(t, err)
+
+			cfg.Certificates = []tls.Certificate{pair}
+		}
+
+		return cfg
+	}
+
+	body, err := proto.Marshal(testRequest("https line"))
+	require.NoError(t, err)
+
+	url := "https://" + testHTTPAddr + "/v1/logs"
+
+	for _, c := range []*testCert{nil, intruder} {
+		httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: clientTLS(c)}}
+
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, url, bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", contentTypeProtobuf)
+
+		_, err = httpClient.Do(req)
+		require.Error(t, err)
+	}
+
+	httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: clientTLS(client)}}
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		resp := postHTTP(t, httpClient, url, contentTypeProtobuf, body, nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}()
+
+	events := receiveEvents(t, out, 1)
+	<-done
+	assert.Equal(t, "https line", events[0].Line.Raw)
+
+	conn, err := grpc.NewClient(testGRPCAddr, grpc.WithTransportCredentials(credentials.NewTLS(clientTLS(intruder))))
+	require.NoError(t, err)
+
+	_, err = collogspb.NewLogsServiceClient(conn).Export(t.Context(), testRequest("grpc line"))
+	require.Error(t, err)
+	conn.Close()
+
+	conn, err = grpc.NewClient(testGRPCAddr, grpc.WithTransportCredentials(credentials.NewTLS(clientTLS(client))))
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	done = make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		_, err := collogspb.NewLogsServiceClient(conn).Export(t.Context(), testRequest("grpc line"))
+		assert.NoError(t, err)
+	}()
+
+	events = receiveEvents(t, out, 1)
+	<-done
+	assert.Equal(t, "grpc line", events[0].Line.Raw)
+}
@@ -0,0 +1,593 @@
+package redisacquisition
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/tomb.v2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/crowdsecurity/go-cs-lib/trace"
+
+	"github.com/crowdsecurity/crowdsec/pkg/acquisition/configuration"
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+const dataSourceName = "redis"
+
+const (
+	defaultGroup = "crowdsec"
+	defaultField = "message"
+	defaultBatch = 100
+	// how long a read waits for new entries, before checking if the datasource must stop
+	readBlock = time.Second
+)
+
+var linesRead = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "cs_redissource_hits_total",
+		Help: "Total lines that were read from stream",
+	},
+	[]string{"stream"})
+
+type RedisConfiguration struct {
+	Addr     string   `yaml:"addr"`
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"`
+	DB       int      `yaml:"db"`
+	Streams  []string `yaml:"streams"`
+	// Group is the consumer group, created if it doesn't exist
+	Group string `yaml:"group"`
+	// Consumer is the name of crowdsec in the group, it must be the same across restarts
+	// for the entries that were not acknowledged to be read again
+	Consumer string `yaml:"consumer"`
+	// StartID is where a new group starts reading: "$" for the new entries, "0" for the whole stream
+	StartID string `yaml:"start_id"`
+	// Field of the entries that contains the log line
+	Field     string     `yaml:"field"`
+	BatchSize int64      `yaml:"batch_size"`
+	TLS       *TLSConfig `yaml:"tls"`
+
+	configuration.DataSourceCommonCfg `yaml:",inline"`
+}
+
+type TLSConfig struct {
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+	ClientCert         string `yaml:"client_cert"`
+	ClientKey          string `yaml:"client_key"`
+	CaCert             string `yaml:"ca_cert"`
+}
+
+type RedisSource struct {
+	metricsLevel int
+	Config       RedisConfiguration
+	logger       *log.Entry
+	client       *redis.Client
+}
+
+func (r *RedisSource) GetUuid() string {
+	return r.Config.UniqueId
+}
+
+func (r *RedisSource) UnmarshalConfig(yamlConfig []byte) error {
+	r.Config = RedisConfiguration{}
+
+	err := yaml.Unmarshal(yamlConfig, &r.Config)
+	if err != nil {
+		return fmt.Errorf("cannot parse %s datasource configuration: %w", dataSourceName, err)
+	}
+
+	r.Config.setDefaults()
+
+	return nil
+}
+
+func (rc *RedisConfiguration) setDefaults() {
+	if rc.Mode == "" {
+		rc.Mode = configuration.TAIL_MODE
+	}
+
+	if rc.Group == "" {
+		rc.Group = defaultGroup
+	}
+
+	if rc.Consumer == "" {
+		hostname, err := os.Hostname()
+		if err != nil || hostname == "" {
+			hostname = "crowdsec"
+		}
+
+		rc.Consumer = hostname
+	}
+
+	if rc.StartID == "" {
+		rc.StartID = "$"
+	}
+
+	if rc.Field == "" {
+		rc.Field = defaultField
+	}
+
+	if rc.BatchSize == 0 {
+		rc.BatchSize = defaultBatch
+	}
+}
+
+func (rc *RedisConfiguration) Validate() error {
+	if rc.Addr == "" {
+		return errors.New("addr is required")
+	}
+
+	if len(rc.Streams) == 0 {
+		return errors.New("at least one stream is required")
+	}
+
+	if rc.BatchSize < 0 {
+		return errors.New("batch_size must be positive")
+	}
+
+	if rc.TLS != nil && (rc.TLS.ClientCert == "") != (rc.TLS.ClientKey == "") {
+		return errors.New("client_cert and client_key must be set together")
+	}
+
+	if rc.Mode != configuration.TAIL_MODE && rc.Mode != configuration.CAT_MODE {
+		return fmt.Errorf("unsupported mode %s for %s datasource", rc.Mode, dataSourceName)
+	}
+
+	return nil
+}
+
+func (r *RedisSource) Configure(yamlConfig []byte, logger *log.Entry, metricsLevel int) error {
+	r.logger = logger
+	r.metricsLevel = metricsLevel
+
+	err := r.UnmarshalConfig(yamlConfig)
+	if err != nil {
+		return err
+	}
+
+	return r.configure()
+}
+
+func (r *RedisSource) configure() error {
+	if err := r.Config.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	options := &redis.Options{
+		Addr:     r.Config.Addr,
+		Username: r.Config.Username,
+		Password: r.Config.Password,
+		DB:       r.Config.DB,
+		// the reads block for readBlock
+		ReadTimeout: readBlock + 5*time.Second,
+	}
+
+	if r.Config.TLS != nil {
+		tlsConfig, err := r.Config.NewTLSConfig()
+		if err != nil {
+			return fmt.Errorf("cannot create %s tls config: %w", dataSourceName, err)
+		}
+
+		options.TLSConfig = tlsConfig
+	}
+
+	r.client = redis.NewClient(options)
+
+	return nil
+}
+
+// ConfigureByDSN reads the streams until they're empty:
+// redis://[user:password@]host:port[/db]?stream=logs[&stream=other][&group=crowdsec][&consumer=name][&field=message][&start_id=0]
+func (r *RedisSource) ConfigureByDSN(dsn string, labels map[string]string, logger *log.Entry, uuid string) error {
+	r.logger = logger
+	r.Config = RedisConfiguration{}
+	r.Config.Mode = configuration.CAT_MODE
+	r.Config.Labels = labels
+	r.Config.UniqueId = uuid
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return fmt.Errorf("while parsing dsn '%s': %w", dsn, err)
+	}
+
+	if u.Scheme != dataSourceName {
+		return fmt.Errorf("invalid DSN %s for %s source, must start with %s://", dsn, dataSourceName, dataSourceName)
+	}
+
+	r.Config.Addr = u.Host
+
+	if u.User != nil {
+		r.Config.Username = u.User.Username()
+		r.Config.Password, _ = u.User.Password()
+	}
+
+	if db := strings.Trim(u.Path, "/"); db != "" {
+		r.Config.DB, err = strconv.Atoi(db)
+		if err != nil {
+			return fmt.Errorf("invalid db in dsn: %w", err)
+		}
+	}
+
+	params := u.Query()
+
+	r.Config.Streams = params["stream"]
+	r.Config.Group = params.Get("group")
+	r.Config.Consumer = params.Get("consumer")
+	r.Config.Field = params.Get("field")
+	// when reading from the command line, a new group reads the whole stream
+	r.Config.StartID = "0"
+
+	if startID := params.Get("start_id"); startID != "" {
+		r.Config.StartID = startID
+	}
+
+	if logLevel := params.Get("log_level"); logLevel != "" {
+		level, err := log.ParseLevel(logLevel)
+		if err != nil {
+			return fmt.Errorf("invalid log_level in dsn: %w", err)
+		}
+
+		r.Config.LogLevel = &level
+		r.logger.Logger.SetLevel(level)
+	}
+
+	r.Config.setDefaults()
+
+	return r.configure()
+}
+
+func (r *RedisSource) GetMode() string {
+	return r.Config.Mode
+}
+
+func (r *RedisSource) GetName() string {
+	return dataSourceName
+}
+
+func (r *RedisSource) CanRun() error {
+	return nil
+}
+
+func (r *RedisSource) GetMetrics() []prometheus.Collector {
+	return []prometheus.Collector{linesRead}
+}
+
+func (r *RedisSource) GetAggregMetrics() []prometheus.Collector {
+	return []prometheus.Collector{linesRead}
+}
+
+func (r *RedisSource) Dump() interface{} {
+	return r
+}
+
+func (rc *RedisConfiguration) NewTLSConfig() (*tls.Config, error) {
+	tlsConfig := tls.Config{
+		InsecureSkipVerify: rc.TLS.InsecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	if rc.TLS.ClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(rc.TLS.ClientCert, rc.TLS.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client cert/key: %w", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if rc.TLS.CaCert != "" {
+		caCert, err := os.ReadFile(rc.TLS.CaCert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca cert: %w", err)
+		}
+
+		caCertPool, err := x509.SystemCertPool()
+		if err != nil {
+			return nil, fmt.Errorf("unable to load system CA certificates: %w", err)
+		}
+
+		if caCertPool == nil {
+			caCertPool = x509.NewCertPool()
+		}
+
+		caCertPool.AppendCertsFromPEM(caCert)
+		tlsConfig.RootCAs = caCertPool
+	}
+
+	return &tlsConfig, nil
+}
+
+// createGroups creates the consumer group on the streams, and the streams if they don't exist
+func (r *RedisSource) createGroups(ctx context.Context) error {
+	for _, stream := range r.Config.Streams {
+		err := r.client.XGroupCreateMkStream(ctx, stream, r.Config.Group, r.Config.StartID).Err()
+		if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+			return fmt.Errorf("while creating group '%s' on stream '%s': %w", r.Config.Group, stream, err)
+		}
+	}
+
+	return nil
+}
+
+// entryTime returns the time an entry was added, from its id (<milliseconds>-<sequence>)
+func entryTime(id string) time.Time {
+	ms, _, _ := strings.Cut(id, "-")
+
+	msec, err := strconv.ParseInt(ms, 10, 64)
+	if err != nil {
+		return time.Now().UTC()
+	}
+
+	return time.UnixMilli(msec).UTC()
+}
+
+func (r *RedisSource) makeEvent(stream string, msg redis.XMessage) types.Event {
+	raw, ok := msg.Values[r.Config.Field].(string)
+	if !ok {
+		// no log line in the entry, send all of it
+		b, err := json.Marshal(msg.Values)
+		if err != nil {
+			r.logger.Errorf("while serializing entry %s of stream '%s': %s", msg.ID, stream, err)
+		}
+
+		raw = string(b)
+	}
+
+	evt := types.MakeEvent(r.Config.UseTimeMachine, types.LOG, true)
+	evt.Line = types.Line{
+		Raw:     raw,
+		Labels:  r.Config.Labels,
+		Time:    entryTime(msg.ID),
+		Src:     stream,
+		Process: true,
+		Module:  r.GetName(),
+	}
+	evt.Unmarshaled["redis"] = map[string]any{
+		"stream": stream,
+		"id":     msg.ID,
+		"fields": msg.Values,
+	}
+
+	return evt
+}
+
+func (r *RedisSource) ack(stream string, id string) {
+	err := r.client.XAck(context.Background(), stream, r.Config.Group, id).Err()
+	if errors.Is(err, redis.ErrClosed) {
+		// the datasource stopped before the entry was parsed, it stays pending and is read again after a restart
+		r.logger.Debugf("not acknowledging entry %s of stream '%s': the datasource is stopped", id, stream)
+		return
+	}
+
+	if err != nil {
+		r.logger.Errorf("while acknowledging entry %s of stream '%s': %s", id, stream, err)
+	}
+}
+
+// readGroup reads a batch of entries of the streams, after the given ids: "0" or an id for the
+// entries that were delivered to this consumer but not acknowledged, ">" for the new ones
+func (r *RedisSource) readGroup(ctx context.Context, ids []string, block time.Duration) ([]redis.XStream, error) {
+	streams := make([]string, 0, 2*len(r.Config.Streams))
+	streams = append(streams, r.Config.Streams...)
+	streams = append(streams, ids...)
+
+	res, err := r.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    r.Config.Group,
+		Consumer: r.Config.Consumer,
+		Streams:  streams,
+		Count:    r.Config.BatchSize,
+		Block:    block,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+
+	return res, err
+}
+
+// newIDs returns the ids to read the new entries of all the streams
+func (r *RedisSource) newIDs() []string {
+	ids := make([]string, len(r.Config.Streams))
+	for i := range ids {
+		ids[i] = ">"
+	}
+
+	return ids
+}
+
+// sendEntries sends a batch of entries, and returns the number of entries that were sent.
+// In streaming mode, the entries are acknowledged once parsed, in one-shot mode as soon
+// as they are sent because crowdsec exits when the acquisition is done.
+func (r *RedisSource) sendEntries(streams []redis.XStream, ackOnSend bool, out chan types.Event, t *tomb.Tomb) (int, bool) {
+	count := 0
+
+	for _, stream := range streams {
+		for _, msg := range stream.Messages {
+			evt := r.makeEvent(stream.Stream, msg)
+
+			if !ackOnSend {
+				evt.Line.Ack = func() { r.ack(stream.Stream, msg.ID) }
+			}
+
+			if r.metricsLevel != configuration.METRICS_NONE {
+				linesRead.With(prometheus.Labels{"stream": stream.Stream}).Inc()
+			}
+
+			select {
+			case out <- evt:
+			case <-t.Dying():
+				return count, false
+			}
+
+			if ackOnSend {
+				r.ack(stream.Stream, msg.ID)
+			}
+
+			count++
+		}
+	}
+
+	return count, true
+}
+
+// readPending sends the entries that were delivered to this consumer before a restart but never acknowledged
+func (r *RedisSource) readPending(ctx context.Context, ackOnSend bool, out chan types.Event, t *tomb.Tomb) (bool, error) {
+	ids := make([]string, len(r.Config.Streams))
+	for i := range ids {
+		ids[i] = "0"
+	}
+
+	total := 0
+
+	for {
+		streams, err := r.readGroup(ctx, ids, -1)
+		if err != nil {
+			return false, fmt.Errorf("while reading pending entries: %w", err)
+		}
+
+		// continue after the last entry of each stream
+		for _, stream := range streams {
+			if len(stream.Messages) == 0 {
+				continue
+			}
+
+			for i, name := range r.Config.Streams {
+				if name == stream.Stream {
+					ids[i] = stream.Messages[len(stream.Messages)-1].ID
+				}
+			}
+		}
+
+		count, alive := r.sendEntries(streams, ackOnSend, out, t)
+		if !alive {
+			return false, nil
+		}
+
+		if count == 0 {
+			break
+		}
+
+		total += count
+	}
+
+	if total > 0 {
+		r.logger.Infof("read %d entries that were not acknowledged", total)
+	}
+
+	return true, nil
+}
+
+func (r *RedisSource) OneShotAcquisition(ctx context.Context, out chan types.Event, t *tomb.Tomb) error {
+	r.logger.Debugf("reading streams %v until they're empty", r.Config.Streams)
+
+	defer trace.CatchPanic("crowdsec/acquis/redis/oneshot")
+	defer r.client.Close()
+
+	if err := r.createGroups(ctx); err != nil {
+		return err
+	}
+
+	alive, err := r.readPending(ctx, true, out, t)
+	if err != nil || !alive {
+		return err
+	}
+
+	total := 0
+
+	for {
+		streams, err := r.readGroup(ctx, r.newIDs(), -1)
+		if err != nil {
+			return fmt.Errorf("while reading streams: %w", err)
+		}
+
+		count, alive := r.sendEntries(streams, true, out, t)
+		if !alive {
+			return nil
+		}
+
+		total += count
+
+		if count == 0 {
+			r.logger.Infof("read %d entries, streams are empty", total)
+			return nil
+		}
+	}
+}
+
+func (r *RedisSource) RunReader(ctx context.Context, out chan types.Event, t *tomb.Tomb) error {
+	if err := r.createGroups(ctx); err != nil {
+		return err
+	}
+
+	alive, err := r.readPending(ctx, false, out, t)
+	if err != nil || !alive {
+		return err
+	}
+
+	for {
+		select {
+		case <-t.Dying():
+			r.logger.Infof("%s datasource stopping", dataSourceName)
+			return nil
+		default:
+		}
+
+		streams, err := r.readGroup(ctx, r.newIDs(), readBlock)
+		if errors.Is(err, redis.ErrClosed) {
+			// the client was closed because the datasource is stopping
+			r.logger.Infof("%s datasource stopping", dataSourceName)
+			return nil
+		}
+
+		if err != nil {
+			r.logger.Errorf("while reading streams: %s", err)
+
+			select {
+			case <-t.Dying():
+			case <-time.After(readBlock):
+			}
+
+			continue
+		}
+
+		if _, alive := r.sendEntries(streams, false, out, t); !alive {
+			r.logger.Infof("%s datasource stopping", dataSourceName)
+			return nil
+		}
+	}
+}
+
+func (r *RedisSource) StreamingAcquisition(ctx context.Context, out chan types.Event, t *tomb.Tomb) error {
+	r.logger.Infof("start reader on '%s' with streams %v", r.Config.Addr, r.Config.Streams)
+
+	t.Go(func() error {
+		defer trace.CatchPanic("crowdsec/acquis/redis/live")
+		return r.RunReader(ctx, out, t)
+	})
+
+	t.Go(func() error {
+		<-t.Dying()
+
+		// the entries that are still being parsed are not acknowledged, and are read again after a restart
+		if err := r.client.Close(); err != nil {
+			r.logger.Errorf("while closing redis client: %s", err)
+		}
+
+		return nil
+	})
+
+	return nil
+}
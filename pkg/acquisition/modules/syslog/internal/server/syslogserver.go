@@ -1,9 +1,11 @@
 package syslogserver
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net"
 	"strings"
+	"sync"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -17,6 +19,18 @@ type SyslogServer struct {
 	udpConn       *net.UDPConn
 	Logger        *log.Entry
 	MaxMessageLen int
+	Proto         string      // udp (default) or tcp
+	Framing       string      // tcp only: auto (default), newline or octet_counting
+	TLSConfig     *tls.Config // tcp only
+	// tcp only: a connection is closed when no message is received for this long, if set
+	IdleTimeout time.Duration
+	// tcp only: the connections are refused beyond this number, if set
+	MaxConnections int
+
+	tcpListener net.Listener
+	connsMu     sync.Mutex
+	conns       map[net.Conn]struct{}
+	closed      bool
 }
 
 type SyslogMessage struct {
@@ -27,6 +41,9 @@ type SyslogMessage struct {
 func (s *SyslogServer) Listen(listenAddr string, port int) error {
 	s.listenAddr = listenAddr
 	s.port = port
+	if s.Proto == "tcp" {
+		return s.listenTCP()
+	}
 	udpAddr, err := net.ResolveUDPAddr("udp", fmt.Sprintf("%s:%d", s.listenAddr, s.port))
 	if err != nil {
 		return fmt.Errorf("could not resolve addr %s: %w", s.listenAddr, err)
@@ -50,6 +67,10 @@ func (s *SyslogServer) SetChannel(c chan SyslogMessage) {
 }
 
 func (s *SyslogServer) StartServer() *tomb.Tomb {
+	if s.Proto == "tcp" {
+		return s.startTCPServer()
+	}
+
 	t := tomb.Tomb{}
 
 	t.Go(func() error {
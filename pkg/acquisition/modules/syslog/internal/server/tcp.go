@@ -0,0 +1,317 @@
+package syslogserver
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/tomb.v2"
+
+	"github.com/crowdsecurity/go-cs-lib/trace"
+)
+
+const (
+	// RFC 6587: the framing is detected for each message, an octet count starts with a digit
+	FramingAuto          = "auto"
+	FramingNewline       = "newline"
+	FramingOctetCounting = "octet_counting"
+)
+
+const (
+	// MSG-LEN is a number of bytes, a message can't be that long
+	maxOctetCountDigits = 9
+	tlsHandshakeTimeout = 10 * time.Second
+)
+
+var (
+	errServerClosed       = errors.New("server closed")
+	errTooManyConnections = errors.New("too many connections")
+)
+
+// listenTCP opens the TCP listener, with TLS if TLSConfig is set
+func (s *SyslogServer) listenTCP() error {
+	var err error
+
+	addr := net.JoinHostPort(s.listenAddr, strconv.Itoa(s.port))
+
+	if s.TLSConfig != nil {
+		s.tcpListener, err = tls.Listen("tcp", addr, s.TLSConfig)
+	} else {
+		s.tcpListener, err = net.Listen("tcp", addr)
+	}
+
+	if err != nil {
+		return fmt.Errorf("could not listen on port %d: %w", s.port, err)
+	}
+
+	s.conns = make(map[net.Conn]struct{})
+
+	s.Logger.Debugf("listening on %s (tcp, tls: %t)", addr, s.TLSConfig != nil)
+
+	return nil
+}
+
+func (s *SyslogServer) startTCPServer() *tomb.Tomb {
+	t := tomb.Tomb{}
+
+	t.Go(func() error {
+		t.Go(func() error {
+			defer trace.CatchPanic("crowdsec/acquis/syslog/server/accept")
+			return s.acceptTCP(&t)
+		})
+
+		<-t.Dying()
+		s.Logger.Info("Syslog server tomb is dying")
+
+		return s.closeTCP()
+	})
+
+	return &t
+}
+
+// trackConn keeps the connection, to close it when the server stops. It fails if the server is already
+// stopping, or if it has too many connections.
+func (s *SyslogServer) trackConn(conn net.Conn) error {
+	s.connsMu.Lock()
+	defer s.connsMu.Unlock()
+
+	if s.closed {
+		return errServerClosed
+	}
+
+	if s.MaxConnections > 0 && len(s.conns) >= s.MaxConnections {
+		return errTooManyConnections
+	}
+
+	s.conns[conn] = struct{}{}
+
+	return nil
+}
+
+func (s *SyslogServer) untrackConn(conn net.Conn) {
+	s.connsMu.Lock()
+	defer s.connsMu.Unlock()
+
+	delete(s.conns, conn)
+}
+
+// closeTCP closes the listener and the open connections, so that their goroutines return
+func (s *SyslogServer) closeTCP() error {
+	s.connsMu.Lock()
+	defer s.connsMu.Unlock()
+
+	s.closed = true
+
+	for conn := range s.conns {
+		conn.Close()
+	}
+
+	if err := s.tcpListener.Close(); err != nil {
+		return fmt.Errorf("could not close TCP listener: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SyslogServer) acceptTCP(t *tomb.Tomb) error {
+	for {
+		conn, err := s.tcpListener.Accept()
+		if err != nil {
+			select {
+			case <-t.Dying():
+				return nil
+			default:
+			}
+
+			s.Logger.Errorf("error while accepting connection: %s", err)
+
+			return err
+		}
+
+		if err := s.trackConn(conn); err != nil {
+			conn.Close()
+
+			if errors.Is(err, errTooManyConnections) {
+				s.Logger.Warnf("connection from %s refused: more than %d connections", conn.RemoteAddr(), s.MaxConnections)
+				continue
+			}
+
+			return nil
+		}
+
+		t.Go(func() error {
+			defer trace.CatchPanic("crowdsec/acquis/syslog/server/conn")
+			defer s.untrackConn(conn)
+			defer conn.Close()
+
+			s.handleConn(conn, t)
+
+			return nil
+		})
+	}
+}
+
+func (s *SyslogServer) handleConn(conn net.Conn, t *tomb.Tomb) {
+	client, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		client = conn.RemoteAddr().String()
+	}
+
+	logger := s.Logger.WithField("client", client)
+
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		// the client certificate is verified during the handshake
+		if err := conn.SetDeadline(time.Now().Add(tlsHandshakeTimeout)); err != nil {
+			logger.Errorf("could not set deadline: %s", err)
+			return
+		}
+
+		if err := tlsConn.Handshake(); err != nil {
+			logger.Warnf("TLS handshake failed: %s", err)
+			return
+		}
+
+		// the read deadline is set for each message
+		if err := conn.SetDeadline(time.Time{}); err != nil {
+			logger.Errorf("could not reset deadline: %s", err)
+			return
+		}
+	}
+
+	logger.Debug("client connected")
+
+	// RFC3164 says 1024 bytes max
+	// RFC5424 says 480 bytes minimum, and should support up to 2048 bytes
+	reader := bufio.NewReaderSize(conn, s.MaxMessageLen)
+
+	for {
+		if s.IdleTimeout > 0 {
+			if err := conn.SetReadDeadline(time.Now().Add(s.IdleTimeout)); err != nil {
+				logger.Errorf("could not set read deadline: %s", err)
+				return
+			}
+		}
+
+		msg, err := s.readFrame(reader, logger)
+		if err != nil {
+			select {
+			case <-t.Dying():
+			default:
+				var netErr net.Error
+
+				switch {
+				case errors.Is(err, io.EOF):
+					logger.Debug("client disconnected")
+				case errors.As(err, &netErr) && netErr.Timeout():
+					logger.Debugf("no message for %s, closing the connection", s.IdleTimeout)
+				default:
+					logger.Errorf("error while reading from connection: %s", err)
+				}
+			}
+
+			return
+		}
+
+		if len(msg) == 0 {
+			continue
+		}
+
+		select {
+		case s.channel <- SyslogMessage{Message: msg, Client: client}:
+		case <-t.Dying():
+			return
+		}
+	}
+}
+
+// readFrame returns the next message of a TCP stream
+func (s *SyslogServer) readFrame(reader *bufio.Reader, logger *log.Entry) ([]byte, error) {
+	framing := s.Framing
+
+	if framing == "" || framing == FramingAuto {
+		first, err := reader.Peek(1)
+		if err != nil {
+			return nil, err
+		}
+
+		framing = FramingNewline
+
+		if first[0] >= '0' && first[0] <= '9' {
+			framing = FramingOctetCounting
+		}
+	}
+
+	if framing == FramingOctetCounting {
+		return s.readOctetCounted(reader, logger)
+	}
+
+	return s.readLine(reader, logger)
+}
+
+// readOctetCounted reads a message framed as "MSG-LEN SP SYSLOG-MSG", see RFC 6587 section 3.4.1
+func (s *SyslogServer) readOctetCounted(reader *bufio.Reader, logger *log.Entry) ([]byte, error) {
+	length := 0
+
+	for digits := 0; ; digits++ {
+		c, err := reader.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		if c == ' ' && digits > 0 {
+			break
+		}
+
+		if c < '0' || c > '9' || digits == maxOctetCountDigits {
+			// there is no way to find the next message
+			return nil, errors.New("invalid octet count")
+		}
+
+		length = length*10 + int(c-'0')
+	}
+
+	msg := make([]byte, min(length, s.MaxMessageLen))
+
+	if _, err := io.ReadFull(reader, msg); err != nil {
+		return nil, err
+	}
+
+	if length > len(msg) {
+		logger.Warnf("message of %d bytes truncated to %d bytes", length, len(msg))
+
+		if _, err := reader.Discard(length - len(msg)); err != nil {
+			return nil, err
+		}
+	}
+
+	return msg, nil
+}
+
+// readLine reads a message terminated by a newline, see RFC 6587 section 3.4.2
+func (s *SyslogServer) readLine(reader *bufio.Reader, logger *log.Entry) ([]byte, error) {
+	line, err := reader.ReadSlice('\n')
+	// the buffer is reused by the next read
+	msg := bytes.Clone(line)
+
+	if errors.Is(err, bufio.ErrBufferFull) {
+		logger.Warnf("message truncated to %d bytes", len(msg))
+
+		for errors.Is(err, bufio.ErrBufferFull) {
+			_, err = reader.ReadSlice('\n')
+		}
+	}
+
+	// the last message may not have a newline
+	if err != nil && !(errors.Is(err, io.EOF) && len(msg) > 0) {
+		return nil, err
+	}
+
+	return bytes.TrimRight(msg, "\r\n"), nil
+}
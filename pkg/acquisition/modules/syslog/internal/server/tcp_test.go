@@ -0,0 +1,102 @@
+package syslogserver
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/crowdsecurity/go-cs-lib/cstest"
+)
+
+func TestReadFrame(t *testing.T) {
+	tests := []struct {
+		name        string
+		framing     string
+		input       string
+		expected    []string
+		expectedErr string
+	}{
+		{
+			name:     "newline",
+			framing:  FramingNewline,
+			input:    "<13>first\n<13>second\r\n\n<13>last",
+			expected: []string{"<13>first", "<13>second", "", "<13>last"},
+		},
+		{
+			name:     "octet counting",
+			framing:  FramingOctetCounting,
+			input:    "9 <13>first11 <13>second\n6 <13>la",
+			expected: []string{"<13>first", "<13>second\n", "<13>la"},
+		},
+		{
+			name:     "auto",
+			framing:  FramingAuto,
+			input:    "9 <13>first<13>second\n9 <13>third",
+			expected: []string{"<13>first", "<13>second", "<13>third"},
+		},
+		{
+			name:     "newline truncated",
+			framing:  FramingNewline,
+			input:    "<13>" + strings.Repeat("a", 30) + "\n<13>next\n",
+			expected: []string{"<13>" + strings.Repeat("a", 16), "<13>next"},
+		},
+		{
+			name:     "octet counting truncated",
+			framing:  FramingOctetCounting,
+			input:    "34 <13>" + strings.Repeat("a", 30) + "8 <13>next",
+			expected: []string{"<13>" + strings.Repeat("a", 16), "<13>next"},
+		},
+		{
+			name:        "invalid octet count",
+			framing:     FramingOctetCounting,
+			input:       "12a <13>first",
+			expectedErr: "invalid octet count",
+		},
+		{
+			name:        "octet count too long",
+			framing:     FramingOctetCounting,
+			input:       "1234567890 <13>first",
+			expectedErr: "invalid octet count",
+		},
+		{
+			name:        "short message",
+			framing:     FramingOctetCounting,
+			input:       "20 <13>first",
+			expectedErr: "unexpected EOF",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := SyslogServer{Framing: tc.framing, MaxMessageLen: 20}
+			reader := bufio.NewReaderSize(strings.NewReader(tc.input), s.MaxMessageLen)
+			logger := log.WithField("test", tc.name)
+
+			actual := []string{}
+
+			for {
+				msg, err := s.readFrame(reader, logger)
+				if errors.Is(err, io.EOF) {
+					break
+				}
+
+				cstest.RequireErrorContains(t, err, tc.expectedErr)
+
+				if err != nil {
+					break
+				}
+
+				actual = append(actual, string(msg))
+			}
+
+			if tc.expectedErr == "" {
+				assert.Equal(t, tc.expected, actual)
+			}
+		})
+	}
+}
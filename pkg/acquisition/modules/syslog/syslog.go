@@ -3,9 +3,12 @@ package syslogacquisition
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"net"
+	"os"
 	"strings"
 	"time"
 
@@ -14,6 +17,7 @@ import (
 	"gopkg.in/tomb.v2"
 	"gopkg.in/yaml.v2"
 
+	"github.com/crowdsecurity/go-cs-lib/ptr"
 	"github.com/crowdsecurity/go-cs-lib/trace"
 
 	"github.com/crowdsecurity/crowdsec/pkg/acquisition/configuration"
@@ -23,19 +27,35 @@ import (
 	"github.com/crowdsecurity/crowdsec/pkg/types"
 )
 
+const (
+	defaultIdleTimeout    = 10 * time.Minute
+	defaultMaxConnections = 1024
+)
+
 type SyslogConfiguration struct {
-	Proto                             string `yaml:"protocol,omitempty"`
-	Port                              int    `yaml:"listen_port,omitempty"`
-	Addr                              string `yaml:"listen_addr,omitempty"`
-	MaxMessageLen                     int    `yaml:"max_message_len,omitempty"`
-	DisableRFCParser                  bool   `yaml:"disable_rfc_parser,omitempty"` // if true, we don't try to be smart and just remove the PRI
+	Proto                             string         `yaml:"protocol,omitempty"` // udp (default) or tcp
+	Port                              int            `yaml:"listen_port,omitempty"`
+	Addr                              string         `yaml:"listen_addr,omitempty"`
+	MaxMessageLen                     int            `yaml:"max_message_len,omitempty"`
+	DisableRFCParser                  bool           `yaml:"disable_rfc_parser,omitempty"` // if true, we don't try to be smart and just remove the PRI
+	Framing                           string         `yaml:"framing,omitempty"`            // tcp only: auto (default), newline or octet_counting
+	TLS                               *TLSConfig     `yaml:"tls,omitempty"`                // tcp only
+	IdleTimeout                       *time.Duration `yaml:"idle_timeout,omitempty"`       // tcp only: close the connections without a message for this long, 0 to keep them
+	MaxConnections                    int            `yaml:"max_connections,omitempty"`    // tcp only: refuse the connections beyond this number
 	configuration.DataSourceCommonCfg `yaml:",inline"`
 }
 
+type TLSConfig struct {
+	ServerCert string `yaml:"server_cert"`
+	ServerKey  string `yaml:"server_key"`
+	CaCert     string `yaml:"ca_cert"` // if set, the clients must present a certificate signed by this CA
+}
+
 type SyslogSource struct {
 	metricsLevel int
 	config       SyslogConfiguration
 	logger       *log.Entry
+	tlsConfig    *tls.Config
 	server       *syslogserver.SyslogServer
 	serverTomb   *tomb.Tomb
 }
@@ -116,6 +136,9 @@ func (s *SyslogSource) UnmarshalConfig(yamlConfig []byte) error {
 	if s.config.MaxMessageLen == 0 {
 		s.config.MaxMessageLen = 2048
 	}
+	if s.config.Proto == "" {
+		s.config.Proto = "udp"
+	}
 	if !validatePort(s.config.Port) {
 		return fmt.Errorf("invalid port %d", s.config.Port)
 	}
@@ -123,9 +146,90 @@ func (s *SyslogSource) UnmarshalConfig(yamlConfig []byte) error {
 		return fmt.Errorf("invalid listen IP %s", s.config.Addr)
 	}
 
+	switch s.config.Proto {
+	case "udp":
+		if s.config.Framing != "" {
+			return errors.New("framing is only supported with protocol tcp")
+		}
+
+		if s.config.TLS != nil {
+			return errors.New("tls is only supported with protocol tcp")
+		}
+
+		if s.config.IdleTimeout != nil || s.config.MaxConnections != 0 {
+			return errors.New("idle_timeout and max_connections are only supported with protocol tcp")
+		}
+	case "tcp":
+		if s.config.IdleTimeout == nil {
+			s.config.IdleTimeout = ptr.Of(defaultIdleTimeout)
+		}
+
+		if *s.config.IdleTimeout < 0 {
+			return errors.New("idle_timeout can't be negative")
+		}
+
+		if s.config.MaxConnections == 0 {
+			s.config.MaxConnections = defaultMaxConnections
+		}
+
+		if s.config.MaxConnections < 0 {
+			return errors.New("max_connections must be positive")
+		}
+
+		switch s.config.Framing {
+		case "":
+			s.config.Framing = syslogserver.FramingAuto
+		case syslogserver.FramingAuto, syslogserver.FramingNewline, syslogserver.FramingOctetCounting:
+		default:
+			return fmt.Errorf("invalid framing %s, must be one of %s, %s, %s", s.config.Framing,
+				syslogserver.FramingAuto, syslogserver.FramingNewline, syslogserver.FramingOctetCounting)
+		}
+	default:
+		return fmt.Errorf("invalid protocol %s, must be udp or tcp", s.config.Proto)
+	}
+
+	if s.config.TLS != nil {
+		if s.config.TLS.ServerCert == "" {
+			return errors.New("tls.server_cert is required")
+		}
+
+		if s.config.TLS.ServerKey == "" {
+			return errors.New("tls.server_key is required")
+		}
+	}
+
 	return nil
 }
 
+func (s *SyslogSource) newTLSConfig() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(s.config.TLS.ServerCert, s.config.TLS.ServerKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server cert/key: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if s.config.TLS.CaCert != "" {
+		caCert, err := os.ReadFile(s.config.TLS.CaCert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca cert: %w", err)
+		}
+
+		caCertPool := x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no certificate found in %s", s.config.TLS.CaCert)
+		}
+
+		tlsConfig.ClientCAs = caCertPool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, nil
+}
+
 func (s *SyslogSource) Configure(yamlConfig []byte, logger *log.Entry, metricsLevel int) error {
 	s.logger = logger
 	s.logger.Infof("Starting syslog datasource configuration")
@@ -135,12 +239,29 @@ func (s *SyslogSource) Configure(yamlConfig []byte, logger *log.Entry, metricsLe
 		return err
 	}
 
+	if s.config.TLS != nil {
+		s.tlsConfig, err = s.newTLSConfig()
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 func (s *SyslogSource) StreamingAcquisition(ctx context.Context, out chan types.Event, t *tomb.Tomb) error {
 	c := make(chan syslogserver.SyslogMessage)
-	s.server = &syslogserver.SyslogServer{Logger: s.logger.WithField("syslog", "internal"), MaxMessageLen: s.config.MaxMessageLen}
+	s.server = &syslogserver.SyslogServer{
+		Logger:        s.logger.WithField("syslog", "internal"),
+		MaxMessageLen: s.config.MaxMessageLen,
+		Proto:         s.config.Proto,
+		Framing:       s.config.Framing,
+		TLSConfig:     s.tlsConfig,
+	}
+	if s.config.Proto == "tcp" {
+		s.server.IdleTimeout = *s.config.IdleTimeout
+		s.server.MaxConnections = s.config.MaxConnections
+	}
 	s.server.SetChannel(c)
 	err := s.server.Listen(s.config.Addr, s.config.Port)
 	if err != nil {
@@ -1,8 +1,20 @@
 package syslogacquisition
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"io"
+	"math/big"
 	"net"
+	"os"
+	"path/filepath"
 	"runtime"
 	"testing"
 	"time"
@@ -51,6 +63,77 @@ source: syslog
 listen_addr: 10.0.0`,
 			expectedErr: "invalid listen IP 10.0.0",
 		},
+		{
+			config: `
+source: syslog
+protocol: sctp`,
+			expectedErr: "invalid protocol sctp, must be udp or tcp",
+		},
+		{
+			config: `
+source: syslog
+framing: newline`,
+			expectedErr: "framing is only supported with protocol tcp",
+		},
+		{
+			config: `
+source: syslog
+tls:
+  server_cert: cert.pem
+  server_key: key.pem`,
+			expectedErr: "tls is only supported with protocol tcp",
+		},
+		{
+			config: `
+source: syslog
+max_connections: 10`,
+			expectedErr: "idle_timeout and max_connections are only supported with protocol tcp",
+		},
+		{
+			config: `
+source: syslog
+protocol: tcp
+idle_timeout: -1s`,
+			expectedErr: "idle_timeout can't be negative",
+		},
+		{
+			config: `
+source: syslog
+protocol: tcp
+max_connections: -1`,
+			expectedErr: "max_connections must be positive",
+		},
+		{
+			config: `
+source: syslog
+protocol: tcp
+framing: nul`,
+			expectedErr: "invalid framing nul, must be one of auto, newline, octet_counting",
+		},
+		{
+			config: `
+source: syslog
+protocol: tcp
+tls:
+  server_cert: cert.pem`,
+			expectedErr: "tls.server_key is required",
+		},
+		{
+			config: `
+source: syslog
+protocol: tcp
+tls:
+  server_cert: /does/not/exist.pem
+  server_key: /does/not/exist.key`,
+			expectedErr: "failed to load server cert/key",
+		},
+		{
+			config: `
+source: syslog
+protocol: tcp
+framing: octet_counting`,
+			expectedErr: "",
+		},
 	}
 
 	subLogger := log.WithField("type", "syslog")
@@ -193,3 +276,242 @@ disable_rfc_parser: true`,
 		})
 	}
 }
+
+// newTestCert creates a certificate for 127.0.0.1 signed by parent, or a CA if parent is nil
+func newTestCert(t *testing.T, dir string, name string, parent *tls.Certificate) (tls.Certificate, string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	signer, signerKey := template, any(key)
+
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		signer, signerKey = parent.Leaf, parent.PrivateKey
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+
+	leaf, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, name+".pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+
+	keyFile := filepath.Join(dir, name+".key")
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600))
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, certFile, keyFile
+}
+
+func startSyslog(t *testing.T, config string) (chan types.Event, *tomb.Tomb) {
+	t.Helper()
+
+	s := SyslogSource{}
+	require.NoError(t, s.Configure([]byte(config), log.WithField("type", "syslog"), configuration.METRICS_NONE))
+
+	out := make(chan types.Event)
+	tmb := tomb.Tomb{}
+	require.NoError(t, s.StreamingAcquisition(t.Context(), out, &tmb))
+
+	t.Cleanup(func() {
+		tmb.Kill(nil)
+		require.NoError(t, tmb.Wait())
+	})
+
+	return out, &tmb
+}
+
+func readLines(t *testing.T, out chan types.Event, count int) []string {
+	t.Helper()
+
+	ret := []string{}
+
+	for range count {
+		select {
+		case evt := <-out:
+			assert.Equal(t, "127.0.0.1", evt.Line.Src)
+			ret = append(ret, evt.Line.Raw)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timeout waiting for a line, got %v", ret)
+		}
+	}
+
+	return ret
+}
+
+func TestStreamingAcquisitionTCP(t *testing.T) {
+	rfc3164 := "<13>May 18 12:37:56 mantis sshd[49340]: blabla"
+	rfc5424 := "<13>1 2021-05-18T11:58:40.828081+02:00 mantis sshd 49340 - - blabla2"
+
+	octetCounted := func(msg string) string {
+		return fmt.Sprintf("%d %s", len(msg), msg)
+	}
+
+	tests := []struct {
+		name    string
+		framing string
+		payload string
+	}{
+		{
+			name:    "newline",
+			framing: "newline",
+			payload: rfc3164 + "\n" + rfc5424 + "\n",
+		},
+		{
+			name:    "octet counting",
+			framing: "octet_counting",
+			payload: octetCounted(rfc3164) + octetCounted(rfc5424),
+		},
+		{
+			name:    "auto",
+			framing: "auto",
+			payload: octetCounted(rfc3164) + rfc5424 + "\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			out, _ := startSyslog(t, `source: syslog
+protocol: tcp
+listen_port: 4243
+listen_addr: 127.0.0.1
+framing: `+tc.framing)
+
+			conn, err := net.Dial("tcp", "127.0.0.1:4243")
+			require.NoError(t, err)
+
+			_, err = fmt.Fprint(conn, tc.payload)
+			require.NoError(t, err)
+			require.NoError(t, conn.Close())
+
+			assert.Equal(t, []string{
+				"May 18 12:37:56 mantis sshd[49340]: blabla",
+				"May 18 11:58:40 mantis sshd[49340]: blabla2",
+			}, readLines(t, out, 2))
+		})
+	}
+}
+
+func TestStreamingAcquisitionTCPLimits(t *testing.T) {
+	out, _ := startSyslog(t, `source: syslog
+protocol: tcp
+listen_port: 4245
+listen_addr: 127.0.0.1
+idle_timeout: 500ms
+max_connections: 1`)
+
+	first, err := net.Dial("tcp", "127.0.0.1:4245")
+	require.NoError(t, err)
+
+	defer first.Close()
+
+	// let the server accept the first connection
+	time.Sleep(100 * time.Millisecond)
+
+	// the second connection is closed right away
+	second, err := net.Dial("tcp", "127.0.0.1:4245")
+	require.NoError(t, err)
+
+	defer second.Close()
+
+	require.NoError(t, second.SetReadDeadline(time.Now().Add(2*time.Second)))
+	_, err = second.Read(make([]byte, 1))
+	require.ErrorIs(t, err, io.EOF)
+
+	// the first one is closed once idle, messages keep it open
+	for range 3 {
+		time.Sleep(200 * time.Millisecond)
+
+		_, err = fmt.Fprint(first, "<13>May 18 12:37:56 mantis sshd[49340]: blabla\n")
+		require.NoError(t, err)
+
+		readLines(t, out, 1)
+	}
+
+	start := time.Now()
+
+	require.NoError(t, first.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, err = first.Read(make([]byte, 1))
+	require.ErrorIs(t, err, io.EOF)
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
+func TestStreamingAcquisitionTLS(t *testing.T) {
+	dir := t.TempDir()
+
+	ca, caFile, _ := newTestCert(t, dir, "ca", nil)
+	_, certFile, keyFile := newTestCert(t, dir, "server", &ca)
+	client, _, _ := newTestCert(t, dir, "client", &ca)
+	// not signed by the CA
+	other, _, _ := newTestCert(t, dir, "other", nil)
+
+	out, _ := startSyslog(t, fmt.Sprintf(`source: syslog
+protocol: tcp
+listen_port: 4244
+listen_addr: 127.0.0.1
+tls:
+  server_cert: %s
+  server_key: %s
+  ca_cert: %s`, certFile, keyFile, caFile))
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Leaf)
+
+	send := func(cert tls.Certificate, msg string) error {
+		conn, err := tls.Dial("tcp", "127.0.0.1:4244", &tls.Config{
+			RootCAs:      pool,
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		})
+		if err != nil {
+			return err
+		}
+
+		defer conn.Close()
+
+		if _, err := fmt.Fprintf(conn, "%d %s", len(msg), msg); err != nil {
+			return err
+		}
+
+		// with TLS 1.3, the client learns that its certificate was rejected when it reads
+		_ = conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+		_, err = conn.Read(make([]byte, 1))
+
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return nil
+		}
+
+		return err
+	}
+
+	require.NoError(t, send(client, "<13>May 18 12:37:56 mantis sshd[49340]: trusted"))
+	assert.Equal(t, []string{"May 18 12:37:56 mantis sshd[49340]: trusted"}, readLines(t, out, 1))
+
+	require.Error(t, send(other, "<13>May 18 12:37:56 mantis sshd[49340]: untrusted"))
+
+	select {
+	case evt := <-out:
+		t.Fatalf("unexpected line %s", evt.Line.Raw)
+	case <-time.After(500 * time.Millisecond):
+	}
+}
@@ -0,0 +1,237 @@
+package acquisition
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	tomb "gopkg.in/tomb.v2"
+
+	"github.com/crowdsecurity/go-cs-lib/trace"
+
+	"github.com/crowdsecurity/crowdsec/pkg/acquisition/configuration"
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+const (
+	defaultMultilineMaxLines     = 500
+	defaultMultilineFlushTimeout = time.Second
+)
+
+var multilineRuntimes = map[string]*multilineRuntime{}
+
+type multilineRuntime struct {
+	start        *regexp.Regexp
+	continuation *regexp.Regexp
+	maxLines     int
+	flushTimeout time.Duration
+}
+
+func compileMultiline(cfg *configuration.MultilineCfg) (*multilineRuntime, error) {
+	if cfg.StartPattern == "" && cfg.ContinuationPattern == "" {
+		return nil, errors.New("start_pattern or continuation_pattern is required")
+	}
+
+	if cfg.MaxLines < 0 {
+		return nil, errors.New("max_lines must be positive")
+	}
+
+	if cfg.FlushTimeout < 0 {
+		return nil, errors.New("flush_timeout must be positive")
+	}
+
+	rt := &multilineRuntime{
+		maxLines:     cfg.MaxLines,
+		flushTimeout: cfg.FlushTimeout,
+	}
+
+	if rt.maxLines == 0 {
+		rt.maxLines = defaultMultilineMaxLines
+	}
+
+	if rt.flushTimeout == 0 {
+		rt.flushTimeout = defaultMultilineFlushTimeout
+	}
+
+	var err error
+
+	if cfg.StartPattern != "" {
+		if rt.start, err = regexp.Compile(cfg.StartPattern); err != nil {
+			return nil, fmt.Errorf("invalid start_pattern: %w", err)
+		}
+	}
+
+	if cfg.ContinuationPattern != "" {
+		if rt.continuation, err = regexp.Compile(cfg.ContinuationPattern); err != nil {
+			return nil, fmt.Errorf("invalid continuation_pattern: %w", err)
+		}
+	}
+
+	return rt, nil
+}
+
+// continues returns true if the line must be appended to the event being assembled
+func (rt *multilineRuntime) continues(line string) bool {
+	if rt.start != nil && rt.start.MatchString(line) {
+		return false
+	}
+
+	if rt.continuation != nil {
+		return rt.continuation.MatchString(line)
+	}
+
+	return true
+}
+
+// multilineBuffer holds the lines of an event being assembled, for a given source
+type multilineBuffer struct {
+	evt      types.Event
+	lines    []string
+	acks     []func()
+	deadline time.Time
+}
+
+func newMultilineBuffer(evt types.Event) *multilineBuffer {
+	buf := &multilineBuffer{evt: evt}
+	buf.add(evt)
+
+	return buf
+}
+
+func (b *multilineBuffer) add(evt types.Event) {
+	b.lines = append(b.lines, evt.Line.Raw)
+
+	if evt.Line.Ack != nil {
+		b.acks = append(b.acks, evt.Line.Ack)
+	}
+}
+
+// event returns the first event of the buffer, with all the lines joined
+func (b *multilineBuffer) event() types.Event {
+	evt := b.evt
+	evt.Line.Raw = strings.Join(b.lines, "\n")
+	evt.Line.Ack = nil
+
+	switch len(b.acks) {
+	case 0:
+	case 1:
+		evt.Line.Ack = b.acks[0]
+	default:
+		acks := b.acks
+		evt.Line.Ack = func() {
+			for _, ack := range acks {
+				ack()
+			}
+		}
+	}
+
+	return evt
+}
+
+// multiline joins the lines received from a datasource into events, one event being assembled per source.
+// It returns when the acquisition is dying, or when the input channel is closed after flushing the
+// pending events.
+func multiline(input chan types.Event, output chan types.Event, acquisTomb *tomb.Tomb, rt *multilineRuntime, logger *log.Entry) {
+	defer trace.CatchPanic("crowdsec/acquis")
+	logger.Infof("multiline assembler started")
+
+	buffers := map[string]*multilineBuffer{}
+
+	timer := time.NewTimer(rt.flushTimeout)
+	defer timer.Stop()
+
+	send := func(src string) bool {
+		buf := buffers[src]
+		delete(buffers, src)
+
+		select {
+		case output <- buf.event():
+			return true
+		case <-acquisTomb.Dying():
+			return false
+		}
+	}
+
+	for {
+		select {
+		case <-acquisTomb.Dying():
+			logger.Debugf("multiline assembler is dying")
+			return
+		case evt, ok := <-input:
+			if !ok {
+				logger.Debugf("input closed, flushing %d pending events", len(buffers))
+
+				for src := range buffers {
+					if !send(src) {
+						return
+					}
+				}
+
+				return
+			}
+
+			src := evt.Line.Src
+
+			buf, found := buffers[src]
+
+			switch {
+			case found && rt.continues(evt.Line.Raw):
+				buf.add(evt)
+			case found:
+				if !send(src) {
+					return
+				}
+
+				fallthrough
+			default:
+				buf = newMultilineBuffer(evt)
+				buffers[src] = buf
+			}
+
+			if len(buf.lines) >= rt.maxLines {
+				logger.Tracef("max lines reached for %s", src)
+
+				if !send(src) {
+					return
+				}
+
+				continue
+			}
+
+			buf.deadline = time.Now().Add(rt.flushTimeout)
+		case now := <-timer.C:
+			for src, buf := range buffers {
+				if now.Before(buf.deadline) {
+					continue
+				}
+
+				logger.Tracef("flush timeout reached for %s", src)
+
+				if !send(src) {
+					return
+				}
+			}
+		}
+
+		// wake up for the earliest deadline
+		next := rt.flushTimeout
+
+		for _, buf := range buffers {
+			if d := time.Until(buf.deadline); d < next {
+				next = d
+			}
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+
+		timer.Reset(next)
+	}
+}
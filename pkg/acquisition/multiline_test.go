@@ -0,0 +1,269 @@
+package acquisition
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tomb "gopkg.in/tomb.v2"
+
+	"github.com/crowdsecurity/go-cs-lib/cstest"
+
+	"github.com/crowdsecurity/crowdsec/pkg/acquisition/configuration"
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+func TestCompileMultiline(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         configuration.MultilineCfg
+		expectedErr string
+	}{
+		{
+			name:        "no pattern",
+			cfg:         configuration.MultilineCfg{MaxLines: 10},
+			expectedErr: "start_pattern or continuation_pattern is required",
+		},
+		{
+			name:        "bad start pattern",
+			cfg:         configuration.MultilineCfg{StartPattern: "^(foo"},
+			expectedErr: "invalid start_pattern: error parsing regexp: missing closing ): `^(foo`",
+		},
+		{
+			name:        "bad continuation pattern",
+			cfg:         configuration.MultilineCfg{ContinuationPattern: "[a-"},
+			expectedErr: "invalid continuation_pattern: error parsing regexp: missing closing ]: `[a-`",
+		},
+		{
+			name:        "negative max lines",
+			cfg:         configuration.MultilineCfg{StartPattern: "^foo", MaxLines: -1},
+			expectedErr: "max_lines must be positive",
+		},
+		{
+			name:        "negative flush timeout",
+			cfg:         configuration.MultilineCfg{StartPattern: "^foo", FlushTimeout: -time.Second},
+			expectedErr: "flush_timeout must be positive",
+		},
+		{
+			name: "defaults",
+			cfg:  configuration.MultilineCfg{ContinuationPattern: `^\s`},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rt, err := compileMultiline(&tc.cfg)
+			cstest.RequireErrorContains(t, err, tc.expectedErr)
+
+			if tc.expectedErr != "" {
+				return
+			}
+
+			assert.Equal(t, defaultMultilineMaxLines, rt.maxLines)
+			assert.Equal(t, defaultMultilineFlushTimeout, rt.flushTimeout)
+		})
+	}
+}
+
+func makeLine(src string, raw string) types.Event {
+	evt := types.MakeEvent(false, types.LOG, true)
+	evt.Line.Src = src
+	evt.Line.Raw = raw
+
+	return evt
+}
+
+// runMultiline sends the lines to the assembler, closes the input and returns the assembled lines
+func runMultiline(t *testing.T, cfg configuration.MultilineCfg, lines ...types.Event) []string {
+	t.Helper()
+
+	rt, err := compileMultiline(&cfg)
+	require.NoError(t, err)
+
+	input := make(chan types.Event)
+	output := make(chan types.Event, len(lines))
+	acquisTomb := tomb.Tomb{}
+
+	done := make(chan struct{})
+
+	go func() {
+		multiline(input, output, &acquisTomb, rt, log.WithField("test", t.Name()))
+		close(done)
+	}()
+
+	for _, line := range lines {
+		input <- line
+	}
+
+	close(input)
+	<-done
+	close(output)
+
+	ret := []string{}
+	for evt := range output {
+		ret = append(ret, evt.Line.Raw)
+	}
+
+	return ret
+}
+
+func TestMultilinePatterns(t *testing.T) {
+	javaTrace := []types.Event{
+		makeLine("app.log", "2024-01-02 03:04:05 ERROR something failed"),
+		makeLine("app.log", "java.lang.NullPointerException: oops"),
+		makeLine("app.log", "\tat com.example.Foo.bar(Foo.java:42)"),
+		makeLine("app.log", "\tat com.example.Main.main(Main.java:7)"),
+		makeLine("app.log", "2024-01-02 03:04:06 INFO recovered"),
+	}
+
+	tests := []struct {
+		name     string
+		cfg      configuration.MultilineCfg
+		lines    []types.Event
+		expected []string
+	}{
+		{
+			name:  "start pattern",
+			cfg:   configuration.MultilineCfg{StartPattern: `^\d{4}-\d{2}-\d{2} `},
+			lines: javaTrace,
+			expected: []string{
+				"2024-01-02 03:04:05 ERROR something failed\njava.lang.NullPointerException: oops\n\tat com.example.Foo.bar(Foo.java:42)\n\tat com.example.Main.main(Main.java:7)",
+				"2024-01-02 03:04:06 INFO recovered",
+			},
+		},
+		{
+			name:  "continuation pattern",
+			cfg:   configuration.MultilineCfg{ContinuationPattern: `^\s`},
+			lines: javaTrace,
+			expected: []string{
+				"2024-01-02 03:04:05 ERROR something failed",
+				"java.lang.NullPointerException: oops\n\tat com.example.Foo.bar(Foo.java:42)\n\tat com.example.Main.main(Main.java:7)",
+				"2024-01-02 03:04:06 INFO recovered",
+			},
+		},
+		{
+			name:  "both patterns",
+			cfg:   configuration.MultilineCfg{StartPattern: `^\d{4}-`, ContinuationPattern: `^(\s|java\.)`},
+			lines: append(javaTrace, makeLine("app.log", "garbage"), makeLine("app.log", "\tmore")),
+			expected: []string{
+				"2024-01-02 03:04:05 ERROR something failed\njava.lang.NullPointerException: oops\n\tat com.example.Foo.bar(Foo.java:42)\n\tat com.example.Main.main(Main.java:7)",
+				"2024-01-02 03:04:06 INFO recovered",
+				"garbage\n\tmore",
+			},
+		},
+		{
+			name:  "max lines",
+			cfg:   configuration.MultilineCfg{StartPattern: `^\d{4}-`, MaxLines: 3},
+			lines: javaTrace,
+			expected: []string{
+				"2024-01-02 03:04:05 ERROR something failed\njava.lang.NullPointerException: oops\n\tat com.example.Foo.bar(Foo.java:42)",
+				"\tat com.example.Main.main(Main.java:7)",
+				"2024-01-02 03:04:06 INFO recovered",
+			},
+		},
+		{
+			name: "one event per source",
+			cfg:  configuration.MultilineCfg{ContinuationPattern: `^\s`},
+			lines: []types.Event{
+				makeLine("a.log", "a1"),
+				makeLine("b.log", "b1"),
+				makeLine("a.log", " a2"),
+				makeLine("b.log", " b2"),
+				makeLine("a.log", "a3"),
+			},
+			expected: []string{
+				"a1\n a2",
+				"b1\n b2",
+				"a3",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.ElementsMatch(t, tc.expected, runMultiline(t, tc.cfg, tc.lines...))
+		})
+	}
+}
+
+func TestMultilineFlushTimeout(t *testing.T) {
+	rt, err := compileMultiline(&configuration.MultilineCfg{
+		ContinuationPattern: `^\s`,
+		FlushTimeout:        100 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	input := make(chan types.Event)
+	output := make(chan types.Event)
+	acquisTomb := tomb.Tomb{}
+
+	acquisTomb.Go(func() error {
+		multiline(input, output, &acquisTomb, rt, log.WithField("test", t.Name()))
+		return nil
+	})
+
+	acked := 0
+	ack := func() { acked++ }
+
+	for _, raw := range []string{"first", " second", " third"} {
+		evt := makeLine("app.log", raw)
+		evt.Line.Ack = ack
+		input <- evt
+	}
+
+	select {
+	case evt := <-output:
+		assert.Equal(t, "first\n second\n third", evt.Line.Raw)
+		assert.Equal(t, "app.log", evt.Line.Src)
+
+		// acknowledging the event acknowledges all of its lines
+		require.NotNil(t, evt.Line.Ack)
+		evt.Line.Ack()
+		assert.Equal(t, 3, acked)
+	case <-time.After(2 * time.Second):
+		t.Fatal("event was not flushed")
+	}
+
+	acquisTomb.Kill(nil)
+	require.NoError(t, acquisTomb.Wait())
+}
+
+type MockMultilineCat struct {
+	MockCat
+}
+
+func (f *MockMultilineCat) GetUuid() string { return "multiline-cat" }
+
+func (f *MockMultilineCat) OneShotAcquisition(_ context.Context, out chan types.Event, _ *tomb.Tomb) error {
+	for _, raw := range []string{"begin 1", "end 1", "begin 2", "end 2"} {
+		out <- makeLine("test", raw)
+	}
+
+	return nil
+}
+
+func TestStartAcquisitionMultiline(t *testing.T) {
+	rt, err := compileMultiline(&configuration.MultilineCfg{StartPattern: "^begin"})
+	require.NoError(t, err)
+
+	multilineRuntimes["multiline-cat"] = rt
+
+	t.Cleanup(func() { delete(multilineRuntimes, "multiline-cat") })
+
+	out := make(chan types.Event, 10)
+	acquisTomb := tomb.Tomb{}
+
+	// the acquisition returns once the pending event has been flushed
+	require.NoError(t, StartAcquisition(t.Context(), []DataSource{&MockMultilineCat{}}, out, &acquisTomb))
+	close(out)
+
+	raw := []string{}
+	for evt := range out {
+		raw = append(raw, evt.Line.Raw)
+	}
+
+	assert.Equal(t, []string{"begin 1\nend 1", "begin 2\nend 2"}, raw)
+}
@@ -0,0 +1,12 @@
+//go:build !no_datasource_nats
+
+package acquisition
+
+import (
+	natsacquisition "github.com/crowdsecurity/crowdsec/pkg/acquisition/modules/nats"
+)
+
+//nolint:gochecknoinits
+func init() {
+	registerDataSource("nats", func() DataSource { return &natsacquisition.NatsSource{} })
+}
@@ -0,0 +1,12 @@
+//go:build !no_datasource_otlp
+
+package acquisition
+
+import (
+	otlpacquisition "github.com/crowdsecurity/crowdsec/pkg/acquisition/modules/otlp"
+)
+
+//nolint:gochecknoinits
+func init() {
+	registerDataSource("otlp", func() DataSource { return &otlpacquisition.OtlpSource{} })
+}
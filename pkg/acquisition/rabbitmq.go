@@ -0,0 +1,12 @@
+//go:build !no_datasource_rabbitmq
+
+package acquisition
+
+import (
+	rabbitmqacquisition "github.com/crowdsecurity/crowdsec/pkg/acquisition/modules/rabbitmq"
+)
+
+//nolint:gochecknoinits
+func init() {
+	registerDataSource("rabbitmq", func() DataSource { return &rabbitmqacquisition.RabbitMQSource{} })
+}
@@ -0,0 +1,197 @@
+package acquisition
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	tomb "gopkg.in/tomb.v2"
+
+	"github.com/crowdsecurity/go-cs-lib/trace"
+
+	"github.com/crowdsecurity/crowdsec/pkg/acquisition/configuration"
+	"github.com/crowdsecurity/crowdsec/pkg/time/rate"
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+const defaultRateLimitQueueSize = 1000
+
+var LinesDropped = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "cs_acquisition_lines_dropped_total",
+		Help: "Total lines dropped by the rate limit or because the parsers were behind.",
+	},
+	[]string{"datasource", "reason"},
+)
+
+var LinesSampled = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "cs_acquisition_lines_sampled_total",
+		Help: "Total lines discarded by sampling.",
+	},
+	[]string{"datasource"},
+)
+
+var rateLimitRuntimes = map[string]*rateLimitRuntime{}
+
+type rateLimitRuntime struct {
+	// name of the datasource in the metrics
+	name        string
+	limiter     *rate.Limiter
+	sampleRatio float64
+	drop        bool
+	queueSize   int
+}
+
+func compileRateLimit(name string, cfg *configuration.RateLimitCfg) (*rateLimitRuntime, error) {
+	if cfg.EventsPerSecond < 0 {
+		return nil, errors.New("events_per_second must be positive")
+	}
+
+	if cfg.Burst < 0 {
+		return nil, errors.New("burst must be positive")
+	}
+
+	if cfg.SampleRatio < 0 || cfg.SampleRatio > 1 {
+		return nil, errors.New("sample_ratio must be between 0 and 1")
+	}
+
+	if cfg.QueueSize < 0 {
+		return nil, errors.New("queue_size must be positive")
+	}
+
+	rt := &rateLimitRuntime{
+		name:        name,
+		sampleRatio: cfg.SampleRatio,
+		queueSize:   cfg.QueueSize,
+	}
+
+	switch cfg.Policy {
+	case "", configuration.RATE_LIMIT_BLOCK:
+	case configuration.RATE_LIMIT_DROP:
+		rt.drop = true
+	default:
+		return nil, fmt.Errorf("unknown policy '%s', must be %s or %s", cfg.Policy, configuration.RATE_LIMIT_BLOCK, configuration.RATE_LIMIT_DROP)
+	}
+
+	if rt.sampleRatio == 0 {
+		rt.sampleRatio = 1
+	}
+
+	if rt.queueSize == 0 {
+		rt.queueSize = defaultRateLimitQueueSize
+	}
+
+	if cfg.EventsPerSecond > 0 {
+		burst := cfg.Burst
+		if burst == 0 {
+			burst = int(math.Ceil(cfg.EventsPerSecond))
+		}
+
+		rt.limiter = rate.NewLimiter(rate.Limit(cfg.EventsPerSecond), burst)
+	}
+
+	return rt, nil
+}
+
+// discard counts an event that is not sent to the parsers. It is acknowledged, so that the
+// datasources that can replay their events don't send it again.
+func discard(evt types.Event, counter prometheus.Counter) {
+	counter.Inc()
+
+	if evt.Line.Ack != nil {
+		evt.Line.Ack()
+	}
+}
+
+// forward sends the queued events to the parsers, until the queue is closed
+func forward(queue chan types.Event, output chan types.Event, acquisTomb *tomb.Tomb) {
+	for evt := range queue {
+		select {
+		case output <- evt:
+		case <-acquisTomb.Dying():
+			return
+		}
+	}
+}
+
+// rateLimit samples and limits the events received from a datasource. It returns when the acquisition is dying,
+// or when the input channel is closed and the queued events were sent.
+func rateLimit(input chan types.Event, output chan types.Event, acquisTomb *tomb.Tomb, rt *rateLimitRuntime, logger *log.Entry) {
+	defer trace.CatchPanic("crowdsec/acquis")
+	logger.Infof("rate limiter started")
+
+	// canceled when the tomb is dying
+	ctx := acquisTomb.Context(context.Background())
+
+	sampled := LinesSampled.With(prometheus.Labels{"datasource": rt.name})
+	overRate := LinesDropped.With(prometheus.Labels{"datasource": rt.name, "reason": "rate_limit"})
+	behind := LinesDropped.With(prometheus.Labels{"datasource": rt.name, "reason": "backpressure"})
+
+	var queue chan types.Event
+
+	if rt.drop {
+		queue = make(chan types.Event, rt.queueSize)
+		forwarded := make(chan struct{})
+
+		go func() {
+			defer trace.CatchPanic("crowdsec/acquis")
+			defer close(forwarded)
+			forward(queue, output, acquisTomb)
+		}()
+
+		defer func() {
+			close(queue)
+			<-forwarded
+		}()
+	}
+
+	for {
+		select {
+		case <-acquisTomb.Dying():
+			logger.Debugf("rate limiter is dying")
+			return
+		case evt, ok := <-input:
+			if !ok {
+				return
+			}
+
+			if rt.sampleRatio < 1 && rand.Float64() >= rt.sampleRatio { //nolint:gosec // no need for a secure random
+				discard(evt, sampled)
+				continue
+			}
+
+			if rt.limiter != nil {
+				if rt.drop {
+					if !rt.limiter.Allow() {
+						discard(evt, overRate)
+						continue
+					}
+				} else if err := rt.limiter.Wait(ctx); err != nil {
+					logger.Debugf("rate limiter is dying")
+					return
+				}
+			}
+
+			if rt.drop {
+				select {
+				case queue <- evt:
+				default:
+					discard(evt, behind)
+				}
+
+				continue
+			}
+
+			select {
+			case output <- evt:
+			case <-acquisTomb.Dying():
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package acquisition
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tomb "gopkg.in/tomb.v2"
+
+	"github.com/crowdsecurity/go-cs-lib/cstest"
+
+	"github.com/crowdsecurity/crowdsec/pkg/acquisition/configuration"
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+func TestCompileRateLimit(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         configuration.RateLimitCfg
+		expectedErr string
+	}{
+		{
+			name:        "negative rate",
+			cfg:         configuration.RateLimitCfg{EventsPerSecond: -1},
+			expectedErr: "events_per_second must be positive",
+		},
+		{
+			name:        "negative burst",
+			cfg:         configuration.RateLimitCfg{Burst: -1},
+			expectedErr: "burst must be positive",
+		},
+		{
+			name:        "sample ratio",
+			cfg:         configuration.RateLimitCfg{SampleRatio: 1.5},
+			expectedErr: "sample_ratio must be between 0 and 1",
+		},
+		{
+			name:        "queue size",
+			cfg:         configuration.RateLimitCfg{QueueSize: -1},
+			expectedErr: "queue_size must be positive",
+		},
+		{
+			name:        "policy",
+			cfg:         configuration.RateLimitCfg{Policy: "wait"},
+			expectedErr: "unknown policy 'wait', must be block or drop",
+		},
+		{
+			name: "valid",
+			cfg:  configuration.RateLimitCfg{EventsPerSecond: 2.5, Policy: "drop"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rt, err := compileRateLimit("test", &tc.cfg)
+			cstest.RequireErrorContains(t, err, tc.expectedErr)
+
+			if tc.expectedErr != "" {
+				return
+			}
+
+			assert.True(t, rt.drop)
+			assert.InDelta(t, 1.0, rt.sampleRatio, 0)
+			assert.Equal(t, defaultRateLimitQueueSize, rt.queueSize)
+			assert.Equal(t, 3, rt.limiter.Burst())
+		})
+	}
+}
+
+// startRateLimit runs the rate limiter until the test ends
+func startRateLimit(t *testing.T, cfg configuration.RateLimitCfg, output chan types.Event) chan types.Event {
+	t.Helper()
+
+	rt, err := compileRateLimit(t.Name(), &cfg)
+	require.NoError(t, err)
+
+	LinesDropped.Reset()
+	LinesSampled.Reset()
+
+	input := make(chan types.Event)
+	acquisTomb := tomb.Tomb{}
+
+	acquisTomb.Go(func() error {
+		rateLimit(input, output, &acquisTomb, rt, log.WithField("test", t.Name()))
+		return nil
+	})
+
+	t.Cleanup(func() {
+		acquisTomb.Kill(nil)
+		require.NoError(t, acquisTomb.Wait())
+	})
+
+	return input
+}
+
+func dropped(t *testing.T, reason string) int {
+	t.Helper()
+
+	return int(testutil.ToFloat64(LinesDropped.With(prometheus.Labels{"datasource": t.Name(), "reason": reason})))
+}
+
+func TestRateLimitSample(t *testing.T) {
+	output := make(chan types.Event, 1000)
+	input := startRateLimit(t, configuration.RateLimitCfg{SampleRatio: 0.25}, output)
+
+	var acked atomic.Int32
+
+	for i := range 1000 {
+		evt := makeLine("test", strconv.Itoa(i))
+		evt.Line.Ack = func() { acked.Add(1) }
+		input <- evt
+	}
+
+	sampled := 0
+
+	require.Eventually(t, func() bool {
+		sampled = int(testutil.ToFloat64(LinesSampled.With(prometheus.Labels{"datasource": t.Name()})))
+		return len(output)+sampled == 1000
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// around 250 are kept
+	assert.InDelta(t, 250, len(output), 75)
+	// the events that are not kept are acknowledged
+	assert.Equal(t, sampled, int(acked.Load()))
+}
+
+func TestRateLimitDrop(t *testing.T) {
+	output := make(chan types.Event, 100)
+	input := startRateLimit(t, configuration.RateLimitCfg{EventsPerSecond: 1, Burst: 5, Policy: "drop"}, output)
+
+	for i := range 20 {
+		input <- makeLine("test", strconv.Itoa(i))
+	}
+
+	require.Eventually(t, func() bool {
+		return len(output) == 5
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, 15, dropped(t, "rate_limit"))
+	assert.Equal(t, "0", (<-output).Line.Raw)
+}
+
+func TestRateLimitBackpressure(t *testing.T) {
+	// the parsers don't read anything
+	output := make(chan types.Event)
+	input := startRateLimit(t, configuration.RateLimitCfg{Policy: "drop", QueueSize: 3}, output)
+
+	start := time.Now()
+
+	for i := range 20 {
+		input <- makeLine("test", strconv.Itoa(i))
+	}
+
+	// the datasource is not blocked
+	assert.Less(t, time.Since(start), time.Second)
+
+	// one event is held by the forwarder, up to 3 are in the queue
+	assert.GreaterOrEqual(t, dropped(t, "backpressure"), 16)
+
+	// the parsers catch up
+	assert.Equal(t, "0", (<-output).Line.Raw)
+}
+
+func TestRateLimitBlock(t *testing.T) {
+	output := make(chan types.Event, 100)
+	input := startRateLimit(t, configuration.RateLimitCfg{EventsPerSecond: 20, Burst: 1}, output)
+
+	start := time.Now()
+
+	for i := range 6 {
+		input <- makeLine("test", strconv.Itoa(i))
+	}
+
+	require.Eventually(t, func() bool {
+		return len(output) == 6
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the first event uses the burst, the next ones wait 50ms each
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+	assert.Zero(t, dropped(t, "rate_limit"))
+}
+
+type MockRateLimitCat struct {
+	MockCat
+}
+
+func (f *MockRateLimitCat) GetUuid() string { return "rate-limit-cat" }
+
+func (f *MockRateLimitCat) OneShotAcquisition(_ context.Context, out chan types.Event, _ *tomb.Tomb) error {
+	for i := range 10 {
+		out <- makeLine("test", "begin "+strconv.Itoa(i))
+		out <- makeLine("test", "end "+strconv.Itoa(i))
+	}
+
+	return nil
+}
+
+func TestStartAcquisitionRateLimit(t *testing.T) {
+	rl, err := compileRateLimit("mock_cat", &configuration.RateLimitCfg{EventsPerSecond: 1, Burst: 4, Policy: "drop"})
+	require.NoError(t, err)
+
+	ml, err := compileMultiline(&configuration.MultilineCfg{StartPattern: "^begin"})
+	require.NoError(t, err)
+
+	rateLimitRuntimes["rate-limit-cat"] = rl
+	multilineRuntimes["rate-limit-cat"] = ml
+
+	t.Cleanup(func() {
+		delete(rateLimitRuntimes, "rate-limit-cat")
+		delete(multilineRuntimes, "rate-limit-cat")
+	})
+
+	out := make(chan types.Event, 20)
+	acquisTomb := tomb.Tomb{}
+
+	// the acquisition returns once all the stages are done
+	require.NoError(t, StartAcquisition(t.Context(), []DataSource{&MockRateLimitCat{}}, out, &acquisTomb))
+	close(out)
+
+	raw := []string{}
+	for evt := range out {
+		raw = append(raw, evt.Line.Raw)
+	}
+
+	// the lines are joined before being counted
+	assert.Equal(t, []string{"begin 0\nend 0", "begin 1\nend 1", "begin 2\nend 2", "begin 3\nend 3"}, raw)
+}
@@ -0,0 +1,12 @@
+//go:build !no_datasource_redis
+
+package acquisition
+
+import (
+	redisacquisition "github.com/crowdsecurity/crowdsec/pkg/acquisition/modules/redis"
+)
+
+//nolint:gochecknoinits
+func init() {
+	registerDataSource("redis", func() DataSource { return &redisacquisition.RedisSource{} })
+}
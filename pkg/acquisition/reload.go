@@ -0,0 +1,216 @@
+package acquisition
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	log "github.com/sirupsen/logrus"
+	tomb "gopkg.in/tomb.v2"
+
+	"github.com/crowdsecurity/go-cs-lib/trace"
+
+	"github.com/crowdsecurity/crowdsec/pkg/acquisition/configuration"
+	"github.com/crowdsecurity/crowdsec/pkg/csconfig"
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+// wait for the acquisition files to stop changing before reloading them
+var reloadDelay = time.Second
+
+var (
+	// sourcesMu protects the running datasources, and the runtimes of the stages during a reload
+	sourcesMu      sync.Mutex
+	runningSources = map[string]*runningSource{}
+)
+
+type runningSource struct {
+	source DataSource
+	tomb   *tomb.Tomb
+	stop   chan struct{}
+}
+
+// forgetSource removes what was loaded for a datasource. The caller must hold sourcesMu.
+func forgetSource(uniqueId string) {
+	delete(runningSources, uniqueId)
+	delete(configHashes, uniqueId)
+	delete(transformRuntimes, uniqueId)
+	delete(multilineRuntimes, uniqueId)
+	delete(rateLimitRuntimes, uniqueId)
+}
+
+// stopSource stops a datasource and waits for its pipeline to return. The caller must hold sourcesMu.
+func stopSource(uniqueId string) {
+	rs := runningSources[uniqueId]
+
+	log.Infof("stopping datasource %s (%s)", rs.source.GetName(), uniqueId)
+	close(rs.stop)
+
+	if err := rs.tomb.Wait(); err != nil {
+		log.Debugf("datasource %s returned: %s", rs.source.GetName(), err)
+	}
+
+	forgetSource(uniqueId)
+}
+
+// acquisitionFiles lists the current acquisition files, the directory may have changed since the configuration was loaded
+func acquisitionFiles(config *csconfig.CrowdsecServiceCfg) ([]string, error) {
+	files := []string{}
+
+	if config.AcquisitionFilePath != "" {
+		files = append(files, config.AcquisitionFilePath)
+	}
+
+	if config.AcquisitionDirPath != "" {
+		dirFiles, err := config.AcquisitionDirFiles()
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, dirFiles...)
+	}
+
+	return files, nil
+}
+
+// ReloadAcquisition loads the acquisition files again, stops the datasources whose configuration
+// was removed or changed, and starts the new ones. The other datasources are not interrupted.
+// If the new configuration can't be loaded, the running datasources are kept.
+func ReloadAcquisition(ctx context.Context, config *csconfig.CrowdsecServiceCfg, prom *csconfig.PrometheusCfg, output chan types.Event, acquisTomb *tomb.Tomb) error {
+	files, err := acquisitionFiles(config)
+	if err != nil {
+		return err
+	}
+
+	if !acquisTomb.Alive() {
+		return errors.New("acquisition is stopping")
+	}
+
+	newConfig := *config
+	newConfig.AcquisitionFiles = files
+
+	sourcesMu.Lock()
+	defer sourcesMu.Unlock()
+
+	// the running datasources that are still configured, by configuration hash
+	running := map[string][]string{}
+
+	for uniqueId, rs := range runningSources {
+		select {
+		case <-rs.tomb.Dead():
+			// it has nothing more to read, start it again if it's still configured
+			forgetSource(uniqueId)
+			continue
+		default:
+		}
+
+		hash := configHashes[uniqueId]
+		running[hash] = append(running[hash], uniqueId)
+	}
+
+	unchanged := 0
+
+	reuse := func(hash string) bool {
+		uniqueIds := running[hash]
+		if len(uniqueIds) == 0 {
+			return false
+		}
+
+		running[hash] = uniqueIds[1:]
+		unchanged++
+
+		return true
+	}
+
+	sources, err := loadAcquisitionFromFile(&newConfig, prom, reuse)
+	if err != nil {
+		return err
+	}
+
+	stopped := 0
+
+	for _, uniqueIds := range running {
+		for _, uniqueId := range uniqueIds {
+			stopSource(uniqueId)
+			stopped++
+		}
+	}
+
+	if prom != nil && prom.Enabled {
+		if err := GetMetrics(sources, prom.Level == configuration.CFG_METRICS_AGGREGATE); err != nil {
+			log.Errorf("while fetching prometheus metrics for datasources: %s", err)
+		}
+	}
+
+	for _, src := range sources {
+		log.Infof("starting datasource %s (%s)", src.GetName(), src.GetUuid())
+		startSource(ctx, src, output, acquisTomb)
+	}
+
+	log.Infof("acquisition reloaded: %d datasources unchanged, %d stopped, %d started", unchanged, stopped, len(sources))
+
+	return nil
+}
+
+// isAcquisitionFile tells whether a file of acquisition_dir is loaded
+func isAcquisitionFile(name string) bool {
+	ext := filepath.Ext(name)
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// WatchAcquisitionDir reloads the acquisition when a file of acquisition_dir is created, modified or removed.
+// It returns when the acquisition tomb is dying.
+func WatchAcquisitionDir(ctx context.Context, config *csconfig.CrowdsecServiceCfg, prom *csconfig.PrometheusCfg, output chan types.Event, acquisTomb *tomb.Tomb) error {
+	defer trace.CatchPanic("crowdsec/acquis/reload")
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("could not create fsnotify watcher: %w", err)
+	}
+
+	defer watcher.Close()
+
+	if err = watcher.Add(config.AcquisitionDirPath); err != nil {
+		return fmt.Errorf("could not watch %s: %w", config.AcquisitionDirPath, err)
+	}
+
+	log.Infof("watching %s for acquisition changes", config.AcquisitionDirPath)
+
+	// armed once a change is seen, reset by the next ones
+	timer := time.NewTimer(reloadDelay)
+	timer.Stop()
+
+	for {
+		select {
+		case <-acquisTomb.Dying():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			if !isAcquisitionFile(event.Name) || event.Op == fsnotify.Chmod {
+				continue
+			}
+
+			log.Debugf("acquisition file %s changed (%s)", event.Name, event.Op)
+			timer.Reset(reloadDelay)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+
+			log.Errorf("while watching %s: %s", config.AcquisitionDirPath, err)
+		case <-timer.C:
+			log.Info("acquisition files changed, reloading")
+
+			if err := ReloadAcquisition(ctx, config, prom, output, acquisTomb); err != nil {
+				log.Errorf("acquisition not reloaded, keeping the running datasources: %s", err)
+			}
+		}
+	}
+}
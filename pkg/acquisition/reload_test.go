@@ -0,0 +1,203 @@
+package acquisition
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tomb "gopkg.in/tomb.v2"
+	"gopkg.in/yaml.v2"
+
+	"github.com/crowdsecurity/go-cs-lib/cstest"
+
+	"github.com/crowdsecurity/crowdsec/pkg/acquisition/configuration"
+	"github.com/crowdsecurity/crowdsec/pkg/csconfig"
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+// the datasources that were stopped, by id
+var (
+	reloadStoppedMu sync.Mutex
+	reloadStopped   = map[string]bool{}
+)
+
+// MockReload sends its id when it starts, and records when it stops
+type MockReload struct {
+	configuration.DataSourceCommonCfg `yaml:",inline"`
+	ID                                string `yaml:"id"`
+}
+
+func (f *MockReload) UnmarshalConfig(cfg []byte) error { return yaml.UnmarshalStrict(cfg, f) }
+
+func (f *MockReload) Configure(cfg []byte, _ *log.Entry, _ int) error {
+	if err := f.UnmarshalConfig(cfg); err != nil {
+		return err
+	}
+
+	f.Mode = configuration.TAIL_MODE
+
+	return nil
+}
+
+func (f *MockReload) GetName() string { return "mock_reload" }
+func (f *MockReload) GetMode() string { return f.Mode }
+func (f *MockReload) OneShotAcquisition(context.Context, chan types.Event, *tomb.Tomb) error {
+	return errors.New("can't run in cat mode")
+}
+
+func (f *MockReload) StreamingAcquisition(_ context.Context, out chan types.Event, t *tomb.Tomb) error {
+	t.Go(func() error {
+		select {
+		case out <- makeLine("test", f.ID):
+		case <-t.Dying():
+			return nil
+		}
+
+		<-t.Dying()
+
+		reloadStoppedMu.Lock()
+		reloadStopped[f.ID] = true
+		reloadStoppedMu.Unlock()
+
+		return nil
+	})
+
+	return nil
+}
+func (f *MockReload) CanRun() error                            { return nil }
+func (f *MockReload) GetMetrics() []prometheus.Collector       { return nil }
+func (f *MockReload) GetAggregMetrics() []prometheus.Collector { return nil }
+func (f *MockReload) Dump() interface{}                        { return f }
+func (f *MockReload) ConfigureByDSN(string, map[string]string, *log.Entry, string) error {
+	return errors.New("not supported")
+}
+func (f *MockReload) GetUuid() string { return f.UniqueId }
+
+func isStopped(id string) bool {
+	reloadStoppedMu.Lock()
+	defer reloadStoppedMu.Unlock()
+
+	return reloadStopped[id]
+}
+
+func writeAcquisFile(t *testing.T, dir string, name string, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+}
+
+// readIDs returns the ids sent by the datasources that were started
+func readIDs(t *testing.T, out chan types.Event, count int) []string {
+	t.Helper()
+
+	ret := []string{}
+
+	for range count {
+		select {
+		case evt := <-out:
+			ret = append(ret, evt.Line.Raw)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for a datasource, got %v", ret)
+		}
+	}
+
+	return ret
+}
+
+func TestReloadAcquisition(t *testing.T) {
+	ctx := t.Context()
+
+	AcquisitionSources["mock_reload"] = func() DataSource { return &MockReload{} }
+	t.Cleanup(func() { delete(AcquisitionSources, "mock_reload") })
+
+	dir := t.TempDir()
+
+	writeAcquisFile(t, dir, "a.yaml", "source: mock_reload\nid: a\nlabels:\n  type: test\n")
+	writeAcquisFile(t, dir, "b.yaml", "source: mock_reload\nid: b\nlabels:\n  type: test\n")
+	// identical configurations are distinct datasources
+	writeAcquisFile(t, dir, "c.yml", "source: mock_reload\nid: c\nlabels:\n  type: test\n---\nsource: mock_reload\nid: c\nlabels:\n  type: test\n")
+
+	config := &csconfig.CrowdsecServiceCfg{AcquisitionDirPath: dir}
+
+	files, err := config.AcquisitionDirFiles()
+	require.NoError(t, err)
+
+	config.AcquisitionFiles = files
+
+	sources, err := LoadAcquisitionFromFile(config, nil)
+	require.NoError(t, err)
+	require.Len(t, sources, 4)
+
+	out := make(chan types.Event)
+	acquisTomb := tomb.Tomb{}
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		assert.NoError(t, StartAcquisition(ctx, sources, out, &acquisTomb))
+	}()
+
+	assert.ElementsMatch(t, []string{"a", "b", "c", "c"}, readIDs(t, out, 4))
+
+	// b is modified, one copy of c is removed, d is added
+	writeAcquisFile(t, dir, "b.yaml", "source: mock_reload\nid: b2\nlabels:\n  type: test\n")
+	writeAcquisFile(t, dir, "c.yml", "source: mock_reload\nid: c\nlabels:\n  type: test\n")
+	writeAcquisFile(t, dir, "d.yaml", "source: mock_reload\nid: d\nlabels:\n  type: test\n")
+
+	require.NoError(t, ReloadAcquisition(ctx, config, nil, out, &acquisTomb))
+
+	assert.ElementsMatch(t, []string{"b2", "d"}, readIDs(t, out, 2))
+	assert.True(t, isStopped("b"))
+	assert.True(t, isStopped("c"))
+	assert.False(t, isStopped("a"))
+
+	sourcesMu.Lock()
+	assert.Len(t, runningSources, 4)
+
+	for uniqueId := range runningSources {
+		assert.Contains(t, configHashes, uniqueId)
+	}
+	sourcesMu.Unlock()
+
+	// an invalid configuration is not applied
+	writeAcquisFile(t, dir, "a.yaml", "source: mock_reload\nid: a2\n")
+
+	err = ReloadAcquisition(ctx, config, nil, out, &acquisTomb)
+	cstest.RequireErrorContains(t, err, "missing labels in "+filepath.Join(dir, "a.yaml"))
+	assert.False(t, isStopped("a"))
+
+	// the watcher reloads the acquisition when a file changes
+	reloadDelay = 50 * time.Millisecond
+	t.Cleanup(func() { reloadDelay = time.Second })
+
+	acquisTomb.Go(func() error {
+		return WatchAcquisitionDir(ctx, config, nil, out, &acquisTomb)
+	})
+
+	// give the watcher time to start
+	time.Sleep(100 * time.Millisecond)
+
+	writeAcquisFile(t, dir, "a.yaml", "source: mock_reload\nid: a2\nlabels:\n  type: test\n")
+	require.NoError(t, os.Remove(filepath.Join(dir, "d.yaml")))
+	// not an acquisition file
+	writeAcquisFile(t, dir, "e.txt", "source: mock_reload\nid: e\nlabels:\n  type: test\n")
+
+	assert.Equal(t, []string{"a2"}, readIDs(t, out, 1))
+	assert.True(t, isStopped("a"))
+	assert.True(t, isStopped("d"))
+
+	acquisTomb.Kill(nil)
+	<-done
+
+	assert.True(t, isStopped("b2"))
+	assert.True(t, isStopped("a2"))
+	assert.False(t, isStopped("e"))
+}
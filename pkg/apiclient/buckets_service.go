@@ -0,0 +1,56 @@
+package apiclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type BucketsService service
+
+// BucketPourRequest is sent by a log processor pouring into a bucket that is shared with other log processors
+type BucketPourRequest struct {
+	Key       string `json:"key"`
+	Count     int    `json:"count"`
+	Leakspeed string `json:"leakspeed"`
+}
+
+// BucketPourResponse holds the level of the shared bucket after the pour
+type BucketPourResponse struct {
+	Level float64 `json:"level"`
+}
+
+func (s *BucketsService) Pour(ctx context.Context, pour BucketPourRequest) (*BucketPourResponse, *Response, error) {
+	u := fmt.Sprintf("%s/buckets/pour", s.client.URLPrefix)
+
+	req, err := s.client.NewRequestWithContext(ctx, http.MethodPost, u, &pour)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	response := BucketPourResponse{}
+
+	resp, err := s.client.Do(ctx, req, &response)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &response, resp, nil
+}
+
+func (s *BucketsService) Reset(ctx context.Context, key string) (*Response, error) {
+	u := fmt.Sprintf("%s/buckets/%s", s.client.URLPrefix, url.PathEscape(key))
+
+	req, err := s.client.NewRequestWithContext(ctx, http.MethodDelete, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(ctx, req, nil)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	qs "github.com/google/go-querystring/query"
 	log "github.com/sirupsen/logrus"
@@ -37,6 +38,8 @@ type DecisionsStreamOpts struct {
 	ScenariosContaining    string `url:"scenarios_containing,omitempty"`
 	ScenariosNotContaining string `url:"scenarios_not_containing,omitempty"`
 	Origins                string `url:"origins,omitempty"`
+	// the revision returned by the previous pull, see DecisionsRevision
+	Since int `url:"since,omitempty"`
 }
 
 func (o *DecisionsStreamOpts) addQueryParamsToURL(url string) (string, error) {
@@ -265,6 +268,20 @@ func (s *DecisionsService) GetStream(ctx context.Context, opts DecisionsStreamOp
 	return s.FetchV3Decisions(ctx, u)
 }
 
+// DecisionsRevision returns the revision sent by LAPI with the decision stream, or 0 if LAPI does not support it
+func DecisionsRevision(resp *Response) int {
+	if resp == nil || resp.Response == nil {
+		return 0
+	}
+
+	revision, err := strconv.Atoi(resp.Response.Header.Get("X-Crowdsec-Decisions-Revision"))
+	if err != nil {
+		return 0
+	}
+
+	return revision
+}
+
 func (s *DecisionsService) GetStreamV3(ctx context.Context, opts DecisionsStreamOpts) (*modelscapi.GetDecisionsStreamResponse, *Response, error) {
 	u, err := opts.addQueryParamsToURL(s.client.URLPrefix + "/decisions/stream")
 	if err != nil {
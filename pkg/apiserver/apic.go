@@ -1064,7 +1064,11 @@ func (a *apic) Pull(ctx context.Context) error {
 			toldOnce = true
 		}
 
-		time.Sleep(1 * time.Second)
+		select {
+		case <-time.After(1 * time.Second):
+		case <-ctx.Done(): // the instance is not the leader anymore
+			return nil
+		}
 	}
 
 	if err := a.PullTop(ctx, false); err != nil {
@@ -1087,6 +1091,8 @@ func (a *apic) Pull(ctx context.Context) error {
 			a.metricsTomb.Kill(nil)
 			a.pushTomb.Kill(nil)
 
+			return nil
+		case <-ctx.Done(): // the instance is not the leader anymore
 			return nil
 		}
 	}
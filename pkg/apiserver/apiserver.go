@@ -43,8 +43,12 @@ type APIServer struct {
 	httpServer     *http.Server
 	apic           *apic
 	papi           *Papi
+	leaderElector  *leaderElector
 	httpServerTomb tomb.Tomb
 	consoleConfig  *csconfig.ConsoleConfig
+	// stops FeedDecisionBus, with leader election
+	decisionFeedCancel context.CancelFunc
+	decisionFeedDone   chan struct{}
 }
 
 func isBrokenConnection(maybeError any) bool {
@@ -164,18 +168,37 @@ func newGinLogger(config *csconfig.LocalApiServerCfg) (*log.Logger, string, erro
 // NewServer creates a LAPI server.
 // It sets up a gin router, a database client, and a controller.
 func NewServer(ctx context.Context, config *csconfig.LocalApiServerCfg) (*APIServer, error) {
-	var flushScheduler *gocron.Scheduler
+	var (
+		flushScheduler *gocron.Scheduler
+		elector        *leaderElector
+	)
 
 	dbClient, err := database.NewClient(ctx, config.DbConfig)
 	if err != nil {
 		return nil, fmt.Errorf("unable to init database client: %w", err)
 	}
 
-	if config.DbConfig.Flush != nil {
+	if config.LeaderElection != nil && config.LeaderElection.Enable != nil && *config.LeaderElection.Enable {
+		elector = newLeaderElector(dbClient, config.LeaderElection.InstanceName, config.LeaderElection.LeaseDuration)
+	}
+
+	// the scheduler also records the expired decisions in the change log, it runs without a flush configuration
+	if elector != nil {
+		flushScheduler, err = dbClient.NewFlushScheduler(ctx, config.DbConfig.Flush)
+	} else {
 		flushScheduler, err = dbClient.StartFlushScheduler(ctx, config.DbConfig.Flush)
-		if err != nil {
-			return nil, err
-		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if elector != nil {
+		elector.AddRole(LeaderRoleFlush, func(ctx context.Context) {
+			flushScheduler.StartAsync()
+			<-ctx.Done()
+			flushScheduler.Stop()
+		})
 	}
 
 	if log.GetLevel() < log.DebugLevel {
@@ -237,6 +260,8 @@ func NewServer(ctx context.Context, config *csconfig.LocalApiServerCfg) (*APISer
 		ConsoleConfig:                 config.ConsoleConfig,
 		DisableRemoteLapiRegistration: config.DisableRemoteLapiRegistration,
 		AutoRegisterCfg:               config.AutoRegister,
+		// the other instances add and delete decisions too
+		DecisionChangesBus: elector != nil,
 	}
 
 	var (
@@ -293,6 +318,7 @@ func NewServer(ctx context.Context, config *csconfig.LocalApiServerCfg) (*APISer
 		router:         router,
 		apic:           apiClient,
 		papi:           papiClient,
+		leaderElector:  elector,
 		httpServerTomb: tomb.Tomb{},
 		consoleConfig:  config.ConsoleConfig,
 	}, nil
@@ -340,14 +366,25 @@ func (s *APIServer) papiSync(ctx context.Context) error {
 
 func (s *APIServer) initAPIC(ctx context.Context) {
 	s.apic.pushTomb.Go(func() error { return s.apicPush(ctx) })
-	s.apic.pullTomb.Go(func() error { return s.apicPull(ctx) })
+
+	if s.leaderElector != nil {
+		s.leaderElector.AddRole(LeaderRoleCAPIPull, func(ctx context.Context) { _ = s.apicPull(ctx) })
+	} else {
+		s.apic.pullTomb.Go(func() error { return s.apicPull(ctx) })
+	}
 
 	// csConfig.API.Server.ConsoleConfig.ShareCustomScenarios
 	if s.apic.apiClient.IsEnrolled() {
 		if s.consoleConfig.IsPAPIEnabled() && s.papi != nil {
 			if s.papi.URL != "" {
 				log.Info("Starting PAPI decision receiver")
-				s.papi.pullTomb.Go(func() error { return s.papiPull(ctx) })
+
+				if s.leaderElector != nil {
+					s.leaderElector.AddRole(LeaderRolePAPIPull, func(ctx context.Context) { _ = s.papiPull(ctx) })
+				} else {
+					s.papi.pullTomb.Go(func() error { return s.papiPull(ctx) })
+				}
+
 				s.papi.syncTomb.Go(func() error { return s.papiSync(ctx) })
 			} else {
 				log.Warnf("papi_url is not set in online_api_credentials.yaml, can't synchronize with the console. Run cscli console enable console_management to add it.")
@@ -393,6 +430,11 @@ func (s *APIServer) Run(apiReady chan bool) error {
 		s.initAPIC(ctx)
 	}
 
+	if s.leaderElector != nil {
+		s.leaderElector.Start(ctx)
+		s.startDecisionFeed(ctx)
+	}
+
 	s.httpServerTomb.Go(func() error {
 		return s.listenAndServeLAPI(apiReady)
 	})
@@ -502,7 +544,29 @@ func (s *APIServer) listenAndServeLAPI(apiReady chan bool) error {
 	return nil
 }
 
+// startDecisionFeed pushes the decisions of the change log to the bouncers connected to this instance
+func (s *APIServer) startDecisionFeed(ctx context.Context) {
+	ctx, s.decisionFeedCancel = context.WithCancel(ctx)
+	s.decisionFeedDone = make(chan struct{})
+
+	go func() {
+		defer trace.CatchPanic("lapi/decisionFeed")
+		defer close(s.decisionFeedDone)
+
+		s.controller.HandlerV1.FeedDecisionBus(ctx)
+	}()
+}
+
 func (s *APIServer) Close() {
+	if s.leaderElector != nil {
+		s.leaderElector.Stop() // stop the singleton jobs and release their leases while the database is open
+	}
+
+	if s.decisionFeedCancel != nil {
+		s.decisionFeedCancel()
+		<-s.decisionFeedDone
+	}
+
 	if s.apic != nil {
 		s.apic.Shutdown() // stop apic first since it use dbClient
 	}
@@ -521,6 +585,11 @@ func (s *APIServer) Close() {
 func (s *APIServer) Shutdown() error {
 	s.Close()
 
+	// the decision streams would prevent the server from shutting down
+	if s.controller.HandlerV1 != nil {
+		s.controller.HandlerV1.DecisionBus.Close()
+	}
+
 	if s.httpServer != nil {
 		if err := s.httpServer.Shutdown(context.TODO()); err != nil {
 			return err
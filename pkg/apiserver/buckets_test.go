@@ -0,0 +1,64 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/crowdsecurity/crowdsec/pkg/apiclient"
+)
+
+func TestSharedBuckets(t *testing.T) {
+	ctx := t.Context()
+	lapi := SetupLAPITest(t, ctx)
+
+	pour := func(body string) (int, float64) {
+		w := lapi.RecordResponse(t, ctx, http.MethodPost, "/v1/buckets/pour", strings.NewReader(body), passwordAuthType)
+		if w.Code != http.StatusOK {
+			return w.Code, 0
+		}
+
+		resp := apiclient.BucketPourResponse{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+		return w.Code, resp.Level
+	}
+
+	// two agents pouring into the same partition
+	code, level := pour(`{"key": "abcd", "count": 1, "leakspeed": "10m"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.InDelta(t, 1, level, 0.01)
+
+	code, level = pour(`{"key": "abcd", "count": 2, "leakspeed": "10m"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.InDelta(t, 3, level, 0.01)
+
+	code, level = pour(`{"key": "efgh", "count": 1, "leakspeed": "10m"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.InDelta(t, 1, level, 0.01)
+
+	w := lapi.RecordResponse(t, ctx, http.MethodDelete, "/v1/buckets/abcd", emptyBody, passwordAuthType)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	code, level = pour(`{"key": "abcd", "count": 1, "leakspeed": "10m"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.InDelta(t, 1, level, 0.01)
+
+	// bad requests
+	code, _ = pour(`{"key": "abcd", "count": 1, "leakspeed": "fast"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = pour(`{"count": 1, "leakspeed": "10m"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = pour(`{"key": "abcd", "count": 0, "leakspeed": "10m"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// bouncers can't pour
+	w = lapi.RecordResponse(t, ctx, http.MethodPost, "/v1/buckets/pour", strings.NewReader(`{"key": "abcd", "count": 1, "leakspeed": "10m"}`), apiKeyAuthType)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -29,6 +29,8 @@ type Controller struct {
 	HandlerV1                     *v1.Controller
 	AutoRegisterCfg               *csconfig.LocalAPIAutoRegisterCfg
 	DisableRemoteLapiRegistration bool
+	// push the decisions of the change log to the bouncers, see v1.FeedDecisionBus
+	DecisionChangesBus bool
 }
 
 func (c *Controller) Init() error {
@@ -88,6 +90,7 @@ func (c *Controller) NewV1() error {
 		ConsoleConfig:      *c.ConsoleConfig,
 		TrustedIPs:         c.TrustedIPs,
 		AutoRegisterCfg:    c.AutoRegisterCfg,
+		DecisionChangesBus: c.DecisionChangesBus,
 	}
 
 	c.HandlerV1, err = v1.New(&v1Config)
@@ -127,6 +130,8 @@ func (c *Controller) NewV1() error {
 		jwtAuth.GET("/allowlists/:allowlist_name", c.HandlerV1.GetAllowlist)
 		jwtAuth.GET("/allowlists/check/:ip_or_range", c.HandlerV1.CheckInAllowlist)
 		jwtAuth.HEAD("/allowlists/check/:ip_or_range", c.HandlerV1.CheckInAllowlist)
+		jwtAuth.POST("/buckets/pour", c.HandlerV1.PourSharedBucket)
+		jwtAuth.DELETE("/buckets/:bucket_key", c.HandlerV1.ResetSharedBucket)
 	}
 
 	apiKeyAuth := groupV1.Group("")
@@ -136,6 +141,7 @@ func (c *Controller) NewV1() error {
 		apiKeyAuth.HEAD("/decisions", c.HandlerV1.GetDecision)
 		apiKeyAuth.GET("/decisions/stream", c.HandlerV1.StreamDecision)
 		apiKeyAuth.HEAD("/decisions/stream", c.HandlerV1.StreamDecision)
+		apiKeyAuth.GET("/decisions/events", c.HandlerV1.StreamDecisionEvents)
 	}
 
 	eitherAuth := groupV1.Group("")
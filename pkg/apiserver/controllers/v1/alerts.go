@@ -280,6 +280,8 @@ func (c *Controller) CreateAlert(gctx *gin.Context) {
 		return
 	}
 
+	c.publishAlertDecisions(ctx, alerts)
+
 	if c.AlertsAddChan != nil {
 		select {
 		case c.AlertsAddChan <- alertsToSave:
@@ -0,0 +1,54 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/crowdsecurity/crowdsec/pkg/apiclient"
+)
+
+// PourSharedBucket pours into a bucket shared by several log processors and returns its level
+func (c *Controller) PourSharedBucket(gctx *gin.Context) {
+	var input apiclient.BucketPourRequest
+
+	if err := gctx.ShouldBindJSON(&input); err != nil {
+		gctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	if input.Key == "" {
+		gctx.JSON(http.StatusBadRequest, gin.H{"message": "key is required"})
+		return
+	}
+
+	if input.Count <= 0 {
+		gctx.JSON(http.StatusBadRequest, gin.H{"message": "count must be positive"})
+		return
+	}
+
+	leakspeed, err := time.ParseDuration(input.Leakspeed)
+	if err != nil {
+		gctx.JSON(http.StatusBadRequest, gin.H{"message": "invalid leakspeed: " + err.Error()})
+		return
+	}
+
+	level, err := c.DBClient.PourSharedBucket(gctx.Request.Context(), input.Key, input.Count, leakspeed)
+	if err != nil {
+		gctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	gctx.JSON(http.StatusOK, apiclient.BucketPourResponse{Level: level})
+}
+
+// ResetSharedBucket empties a shared bucket, typically once it has overflowed
+func (c *Controller) ResetSharedBucket(gctx *gin.Context) {
+	if err := c.DBClient.ResetSharedBucket(gctx.Request.Context(), gctx.Param("bucket_key")); err != nil {
+		gctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	gctx.Status(http.StatusOK)
+}
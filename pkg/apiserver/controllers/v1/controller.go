@@ -21,6 +21,9 @@ type Controller struct {
 	AlertsAddChan      chan []*models.Alert
 	DecisionDeleteChan chan []*models.Decision
 
+	// DecisionBus pushes the new and deleted decisions to the bouncers
+	DecisionBus *DecisionBus
+
 	PluginChannel   chan csplugin.ProfileAlert
 	ConsoleConfig   csconfig.ConsoleConfig
 	TrustedIPs      []net.IPNet
@@ -38,6 +41,9 @@ type ControllerV1Config struct {
 	ConsoleConfig   csconfig.ConsoleConfig
 	TrustedIPs      []net.IPNet
 	AutoRegisterCfg *csconfig.LocalAPIAutoRegisterCfg
+
+	// the decision bus is fed by the change log, when several LAPI instances share the database
+	DecisionChangesBus bool
 }
 
 func New(cfg *ControllerV1Config) (*Controller, error) {
@@ -48,18 +54,27 @@ func New(cfg *ControllerV1Config) (*Controller, error) {
 		return &Controller{}, fmt.Errorf("failed to compile profiles: %w", err)
 	}
 
+	for _, profile := range profiles {
+		profile.DBClient = cfg.DbClient
+	}
+
 	v1 := &Controller{
 		DBClient:           cfg.DbClient,
 		APIKeyHeader:       middlewares.APIKeyHeader,
 		Profiles:           profiles,
 		AlertsAddChan:      cfg.AlertsAddChan,
 		DecisionDeleteChan: cfg.DecisionDeleteChan,
+		DecisionBus:        NewDecisionBus(defaultDecisionBusHistory),
 		PluginChannel:      cfg.PluginChannel,
 		ConsoleConfig:      cfg.ConsoleConfig,
 		TrustedIPs:         cfg.TrustedIPs,
 		AutoRegisterCfg:    cfg.AutoRegisterCfg,
 	}
 
+	if cfg.DecisionChangesBus {
+		v1.DecisionBus = NewDecisionChangesBus(defaultDecisionBusHistory)
+	}
+
 	v1.Middlewares, err = middlewares.NewMiddlewares(cfg.DbClient)
 	if err != nil {
 		return v1, err
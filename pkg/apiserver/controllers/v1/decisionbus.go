@@ -0,0 +1,345 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/decisionchange"
+	"github.com/crowdsecurity/crowdsec/pkg/models"
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+const (
+	DecisionEventNew     = "new"
+	DecisionEventDeleted = "deleted"
+	// sent after the current decisions, when the stream could not be resumed
+	DecisionEventSynced = "synced"
+)
+
+const (
+	// the events kept to resume a stream after a reconnection
+	defaultDecisionBusHistory = 10000
+	// the events waiting to be sent to a bouncer, it is disconnected if it can't keep up
+	decisionSubscriberBuffer = 1024
+)
+
+// DecisionEvent is a decision that was added or deleted
+type DecisionEvent struct {
+	Seq      uint64
+	Type     string
+	Decision *models.Decision
+	// the duration of the decision is computed when it's sent
+	Until *time.Time
+}
+
+// duration returns the decision with its remaining duration, or nil if a new decision has expired
+func (e DecisionEvent) duration() *models.Decision {
+	if e.Type != DecisionEventNew || e.Until == nil {
+		return e.Decision
+	}
+
+	remaining := e.Until.Sub(time.Now().UTC()).Round(time.Second)
+	if remaining <= 0 {
+		return nil
+	}
+
+	decision := *e.Decision
+	duration := remaining.String()
+	decision.Duration = &duration
+
+	return &decision
+}
+
+// the epoch of the cursors when the bus is fed by the change log, they are the same on all the LAPI instances
+const decisionChangesEpoch = "rev"
+
+// DecisionBus fans out the decisions added or deleted through the API to the bouncers
+// that are connected to the push endpoint. The decisions inserted by other means
+// (CAPI, PAPI, cscli with a direct database access) are only seen by polling.
+//
+// When several LAPI instances share the database, the bus is fed by the decision change log
+// instead (see FeedDecisionBus): the sequence numbers are the revisions, and a bouncer can
+// resume its stream on any instance.
+type DecisionBus struct {
+	mu sync.Mutex
+	// changes when LAPI restarts, the cursors of a previous run can't be resumed
+	epoch string
+	seq   uint64
+	// the streams can be resumed from this sequence number, the previous events are not in the history anymore
+	since         uint64
+	history       []DecisionEvent
+	historySize   int
+	subscribers   map[*DecisionSubscriber]struct{}
+	closed        bool
+	fromChangeLog bool
+}
+
+type DecisionSubscriber struct {
+	Events chan DecisionEvent
+	// closed when the subscriber is disconnected by the bus
+	Done chan struct{}
+}
+
+func NewDecisionBus(historySize int) *DecisionBus {
+	return &DecisionBus{
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		historySize: historySize,
+		subscribers: make(map[*DecisionSubscriber]struct{}),
+	}
+}
+
+// NewDecisionChangesBus returns a bus that publishes the changes of the decision change log, the decisions are
+// published by FeedDecisionBus. Publish does nothing, the changes made through this instance are in the change log.
+func NewDecisionChangesBus(historySize int) *DecisionBus {
+	b := NewDecisionBus(historySize)
+	b.epoch = decisionChangesEpoch
+	b.fromChangeLog = true
+
+	return b
+}
+
+// Cursor identifies the position of an event in the stream
+func (b *DecisionBus) Cursor(seq uint64) string {
+	return fmt.Sprintf("%s-%d", b.epoch, seq)
+}
+
+// parseCursor returns the sequence number of a cursor, or false if it's not from this run of LAPI
+func (b *DecisionBus) parseCursor(cursor string) (uint64, bool) {
+	epoch, seqStr, found := strings.Cut(cursor, "-")
+	if !found || epoch != b.epoch {
+		return 0, false
+	}
+
+	seq, err := strconv.ParseUint(seqStr, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return seq, true
+}
+
+// Publish sends the decisions to the subscribers. The ones that are too slow are disconnected.
+func (b *DecisionBus) Publish(eventType string, decisions []*ent.Decision) {
+	if len(decisions) == 0 || b.fromChangeLog {
+		return
+	}
+
+	formatted := FormatDecisions(decisions)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	for i, decision := range formatted {
+		b.seq++
+
+		decision.Simulated = &decisions[i].Simulated
+		b.send(DecisionEvent{Seq: b.seq, Type: eventType, Decision: decision, Until: decisions[i].Until})
+	}
+}
+
+// startChanges sets the revision of the change log the bus starts from, the previous cursors can't be resumed
+func (b *DecisionBus) startChanges(revision uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq = revision
+	b.since = revision
+}
+
+// publishChanges sends the changes of the change log, with their revision as sequence number.
+// The changes whose decision is not in the database anymore are skipped.
+func (b *DecisionBus) publishChanges(changes []*ent.DecisionChange, decisions map[int]*ent.Decision) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	for _, change := range changes {
+		seq := uint64(change.ID)
+		if seq <= b.seq {
+			continue
+		}
+
+		b.seq = seq
+
+		d, ok := decisions[change.DecisionID]
+		if !ok {
+			continue
+		}
+
+		eventType := DecisionEventNew
+		if change.Type == decisionchange.TypeDeleted {
+			eventType = DecisionEventDeleted
+		}
+
+		decision := FormatDecisions([]*ent.Decision{d})[0]
+		decision.Simulated = &d.Simulated
+		b.send(DecisionEvent{Seq: seq, Type: eventType, Decision: decision, Until: d.Until})
+	}
+}
+
+// send adds an event to the history and sends it to the subscribers. The ones that are too slow
+// are disconnected. The caller must hold the lock.
+func (b *DecisionBus) send(event DecisionEvent) {
+	b.history = append(b.history, event)
+	if len(b.history) > b.historySize {
+		dropped := len(b.history) - b.historySize
+		b.since = b.history[dropped-1].Seq
+		b.history = b.history[dropped:]
+	}
+
+	for sub := range b.subscribers {
+		select {
+		case sub.Events <- event:
+		default:
+			b.drop(sub)
+		}
+	}
+}
+
+// drop disconnects a subscriber. The caller must hold the lock.
+func (b *DecisionBus) drop(sub *DecisionSubscriber) {
+	delete(b.subscribers, sub)
+	close(sub.Done)
+}
+
+// Subscribe registers a subscriber, and returns the events that were published after the cursor.
+// If the cursor is empty or can't be resumed, resumed is false and the sequence number of the last event is returned:
+// the caller must send the current decisions, the next events will follow.
+func (b *DecisionBus) Subscribe(cursor string) (sub *DecisionSubscriber, replay []DecisionEvent, last uint64, resumed bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, nil, 0, false, errors.New("decision bus is closed")
+	}
+
+	sub = &DecisionSubscriber{
+		Events: make(chan DecisionEvent, decisionSubscriberBuffer),
+		Done:   make(chan struct{}),
+	}
+
+	b.subscribers[sub] = struct{}{}
+
+	seq, ok := b.parseCursor(cursor)
+	if !ok || seq > b.seq || seq < b.since {
+		return sub, nil, b.seq, false, nil
+	}
+
+	for _, event := range b.history {
+		if event.Seq > seq {
+			replay = append(replay, event)
+		}
+	}
+
+	return sub, replay, b.seq, true, nil
+}
+
+func (b *DecisionBus) Unsubscribe(sub *DecisionSubscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscribers[sub]; ok {
+		b.drop(sub)
+	}
+}
+
+// Close disconnects the subscribers, so that the server can shut down
+func (b *DecisionBus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+
+	for sub := range b.subscribers {
+		b.drop(sub)
+	}
+}
+
+// decisionFilter applies the scope, origin and scenario filters of the decision stream
+// to the decisions that are pushed.
+type decisionFilter struct {
+	scopes                 []string
+	origins                []string
+	scenariosContaining    []string
+	scenariosNotContaining []string
+	includeSimulated       bool
+}
+
+func newDecisionFilter(filters map[string][]string) decisionFilter {
+	f := decisionFilter{}
+
+	for param, value := range filters {
+		if len(value) == 0 {
+			continue
+		}
+
+		switch param {
+		case "scopes", "scope":
+			for _, scope := range strings.Split(value[0], ",") {
+				f.scopes = append(f.scopes, types.NormalizeScope(scope))
+			}
+		case "origins":
+			f.origins = strings.Split(value[0], ",")
+		case "scenarios_containing":
+			f.scenariosContaining = strings.Split(strings.ToLower(value[0]), ",")
+		case "scenarios_not_containing":
+			f.scenariosNotContaining = strings.Split(strings.ToLower(value[0]), ",")
+		case "simulated":
+			f.includeSimulated = value[0] != "false"
+		}
+	}
+
+	return f
+}
+
+func containsAny(s string, words []string) bool {
+	for _, word := range words {
+		if strings.Contains(s, word) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f decisionFilter) match(decision *models.Decision) bool {
+	if decision.Simulated != nil && *decision.Simulated && !f.includeSimulated {
+		return false
+	}
+
+	if len(f.scopes) > 0 && (decision.Scope == nil || !slices.Contains(f.scopes, *decision.Scope)) {
+		return false
+	}
+
+	if len(f.origins) > 0 && (decision.Origin == nil || !slices.Contains(f.origins, *decision.Origin)) {
+		return false
+	}
+
+	scenario := ""
+	if decision.Scenario != nil {
+		scenario = strings.ToLower(*decision.Scenario)
+	}
+
+	if len(f.scenariosContaining) > 0 && !containsAny(scenario, f.scenariosContaining) {
+		return false
+	}
+
+	if len(f.scenariosNotContaining) > 0 && containsAny(scenario, f.scenariosNotContaining) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,203 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/decisionchange"
+)
+
+func testDecisions(values ...string) []*ent.Decision {
+	until := time.Now().UTC().Add(time.Hour)
+	ret := []*ent.Decision{}
+
+	for i, value := range values {
+		ret = append(ret, &ent.Decision{
+			ID:       i + 1,
+			Until:    &until,
+			Scenario: "crowdsecurity/ssh-bf",
+			Type:     "ban",
+			Scope:    "Ip",
+			Value:    value,
+			Origin:   "crowdsec",
+		})
+	}
+
+	return ret
+}
+
+func TestDecisionBusResume(t *testing.T) {
+	bus := NewDecisionBus(3)
+
+	sub, replay, last, resumed, err := bus.Subscribe("")
+	require.NoError(t, err)
+	assert.False(t, resumed)
+	assert.Empty(t, replay)
+	assert.Equal(t, uint64(0), last)
+
+	bus.Publish(DecisionEventNew, testDecisions("1.2.3.4", "1.2.3.5"))
+	bus.Publish(DecisionEventDeleted, testDecisions("1.2.3.4"))
+
+	for seq := uint64(1); seq <= 3; seq++ {
+		event := <-sub.Events
+		assert.Equal(t, seq, event.Seq)
+	}
+
+	bus.Unsubscribe(sub)
+
+	select {
+	case <-sub.Done:
+	default:
+		t.Fatal("the subscriber is not disconnected")
+	}
+
+	_, replay, _, resumed, err = bus.Subscribe(bus.Cursor(1))
+	require.NoError(t, err)
+	require.True(t, resumed)
+	require.Len(t, replay, 2)
+	assert.Equal(t, DecisionEventDeleted, replay[1].Type)
+
+	// up to date
+	_, replay, _, resumed, err = bus.Subscribe(bus.Cursor(3))
+	require.NoError(t, err)
+	assert.True(t, resumed)
+	assert.Empty(t, replay)
+
+	// the history only keeps 3 events
+	bus.Publish(DecisionEventNew, testDecisions("1.2.3.6"))
+
+	_, _, last, resumed, err = bus.Subscribe(bus.Cursor(0))
+	require.NoError(t, err)
+	assert.False(t, resumed)
+	assert.Equal(t, uint64(4), last)
+
+	// from a previous run
+	_, _, _, resumed, err = bus.Subscribe("abc-2")
+	require.NoError(t, err)
+	assert.False(t, resumed)
+
+	bus.Close()
+
+	_, _, _, _, err = bus.Subscribe("")
+	require.Error(t, err)
+}
+
+func TestDecisionChangesBus(t *testing.T) {
+	decisions := map[int]*ent.Decision{}
+	for _, d := range testDecisions("1.2.3.4", "1.2.3.5") {
+		decisions[d.ID] = d
+	}
+
+	changes := []*ent.DecisionChange{
+		{ID: 11, Type: decisionchange.TypeNew, DecisionID: 1},
+		// the decision was flushed
+		{ID: 12, Type: decisionchange.TypeNew, DecisionID: 42},
+		{ID: 14, Type: decisionchange.TypeNew, DecisionID: 2},
+		{ID: 15, Type: decisionchange.TypeDeleted, DecisionID: 1},
+	}
+
+	// two LAPI instances
+	buses := []*DecisionBus{NewDecisionChangesBus(10), NewDecisionChangesBus(10)}
+	for _, bus := range buses {
+		bus.startChanges(10)
+
+		// the decisions are published by the change log only
+		bus.Publish(DecisionEventNew, testDecisions("1.2.3.6"))
+	}
+
+	sub, _, last, resumed, err := buses[0].Subscribe("")
+	require.NoError(t, err)
+	assert.False(t, resumed)
+	assert.Equal(t, uint64(10), last)
+
+	buses[0].publishChanges(changes[:2], decisions)
+	buses[1].publishChanges(changes, decisions)
+	buses[0].publishChanges(changes, decisions)
+
+	events := []DecisionEvent{}
+	for range 3 {
+		events = append(events, <-sub.Events)
+	}
+
+	assert.Equal(t, uint64(11), events[0].Seq)
+	assert.Equal(t, "1.2.3.4", *events[0].Decision.Value)
+	assert.Equal(t, uint64(14), events[1].Seq)
+	assert.Equal(t, DecisionEventDeleted, events[2].Type)
+	assert.Empty(t, sub.Events)
+
+	// the bouncer reconnects to the other instance
+	_, replay, last, resumed, err := buses[1].Subscribe(buses[0].Cursor(events[0].Seq))
+	require.NoError(t, err)
+	require.True(t, resumed)
+	assert.Equal(t, uint64(15), last)
+	require.Len(t, replay, 2)
+	assert.Equal(t, "1.2.3.5", *replay[0].Decision.Value)
+
+	// before the instance started
+	_, _, _, resumed, err = buses[1].Subscribe(buses[0].Cursor(9))
+	require.NoError(t, err)
+	assert.False(t, resumed)
+}
+
+func TestDecisionBusSlowSubscriber(t *testing.T) {
+	bus := NewDecisionBus(defaultDecisionBusHistory)
+
+	sub, _, _, _, err := bus.Subscribe("")
+	require.NoError(t, err)
+
+	values := make([]string, decisionSubscriberBuffer+1)
+	for i := range values {
+		values[i] = "1.2.3.4"
+	}
+
+	bus.Publish(DecisionEventNew, testDecisions(values...))
+
+	select {
+	case <-sub.Done:
+	default:
+		t.Fatal("the slow subscriber is not disconnected")
+	}
+
+	// it can resume from the events it received
+	_, replay, _, resumed, err := bus.Subscribe(bus.Cursor(decisionSubscriberBuffer))
+	require.NoError(t, err)
+	assert.True(t, resumed)
+	assert.Len(t, replay, 1)
+}
+
+func TestDecisionFilter(t *testing.T) {
+	decision := FormatDecisions(testDecisions("1.2.3.4"))[0]
+	simulated := true
+
+	tests := []struct {
+		name      string
+		filters   map[string][]string
+		simulated bool
+		expected  bool
+	}{
+		{name: "no filter", filters: map[string][]string{}, expected: true},
+		{name: "scope", filters: map[string][]string{"scopes": {"ip,range"}}, expected: true},
+		{name: "other scope", filters: map[string][]string{"scopes": {"range"}}, expected: false},
+		{name: "origin", filters: map[string][]string{"origins": {"cscli,crowdsec"}}, expected: true},
+		{name: "other origin", filters: map[string][]string{"origins": {"CAPI"}}, expected: false},
+		{name: "scenario", filters: map[string][]string{"scenarios_containing": {"SSH"}}, expected: true},
+		{name: "excluded scenario", filters: map[string][]string{"scenarios_not_containing": {"http,ssh"}}, expected: false},
+		{name: "simulated", filters: map[string][]string{}, simulated: true, expected: false},
+		{name: "with simulated", filters: map[string][]string{"simulated": {"true"}}, simulated: true, expected: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d := *decision
+			if tc.simulated {
+				d.Simulated = &simulated
+			}
+
+			assert.Equal(t, tc.expected, newDecisionFilter(tc.filters).match(&d))
+		})
+	}
+}
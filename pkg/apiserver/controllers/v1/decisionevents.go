@@ -0,0 +1,260 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent"
+)
+
+// keep the connection open through the proxies, and update the last pull of the bouncer
+const decisionEventsKeepAlive = 30 * time.Second
+
+// publishAlertDecisions sends the decisions of the new alerts to the bouncers listening for decisions
+func (c *Controller) publishAlertDecisions(ctx context.Context, alertIDs []string) {
+	ids := make([]int, 0, len(alertIDs))
+
+	for _, alertID := range alertIDs {
+		id, err := strconv.Atoi(alertID)
+		if err != nil {
+			log.Errorf("invalid alert id %s: %s", alertID, err)
+			continue
+		}
+
+		ids = append(ids, id)
+	}
+
+	decisions, err := c.DBClient.QueryDecisionsByAlertIDs(ctx, ids)
+	if err != nil {
+		log.Errorf("unable to push the new decisions: %s", err)
+		return
+	}
+
+	c.DecisionBus.Publish(DecisionEventNew, decisions)
+}
+
+const (
+	// how often the change log is read when it feeds the decision bus
+	decisionFeedInterval = time.Second
+	// the changes read at once
+	decisionFeedBatchSize = 1000
+)
+
+// FeedDecisionBus publishes the changes of the decision change log to a bus created by NewDecisionChangesBus,
+// until the context is canceled. The bouncers connected to this instance receive the decisions added or deleted
+// through the other LAPI instances that share the database.
+func (c *Controller) FeedDecisionBus(ctx context.Context) {
+	revision, err := c.DBClient.DecisionRevision(ctx)
+	for err != nil {
+		log.Errorf("unable to read the decision revision, retrying in %s: %s", decisionFeedInterval, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(decisionFeedInterval):
+		}
+
+		revision, err = c.DBClient.DecisionRevision(ctx)
+	}
+
+	c.DecisionBus.startChanges(uint64(revision))
+
+	ticker := time.NewTicker(decisionFeedInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for {
+			changes, decisions, err := c.DBClient.DecisionChangesAfter(ctx, revision, decisionFeedBatchSize)
+			if err != nil {
+				log.Errorf("unable to push the decision changes: %s", err)
+				break
+			}
+
+			if len(changes) == 0 {
+				break
+			}
+
+			c.DecisionBus.publishChanges(changes, decisions)
+
+			revision = changes[len(changes)-1].ID
+
+			if len(changes) < decisionFeedBatchSize {
+				break
+			}
+		}
+	}
+}
+
+// writeDecisionEvent writes a server-sent event, the cursor allows to resume the stream
+func writeDecisionEvent(w io.Writer, cursor string, eventType string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	if cursor != "" {
+		if _, err = fmt.Fprintf(w, "id: %s\n", cursor); err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", eventType, payload)
+
+	return err
+}
+
+// writeDecisionsSnapshot sends the active and expired decisions, as the stream with startup=true does.
+// The cursor is only sent at the end, a bouncer that disconnects before receives all of them again.
+func (c *Controller) writeDecisionsSnapshot(gctx *gin.Context, cursor string, filters map[string][]string) error {
+	ctx := gctx.Request.Context()
+
+	queries := []struct {
+		eventType string
+		query     func(context.Context, map[string][]string) ([]*ent.Decision, error)
+	}{
+		{DecisionEventNew, c.DBClient.QueryAllDecisionsWithFilters},
+		{DecisionEventDeleted, c.DBClient.QueryExpiredDecisionsWithFilters},
+	}
+
+	for _, q := range queries {
+		// the filters are modified by the query
+		data, err := q.query(ctx, maps.Clone(filters))
+		if err != nil {
+			return err
+		}
+
+		for _, decision := range FormatDecisions(data) {
+			if err := writeDecisionEvent(gctx.Writer, "", q.eventType, decision); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := writeDecisionEvent(gctx.Writer, cursor, DecisionEventSynced, struct{}{}); err != nil {
+		return err
+	}
+
+	gctx.Writer.Flush()
+
+	return nil
+}
+
+// StreamDecisionEvents pushes the decisions to a bouncer as server-sent events, as they are added or deleted.
+// A bouncer that reconnects with the Last-Event-ID header (or the cursor parameter) receives the events it missed,
+// or all the decisions if they are not available anymore.
+func (c *Controller) StreamDecisionEvents(gctx *gin.Context) {
+	bouncerInfo, err := getBouncerFromContext(gctx)
+	if err != nil {
+		gctx.JSON(http.StatusUnauthorized, gin.H{"message": "not allowed"})
+
+		return
+	}
+
+	filters := gctx.Request.URL.Query()
+	if _, ok := filters["scopes"]; !ok {
+		filters["scopes"] = []string{"ip,range"}
+	}
+
+	filter := newDecisionFilter(filters)
+
+	cursor := gctx.GetHeader("Last-Event-ID")
+	if cursor == "" {
+		cursor = gctx.Query("cursor")
+	}
+
+	sub, replay, last, resumed, err := c.DecisionBus.Subscribe(cursor)
+	if err != nil {
+		gctx.JSON(http.StatusServiceUnavailable, gin.H{"message": err.Error()})
+
+		return
+	}
+
+	defer c.DecisionBus.Unsubscribe(sub)
+
+	gctx.Writer.Header().Set("Content-Type", "text/event-stream")
+	gctx.Writer.Header().Set("Cache-Control", "no-cache")
+	gctx.Writer.Header().Set("Connection", "keep-alive")
+	gctx.Writer.Header().Set("X-Accel-Buffering", "no")
+	gctx.Writer.WriteHeader(http.StatusOK)
+
+	if !resumed {
+		if err := c.writeDecisionsSnapshot(gctx, c.DecisionBus.Cursor(last), filters); err != nil {
+			log.Errorf("failed sending decisions to '%s': %v", bouncerInfo.Name, err)
+			return
+		}
+	}
+
+	send := func(event DecisionEvent) error {
+		if !filter.match(event.Decision) {
+			return nil
+		}
+
+		decision := event.duration()
+		if decision == nil {
+			return nil
+		}
+
+		return writeDecisionEvent(gctx.Writer, c.DecisionBus.Cursor(event.Seq), event.Type, decision)
+	}
+
+	for _, event := range replay {
+		if err := send(event); err != nil {
+			log.Debugf("bouncer '%s' disconnected: %v", bouncerInfo.Name, err)
+			return
+		}
+	}
+
+	gctx.Writer.Flush()
+
+	updateLastPull := func() {
+		// the request context is canceled when the bouncer disconnects
+		if err := c.DBClient.UpdateBouncerLastPull(context.Background(), time.Now().UTC(), bouncerInfo.ID); err != nil {
+			log.Errorf("unable to update bouncer '%s' pull: %v", bouncerInfo.Name, err)
+		}
+	}
+
+	updateLastPull()
+
+	ticker := time.NewTicker(decisionEventsKeepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-gctx.Request.Context().Done():
+			return
+		case <-sub.Done:
+			// the bouncer reconnects and resumes from its cursor
+			log.Debugf("closing the decision stream of '%s'", bouncerInfo.Name)
+			return
+		case event := <-sub.Events:
+			if err := send(event); err != nil {
+				log.Debugf("bouncer '%s' disconnected: %v", bouncerInfo.Name, err)
+				return
+			}
+
+			gctx.Writer.Flush()
+		case <-ticker.C:
+			if _, err := io.WriteString(gctx.Writer, ": keep-alive\n\n"); err != nil {
+				return
+			}
+
+			gctx.Writer.Flush()
+			updateLastPull()
+		}
+	}
+}
@@ -3,6 +3,8 @@ package v1
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
@@ -10,6 +12,7 @@ import (
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/crowdsecurity/crowdsec/pkg/database"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent"
 	"github.com/crowdsecurity/crowdsec/pkg/fflag"
 	"github.com/crowdsecurity/crowdsec/pkg/models"
@@ -102,6 +105,8 @@ func (c *Controller) DeleteDecisionById(gctx *gin.Context) {
 		return
 	}
 
+	c.DecisionBus.Publish(DecisionEventDeleted, deletedFromDB)
+
 	// transform deleted decisions to be sendable to capi
 	deletedDecisions := FormatDecisions(deletedFromDB)
 
@@ -126,6 +131,8 @@ func (c *Controller) DeleteDecisions(gctx *gin.Context) {
 		return
 	}
 
+	c.DecisionBus.Publish(DecisionEventDeleted, deletedFromDB)
+
 	// transform deleted decisions to be sendable to capi
 	deletedDecisions := FormatDecisions(deletedFromDB)
 
@@ -140,7 +147,7 @@ func (c *Controller) DeleteDecisions(gctx *gin.Context) {
 	gctx.JSON(http.StatusOK, deleteDecisionResp)
 }
 
-func writeStartupDecisions(gctx *gin.Context, filters map[string][]string, dbFunc func(context.Context, map[string][]string) ([]*ent.Decision, error)) error {
+func writeDecisions(gctx *gin.Context, filters map[string][]string, dbFunc func(context.Context, map[string][]string) ([]*ent.Decision, error)) error {
 	// respBuffer := bytes.NewBuffer([]byte{})
 	limit := 30000 // FIXME : make it configurable
 	needComma := false
@@ -199,66 +206,97 @@ func writeStartupDecisions(gctx *gin.Context, filters map[string][]string, dbFun
 	return nil
 }
 
-func writeDeltaDecisions(gctx *gin.Context, filters map[string][]string, lastPull *time.Time, dbFunc func(context.Context, *time.Time, map[string][]string) ([]*ent.Decision, error)) error {
-	// respBuffer := bytes.NewBuffer([]byte{})
-	limit := 30000 // FIXME : make it configurable
-	needComma := false
-	lastId := 0
+// DecisionRevisionHeader is sent with the decision stream, the bouncer passes it as the since parameter of its next pull
+const DecisionRevisionHeader = "X-Crowdsec-Decisions-Revision"
+
+// decisionStreamCursor tells which decisions are sent to a bouncer
+type decisionStreamCursor struct {
+	// all the decisions
+	startup bool
+	// the changes after this revision, or since the last pull of the bouncer if nil
+	since *int
+	// the revision the bouncer passes with its next pull
+	revision int
+}
 
+// newDecisionStreamCursor reads the startup and since parameters. A revision that is not in the change log anymore
+// is the same as startup=true.
+func (c *Controller) newDecisionStreamCursor(gctx *gin.Context) (decisionStreamCursor, error) {
 	ctx := gctx.Request.Context()
 
-	limitStr := strconv.Itoa(limit)
-	filters["limit"] = []string{limitStr}
+	// before the queries: the changes made meanwhile are sent again with the next pull.
+	// The decisions that reach their end are added to the change log by a flush job.
+	revision, err := c.DBClient.DecisionRevision(ctx)
+	if err != nil {
+		return decisionStreamCursor{}, err
+	}
 
-	for {
-		if lastId > 0 {
-			lastIdStr := strconv.Itoa(lastId)
-			filters["id_gt"] = []string{lastIdStr}
-		}
+	cursor := decisionStreamCursor{
+		startup:  gctx.Query("startup") == "true",
+		revision: revision,
+	}
 
-		data, err := dbFunc(ctx, lastPull, filters)
-		if err != nil {
-			return err
-		}
+	sinceStr, ok := gctx.GetQuery("since")
+	if !ok || cursor.startup {
+		return cursor, nil
+	}
 
-		if len(data) > 0 {
-			lastId = data[len(data)-1].ID
+	since, err := strconv.Atoi(sinceStr)
+	if err != nil || since < 0 {
+		return decisionStreamCursor{}, fmt.Errorf("%w: since must be a revision, not '%s'", database.InvalidFilter, sinceStr)
+	}
 
-			results := FormatDecisions(data)
-			for _, decision := range results {
-				decisionJSON, _ := json.Marshal(decision)
+	known, err := c.DBClient.DecisionRevisionKnown(ctx, since)
+	if err != nil {
+		return decisionStreamCursor{}, err
+	}
 
-				if needComma {
-					// respBuffer.Write([]byte(","))
-					gctx.Writer.WriteString(",")
-				} else {
-					needComma = true
-				}
-				//respBuffer.Write(decisionJSON)
-				//_, err := gctx.Writer.Write(respBuffer.Bytes())
-				_, err := gctx.Writer.Write(decisionJSON)
-				if err != nil {
-					gctx.Writer.Flush()
+	if since == 0 || !known {
+		log.Debugf("revision %d is not available, sending all the decisions", since)
 
-					return err
-				}
-				// respBuffer.Reset()
-			}
+		cursor.startup = true
+
+		return cursor, nil
+	}
+
+	cursor.since = &since
+
+	return cursor, nil
+}
+
+// deltaQueries returns the queries of the new and deleted decisions since the last pull or the revision
+func (c *Controller) deltaQueries(bouncerInfo *ent.Bouncer, cursor decisionStreamCursor) (newFunc, deletedFunc func(context.Context, map[string][]string) ([]*ent.Decision, error)) {
+	if cursor.since != nil {
+		since := *cursor.since
+
+		newFunc = func(ctx context.Context, filters map[string][]string) ([]*ent.Decision, error) {
+			return c.DBClient.QueryNewDecisionsSinceRevisionWithFilters(ctx, since, filters)
 		}
 
-		log.Debugf("startup: %d decisions returned (limit: %d, lastid: %d)", len(data), limit, lastId)
+		deletedFunc = func(ctx context.Context, filters map[string][]string) ([]*ent.Decision, error) {
+			return c.DBClient.QueryDeletedDecisionsSinceRevisionWithFilters(ctx, since, filters)
+		}
 
-		if len(data) < limit {
-			gctx.Writer.Flush()
+		return newFunc, deletedFunc
+	}
 
-			break
+	newFunc = func(ctx context.Context, filters map[string][]string) ([]*ent.Decision, error) {
+		return c.DBClient.QueryNewDecisionsSinceWithFilters(ctx, bouncerInfo.LastPull, filters)
+	}
+
+	deletedFunc = func(ctx context.Context, filters map[string][]string) ([]*ent.Decision, error) {
+		since := time.Time{}
+		if bouncerInfo.LastPull != nil {
+			since = bouncerInfo.LastPull.Add(-2 * time.Second)
 		}
+
+		return c.DBClient.QueryExpiredDecisionsSinceWithFilters(ctx, &since, filters) // do we want to give exactly lastPull time ?
 	}
 
-	return nil
+	return newFunc, deletedFunc
 }
 
-func (c *Controller) StreamDecisionChunked(gctx *gin.Context, bouncerInfo *ent.Bouncer, streamStartTime time.Time, filters map[string][]string) error {
+func (c *Controller) StreamDecisionChunked(gctx *gin.Context, bouncerInfo *ent.Bouncer, streamStartTime time.Time, filters map[string][]string, cursor decisionStreamCursor) error {
 	var err error
 
 	gctx.Writer.Header().Set("Content-Type", "application/json")
@@ -267,9 +305,9 @@ func (c *Controller) StreamDecisionChunked(gctx *gin.Context, bouncerInfo *ent.B
 	gctx.Writer.WriteString(`{"new": [`) // No need to check for errors, the doc says it always returns nil
 
 	// if the blocker just started, return all decisions
-	if val, ok := gctx.Request.URL.Query()["startup"]; ok && val[0] == "true" {
+	if cursor.startup {
 		// Active decisions
-		err := writeStartupDecisions(gctx, filters, c.DBClient.QueryAllDecisionsWithFilters)
+		err := writeDecisions(gctx, filters, c.DBClient.QueryAllDecisionsWithFilters)
 		if err != nil {
 			log.Errorf("failed sending new decisions for startup: %v", err)
 			gctx.Writer.WriteString(`], "deleted": []}`)
@@ -280,7 +318,7 @@ func (c *Controller) StreamDecisionChunked(gctx *gin.Context, bouncerInfo *ent.B
 
 		gctx.Writer.WriteString(`], "deleted": [`)
 		// Expired decisions
-		err = writeStartupDecisions(gctx, filters, c.DBClient.QueryExpiredDecisionsWithFilters)
+		err = writeDecisions(gctx, filters, c.DBClient.QueryExpiredDecisionsWithFilters)
 		if err != nil {
 			log.Errorf("failed sending expired decisions for startup: %v", err)
 			gctx.Writer.WriteString(`]}`)
@@ -292,7 +330,9 @@ func (c *Controller) StreamDecisionChunked(gctx *gin.Context, bouncerInfo *ent.B
 		gctx.Writer.WriteString(`]}`)
 		gctx.Writer.Flush()
 	} else {
-		err = writeDeltaDecisions(gctx, filters, bouncerInfo.LastPull, c.DBClient.QueryNewDecisionsSinceWithFilters)
+		newFunc, deletedFunc := c.deltaQueries(bouncerInfo, cursor)
+
+		err = writeDecisions(gctx, filters, newFunc)
 		if err != nil {
 			log.Errorf("failed sending new decisions for delta: %v", err)
 			gctx.Writer.WriteString(`], "deleted": []}`)
@@ -303,7 +343,7 @@ func (c *Controller) StreamDecisionChunked(gctx *gin.Context, bouncerInfo *ent.B
 
 		gctx.Writer.WriteString(`], "deleted": [`)
 
-		err = writeDeltaDecisions(gctx, filters, bouncerInfo.LastPull, c.DBClient.QueryExpiredDecisionsSinceWithFilters)
+		err = writeDecisions(gctx, filters, deletedFunc)
 		if err != nil {
 			log.Errorf("failed sending expired decisions for delta: %v", err)
 			gctx.Writer.WriteString("]}")
@@ -319,7 +359,7 @@ func (c *Controller) StreamDecisionChunked(gctx *gin.Context, bouncerInfo *ent.B
 	return nil
 }
 
-func (c *Controller) StreamDecisionNonChunked(gctx *gin.Context, bouncerInfo *ent.Bouncer, streamStartTime time.Time, filters map[string][]string) error {
+func (c *Controller) StreamDecisionNonChunked(gctx *gin.Context, bouncerInfo *ent.Bouncer, streamStartTime time.Time, filters map[string][]string, cursor decisionStreamCursor) error {
 	var (
 		data []*ent.Decision
 		err  error
@@ -331,37 +371,15 @@ func (c *Controller) StreamDecisionNonChunked(gctx *gin.Context, bouncerInfo *en
 	ret["new"] = []*models.Decision{}
 	ret["deleted"] = []*models.Decision{}
 
-	if val, ok := gctx.Request.URL.Query()["startup"]; ok {
-		if val[0] == "true" {
-			data, err = c.DBClient.QueryAllDecisionsWithFilters(ctx, filters)
-			if err != nil {
-				log.Errorf("failed querying decisions: %v", err)
-				gctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	newFunc, deletedFunc := c.deltaQueries(bouncerInfo, cursor)
 
-				return err
-			}
-			// data = KeepLongestDecision(data)
-			ret["new"] = FormatDecisions(data)
-
-			// getting expired decisions
-			data, err = c.DBClient.QueryExpiredDecisionsWithFilters(ctx, filters)
-			if err != nil {
-				log.Errorf("unable to query expired decision for '%s' : %v", bouncerInfo.Name, err)
-				gctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
-
-				return err
-			}
-
-			ret["deleted"] = FormatDecisions(data)
-
-			gctx.JSON(http.StatusOK, ret)
-
-			return nil
-		}
+	if cursor.startup {
+		newFunc = c.DBClient.QueryAllDecisionsWithFilters
+		deletedFunc = c.DBClient.QueryExpiredDecisionsWithFilters
 	}
 
 	// getting new decisions
-	data, err = c.DBClient.QueryNewDecisionsSinceWithFilters(ctx, bouncerInfo.LastPull, filters)
+	data, err = newFunc(ctx, filters)
 	if err != nil {
 		log.Errorf("unable to query new decision for '%s' : %v", bouncerInfo.Name, err)
 		gctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
@@ -371,13 +389,8 @@ func (c *Controller) StreamDecisionNonChunked(gctx *gin.Context, bouncerInfo *en
 	// data = KeepLongestDecision(data)
 	ret["new"] = FormatDecisions(data)
 
-	since := time.Time{}
-	if bouncerInfo.LastPull != nil {
-		since = bouncerInfo.LastPull.Add(-2 * time.Second)
-	}
-
 	// getting expired decisions
-	data, err = c.DBClient.QueryExpiredDecisionsSinceWithFilters(ctx, &since, filters) // do we want to give exactly lastPull time ?
+	data, err = deletedFunc(ctx, filters)
 	if err != nil {
 		log.Errorf("unable to query expired decision for '%s' : %v", bouncerInfo.Name, err)
 		gctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
@@ -411,15 +424,32 @@ func (c *Controller) StreamDecision(gctx *gin.Context) {
 		return
 	}
 
+	cursor, err := c.newDecisionStreamCursor(gctx)
+	if errors.Is(err, database.InvalidFilter) {
+		gctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+
+		return
+	}
+
+	if err != nil {
+		log.Errorf("unable to read the decision revision for '%s': %v", bouncerInfo.Name, err)
+		c.HandleDBErrors(gctx, err)
+
+		return
+	}
+
+	// the body of the response is not changed, for the bouncers that don't use the revision
+	gctx.Header(DecisionRevisionHeader, strconv.Itoa(cursor.revision))
+
 	filters := gctx.Request.URL.Query()
 	if _, ok := filters["scopes"]; !ok {
 		filters["scopes"] = []string{"ip,range"}
 	}
 
 	if fflag.ChunkedDecisionsStream.IsEnabled() {
-		err = c.StreamDecisionChunked(gctx, bouncerInfo, streamStartTime, filters)
+		err = c.StreamDecisionChunked(gctx, bouncerInfo, streamStartTime, filters, cursor)
 	} else {
-		err = c.StreamDecisionNonChunked(gctx, bouncerInfo, streamStartTime, filters)
+		err = c.StreamDecisionNonChunked(gctx, bouncerInfo, streamStartTime, filters, cursor)
 	}
 
 	if err == nil {
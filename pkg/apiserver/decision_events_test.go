@@ -0,0 +1,156 @@
+package apiserver
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/crowdsecurity/crowdsec/pkg/models"
+)
+
+type decisionEvent struct {
+	id        string
+	eventType string
+	decision  models.Decision
+}
+
+// openDecisionEvents connects to the push endpoint and returns the events as they are received
+func openDecisionEvents(t *testing.T, ctx context.Context, lapi LAPI, url string, lastEventID string) <-chan decisionEvent {
+	t.Helper()
+
+	srv := httptest.NewServer(lapi.router)
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithCancel(ctx)
+	t.Cleanup(cancel)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+url, http.NoBody)
+	require.NoError(t, err)
+	req.Header.Add("X-Api-Key", lapi.bouncerKey)
+
+	if lastEventID != "" {
+		req.Header.Add("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	events := make(chan decisionEvent, 100)
+
+	go func() {
+		defer resp.Body.Close()
+		defer close(events)
+
+		scanner := bufio.NewScanner(resp.Body)
+		event := decisionEvent{}
+
+		for scanner.Scan() {
+			line := scanner.Text()
+
+			switch {
+			case line == "":
+				events <- event
+				event = decisionEvent{}
+			case strings.HasPrefix(line, "id: "):
+				event.id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				event.eventType = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				_ = json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event.decision)
+			}
+		}
+	}()
+
+	return events
+}
+
+func readDecisionEvents(t *testing.T, events <-chan decisionEvent, count int) []decisionEvent {
+	t.Helper()
+
+	ret := []decisionEvent{}
+
+	for range count {
+		select {
+		case event, ok := <-events:
+			require.True(t, ok, "the stream was closed")
+
+			ret = append(ret, event)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for a decision event, got %v", ret)
+		}
+	}
+
+	return ret
+}
+
+func TestStreamDecisionEvents(t *testing.T) {
+	ctx := t.Context()
+	lapi := SetupLAPITest(t, ctx)
+
+	events := openDecisionEvents(t, ctx, lapi, "/v1/decisions/events", "")
+
+	// nothing to send yet
+	synced := readDecisionEvents(t, events, 1)[0]
+	assert.Equal(t, "synced", synced.eventType)
+	assert.NotEmpty(t, synced.id)
+
+	// the bouncer does not want these
+	filtered := openDecisionEvents(t, ctx, lapi, "/v1/decisions/events?origins=cscli", "")
+	readDecisionEvents(t, filtered, 1)
+
+	lapi.InsertAlertFromFile(t, ctx, "./tests/alert_minibulk.json")
+
+	added := readDecisionEvents(t, events, 2)
+	values := []string{}
+
+	for _, event := range added {
+		assert.Equal(t, "new", event.eventType)
+		assert.NotEmpty(t, event.id)
+		assert.Equal(t, "ban", *event.decision.Type)
+
+		values = append(values, *event.decision.Value)
+	}
+
+	assert.ElementsMatch(t, []string{"91.121.79.179", "91.121.79.178"}, values)
+
+	w := lapi.RecordResponse(t, ctx, http.MethodDelete, "/v1/decisions?ip=91.121.79.179", emptyBody, PASSWORD)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	deleted := readDecisionEvents(t, events, 1)[0]
+	assert.Equal(t, "deleted", deleted.eventType)
+	assert.Equal(t, "91.121.79.179", *deleted.decision.Value)
+
+	select {
+	case event := <-filtered:
+		t.Fatalf("unexpected event %v", event)
+	default:
+	}
+
+	// a bouncer that reconnects receives the events it missed
+	resumed := openDecisionEvents(t, ctx, lapi, "/v1/decisions/events", added[0].id)
+	replay := readDecisionEvents(t, resumed, 2)
+	assert.Equal(t, added[1].id, replay[0].id)
+	assert.Equal(t, deleted.id, replay[1].id)
+
+	// an unknown cursor receives the current decisions
+	restarted := openDecisionEvents(t, ctx, lapi, "/v1/decisions/events", "unknown-42")
+	snapshot := readDecisionEvents(t, restarted, 3)
+
+	assert.Equal(t, "new", snapshot[0].eventType)
+	assert.Equal(t, "91.121.79.178", *snapshot[0].decision.Value)
+	assert.Empty(t, snapshot[0].id)
+	assert.Equal(t, "deleted", snapshot[1].eventType)
+	assert.Equal(t, "91.121.79.179", *snapshot[1].decision.Value)
+	assert.Equal(t, "synced", snapshot[2].eventType)
+	assert.Equal(t, deleted.id, snapshot[2].id)
+}
@@ -1,9 +1,18 @@
 package apiserver
 
 import (
+	"context"
+	"net/http"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/crowdsecurity/crowdsec/pkg/apiserver/controllers/v1"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/decisionchange"
+	"github.com/crowdsecurity/crowdsec/pkg/models"
 )
 
 const (
@@ -319,6 +328,69 @@ func TestStreamStartDecisionDedup(t *testing.T) {
 	assert.Empty(t, decisions["new"])
 }
 
+// lastRevision returns the last revision of the change log, without waiting for it to settle
+func lastRevision(t *testing.T, ctx context.Context, lapi LAPI) string {
+	t.Helper()
+
+	last, err := lapi.DBClient.Ent.DecisionChange.Query().Order(ent.Desc(decisionchange.FieldID)).First(ctx)
+	require.NoError(t, err)
+
+	return strconv.Itoa(last.ID)
+}
+
+func decisionValues(decisions []*models.Decision) []string {
+	ret := []string{}
+	for _, d := range decisions {
+		ret = append(ret, *d.Value)
+	}
+
+	return ret
+}
+
+func TestStreamDecisionSinceRevision(t *testing.T) {
+	ctx := t.Context()
+	lapi := SetupLAPITest(t, ctx)
+
+	lapi.InsertAlertFromFile(t, ctx, "./tests/alert_minibulk.json")
+
+	w := lapi.RecordResponse(t, ctx, http.MethodGet, "/v1/decisions/stream?since=abc", emptyBody, APIKEY)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = lapi.RecordResponse(t, ctx, http.MethodGet, "/v1/decisions/stream?startup=true", emptyBody, APIKEY)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEmpty(t, w.Header().Get(v1.DecisionRevisionHeader))
+
+	revision := lastRevision(t, ctx, lapi)
+
+	w = lapi.RecordResponse(t, ctx, http.MethodDelete, "/v1/decisions?ip=91.121.79.179", emptyBody, PASSWORD)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// 3 decisions on the same ip, only the longest one is sent
+	lapi.InsertAlertFromFile(t, ctx, "./tests/alert_sample.json")
+
+	// the replicas of a bouncer share its key, they all receive the changes
+	for range 2 {
+		w = lapi.RecordResponse(t, ctx, http.MethodGet, "/v1/decisions/stream?since="+revision, emptyBody, APIKEY)
+		decisions, code := readDecisionsStreamResp(t, w)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{"127.0.0.1"}, decisionValues(decisions["new"]))
+		assert.Equal(t, []string{"91.121.79.179"}, decisionValues(decisions["deleted"]))
+	}
+
+	w = lapi.RecordResponse(t, ctx, http.MethodGet, "/v1/decisions/stream?since="+lastRevision(t, ctx, lapi), emptyBody, APIKEY)
+	decisions, code := readDecisionsStreamResp(t, w)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Empty(t, decisions["new"])
+	assert.Empty(t, decisions["deleted"])
+
+	// an unknown revision receives all the decisions
+	w = lapi.RecordResponse(t, ctx, http.MethodGet, "/v1/decisions/stream?since=999", emptyBody, APIKEY)
+	decisions, code = readDecisionsStreamResp(t, w)
+	assert.Equal(t, http.StatusOK, code)
+	assert.ElementsMatch(t, []string{"91.121.79.178", "127.0.0.1"}, decisionValues(decisions["new"]))
+	assert.Equal(t, []string{"91.121.79.179"}, decisionValues(decisions["deleted"]))
+}
+
 type DecisionCheck struct {
 	ID       int64
 	Origin   string
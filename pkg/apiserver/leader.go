@@ -0,0 +1,210 @@
+package apiserver
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/tomb.v2"
+
+	"github.com/crowdsecurity/go-cs-lib/trace"
+
+	"github.com/crowdsecurity/crowdsec/pkg/database"
+)
+
+// The background jobs that must run on a single LAPI instance when several of them share a database.
+// The names are the ones of the leases in the lock table.
+const (
+	LeaderRoleCAPIPull = "capi_pull"
+	LeaderRoleFlush    = "flush"
+	LeaderRolePAPIPull = "papi_pull"
+)
+
+type leaderRole struct {
+	name string
+	// run blocks until the job is done or the context is canceled
+	run     func(ctx context.Context)
+	cancel  context.CancelFunc
+	done    chan struct{}
+	expires time.Time
+	// timer stops the job when the lease can't be renewed in time
+	timer   *time.Timer
+	expired atomic.Bool
+}
+
+// leaderElector runs each role while the instance holds its lease, and renews the leases
+// three times per lease duration. When the lease is lost, or can't be renewed before it expires, the job is stopped.
+// The job is stopped a little before the expiry, in case the clocks of the other instances are ahead.
+type leaderElector struct {
+	dbClient      *database.Client
+	instance      string
+	leaseDuration time.Duration
+	roles         []*leaderRole
+	started       bool
+	tomb          tomb.Tomb
+}
+
+func newLeaderElector(dbClient *database.Client, instance string, leaseDuration time.Duration) *leaderElector {
+	return &leaderElector{
+		dbClient:      dbClient,
+		instance:      instance,
+		leaseDuration: leaseDuration,
+	}
+}
+
+// AddRole registers a job, it must be called before Start
+func (e *leaderElector) AddRole(name string, run func(ctx context.Context)) {
+	e.roles = append(e.roles, &leaderRole{name: name, run: run})
+}
+
+func (e *leaderElector) Start(ctx context.Context) {
+	e.started = true
+	e.tomb.Go(func() error {
+		return e.loop(ctx)
+	})
+}
+
+// Stop stops the jobs and releases the leases, so that another instance can take over
+func (e *leaderElector) Stop() {
+	if !e.started {
+		return
+	}
+
+	e.tomb.Kill(nil)
+
+	if err := e.tomb.Wait(); err != nil {
+		log.Errorf("leader election: %s", err)
+	}
+}
+
+func (e *leaderElector) loop(ctx context.Context) error {
+	defer trace.CatchPanic("lapi/leaderElection")
+
+	log.Infof("starting leader election for %d roles (instance: %s, lease duration: %s)", len(e.roles), e.instance, e.leaseDuration)
+
+	ticker := time.NewTicker(e.leaseDuration / 3)
+	defer ticker.Stop()
+
+	e.elect(ctx)
+
+	for {
+		select {
+		case <-ticker.C:
+			e.elect(ctx)
+		case <-e.tomb.Dying():
+			e.resign(ctx)
+			return nil
+		}
+	}
+}
+
+// elect acquires or renews the lease of each role, and starts or stops the jobs accordingly
+func (e *leaderElector) elect(ctx context.Context) {
+	for _, role := range e.roles {
+		if e.returned(ctx, role) {
+			continue
+		}
+
+		// the lease can expire before the database has answered, count from the request
+		start := time.Now()
+
+		leader, err := e.dbClient.AcquireLease(ctx, role.name, e.instance, e.leaseDuration)
+
+		switch {
+		case err != nil:
+			log.Errorf("leader election for %s: %s", role.name, err)
+			// keep running until the lease we hold expires, the timer stops the job then
+			leader = role.cancel != nil && time.Now().Before(e.deadline(role))
+		case leader:
+			role.expires = start.Add(e.leaseDuration)
+		}
+
+		switch {
+		case leader && role.cancel == nil:
+			e.startRole(ctx, role)
+		case leader && err == nil:
+			role.timer.Reset(time.Until(e.deadline(role)))
+		case !leader && role.cancel != nil:
+			log.Infof("instance %s is not the leader for %s anymore", e.instance, role.name)
+			e.stopRole(role)
+		}
+	}
+}
+
+// deadline is the time the job must be stopped by if the lease is not renewed
+func (e *leaderElector) deadline(role *leaderRole) time.Time {
+	return role.expires.Add(-e.leaseDuration / 10)
+}
+
+// returned releases the lease of a job that returned by itself (for example after an error of CAPI),
+// so that it's started again by the instance that gets the lease at the next election.
+// A job stopped by the expiry of its lease can be elected again right away.
+func (e *leaderElector) returned(ctx context.Context, role *leaderRole) bool {
+	if role.cancel == nil {
+		return false
+	}
+
+	select {
+	case <-role.done:
+	default:
+		return false
+	}
+
+	role.timer.Stop()
+	role.cancel()
+	role.cancel = nil
+
+	if role.expired.Load() {
+		log.Warningf("the lease of %s expired on instance %s, the job was stopped", role.name, e.instance)
+		return false
+	}
+
+	log.Warningf("the job %s stopped on instance %s, releasing the lease", role.name, e.instance)
+
+	if err := e.dbClient.ReleaseLease(ctx, role.name, e.instance); err != nil {
+		log.Errorf("leader election for %s: %s", role.name, err)
+	}
+
+	return true
+}
+
+func (e *leaderElector) startRole(ctx context.Context, role *leaderRole) {
+	log.Infof("instance %s is now the leader for %s", e.instance, role.name)
+
+	roleCtx, cancel := context.WithCancel(ctx)
+	role.cancel = cancel
+	role.done = make(chan struct{})
+	role.expired.Store(false)
+
+	role.timer = time.AfterFunc(time.Until(e.deadline(role)), func() {
+		role.expired.Store(true)
+		cancel()
+	})
+
+	go func() {
+		defer close(role.done)
+		role.run(roleCtx)
+	}()
+}
+
+func (e *leaderElector) stopRole(role *leaderRole) {
+	role.timer.Stop()
+	role.cancel()
+	<-role.done
+	role.cancel = nil
+}
+
+func (e *leaderElector) resign(ctx context.Context) {
+	for _, role := range e.roles {
+		if role.cancel == nil {
+			continue
+		}
+
+		e.stopRole(role)
+
+		if err := e.dbClient.ReleaseLease(ctx, role.name, e.instance); err != nil {
+			log.Errorf("leader election for %s: %s", role.name, err)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package apiserver
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeaderElection(t *testing.T) {
+	ctx := t.Context()
+	dbClient := getDBClient(t, ctx)
+
+	leaseDuration := 300 * time.Millisecond
+
+	var running [2]atomic.Bool
+
+	electors := make([]*leaderElector, 2)
+
+	for i := range electors {
+		electors[i] = newLeaderElector(dbClient, []string{"lapi-1", "lapi-2"}[i], leaseDuration)
+		electors[i].AddRole(LeaderRoleFlush, func(ctx context.Context) {
+			running[i].Store(true)
+			<-ctx.Done()
+			running[i].Store(false)
+		})
+	}
+
+	electors[0].Start(ctx)
+
+	require.Eventually(t, running[0].Load, 5*time.Second, 10*time.Millisecond)
+
+	electors[1].Start(ctx)
+	t.Cleanup(electors[1].Stop)
+
+	// the lease is renewed, the second instance waits
+	time.Sleep(2 * leaseDuration)
+	assert.True(t, running[0].Load())
+	assert.False(t, running[1].Load())
+
+	leases, err := dbClient.ListLeases(ctx)
+	require.NoError(t, err)
+	require.Len(t, leases, 1)
+	assert.Equal(t, "lapi-1", leases[0].Owner)
+
+	// the lease is released on shutdown, the second instance takes over
+	electors[0].Stop()
+	assert.False(t, running[0].Load())
+
+	require.Eventually(t, running[1].Load, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestLeaderElectionJobReturns(t *testing.T) {
+	ctx := t.Context()
+	dbClient := getDBClient(t, ctx)
+
+	leaseDuration := 300 * time.Millisecond
+
+	var runs atomic.Int32
+
+	elector := newLeaderElector(dbClient, "lapi-1", leaseDuration)
+	elector.AddRole(LeaderRoleCAPIPull, func(_ context.Context) {
+		// stops by itself, as the CAPI pull after an error
+		runs.Add(1)
+	})
+
+	elector.Start(ctx)
+	t.Cleanup(elector.Stop)
+
+	// the lease is released and the job is started again
+	require.Eventually(t, func() bool { return runs.Load() >= 2 }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestLeaderElectionLeaseExpires(t *testing.T) {
+	ctx := t.Context()
+	dbClient := getDBClient(t, ctx)
+
+	leaseDuration := 300 * time.Millisecond
+
+	var running atomic.Bool
+
+	elector := newLeaderElector(dbClient, "lapi-1", leaseDuration)
+	elector.AddRole(LeaderRoleFlush, func(ctx context.Context) {
+		running.Store(true)
+		<-ctx.Done()
+		running.Store(false)
+	})
+
+	elector.Start(ctx)
+	t.Cleanup(elector.Stop)
+
+	require.Eventually(t, running.Load, 5*time.Second, 10*time.Millisecond)
+
+	// the lease can't be renewed anymore, the job is stopped before the last one expires
+	require.NoError(t, dbClient.Ent.Close())
+
+	lost := time.Now()
+
+	require.Eventually(t, func() bool { return !running.Load() }, 5*time.Second, 10*time.Millisecond)
+	assert.Less(t, time.Since(lost), leaseDuration)
+}
@@ -237,6 +237,7 @@ type LocalApiServerCfg struct {
 	CapiWhitelistsPath            string                   `yaml:"capi_whitelists_path,omitempty"`
 	CapiWhitelists                *CapiWhitelist           `yaml:"-"`
 	AutoRegister                  *LocalAPIAutoRegisterCfg `yaml:"auto_registration,omitempty"`
+	LeaderElection                *LeaderElectionCfg       `yaml:"leader_election,omitempty"`
 }
 
 func (c *LocalApiServerCfg) GetTrustedIPs() ([]net.IPNet, error) {
@@ -280,6 +281,17 @@ type LocalAPIAutoRegisterCfg struct {
 	AllowedRangesParsed []*net.IPNet `yaml:"-"`
 }
 
+// LeaderElectionCfg allows several LAPI instances to share a database: the background jobs
+// that must not run twice (CAPI and PAPI pulls, database flush) only run on the instance holding their lease.
+// The decisions pushed to the bouncers are read from the decision change log, to include the ones of the other instances.
+type LeaderElectionCfg struct {
+	Enable        *bool         `yaml:"enabled"`
+	InstanceName  string        `yaml:"instance_name,omitempty"`
+	LeaseDuration time.Duration `yaml:"lease_duration,omitempty"`
+}
+
+const defaultLeaseDuration = 30 * time.Second
+
 func (c *LocalApiServerCfg) ClientURL() string {
 	if c == nil {
 		return ""
@@ -387,6 +399,14 @@ func (c *Config) LoadAPIServer(inCli bool) error {
 		log.Infof("auto LAPI registration enabled for ranges %+v", c.API.Server.AutoRegister.AllowedRanges)
 	}
 
+	if err := c.API.Server.LoadLeaderElection(); err != nil {
+		return err
+	}
+
+	if *c.API.Server.LeaderElection.Enable && !inCli {
+		log.Infof("leader election enabled, instance name: %s", c.API.Server.LeaderElection.InstanceName)
+	}
+
 	c.API.Server.LogDir = c.Common.LogDir
 	c.API.Server.LogMedia = c.Common.LogMedia
 	c.API.Server.CompressLogs = c.Common.CompressLogs
@@ -541,3 +561,41 @@ func (c *LocalApiServerCfg) LoadAutoRegister() error {
 
 	return nil
 }
+
+func (c *LocalApiServerCfg) LoadLeaderElection() error {
+	if c.LeaderElection == nil {
+		c.LeaderElection = &LeaderElectionCfg{
+			Enable: ptr.Of(false),
+		}
+
+		return nil
+	}
+
+	// Disable by default
+	if c.LeaderElection.Enable == nil {
+		c.LeaderElection.Enable = ptr.Of(false)
+	}
+
+	if !*c.LeaderElection.Enable {
+		return nil
+	}
+
+	if c.LeaderElection.InstanceName == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return fmt.Errorf("missing instance_name value for api.server.leader_election: %w", err)
+		}
+
+		c.LeaderElection.InstanceName = hostname
+	}
+
+	if c.LeaderElection.LeaseDuration < 0 {
+		return errors.New("lease_duration value for api.server.leader_election can't be negative")
+	}
+
+	if c.LeaderElection.LeaseDuration == 0 {
+		c.LeaderElection.LeaseDuration = defaultLeaseDuration
+	}
+
+	return nil
+}
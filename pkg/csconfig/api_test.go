@@ -228,6 +228,9 @@ func TestLoadAPIServer(t *testing.T) {
 					AllowedRanges:       nil,
 					AllowedRangesParsed: nil,
 				},
+				LeaderElection: &LeaderElectionCfg{
+					Enable: ptr.Of(false),
+				},
 			},
 		},
 		{
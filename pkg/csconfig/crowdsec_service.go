@@ -1,9 +1,11 @@
 package csconfig
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
@@ -23,14 +25,82 @@ type CrowdsecServiceCfg struct {
 	BucketsRoutinesCount      int               `yaml:"buckets_routines"`
 	OutputRoutinesCount       int               `yaml:"output_routines"`
 	SimulationConfig          *SimulationConfig `yaml:"-"`
-	BucketStateFile           string            `yaml:"state_input_file,omitempty"` // if we need to unserialize buckets at start
-	BucketStateDumpDir        string            `yaml:"state_output_dir,omitempty"` // if we need to unserialize buckets on shutdown
-	BucketsGCEnabled          bool              `yaml:"-"`                          // we need to garbage collect buckets when in forensic mode
+	BucketStateFile           string            `yaml:"state_input_file,omitempty"`     // if we need to unserialize buckets at start
+	BucketStateDumpDir        string            `yaml:"state_output_dir,omitempty"`     // if we need to unserialize buckets on shutdown
+	BucketsGCEnabled          bool              `yaml:"-"`                              // we need to garbage collect buckets when in forensic mode
+	BucketsState              *BucketsStateCfg  `yaml:"buckets_state,omitempty"`        // automatic persistence of live buckets across restarts
+	BucketsSharedState        string            `yaml:"buckets_shared_state,omitempty"` // where the level of shared buckets is kept: memory (default) or lapi
+	BucketsLimits             *BucketsLimitsCfg `yaml:"buckets_limits,omitempty"`       // caps the number of live buckets
 
 	SimulationFilePath string              `yaml:"-"`
 	ContextToSend      map[string][]string `yaml:"-"`
 }
 
+const defaultBucketsStateInterval = 5 * time.Minute
+
+// BucketsStateCfg configures the snapshot of in-flight buckets that is taken
+// on shutdown/reload (and periodically), and restored when the agent starts.
+type BucketsStateCfg struct {
+	Path string `yaml:"path,omitempty"`
+	// Interval between two periodic snapshots, 0 disables them
+	Interval *time.Duration `yaml:"interval,omitempty"`
+}
+
+// BucketsLimitsCfg caps the number of live buckets, to protect the agent from
+// scenarios with a high-cardinality groupby. A limit of 0 means unlimited.
+type BucketsLimitsCfg struct {
+	MaxBuckets            int    `yaml:"max_buckets"`              // across all the scenarios
+	MaxBucketsPerScenario int    `yaml:"max_buckets_per_scenario"` // for the scenarios that don't set max_buckets
+	EvictionPolicy        string `yaml:"eviction_policy"`          // oldest (default) or least-filled
+}
+
+func (c *BucketsLimitsCfg) validate() error {
+	if c.MaxBuckets < 0 {
+		return fmt.Errorf("buckets_limits.max_buckets can't be negative: %d", c.MaxBuckets)
+	}
+
+	if c.MaxBucketsPerScenario < 0 {
+		return fmt.Errorf("buckets_limits.max_buckets_per_scenario can't be negative: %d", c.MaxBucketsPerScenario)
+	}
+
+	switch c.EvictionPolicy {
+	case "", "oldest", "least-filled":
+	default:
+		return fmt.Errorf("invalid buckets_limits.eviction_policy '%s': must be 'oldest' or 'least-filled'", c.EvictionPolicy)
+	}
+
+	return nil
+}
+
+func (c *Config) loadBucketsState() error {
+	cfg := c.Crowdsec.BucketsState
+
+	if cfg.Path == "" {
+		if c.ConfigPaths == nil || c.ConfigPaths.DataDir == "" {
+			return errors.New("buckets_state.path is not set and there is no data_dir to default to")
+		}
+
+		cfg.Path = filepath.Join(c.ConfigPaths.DataDir, "buckets_state.json")
+	}
+
+	path, err := filepath.Abs(cfg.Path)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path of '%s': %w", cfg.Path, err)
+	}
+
+	cfg.Path = path
+
+	if cfg.Interval == nil {
+		cfg.Interval = ptr.Of(defaultBucketsStateInterval)
+	}
+
+	if *cfg.Interval < 0 {
+		return fmt.Errorf("buckets_state.interval must be positive (or 0 to disable periodic snapshots): %s", *cfg.Interval)
+	}
+
+	return nil
+}
+
 func (c *Config) LoadCrowdsec() error {
 	var err error
 
@@ -69,17 +139,9 @@ func (c *Config) LoadCrowdsec() error {
 			return fmt.Errorf("can't get absolute path of '%s': %w", c.Crowdsec.AcquisitionDirPath, err)
 		}
 
-		var files []string
-
-		files, err = filepath.Glob(c.Crowdsec.AcquisitionDirPath + "/*.yaml")
+		files, err := c.Crowdsec.AcquisitionDirFiles()
 		if err != nil {
-			return fmt.Errorf("while globbing acquis_dir: %w", err)
-		}
-		c.Crowdsec.AcquisitionFiles = append(c.Crowdsec.AcquisitionFiles, files...)
-
-		files, err = filepath.Glob(c.Crowdsec.AcquisitionDirPath + "/*.yml")
-		if err != nil {
-			return fmt.Errorf("while globbing acquis_dir: %w", err)
+			return err
 		}
 		c.Crowdsec.AcquisitionFiles = append(c.Crowdsec.AcquisitionFiles, files...)
 	}
@@ -132,6 +194,24 @@ func (c *Config) LoadCrowdsec() error {
 		c.Crowdsec.AcquisitionFiles[i] = f
 	}
 
+	switch c.Crowdsec.BucketsSharedState {
+	case "", "memory", "lapi":
+	default:
+		return fmt.Errorf("invalid buckets_shared_state '%s': must be 'memory' or 'lapi'", c.Crowdsec.BucketsSharedState)
+	}
+
+	if c.Crowdsec.BucketsLimits != nil {
+		if err = c.Crowdsec.BucketsLimits.validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.Crowdsec.BucketsState != nil {
+		if err = c.loadBucketsState(); err != nil {
+			return err
+		}
+	}
+
 	if err = c.LoadAPIClient(); err != nil {
 		return fmt.Errorf("loading api client: %w", err)
 	}
@@ -139,6 +219,22 @@ func (c *Config) LoadCrowdsec() error {
 	return nil
 }
 
+// AcquisitionDirFiles returns the .yaml and .yml files of acquisition_dir
+func (c *CrowdsecServiceCfg) AcquisitionDirFiles() ([]string, error) {
+	ret := []string{}
+
+	for _, ext := range []string{"yaml", "yml"} {
+		files, err := filepath.Glob(c.AcquisitionDirPath + "/*." + ext)
+		if err != nil {
+			return nil, fmt.Errorf("while globbing acquis_dir: %w", err)
+		}
+
+		ret = append(ret, files...)
+	}
+
+	return ret, nil
+}
+
 func (c *CrowdsecServiceCfg) DumpContextConfigFile() error {
 	// XXX: MakeDirs
 	out, err := yaml.Marshal(c.ContextToSend)
@@ -57,6 +57,8 @@ type FlushDBCfg struct {
 	BouncersGC    *AuthGCCfg     `yaml:"bouncers_autodelete,omitempty"`
 	AgentsGC      *AuthGCCfg     `yaml:"agents_autodelete,omitempty"`
 	MetricsMaxAge *time.Duration `yaml:"metrics_max_age,omitempty"`
+	// The bouncers that pull with an older revision receive all the decisions
+	DecisionChangesMaxAge *time.Duration `yaml:"decision_changes_max_age,omitempty"`
 }
 
 func (c *Config) LoadDBConfig(inCli bool) error {
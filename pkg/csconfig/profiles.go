@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
@@ -30,6 +31,19 @@ type ProfileCfg struct {
 	OnFailure     string            `yaml:"on_failure,omitempty"` // continue or break
 	OnError       string            `yaml:"on_error,omitempty"`   // continue, break, error, report, apply, ignore
 	Notifications []string          `yaml:"notifications,omitempty"`
+	Escalation    *EscalationCfg    `yaml:"escalation,omitempty"`
+}
+
+// EscalationCfg gives longer decisions to the repeat offenders. The offenses are the previous alerts
+// with a local decision for the same scope and value, during the lookback window.
+type EscalationCfg struct {
+	Lookback time.Duration `yaml:"lookback,omitempty"`
+	// Per scope, the duration of the first decision, then of the second one... The last duration is repeated.
+	// They take precedence over duration_expr.
+	Durations   map[string][]time.Duration `yaml:"durations,omitempty"`
+	MaxDuration time.Duration              `yaml:"max_duration,omitempty"`
+	// After this number of offenses, the captcha decisions are turned into bans
+	BanAfter *int `yaml:"ban_after,omitempty"`
 }
 
 func (c *LocalApiServerCfg) LoadProfiles() error {
@@ -5,4 +5,6 @@ type PrometheusCfg struct {
 	Level      string `yaml:"level"` //aggregated|full
 	ListenAddr string `yaml:"listen_addr"`
 	ListenPort int    `yaml:"listen_port"`
+	// time each parser node and stage, this has a cost on every event
+	ParserTiming bool `yaml:"parser_timing"`
 }
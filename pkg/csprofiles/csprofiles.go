@@ -9,6 +9,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/crowdsecurity/crowdsec/pkg/csconfig"
+	"github.com/crowdsecurity/crowdsec/pkg/database"
 	"github.com/crowdsecurity/crowdsec/pkg/exprhelpers"
 	"github.com/crowdsecurity/crowdsec/pkg/models"
 	"github.com/crowdsecurity/crowdsec/pkg/types"
@@ -20,6 +21,9 @@ type Runtime struct {
 	RuntimeDurationExpr *vm.Program          `json:"-" yaml:"-"`
 	Cfg                 *csconfig.ProfileCfg `json:"-" yaml:"-"`
 	Logger              *log.Entry           `json:"-" yaml:"-"`
+	// DBClient is used to count the previous offenses, when the profile has an escalation policy
+	DBClient   *database.Client `json:"-" yaml:"-"`
+	escalation *escalation
 }
 
 const defaultDuration = "4h"
@@ -74,6 +78,12 @@ func NewProfile(profilesCfg []*csconfig.ProfileCfg) ([]*Runtime, error) {
 			runtime.RuntimeDurationExpr = runtimeDurationExpr
 		}
 
+		if profile.Escalation != nil {
+			if runtime.escalation, err = newEscalation(profile.Escalation); err != nil {
+				return nil, fmt.Errorf("invalid escalation of %s: %w", profile.Name, err)
+			}
+		}
+
 		for _, decision := range profile.Decisions {
 			if runtime.RuntimeDurationExpr == nil {
 				var duration string
@@ -159,6 +169,11 @@ func (profile *Runtime) GenerateDecisionFromProfile(alert *models.Alert) ([]*mod
 
 		decision.Scenario = new(string)
 		*decision.Scenario = *alert.Scenario
+
+		if profile.escalation != nil {
+			profile.escalate(alert, &decision)
+		}
+
 		decisions = append(decisions, &decision)
 	}
 
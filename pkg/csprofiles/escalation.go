@@ -0,0 +1,106 @@
+package csprofiles
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/crowdsecurity/crowdsec/pkg/csconfig"
+	"github.com/crowdsecurity/crowdsec/pkg/models"
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+const defaultEscalationLookback = 7 * 24 * time.Hour
+
+type escalation struct {
+	lookback    time.Duration
+	durations   map[string][]time.Duration // by normalized scope
+	maxDuration time.Duration
+	banAfter    *int
+}
+
+func newEscalation(cfg *csconfig.EscalationCfg) (*escalation, error) {
+	if cfg.Lookback < 0 {
+		return nil, errors.New("lookback can't be negative")
+	}
+
+	if cfg.MaxDuration < 0 {
+		return nil, errors.New("max_duration can't be negative")
+	}
+
+	if cfg.BanAfter != nil && *cfg.BanAfter < 0 {
+		return nil, errors.New("ban_after can't be negative")
+	}
+
+	ret := &escalation{
+		lookback:    cfg.Lookback,
+		durations:   make(map[string][]time.Duration, len(cfg.Durations)),
+		maxDuration: cfg.MaxDuration,
+		banAfter:    cfg.BanAfter,
+	}
+
+	if ret.lookback == 0 {
+		ret.lookback = defaultEscalationLookback
+	}
+
+	for scope, durations := range cfg.Durations {
+		if len(durations) == 0 {
+			return nil, fmt.Errorf("no durations for scope %s", scope)
+		}
+
+		for _, duration := range durations {
+			if duration <= 0 {
+				return nil, fmt.Errorf("invalid duration %s for scope %s", duration, scope)
+			}
+		}
+
+		ret.durations[types.NormalizeScope(scope)] = durations
+	}
+
+	return ret, nil
+}
+
+// escalate adjusts the duration and the type of the decision to the number of previous offenses.
+// If they can't be counted, the decision is left as is.
+func (profile *Runtime) escalate(alert *models.Alert, decision *models.Decision) {
+	if profile.DBClient == nil {
+		profile.Logger.Warningf("no database to count the previous offenses of %s %s", *decision.Scope, *decision.Value)
+		return
+	}
+
+	now := time.Now().UTC()
+
+	// a stored alert is evaluated again: only count the offenses that came before it, not the alert itself
+	if alert.ID != 0 {
+		if createdAt, err := time.Parse(time.RFC3339, alert.CreatedAt); err == nil {
+			now = createdAt
+		}
+	}
+
+	since := now.Add(-profile.escalation.lookback)
+
+	offenses, err := profile.DBClient.CountOffenses(context.TODO(), *decision.Scope, *decision.Value, since, int(alert.ID))
+	if err != nil {
+		profile.Logger.Warningf("failed to count the previous offenses of %s %s: %s", *decision.Scope, *decision.Value, err)
+		return
+	}
+
+	if durations, ok := profile.escalation.durations[types.NormalizeScope(*decision.Scope)]; ok {
+		*decision.Duration = durations[min(offenses, len(durations)-1)].String()
+	}
+
+	if profile.escalation.maxDuration > 0 {
+		duration, err := time.ParseDuration(*decision.Duration)
+		if err == nil && duration > profile.escalation.maxDuration {
+			*decision.Duration = profile.escalation.maxDuration.String()
+		}
+	}
+
+	if profile.escalation.banAfter != nil && offenses >= *profile.escalation.banAfter && strings.EqualFold(*decision.Type, "captcha") {
+		*decision.Type = "ban"
+	}
+
+	profile.Logger.Debugf("%s %s has %d previous offenses: %s for %s", *decision.Scope, *decision.Value, offenses, *decision.Type, *decision.Duration)
+}
@@ -0,0 +1,247 @@
+package csprofiles
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/crowdsecurity/go-cs-lib/ptr"
+
+	"github.com/crowdsecurity/crowdsec/pkg/csconfig"
+	"github.com/crowdsecurity/crowdsec/pkg/database"
+	"github.com/crowdsecurity/crowdsec/pkg/models"
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+func getDBClient(t *testing.T) *database.Client {
+	t.Helper()
+
+	dbClient, err := database.NewClient(t.Context(), &csconfig.DatabaseCfg{
+		Type:   "sqlite",
+		DbName: "crowdsec",
+		DbPath: ":memory:",
+	})
+	require.NoError(t, err)
+
+	return dbClient
+}
+
+func testAlert(scope string, value string) *models.Alert {
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	return &models.Alert{
+		Scenario:        ptr.Of("crowdsecurity/ssh-bf"),
+		ScenarioHash:    ptr.Of(""),
+		ScenarioVersion: ptr.Of(""),
+		Message:         ptr.Of(""),
+		EventsCount:     ptr.Of(int32(1)),
+		Capacity:        ptr.Of(int32(5)),
+		Leakspeed:       ptr.Of("10s"),
+		StartAt:         ptr.Of(now),
+		StopAt:          ptr.Of(now),
+		Simulated:       ptr.Of(false),
+		Remediation:     true,
+		Source:          &models.Source{Scope: ptr.Of(scope), Value: ptr.Of(value)},
+	}
+}
+
+// addOffense stores an alert with a decision, as if it had been created by a previous evaluation, and returns its id
+func addOffense(t *testing.T, dbClient *database.Client, scope string, value string, origin string) int64 {
+	t.Helper()
+
+	alert := testAlert(scope, value)
+	alert.Decisions = []*models.Decision{{
+		Duration: ptr.Of("4h"),
+		Scenario: alert.Scenario,
+		Scope:    ptr.Of(scope),
+		Value:    ptr.Of(value),
+		Type:     ptr.Of("ban"),
+		Origin:   ptr.Of(origin),
+	}}
+
+	ids, err := dbClient.CreateAlert(t.Context(), "", []*models.Alert{alert})
+	require.NoError(t, err)
+	require.Len(t, ids, 1)
+
+	id, err := strconv.ParseInt(ids[0], 10, 64)
+	require.NoError(t, err)
+
+	return id
+}
+
+func TestEscalation(t *testing.T) {
+	dbClient := getDBClient(t)
+
+	profileCfg := &csconfig.ProfileCfg{
+		Name:    "escalation",
+		Filters: []string{"1==1"},
+		Decisions: []models.Decision{
+			{Type: ptr.Of("captcha"), Duration: ptr.Of("1h")},
+		},
+		Escalation: &csconfig.EscalationCfg{
+			Durations: map[string][]time.Duration{
+				"ip": {4 * time.Hour, 24 * time.Hour, 168 * time.Hour},
+			},
+			MaxDuration: 72 * time.Hour,
+			BanAfter:    ptr.Of(2),
+		},
+	}
+
+	profiles, err := NewProfile([]*csconfig.ProfileCfg{profileCfg})
+	require.NoError(t, err)
+
+	profile := profiles[0]
+	profile.DBClient = dbClient
+
+	evaluate := func(alert *models.Alert) *models.Decision {
+		decisions, matched, err := profile.EvaluateProfile(alert)
+		require.NoError(t, err)
+		require.True(t, matched)
+		require.Len(t, decisions, 1)
+
+		return decisions[0]
+	}
+
+	// first offense
+	decision := evaluate(testAlert(types.Ip, "1.2.3.4"))
+	assert.Equal(t, "4h0m0s", *decision.Duration)
+	assert.Equal(t, "captcha", *decision.Type)
+
+	addOffense(t, dbClient, types.Ip, "1.2.3.4", types.CrowdSecOrigin)
+
+	decision = evaluate(testAlert(types.Ip, "1.2.3.4"))
+	assert.Equal(t, "24h0m0s", *decision.Duration)
+	assert.Equal(t, "captcha", *decision.Type)
+
+	// the community blocklist does not count
+	addOffense(t, dbClient, types.Ip, "1.2.3.4", types.CAPIOrigin)
+
+	decision = evaluate(testAlert(types.Ip, "1.2.3.4"))
+	assert.Equal(t, "24h0m0s", *decision.Duration)
+
+	addOffense(t, dbClient, types.Ip, "1.2.3.4", types.CscliOrigin)
+
+	// the last step is capped, and the captcha is turned into a ban
+	decision = evaluate(testAlert(types.Ip, "1.2.3.4"))
+	assert.Equal(t, "72h0m0s", *decision.Duration)
+	assert.Equal(t, "ban", *decision.Type)
+
+	addOffense(t, dbClient, types.Ip, "1.2.3.4", types.CrowdSecOrigin)
+
+	decision = evaluate(testAlert(types.Ip, "1.2.3.4"))
+	assert.Equal(t, "72h0m0s", *decision.Duration)
+
+	// other values and scopes have their own offenses
+	decision = evaluate(testAlert(types.Ip, "1.2.3.5"))
+	assert.Equal(t, "4h0m0s", *decision.Duration)
+	assert.Equal(t, "captcha", *decision.Type)
+
+	// no ladder for the scope, the profile duration is kept
+	addOffense(t, dbClient, types.Range, "1.2.3.0/24", types.CrowdSecOrigin)
+	addOffense(t, dbClient, types.Range, "1.2.3.0/24", types.CrowdSecOrigin)
+
+	decision = evaluate(testAlert(types.Range, "1.2.3.0/24"))
+	assert.Equal(t, "1h", *decision.Duration)
+	assert.Equal(t, "ban", *decision.Type)
+
+	// a stored alert that is evaluated again only counts the offenses that came before it, not itself
+	addOffense(t, dbClient, types.Ip, "1.2.3.9", types.CrowdSecOrigin)
+
+	stored := testAlert(types.Ip, "1.2.3.9")
+	stored.ID = addOffense(t, dbClient, types.Ip, "1.2.3.9", types.CrowdSecOrigin)
+	stored.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+
+	addOffense(t, dbClient, types.Ip, "1.2.3.9", types.CrowdSecOrigin)
+
+	decision = evaluate(stored)
+	assert.Equal(t, "24h0m0s", *decision.Duration)
+	assert.Equal(t, "captcha", *decision.Type)
+}
+
+func TestEscalationLookback(t *testing.T) {
+	dbClient := getDBClient(t)
+
+	profileCfg := &csconfig.ProfileCfg{
+		Filters: []string{"1==1"},
+		Decisions: []models.Decision{
+			{Type: ptr.Of("ban"), Duration: ptr.Of("4h")},
+		},
+		Escalation: &csconfig.EscalationCfg{
+			Lookback: 500 * time.Millisecond,
+			Durations: map[string][]time.Duration{
+				"Ip": {time.Hour, 2 * time.Hour},
+			},
+		},
+	}
+
+	profiles, err := NewProfile([]*csconfig.ProfileCfg{profileCfg})
+	require.NoError(t, err)
+
+	profile := profiles[0]
+	profile.DBClient = dbClient
+
+	addOffense(t, dbClient, types.Ip, "1.2.3.4", types.CrowdSecOrigin)
+
+	decisions, _, err := profile.EvaluateProfile(testAlert(types.Ip, "1.2.3.4"))
+	require.NoError(t, err)
+	assert.Equal(t, "2h0m0s", *decisions[0].Duration)
+
+	time.Sleep(time.Second)
+
+	decisions, _, err = profile.EvaluateProfile(testAlert(types.Ip, "1.2.3.4"))
+	require.NoError(t, err)
+	assert.Equal(t, "1h0m0s", *decisions[0].Duration)
+}
+
+func TestEscalationConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		escalation  *csconfig.EscalationCfg
+		expectedErr string
+	}{
+		{
+			name:       "defaults",
+			escalation: &csconfig.EscalationCfg{},
+		},
+		{
+			name:        "negative lookback",
+			escalation:  &csconfig.EscalationCfg{Lookback: -time.Hour},
+			expectedErr: "invalid escalation of test: lookback can't be negative",
+		},
+		{
+			name:        "empty ladder",
+			escalation:  &csconfig.EscalationCfg{Durations: map[string][]time.Duration{"ip": {}}},
+			expectedErr: "invalid escalation of test: no durations for scope ip",
+		},
+		{
+			name:        "zero duration",
+			escalation:  &csconfig.EscalationCfg{Durations: map[string][]time.Duration{"ip": {time.Hour, 0}}},
+			expectedErr: "invalid escalation of test: invalid duration 0s for scope ip",
+		},
+		{
+			name:        "negative ban_after",
+			escalation:  &csconfig.EscalationCfg{BanAfter: ptr.Of(-1)},
+			expectedErr: "invalid escalation of test: ban_after can't be negative",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			profiles, err := NewProfile([]*csconfig.ProfileCfg{{
+				Name:       "test",
+				Filters:    []string{"1==1"},
+				Escalation: tc.escalation,
+			}})
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, defaultEscalationLookback, profiles[0].escalation.lookback)
+		})
+	}
+}
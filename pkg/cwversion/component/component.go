@@ -16,7 +16,12 @@ var Built = map[string]bool{
 	"datasource_k8s-audit":    false,
 	"datasource_kafka":        false,
 	"datasource_kinesis":      false,
+	"datasource_kubernetes":   false,
 	"datasource_loki":         false,
+	"datasource_nats":         false,
+	"datasource_otlp":         false,
+	"datasource_rabbitmq":     false,
+	"datasource_redis":        false,
 	"datasource_s3":           false,
 	"datasource_syslog":       false,
 	"datasource_wineventlog":  false,
@@ -18,6 +18,7 @@ import (
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/alert"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/decision"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/decisionchange"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/event"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/meta"
 	"github.com/crowdsecurity/crowdsec/pkg/models"
@@ -180,6 +181,8 @@ func (c *Client) CreateOrUpdateAlert(ctx context.Context, machineID string, aler
 		decisions = append(decisions, decisionsCreateRet...)
 	}
 
+	c.recordDecisionChanges(ctx, decisionchange.TypeNew, decisions)
+
 	// now that we bulk created missing decisions, let's update the alert
 
 	decisionChunks := slicetools.Chunks(decisions, c.decisionBulkSize)
@@ -274,7 +277,7 @@ func (c *Client) UpdateCommunityBlocklist(ctx context.Context, alertItem *models
 	}
 
 	deleted := 0
-	inserted := 0
+	inserted := []*ent.Decision{}
 
 	decisionBuilders := make([]*ent.DecisionCreate, 0, len(alertItem.Decisions))
 	valueList := make([]string, 0, len(alertItem.Decisions))
@@ -360,7 +363,7 @@ func (c *Client) UpdateCommunityBlocklist(ctx context.Context, alertItem *models
 			return 0, 0, 0, rollbackOnError(txClient, err, "bulk creating decisions")
 		}
 
-		inserted += len(insertedDecisions)
+		inserted = append(inserted, insertedDecisions...)
 	}
 
 	log.Debugf("deleted %d decisions for %s vs %s", deleted, DecOrigin, *alertItem.Decisions[0].Origin)
@@ -370,7 +373,10 @@ func (c *Client) UpdateCommunityBlocklist(ctx context.Context, alertItem *models
 		return 0, 0, 0, rollbackOnError(txClient, err, "error committing transaction")
 	}
 
-	return alertRef.ID, inserted, deleted, nil
+	// outside of the transaction, to keep the changes in the order they are committed
+	c.recordDecisionChanges(ctx, decisionchange.TypeNew, inserted)
+
+	return alertRef.ID, len(inserted), deleted, nil
 }
 
 func (c *Client) createDecisionChunk(ctx context.Context, simulated bool, stopAtTime time.Time, decisions []*models.Decision) ([]*ent.Decision, error) {
@@ -423,6 +429,8 @@ func (c *Client) createDecisionChunk(ctx context.Context, simulated bool, stopAt
 		return nil, err
 	}
 
+	c.recordDecisionChanges(ctx, decisionchange.TypeNew, ret)
+
 	return ret, nil
 }
 
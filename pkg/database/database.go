@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sync"
+	"time"
 
 	entsql "entgo.io/ent/dialect/sql"
 	// load database backends
@@ -26,6 +28,9 @@ type Client struct {
 	Type             string
 	WalMode          *bool
 	decisionBulkSize int
+	// the decisions that expired before this time are in the change log
+	expiredMu    sync.Mutex
+	expiredSince *time.Time
 }
 
 func getEntDriver(dbtype string, dbdialect string, dsn string, config *csconfig.DatabaseCfg) (*entsql.Driver, error) {
@@ -0,0 +1,244 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/pkg/errors"
+
+	"github.com/crowdsecurity/go-cs-lib/slicetools"
+
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/decision"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/decisionchange"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/predicate"
+)
+
+// The revision given to a bouncer leaves out the most recent changes: concurrent inserts
+// can be committed out of order, and a change that is not visible yet would be skipped.
+// The recent changes are sent again with the next pull.
+var decisionRevisionSettleTime = 5 * time.Second
+
+// Same as decisionRevisionSettleTime, for the changes that are pushed to the bouncers: the decisions
+// added or deleted through another LAPI instance are pushed after this delay.
+var decisionFeedSettleTime = 2 * time.Second
+
+// recordDecisionChanges adds the decisions to the change log. The decisions are already committed,
+// the error is only logged: at worst, the bouncers that pull by revision miss them.
+func (c *Client) recordDecisionChanges(ctx context.Context, changeType decisionchange.Type, decisions []*ent.Decision) {
+	for _, chunk := range slicetools.Chunks(decisions, c.decisionBulkSize) {
+		builders := make([]*ent.DecisionChangeCreate, len(chunk))
+
+		for i, d := range chunk {
+			builders[i] = c.Ent.DecisionChange.Create().
+				SetType(changeType).
+				SetDecisionID(d.ID)
+		}
+
+		if err := c.Ent.DecisionChange.CreateBulk(builders...).Exec(ctx); err != nil {
+			c.Log.Errorf("unable to record %d %s decisions in the change log: %s", len(chunk), changeType, err)
+			return
+		}
+	}
+}
+
+// recordExpiredDecisions is the flush job of RecordExpiredDecisions
+func (c *Client) recordExpiredDecisions(ctx context.Context) {
+	if err := c.RecordExpiredDecisions(ctx); err != nil {
+		c.Log.Errorf("while recording expired decisions: %s", err)
+	}
+}
+
+// RecordExpiredDecisions adds the decisions that reached their end since the last call to the change log,
+// so that they are sent as deleted to the bouncers that pull by revision.
+func (c *Client) RecordExpiredDecisions(ctx context.Context) error {
+	c.expiredMu.Lock()
+	defer c.expiredMu.Unlock()
+
+	now := time.Now().UTC()
+
+	if c.expiredSince == nil {
+		// resume after the last change, the decisions may have expired while we were not running
+		last, err := c.Ent.DecisionChange.Query().Order(ent.Desc(decisionchange.FieldID)).First(ctx)
+
+		switch {
+		case ent.IsNotFound(err):
+			c.expiredSince = &now
+		case err != nil:
+			return fmt.Errorf("querying the last decision change: %w", err)
+		default:
+			c.expiredSince = &last.CreatedAt
+		}
+	}
+
+	expired, err := c.Ent.Decision.Query().Where(
+		decision.UntilGT(*c.expiredSince),
+		decision.UntilLTE(now),
+	).All(ctx)
+	if err != nil {
+		return fmt.Errorf("querying expired decisions: %w", err)
+	}
+
+	// the decisions that were deleted are already in the change log
+	recorded := map[int]bool{}
+
+	for _, chunk := range slicetools.Chunks(decisionIDs(expired), decisionDeleteBulkSize) {
+		changes, err := c.Ent.DecisionChange.Query().Where(
+			decisionchange.TypeEQ(decisionchange.TypeDeleted),
+			decisionchange.DecisionIDIn(chunk...),
+		).All(ctx)
+		if err != nil {
+			return fmt.Errorf("querying deleted decisions: %w", err)
+		}
+
+		for _, change := range changes {
+			recorded[change.DecisionID] = true
+		}
+	}
+
+	toRecord := []*ent.Decision{}
+
+	for _, d := range expired {
+		if !recorded[d.ID] {
+			toRecord = append(toRecord, d)
+		}
+	}
+
+	c.recordDecisionChanges(ctx, decisionchange.TypeDeleted, toRecord)
+
+	c.expiredSince = &now
+
+	return nil
+}
+
+// DecisionRevision returns the revision to give to the bouncers, for their next pull
+func (c *Client) DecisionRevision(ctx context.Context) (int, error) {
+	last, err := c.Ent.DecisionChange.Query().
+		Where(decisionchange.CreatedAtLTE(time.Now().UTC().Add(-decisionRevisionSettleTime))).
+		Order(ent.Desc(decisionchange.FieldID)).
+		First(ctx)
+
+	switch {
+	case ent.IsNotFound(err):
+		return 0, nil
+	case err != nil:
+		return 0, errors.Wrap(QueryFail, "decision revision")
+	}
+
+	return last.ID, nil
+}
+
+// DecisionChangesAfter returns the changes after the revision, in order, and their decisions by id. The decisions that
+// were flushed are not returned. The most recent changes are left out, they are returned by a later call.
+func (c *Client) DecisionChangesAfter(ctx context.Context, revision int, limit int) ([]*ent.DecisionChange, map[int]*ent.Decision, error) {
+	changes, err := c.Ent.DecisionChange.Query().
+		Where(
+			decisionchange.IDGT(revision),
+			decisionchange.CreatedAtLTE(time.Now().UTC().Add(-decisionFeedSettleTime)),
+		).
+		Order(ent.Asc(decisionchange.FieldID)).
+		Limit(limit).
+		All(ctx)
+	if err != nil {
+		return nil, nil, errors.Wrapf(QueryFail, "decision changes after revision %d: %s", revision, err)
+	}
+
+	ids := make([]int, len(changes))
+	for i, change := range changes {
+		ids[i] = change.DecisionID
+	}
+
+	decisions := make(map[int]*ent.Decision, len(ids))
+
+	for _, chunk := range slicetools.Chunks(ids, decisionDeleteBulkSize) {
+		data, err := c.Ent.Decision.Query().Where(decision.IDIn(chunk...)).All(ctx)
+		if err != nil {
+			return nil, nil, errors.Wrapf(QueryFail, "decisions of the changes after revision %d: %s", revision, err)
+		}
+
+		for _, d := range data {
+			decisions[d.ID] = d
+		}
+	}
+
+	return changes, decisions, nil
+}
+
+// DecisionRevisionKnown returns false if the changes following the revision are not in the change log anymore,
+// or if the revision does not come from this database: the bouncer must receive all the decisions.
+func (c *Client) DecisionRevisionKnown(ctx context.Context, revision int) (bool, error) {
+	var bounds []struct {
+		First *int `json:"min"`
+		Last  *int `json:"max"`
+	}
+
+	err := c.Ent.DecisionChange.Query().
+		Aggregate(ent.Min(decisionchange.FieldID), ent.Max(decisionchange.FieldID)).
+		Scan(ctx, &bounds)
+	if err != nil {
+		return false, errors.Wrap(QueryFail, "decision revision bounds")
+	}
+
+	if len(bounds) == 0 || bounds[0].First == nil || bounds[0].Last == nil {
+		return false, nil
+	}
+
+	return revision >= *bounds[0].First-1 && revision <= *bounds[0].Last, nil
+}
+
+// changedSince selects the decisions with a change of the given type after the revision
+func changedSince(changeType decisionchange.Type, revision int) predicate.Decision {
+	return func(s *sql.Selector) {
+		t := sql.Table(decisionchange.Table)
+		s.Where(sql.In(
+			s.C(decision.FieldID),
+			sql.Select(t.C(decisionchange.FieldDecisionID)).From(t).Where(sql.And(
+				sql.GT(t.C(decisionchange.FieldID), revision),
+				sql.EQ(t.C(decisionchange.FieldType), string(changeType)),
+			)),
+		))
+	}
+}
+
+func (c *Client) queryDecisionsSinceRevision(ctx context.Context, changeType decisionchange.Type, revision int, filters map[string][]string) ([]*ent.Decision, error) {
+	query := c.Ent.Decision.Query().Where(changedSince(changeType, revision))
+
+	if changeType == decisionchange.TypeNew {
+		query = query.Where(decision.UntilGT(time.Now().UTC()))
+	} else {
+		query = query.Where(decision.UntilLTE(time.Now().UTC()))
+	}
+
+	// Allow a bouncer to ask for non-deduplicated results
+	if v, ok := filters["dedup"]; !ok || v[0] != "false" {
+		query = query.Where(longestDecisionForScopeTypeValue)
+	}
+
+	query, err := BuildDecisionRequestWithFilter(query, filters)
+	if err != nil {
+		c.Log.Warningf("queryDecisionsSinceRevision : %s", err)
+		return []*ent.Decision{}, errors.Wrapf(QueryFail, "%s decisions since revision %d", changeType, revision)
+	}
+
+	query = query.Order(ent.Asc(decision.FieldID))
+
+	data, err := query.All(ctx)
+	if err != nil {
+		c.Log.Warningf("queryDecisionsSinceRevision : %s", err)
+		return []*ent.Decision{}, errors.Wrapf(QueryFail, "%s decisions since revision %d", changeType, revision)
+	}
+
+	return data, nil
+}
+
+// QueryNewDecisionsSinceRevisionWithFilters returns the active decisions that were added after the revision
+func (c *Client) QueryNewDecisionsSinceRevisionWithFilters(ctx context.Context, revision int, filters map[string][]string) ([]*ent.Decision, error) {
+	return c.queryDecisionsSinceRevision(ctx, decisionchange.TypeNew, revision, filters)
+}
+
+// QueryDeletedDecisionsSinceRevisionWithFilters returns the decisions that were deleted or expired after the revision
+func (c *Client) QueryDeletedDecisionsSinceRevisionWithFilters(ctx context.Context, revision int, filters map[string][]string) ([]*ent.Decision, error) {
+	return c.queryDecisionsSinceRevision(ctx, decisionchange.TypeDeleted, revision, filters)
+}
@@ -0,0 +1,125 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/crowdsecurity/go-cs-lib/ptr"
+
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent"
+	"github.com/crowdsecurity/crowdsec/pkg/models"
+)
+
+func testDecision(value string, duration string) *models.Decision {
+	return &models.Decision{
+		Duration: ptr.Of(duration),
+		Scenario: ptr.Of("crowdsecurity/test"),
+		Scope:    ptr.Of("Ip"),
+		Value:    ptr.Of(value),
+		Type:     ptr.Of("ban"),
+		Origin:   ptr.Of("crowdsec"),
+	}
+}
+
+func decisionValues(decisions []*ent.Decision) []string {
+	ret := []string{}
+	for _, d := range decisions {
+		ret = append(ret, d.Value)
+	}
+
+	return ret
+}
+
+func TestDecisionRevision(t *testing.T) {
+	ctx := t.Context()
+	dbClient := getDBClient(t, ctx)
+
+	decisionRevisionSettleTime = 0
+	decisionFeedSettleTime = 0
+
+	t.Cleanup(func() {
+		decisionRevisionSettleTime = 5 * time.Second
+		decisionFeedSettleTime = 2 * time.Second
+	})
+
+	revision, err := dbClient.DecisionRevision(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, revision)
+
+	known, err := dbClient.DecisionRevisionKnown(ctx, 0)
+	require.NoError(t, err)
+	assert.False(t, known)
+
+	created, err := dbClient.createDecisionChunk(ctx, false, time.Now().UTC(), []*models.Decision{
+		testDecision("1.2.3.4", "1h"),
+		testDecision("1.2.3.5", "1h"),
+		testDecision("1.2.3.6", "1s"),
+	})
+	require.NoError(t, err)
+	require.Len(t, created, 3)
+
+	revision, err = dbClient.DecisionRevision(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, revision)
+
+	for rev, expected := range map[int]bool{0: true, 3: true, 4: false} {
+		known, err = dbClient.DecisionRevisionKnown(ctx, rev)
+		require.NoError(t, err)
+		assert.Equal(t, expected, known, "revision %d", rev)
+	}
+
+	decisions, err := dbClient.QueryNewDecisionsSinceRevisionWithFilters(ctx, 0, map[string][]string{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.2.3.4", "1.2.3.5", "1.2.3.6"}, decisionValues(decisions))
+
+	// deleted before its end
+	_, err = dbClient.ExpireDecisions(ctx, created[:1])
+	require.NoError(t, err)
+
+	// reaches its end
+	time.Sleep(1100 * time.Millisecond)
+
+	require.NoError(t, dbClient.RecordExpiredDecisions(ctx))
+	// the decisions are not recorded twice
+	require.NoError(t, dbClient.RecordExpiredDecisions(ctx))
+
+	revision, err = dbClient.DecisionRevision(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 5, revision)
+
+	decisions, err = dbClient.QueryNewDecisionsSinceRevisionWithFilters(ctx, 3, map[string][]string{})
+	require.NoError(t, err)
+	assert.Empty(t, decisions)
+
+	decisions, err = dbClient.QueryDeletedDecisionsSinceRevisionWithFilters(ctx, 3, map[string][]string{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.2.3.4", "1.2.3.6"}, decisionValues(decisions))
+
+	decisions, err = dbClient.QueryDeletedDecisionsSinceRevisionWithFilters(ctx, 4, map[string][]string{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.2.3.6"}, decisionValues(decisions))
+
+	decisions, err = dbClient.QueryNewDecisionsSinceRevisionWithFilters(ctx, 0, map[string][]string{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.2.3.5"}, decisionValues(decisions))
+
+	changes, byID, err := dbClient.DecisionChangesAfter(ctx, 2, 2)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, 3, changes[0].ID)
+	assert.Equal(t, "1.2.3.6", byID[changes[0].DecisionID].Value)
+	assert.Equal(t, "deleted", changes[1].Type.String())
+	assert.Equal(t, "1.2.3.4", byID[changes[1].DecisionID].Value)
+
+	// the last change is kept
+	dbClient.flushDecisionChanges(ctx, ptr.Of(time.Duration(0)))
+
+	for rev, expected := range map[int]bool{3: false, 4: true, 5: true} {
+		known, err = dbClient.DecisionRevisionKnown(ctx, rev)
+		require.NoError(t, err)
+		assert.Equal(t, expected, known, "revision %d", rev)
+	}
+}
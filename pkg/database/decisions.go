@@ -13,7 +13,9 @@ import (
 	"github.com/crowdsecurity/go-cs-lib/slicetools"
 
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/alert"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/decision"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/decisionchange"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/predicate"
 	"github.com/crowdsecurity/crowdsec/pkg/types"
 )
@@ -227,6 +229,20 @@ func (c *Client) QueryDecisionWithFilter(ctx context.Context, filter map[string]
 	return data, nil
 }
 
+// QueryDecisionsByAlertIDs returns the decisions of the given alerts
+func (c *Client) QueryDecisionsByAlertIDs(ctx context.Context, alertIDs []int) ([]*ent.Decision, error) {
+	data, err := c.Ent.Decision.Query().
+		Where(decision.HasOwnerWith(alert.IDIn(alertIDs...))).
+		Order(ent.Asc(decision.FieldID)).
+		All(ctx)
+	if err != nil {
+		c.Log.Warningf("QueryDecisionsByAlertIDs : %s", err)
+		return []*ent.Decision{}, errors.Wrap(QueryFail, "query decisions by alert ids")
+	}
+
+	return data, nil
+}
+
 // ent translation of https://stackoverflow.com/a/28090544
 func longestDecisionForScopeTypeValue(s *sql.Selector) {
 	t := sql.Table(decision.Table)
@@ -581,6 +597,8 @@ func (c *Client) ExpireDecisions(ctx context.Context, decisions []*ent.Decision)
 			return 0, fmt.Errorf("expire decisions with provided filter: %w", err)
 		}
 
+		c.recordDecisionChanges(ctx, decisionchange.TypeDeleted, decisions)
+
 		return rows, nil
 	}
 
@@ -763,6 +781,32 @@ func (c *Client) CountDecisionsSinceByValue(ctx context.Context, decisionValue s
 	return count, nil
 }
 
+// CountOffenses returns the number of alerts that led to a decision on the scope and value since the given time.
+// The decisions from the community blocklist and the third-party lists are not offenses.
+// If beforeAlert is not zero, only the alerts stored before that one are counted: the ids are given in order,
+// unlike the creation times that can be the same, and the decisions are inserted before their alert.
+func (c *Client) CountOffenses(ctx context.Context, scope string, value string, since time.Time, beforeAlert int) (int, error) {
+	query := c.Ent.Alert.Query().Where(
+		alert.HasDecisionsWith(
+			decision.ScopeEqualFold(scope),
+			decision.ValueEQ(value),
+			decision.CreatedAtGT(since),
+			decision.OriginNotIn(types.CAPIOrigin, types.ListOrigin),
+		),
+	)
+
+	if beforeAlert != 0 {
+		query = query.Where(alert.IDLT(beforeAlert))
+	}
+
+	count, err := query.Count(ctx)
+	if err != nil {
+		return 0, errors.Wrapf(QueryFail, "counting offenses for %s %s: %s", scope, value, err)
+	}
+
+	return count, nil
+}
+
 func applyStartIpEndIpFilter(decisions *ent.DecisionQuery, contains bool, ip_sz int, start_ip int64, start_sfx int64, end_ip int64, end_sfx int64) (*ent.DecisionQuery, error) {
 	if ip_sz == 4 {
 		if contains {
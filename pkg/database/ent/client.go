@@ -21,11 +21,13 @@ import (
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/bouncer"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/configitem"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/decision"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/decisionchange"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/event"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/lock"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/machine"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/meta"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/metric"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/sharedbucket"
 )
 
 // Client is the client that holds all ent builders.
@@ -45,6 +47,8 @@ type Client struct {
 	ConfigItem *ConfigItemClient
 	// Decision is the client for interacting with the Decision builders.
 	Decision *DecisionClient
+	// DecisionChange is the client for interacting with the DecisionChange builders.
+	DecisionChange *DecisionChangeClient
 	// Event is the client for interacting with the Event builders.
 	Event *EventClient
 	// Lock is the client for interacting with the Lock builders.
@@ -55,6 +59,8 @@ type Client struct {
 	Meta *MetaClient
 	// Metric is the client for interacting with the Metric builders.
 	Metric *MetricClient
+	// SharedBucket is the client for interacting with the SharedBucket builders.
+	SharedBucket *SharedBucketClient
 }
 
 // NewClient creates a new client configured with the given options.
@@ -72,11 +78,13 @@ func (c *Client) init() {
 	c.Bouncer = NewBouncerClient(c.config)
 	c.ConfigItem = NewConfigItemClient(c.config)
 	c.Decision = NewDecisionClient(c.config)
+	c.DecisionChange = NewDecisionChangeClient(c.config)
 	c.Event = NewEventClient(c.config)
 	c.Lock = NewLockClient(c.config)
 	c.Machine = NewMachineClient(c.config)
 	c.Meta = NewMetaClient(c.config)
 	c.Metric = NewMetricClient(c.config)
+	c.SharedBucket = NewSharedBucketClient(c.config)
 }
 
 type (
@@ -167,19 +175,21 @@ func (c *Client) Tx(ctx context.Context) (*Tx, error) {
 	cfg := c.config
 	cfg.driver = tx
 	return &Tx{
-		ctx:           ctx,
-		config:        cfg,
-		Alert:         NewAlertClient(cfg),
-		AllowList:     NewAllowListClient(cfg),
-		AllowListItem: NewAllowListItemClient(cfg),
-		Bouncer:       NewBouncerClient(cfg),
-		ConfigItem:    NewConfigItemClient(cfg),
-		Decision:      NewDecisionClient(cfg),
-		Event:         NewEventClient(cfg),
-		Lock:          NewLockClient(cfg),
-		Machine:       NewMachineClient(cfg),
-		Meta:          NewMetaClient(cfg),
-		Metric:        NewMetricClient(cfg),
+		ctx:            ctx,
+		config:         cfg,
+		Alert:          NewAlertClient(cfg),
+		AllowList:      NewAllowListClient(cfg),
+		AllowListItem:  NewAllowListItemClient(cfg),
+		Bouncer:        NewBouncerClient(cfg),
+		ConfigItem:     NewConfigItemClient(cfg),
+		Decision:       NewDecisionClient(cfg),
+		DecisionChange: NewDecisionChangeClient(cfg),
+		Event:          NewEventClient(cfg),
+		Lock:           NewLockClient(cfg),
+		Machine:        NewMachineClient(cfg),
+		Meta:           NewMetaClient(cfg),
+		Metric:         NewMetricClient(cfg),
+		SharedBucket:   NewSharedBucketClient(cfg),
 	}, nil
 }
 
@@ -197,19 +207,21 @@ func (c *Client) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error)
 	cfg := c.config
 	cfg.driver = &txDriver{tx: tx, drv: c.driver}
 	return &Tx{
-		ctx:           ctx,
-		config:        cfg,
-		Alert:         NewAlertClient(cfg),
-		AllowList:     NewAllowListClient(cfg),
-		AllowListItem: NewAllowListItemClient(cfg),
-		Bouncer:       NewBouncerClient(cfg),
-		ConfigItem:    NewConfigItemClient(cfg),
-		Decision:      NewDecisionClient(cfg),
-		Event:         NewEventClient(cfg),
-		Lock:          NewLockClient(cfg),
-		Machine:       NewMachineClient(cfg),
-		Meta:          NewMetaClient(cfg),
-		Metric:        NewMetricClient(cfg),
+		ctx:            ctx,
+		config:         cfg,
+		Alert:          NewAlertClient(cfg),
+		AllowList:      NewAllowListClient(cfg),
+		AllowListItem:  NewAllowListItemClient(cfg),
+		Bouncer:        NewBouncerClient(cfg),
+		ConfigItem:     NewConfigItemClient(cfg),
+		Decision:       NewDecisionClient(cfg),
+		DecisionChange: NewDecisionChangeClient(cfg),
+		Event:          NewEventClient(cfg),
+		Lock:           NewLockClient(cfg),
+		Machine:        NewMachineClient(cfg),
+		Meta:           NewMetaClient(cfg),
+		Metric:         NewMetricClient(cfg),
+		SharedBucket:   NewSharedBucketClient(cfg),
 	}, nil
 }
 
@@ -240,7 +252,7 @@ func (c *Client) Close() error {
 func (c *Client) Use(hooks ...Hook) {
 	for _, n := range []interface{ Use(...Hook) }{
 		c.Alert, c.AllowList, c.AllowListItem, c.Bouncer, c.ConfigItem, c.Decision,
-		c.Event, c.Lock, c.Machine, c.Meta, c.Metric,
+		c.DecisionChange, c.Event, c.Lock, c.Machine, c.Meta, c.Metric, c.SharedBucket,
 	} {
 		n.Use(hooks...)
 	}
@@ -251,7 +263,7 @@ func (c *Client) Use(hooks ...Hook) {
 func (c *Client) Intercept(interceptors ...Interceptor) {
 	for _, n := range []interface{ Intercept(...Interceptor) }{
 		c.Alert, c.AllowList, c.AllowListItem, c.Bouncer, c.ConfigItem, c.Decision,
-		c.Event, c.Lock, c.Machine, c.Meta, c.Metric,
+		c.DecisionChange, c.Event, c.Lock, c.Machine, c.Meta, c.Metric, c.SharedBucket,
 	} {
 		n.Intercept(interceptors...)
 	}
@@ -272,6 +284,8 @@ func (c *Client) Mutate(ctx context.Context, m Mutation) (Value, error) {
 		return c.ConfigItem.mutate(ctx, m)
 	case *DecisionMutation:
 		return c.Decision.mutate(ctx, m)
+	case *DecisionChangeMutation:
+		return c.DecisionChange.mutate(ctx, m)
 	case *EventMutation:
 		return c.Event.mutate(ctx, m)
 	case *LockMutation:
@@ -282,6 +296,8 @@ func (c *Client) Mutate(ctx context.Context, m Mutation) (Value, error) {
 		return c.Meta.mutate(ctx, m)
 	case *MetricMutation:
 		return c.Metric.mutate(ctx, m)
+	case *SharedBucketMutation:
+		return c.SharedBucket.mutate(ctx, m)
 	default:
 		return nil, fmt.Errorf("ent: unknown mutation type %T", m)
 	}
@@ -1197,6 +1213,139 @@ func (c *DecisionClient) mutate(ctx context.Context, m *DecisionMutation) (Value
 	}
 }
 
+// DecisionChangeClient is a client for the DecisionChange schema.
+type DecisionChangeClient struct {
+	config
+}
+
+// NewDecisionChangeClient returns a client for the DecisionChange from the given config.
+func NewDecisionChangeClient(c config) *DecisionChangeClient {
+	return &DecisionChangeClient{config: c}
+}
+
+// Use adds a list of mutation hooks to the hooks stack.
+// A call to `Use(f, g, h)` equals to `decisionchange.Hooks(f(g(h())))`.
+func (c *DecisionChangeClient) Use(hooks ...Hook) {
+	c.hooks.DecisionChange = append(c.hooks.DecisionChange, hooks...)
+}
+
+// Intercept adds a list of query interceptors to the interceptors stack.
+// A call to `Intercept(f, g, h)` equals to `decisionchange.Intercept(f(g(h())))`.
+func (c *DecisionChangeClient) Intercept(interceptors ...Interceptor) {
+	c.inters.DecisionChange = append(c.inters.DecisionChange, interceptors...)
+}
+
+// Create returns a builder for creating a DecisionChange entity.
+func (c *DecisionChangeClient) Create() *DecisionChangeCreate {
+	mutation := newDecisionChangeMutation(c.config, OpCreate)
+	return &DecisionChangeCreate{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// CreateBulk returns a builder for creating a bulk of DecisionChange entities.
+func (c *DecisionChangeClient) CreateBulk(builders ...*DecisionChangeCreate) *DecisionChangeCreateBulk {
+	return &DecisionChangeCreateBulk{config: c.config, builders: builders}
+}
+
+// MapCreateBulk creates a bulk creation builder from the given slice. For each item in the slice, the function creates
+// a builder and applies setFunc on it.
+func (c *DecisionChangeClient) MapCreateBulk(slice any, setFunc func(*DecisionChangeCreate, int)) *DecisionChangeCreateBulk {
+	rv := reflect.ValueOf(slice)
+	if rv.Kind() != reflect.Slice {
+		return &DecisionChangeCreateBulk{err: fmt.Errorf("calling to DecisionChangeClient.MapCreateBulk with wrong type %T, need slice", slice)}
+	}
+	builders := make([]*DecisionChangeCreate, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		builders[i] = c.Create()
+		setFunc(builders[i], i)
+	}
+	return &DecisionChangeCreateBulk{config: c.config, builders: builders}
+}
+
+// Update returns an update builder for DecisionChange.
+func (c *DecisionChangeClient) Update() *DecisionChangeUpdate {
+	mutation := newDecisionChangeMutation(c.config, OpUpdate)
+	return &DecisionChangeUpdate{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// UpdateOne returns an update builder for the given entity.
+func (c *DecisionChangeClient) UpdateOne(dc *DecisionChange) *DecisionChangeUpdateOne {
+	mutation := newDecisionChangeMutation(c.config, OpUpdateOne, withDecisionChange(dc))
+	return &DecisionChangeUpdateOne{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// UpdateOneID returns an update builder for the given id.
+func (c *DecisionChangeClient) UpdateOneID(id int) *DecisionChangeUpdateOne {
+	mutation := newDecisionChangeMutation(c.config, OpUpdateOne, withDecisionChangeID(id))
+	return &DecisionChangeUpdateOne{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// Delete returns a delete builder for DecisionChange.
+func (c *DecisionChangeClient) Delete() *DecisionChangeDelete {
+	mutation := newDecisionChangeMutation(c.config, OpDelete)
+	return &DecisionChangeDelete{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// DeleteOne returns a builder for deleting the given entity.
+func (c *DecisionChangeClient) DeleteOne(dc *DecisionChange) *DecisionChangeDeleteOne {
+	return c.DeleteOneID(dc.ID)
+}
+
+// DeleteOneID returns a builder for deleting the given entity by its id.
+func (c *DecisionChangeClient) DeleteOneID(id int) *DecisionChangeDeleteOne {
+	builder := c.Delete().Where(decisionchange.ID(id))
+	builder.mutation.id = &id
+	builder.mutation.op = OpDeleteOne
+	return &DecisionChangeDeleteOne{builder}
+}
+
+// Query returns a query builder for DecisionChange.
+func (c *DecisionChangeClient) Query() *DecisionChangeQuery {
+	return &DecisionChangeQuery{
+		config: c.config,
+		ctx:    &QueryContext{Type: TypeDecisionChange},
+		inters: c.Interceptors(),
+	}
+}
+
+// Get returns a DecisionChange entity by its id.
+func (c *DecisionChangeClient) Get(ctx context.Context, id int) (*DecisionChange, error) {
+	return c.Query().Where(decisionchange.ID(id)).Only(ctx)
+}
+
+// GetX is like Get, but panics if an error occurs.
+func (c *DecisionChangeClient) GetX(ctx context.Context, id int) *DecisionChange {
+	obj, err := c.Get(ctx, id)
+	if err != nil {
+		panic(err)
+	}
+	return obj
+}
+
+// Hooks returns the client hooks.
+func (c *DecisionChangeClient) Hooks() []Hook {
+	return c.hooks.DecisionChange
+}
+
+// Interceptors returns the client interceptors.
+func (c *DecisionChangeClient) Interceptors() []Interceptor {
+	return c.inters.DecisionChange
+}
+
+func (c *DecisionChangeClient) mutate(ctx context.Context, m *DecisionChangeMutation) (Value, error) {
+	switch m.Op() {
+	case OpCreate:
+		return (&DecisionChangeCreate{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+	case OpUpdate:
+		return (&DecisionChangeUpdate{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+	case OpUpdateOne:
+		return (&DecisionChangeUpdateOne{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+	case OpDelete, OpDeleteOne:
+		return (&DecisionChangeDelete{config: c.config, hooks: c.Hooks(), mutation: m}).Exec(ctx)
+	default:
+		return nil, fmt.Errorf("ent: unknown DecisionChange mutation op: %q", m.Op())
+	}
+}
+
 // EventClient is a client for the Event schema.
 type EventClient struct {
 	config
@@ -1910,14 +2059,147 @@ func (c *MetricClient) mutate(ctx context.Context, m *MetricMutation) (Value, er
 	}
 }
 
+// SharedBucketClient is a client for the SharedBucket schema.
+type SharedBucketClient struct {
+	config
+}
+
+// NewSharedBucketClient returns a client for the SharedBucket from the given config.
+func NewSharedBucketClient(c config) *SharedBucketClient {
+	return &SharedBucketClient{config: c}
+}
+
+// Use adds a list of mutation hooks to the hooks stack.
+// A call to `Use(f, g, h)` equals to `sharedbucket.Hooks(f(g(h())))`.
+func (c *SharedBucketClient) Use(hooks ...Hook) {
+	c.hooks.SharedBucket = append(c.hooks.SharedBucket, hooks...)
+}
+
+// Intercept adds a list of query interceptors to the interceptors stack.
+// A call to `Intercept(f, g, h)` equals to `sharedbucket.Intercept(f(g(h())))`.
+func (c *SharedBucketClient) Intercept(interceptors ...Interceptor) {
+	c.inters.SharedBucket = append(c.inters.SharedBucket, interceptors...)
+}
+
+// Create returns a builder for creating a SharedBucket entity.
+func (c *SharedBucketClient) Create() *SharedBucketCreate {
+	mutation := newSharedBucketMutation(c.config, OpCreate)
+	return &SharedBucketCreate{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// CreateBulk returns a builder for creating a bulk of SharedBucket entities.
+func (c *SharedBucketClient) CreateBulk(builders ...*SharedBucketCreate) *SharedBucketCreateBulk {
+	return &SharedBucketCreateBulk{config: c.config, builders: builders}
+}
+
+// MapCreateBulk creates a bulk creation builder from the given slice. For each item in the slice, the function creates
+// a builder and applies setFunc on it.
+func (c *SharedBucketClient) MapCreateBulk(slice any, setFunc func(*SharedBucketCreate, int)) *SharedBucketCreateBulk {
+	rv := reflect.ValueOf(slice)
+	if rv.Kind() != reflect.Slice {
+		return &SharedBucketCreateBulk{err: fmt.Errorf("calling to SharedBucketClient.MapCreateBulk with wrong type %T, need slice", slice)}
+	}
+	builders := make([]*SharedBucketCreate, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		builders[i] = c.Create()
+		setFunc(builders[i], i)
+	}
+	return &SharedBucketCreateBulk{config: c.config, builders: builders}
+}
+
+// Update returns an update builder for SharedBucket.
+func (c *SharedBucketClient) Update() *SharedBucketUpdate {
+	mutation := newSharedBucketMutation(c.config, OpUpdate)
+	return &SharedBucketUpdate{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// UpdateOne returns an update builder for the given entity.
+func (c *SharedBucketClient) UpdateOne(sb *SharedBucket) *SharedBucketUpdateOne {
+	mutation := newSharedBucketMutation(c.config, OpUpdateOne, withSharedBucket(sb))
+	return &SharedBucketUpdateOne{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// UpdateOneID returns an update builder for the given id.
+func (c *SharedBucketClient) UpdateOneID(id int) *SharedBucketUpdateOne {
+	mutation := newSharedBucketMutation(c.config, OpUpdateOne, withSharedBucketID(id))
+	return &SharedBucketUpdateOne{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// Delete returns a delete builder for SharedBucket.
+func (c *SharedBucketClient) Delete() *SharedBucketDelete {
+	mutation := newSharedBucketMutation(c.config, OpDelete)
+	return &SharedBucketDelete{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// DeleteOne returns a builder for deleting the given entity.
+func (c *SharedBucketClient) DeleteOne(sb *SharedBucket) *SharedBucketDeleteOne {
+	return c.DeleteOneID(sb.ID)
+}
+
+// DeleteOneID returns a builder for deleting the given entity by its id.
+func (c *SharedBucketClient) DeleteOneID(id int) *SharedBucketDeleteOne {
+	builder := c.Delete().Where(sharedbucket.ID(id))
+	builder.mutation.id = &id
+	builder.mutation.op = OpDeleteOne
+	return &SharedBucketDeleteOne{builder}
+}
+
+// Query returns a query builder for SharedBucket.
+func (c *SharedBucketClient) Query() *SharedBucketQuery {
+	return &SharedBucketQuery{
+		config: c.config,
+		ctx:    &QueryContext{Type: TypeSharedBucket},
+		inters: c.Interceptors(),
+	}
+}
+
+// Get returns a SharedBucket entity by its id.
+func (c *SharedBucketClient) Get(ctx context.Context, id int) (*SharedBucket, error) {
+	return c.Query().Where(sharedbucket.ID(id)).Only(ctx)
+}
+
+// GetX is like Get, but panics if an error occurs.
+func (c *SharedBucketClient) GetX(ctx context.Context, id int) *SharedBucket {
+	obj, err := c.Get(ctx, id)
+	if err != nil {
+		panic(err)
+	}
+	return obj
+}
+
+// Hooks returns the client hooks.
+func (c *SharedBucketClient) Hooks() []Hook {
+	return c.hooks.SharedBucket
+}
+
+// Interceptors returns the client interceptors.
+func (c *SharedBucketClient) Interceptors() []Interceptor {
+	return c.inters.SharedBucket
+}
+
+func (c *SharedBucketClient) mutate(ctx context.Context, m *SharedBucketMutation) (Value, error) {
+	switch m.Op() {
+	case OpCreate:
+		return (&SharedBucketCreate{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+	case OpUpdate:
+		return (&SharedBucketUpdate{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+	case OpUpdateOne:
+		return (&SharedBucketUpdateOne{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+	case OpDelete, OpDeleteOne:
+		return (&SharedBucketDelete{config: c.config, hooks: c.Hooks(), mutation: m}).Exec(ctx)
+	default:
+		return nil, fmt.Errorf("ent: unknown SharedBucket mutation op: %q", m.Op())
+	}
+}
+
 // hooks and interceptors per client, for fast access.
 type (
 	hooks struct {
-		Alert, AllowList, AllowListItem, Bouncer, ConfigItem, Decision, Event, Lock,
-		Machine, Meta, Metric []ent.Hook
+		Alert, AllowList, AllowListItem, Bouncer, ConfigItem, Decision, DecisionChange,
+		Event, Lock, Machine, Meta, Metric, SharedBucket []ent.Hook
 	}
 	inters struct {
-		Alert, AllowList, AllowListItem, Bouncer, ConfigItem, Decision, Event, Lock,
-		Machine, Meta, Metric []ent.Interceptor
+		Alert, AllowList, AllowListItem, Bouncer, ConfigItem, Decision, DecisionChange,
+		Event, Lock, Machine, Meta, Metric, SharedBucket []ent.Interceptor
 	}
 )
@@ -0,0 +1,128 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/decisionchange"
+)
+
+// DecisionChange is the model entity for the DecisionChange schema.
+type DecisionChange struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int `json:"id,omitempty"`
+	// CreatedAt holds the value of the "created_at" field.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	// new: the decision was added, deleted: the decision was expired before its end
+	Type decisionchange.Type `json:"type,omitempty"`
+	// Not an edge, the decision can be deleted while the change is kept
+	DecisionID   int `json:"decision_id,omitempty"`
+	selectValues sql.SelectValues
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*DecisionChange) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case decisionchange.FieldID, decisionchange.FieldDecisionID:
+			values[i] = new(sql.NullInt64)
+		case decisionchange.FieldType:
+			values[i] = new(sql.NullString)
+		case decisionchange.FieldCreatedAt:
+			values[i] = new(sql.NullTime)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the DecisionChange fields.
+func (dc *DecisionChange) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case decisionchange.FieldID:
+			value, ok := values[i].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			dc.ID = int(value.Int64)
+		case decisionchange.FieldCreatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field created_at", values[i])
+			} else if value.Valid {
+				dc.CreatedAt = value.Time
+			}
+		case decisionchange.FieldType:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field type", values[i])
+			} else if value.Valid {
+				dc.Type = decisionchange.Type(value.String)
+			}
+		case decisionchange.FieldDecisionID:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field decision_id", values[i])
+			} else if value.Valid {
+				dc.DecisionID = int(value.Int64)
+			}
+		default:
+			dc.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the DecisionChange.
+// This includes values selected through modifiers, order, etc.
+func (dc *DecisionChange) Value(name string) (ent.Value, error) {
+	return dc.selectValues.Get(name)
+}
+
+// Update returns a builder for updating this DecisionChange.
+// Note that you need to call DecisionChange.Unwrap() before calling this method if this DecisionChange
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (dc *DecisionChange) Update() *DecisionChangeUpdateOne {
+	return NewDecisionChangeClient(dc.config).UpdateOne(dc)
+}
+
+// Unwrap unwraps the DecisionChange entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (dc *DecisionChange) Unwrap() *DecisionChange {
+	_tx, ok := dc.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: DecisionChange is not a transactional entity")
+	}
+	dc.config.driver = _tx.drv
+	return dc
+}
+
+// String implements the fmt.Stringer.
+func (dc *DecisionChange) String() string {
+	var builder strings.Builder
+	builder.WriteString("DecisionChange(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", dc.ID))
+	builder.WriteString("created_at=")
+	builder.WriteString(dc.CreatedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	builder.WriteString("type=")
+	builder.WriteString(fmt.Sprintf("%v", dc.Type))
+	builder.WriteString(", ")
+	builder.WriteString("decision_id=")
+	builder.WriteString(fmt.Sprintf("%v", dc.DecisionID))
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// DecisionChanges is a parsable slice of DecisionChange.
+type DecisionChanges []*DecisionChange
@@ -0,0 +1,94 @@
+// Code generated by ent, DO NOT EDIT.
+
+package decisionchange
+
+import (
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+const (
+	// Label holds the string label denoting the decisionchange type in the database.
+	Label = "decision_change"
+	// FieldID holds the string denoting the id field in the database.
+	FieldID = "id"
+	// FieldCreatedAt holds the string denoting the created_at field in the database.
+	FieldCreatedAt = "created_at"
+	// FieldType holds the string denoting the type field in the database.
+	FieldType = "type"
+	// FieldDecisionID holds the string denoting the decision_id field in the database.
+	FieldDecisionID = "decision_id"
+	// Table holds the table name of the decisionchange in the database.
+	Table = "decision_changes"
+)
+
+// Columns holds all SQL columns for decisionchange fields.
+var Columns = []string{
+	FieldID,
+	FieldCreatedAt,
+	FieldType,
+	FieldDecisionID,
+}
+
+// ValidColumn reports if the column name is valid (part of the table columns).
+func ValidColumn(column string) bool {
+	for i := range Columns {
+		if column == Columns[i] {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// DefaultCreatedAt holds the default value on creation for the "created_at" field.
+	DefaultCreatedAt func() time.Time
+)
+
+// Type defines the type for the "type" enum field.
+type Type string
+
+// Type values.
+const (
+	TypeNew     Type = "new"
+	TypeDeleted Type = "deleted"
+)
+
+func (_type Type) String() string {
+	return string(_type)
+}
+
+// TypeValidator is a validator for the "type" field enum values. It is called by the builders before save.
+func TypeValidator(_type Type) error {
+	switch _type {
+	case TypeNew, TypeDeleted:
+		return nil
+	default:
+		return fmt.Errorf("decisionchange: invalid enum value for type field: %q", _type)
+	}
+}
+
+// OrderOption defines the ordering options for the DecisionChange queries.
+type OrderOption func(*sql.Selector)
+
+// ByID orders the results by the id field.
+func ByID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldID, opts...).ToFunc()
+}
+
+// ByCreatedAt orders the results by the created_at field.
+func ByCreatedAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldCreatedAt, opts...).ToFunc()
+}
+
+// ByType orders the results by the type field.
+func ByType(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldType, opts...).ToFunc()
+}
+
+// ByDecisionID orders the results by the decision_id field.
+func ByDecisionID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldDecisionID, opts...).ToFunc()
+}
@@ -0,0 +1,180 @@
+// Code generated by ent, DO NOT EDIT.
+
+package decisionchange
+
+import (
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/predicate"
+)
+
+// ID filters vertices based on their ID field.
+func ID(id int) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.FieldEQ(FieldID, id))
+}
+
+// IDEQ applies the EQ predicate on the ID field.
+func IDEQ(id int) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.FieldEQ(FieldID, id))
+}
+
+// IDNEQ applies the NEQ predicate on the ID field.
+func IDNEQ(id int) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.FieldNEQ(FieldID, id))
+}
+
+// IDIn applies the In predicate on the ID field.
+func IDIn(ids ...int) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.FieldIn(FieldID, ids...))
+}
+
+// IDNotIn applies the NotIn predicate on the ID field.
+func IDNotIn(ids ...int) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.FieldNotIn(FieldID, ids...))
+}
+
+// IDGT applies the GT predicate on the ID field.
+func IDGT(id int) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.FieldGT(FieldID, id))
+}
+
+// IDGTE applies the GTE predicate on the ID field.
+func IDGTE(id int) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.FieldGTE(FieldID, id))
+}
+
+// IDLT applies the LT predicate on the ID field.
+func IDLT(id int) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.FieldLT(FieldID, id))
+}
+
+// IDLTE applies the LTE predicate on the ID field.
+func IDLTE(id int) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.FieldLTE(FieldID, id))
+}
+
+// CreatedAt applies equality check predicate on the "created_at" field. It's identical to CreatedAtEQ.
+func CreatedAt(v time.Time) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// DecisionID applies equality check predicate on the "decision_id" field. It's identical to DecisionIDEQ.
+func DecisionID(v int) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.FieldEQ(FieldDecisionID, v))
+}
+
+// CreatedAtEQ applies the EQ predicate on the "created_at" field.
+func CreatedAtEQ(v time.Time) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtNEQ applies the NEQ predicate on the "created_at" field.
+func CreatedAtNEQ(v time.Time) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.FieldNEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtIn applies the In predicate on the "created_at" field.
+func CreatedAtIn(vs ...time.Time) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.FieldIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtNotIn applies the NotIn predicate on the "created_at" field.
+func CreatedAtNotIn(vs ...time.Time) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.FieldNotIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtGT applies the GT predicate on the "created_at" field.
+func CreatedAtGT(v time.Time) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.FieldGT(FieldCreatedAt, v))
+}
+
+// CreatedAtGTE applies the GTE predicate on the "created_at" field.
+func CreatedAtGTE(v time.Time) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.FieldGTE(FieldCreatedAt, v))
+}
+
+// CreatedAtLT applies the LT predicate on the "created_at" field.
+func CreatedAtLT(v time.Time) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.FieldLT(FieldCreatedAt, v))
+}
+
+// CreatedAtLTE applies the LTE predicate on the "created_at" field.
+func CreatedAtLTE(v time.Time) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.FieldLTE(FieldCreatedAt, v))
+}
+
+// TypeEQ applies the EQ predicate on the "type" field.
+func TypeEQ(v Type) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.FieldEQ(FieldType, v))
+}
+
+// TypeNEQ applies the NEQ predicate on the "type" field.
+func TypeNEQ(v Type) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.FieldNEQ(FieldType, v))
+}
+
+// TypeIn applies the In predicate on the "type" field.
+func TypeIn(vs ...Type) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.FieldIn(FieldType, vs...))
+}
+
+// TypeNotIn applies the NotIn predicate on the "type" field.
+func TypeNotIn(vs ...Type) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.FieldNotIn(FieldType, vs...))
+}
+
+// DecisionIDEQ applies the EQ predicate on the "decision_id" field.
+func DecisionIDEQ(v int) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.FieldEQ(FieldDecisionID, v))
+}
+
+// DecisionIDNEQ applies the NEQ predicate on the "decision_id" field.
+func DecisionIDNEQ(v int) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.FieldNEQ(FieldDecisionID, v))
+}
+
+// DecisionIDIn applies the In predicate on the "decision_id" field.
+func DecisionIDIn(vs ...int) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.FieldIn(FieldDecisionID, vs...))
+}
+
+// DecisionIDNotIn applies the NotIn predicate on the "decision_id" field.
+func DecisionIDNotIn(vs ...int) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.FieldNotIn(FieldDecisionID, vs...))
+}
+
+// DecisionIDGT applies the GT predicate on the "decision_id" field.
+func DecisionIDGT(v int) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.FieldGT(FieldDecisionID, v))
+}
+
+// DecisionIDGTE applies the GTE predicate on the "decision_id" field.
+func DecisionIDGTE(v int) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.FieldGTE(FieldDecisionID, v))
+}
+
+// DecisionIDLT applies the LT predicate on the "decision_id" field.
+func DecisionIDLT(v int) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.FieldLT(FieldDecisionID, v))
+}
+
+// DecisionIDLTE applies the LTE predicate on the "decision_id" field.
+func DecisionIDLTE(v int) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.FieldLTE(FieldDecisionID, v))
+}
+
+// And groups predicates with the AND operator between them.
+func And(predicates ...predicate.DecisionChange) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.AndPredicates(predicates...))
+}
+
+// Or groups predicates with the OR operator between them.
+func Or(predicates ...predicate.DecisionChange) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.OrPredicates(predicates...))
+}
+
+// Not applies the not operator on the given predicate.
+func Not(p predicate.DecisionChange) predicate.DecisionChange {
+	return predicate.DecisionChange(sql.NotPredicates(p))
+}
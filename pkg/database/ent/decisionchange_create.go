@@ -0,0 +1,233 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/decisionchange"
+)
+
+// DecisionChangeCreate is the builder for creating a DecisionChange entity.
+type DecisionChangeCreate struct {
+	config
+	mutation *DecisionChangeMutation
+	hooks    []Hook
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (dcc *DecisionChangeCreate) SetCreatedAt(t time.Time) *DecisionChangeCreate {
+	dcc.mutation.SetCreatedAt(t)
+	return dcc
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (dcc *DecisionChangeCreate) SetNillableCreatedAt(t *time.Time) *DecisionChangeCreate {
+	if t != nil {
+		dcc.SetCreatedAt(*t)
+	}
+	return dcc
+}
+
+// SetType sets the "type" field.
+func (dcc *DecisionChangeCreate) SetType(d decisionchange.Type) *DecisionChangeCreate {
+	dcc.mutation.SetType(d)
+	return dcc
+}
+
+// SetDecisionID sets the "decision_id" field.
+func (dcc *DecisionChangeCreate) SetDecisionID(i int) *DecisionChangeCreate {
+	dcc.mutation.SetDecisionID(i)
+	return dcc
+}
+
+// Mutation returns the DecisionChangeMutation object of the builder.
+func (dcc *DecisionChangeCreate) Mutation() *DecisionChangeMutation {
+	return dcc.mutation
+}
+
+// Save creates the DecisionChange in the database.
+func (dcc *DecisionChangeCreate) Save(ctx context.Context) (*DecisionChange, error) {
+	dcc.defaults()
+	return withHooks(ctx, dcc.sqlSave, dcc.mutation, dcc.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (dcc *DecisionChangeCreate) SaveX(ctx context.Context) *DecisionChange {
+	v, err := dcc.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (dcc *DecisionChangeCreate) Exec(ctx context.Context) error {
+	_, err := dcc.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (dcc *DecisionChangeCreate) ExecX(ctx context.Context) {
+	if err := dcc.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (dcc *DecisionChangeCreate) defaults() {
+	if _, ok := dcc.mutation.CreatedAt(); !ok {
+		v := decisionchange.DefaultCreatedAt()
+		dcc.mutation.SetCreatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (dcc *DecisionChangeCreate) check() error {
+	if _, ok := dcc.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "DecisionChange.created_at"`)}
+	}
+	if _, ok := dcc.mutation.GetType(); !ok {
+		return &ValidationError{Name: "type", err: errors.New(`ent: missing required field "DecisionChange.type"`)}
+	}
+	if v, ok := dcc.mutation.GetType(); ok {
+		if err := decisionchange.TypeValidator(v); err != nil {
+			return &ValidationError{Name: "type", err: fmt.Errorf(`ent: validator failed for field "DecisionChange.type": %w`, err)}
+		}
+	}
+	if _, ok := dcc.mutation.DecisionID(); !ok {
+		return &ValidationError{Name: "decision_id", err: errors.New(`ent: missing required field "DecisionChange.decision_id"`)}
+	}
+	return nil
+}
+
+func (dcc *DecisionChangeCreate) sqlSave(ctx context.Context) (*DecisionChange, error) {
+	if err := dcc.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := dcc.createSpec()
+	if err := sqlgraph.CreateNode(ctx, dcc.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	dcc.mutation.id = &_node.ID
+	dcc.mutation.done = true
+	return _node, nil
+}
+
+func (dcc *DecisionChangeCreate) createSpec() (*DecisionChange, *sqlgraph.CreateSpec) {
+	var (
+		_node = &DecisionChange{config: dcc.config}
+		_spec = sqlgraph.NewCreateSpec(decisionchange.Table, sqlgraph.NewFieldSpec(decisionchange.FieldID, field.TypeInt))
+	)
+	if value, ok := dcc.mutation.CreatedAt(); ok {
+		_spec.SetField(decisionchange.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := dcc.mutation.GetType(); ok {
+		_spec.SetField(decisionchange.FieldType, field.TypeEnum, value)
+		_node.Type = value
+	}
+	if value, ok := dcc.mutation.DecisionID(); ok {
+		_spec.SetField(decisionchange.FieldDecisionID, field.TypeInt, value)
+		_node.DecisionID = value
+	}
+	return _node, _spec
+}
+
+// DecisionChangeCreateBulk is the builder for creating many DecisionChange entities in bulk.
+type DecisionChangeCreateBulk struct {
+	config
+	err      error
+	builders []*DecisionChangeCreate
+}
+
+// Save creates the DecisionChange entities in the database.
+func (dccb *DecisionChangeCreateBulk) Save(ctx context.Context) ([]*DecisionChange, error) {
+	if dccb.err != nil {
+		return nil, dccb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(dccb.builders))
+	nodes := make([]*DecisionChange, len(dccb.builders))
+	mutators := make([]Mutator, len(dccb.builders))
+	for i := range dccb.builders {
+		func(i int, root context.Context) {
+			builder := dccb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*DecisionChangeMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, dccb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, dccb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, dccb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (dccb *DecisionChangeCreateBulk) SaveX(ctx context.Context) []*DecisionChange {
+	v, err := dccb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (dccb *DecisionChangeCreateBulk) Exec(ctx context.Context) error {
+	_, err := dccb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (dccb *DecisionChangeCreateBulk) ExecX(ctx context.Context) {
+	if err := dccb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,88 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/decisionchange"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/predicate"
+)
+
+// DecisionChangeDelete is the builder for deleting a DecisionChange entity.
+type DecisionChangeDelete struct {
+	config
+	hooks    []Hook
+	mutation *DecisionChangeMutation
+}
+
+// Where appends a list predicates to the DecisionChangeDelete builder.
+func (dcd *DecisionChangeDelete) Where(ps ...predicate.DecisionChange) *DecisionChangeDelete {
+	dcd.mutation.Where(ps...)
+	return dcd
+}
+
+// Exec executes the deletion query and returns how many vertices were deleted.
+func (dcd *DecisionChangeDelete) Exec(ctx context.Context) (int, error) {
+	return withHooks(ctx, dcd.sqlExec, dcd.mutation, dcd.hooks)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (dcd *DecisionChangeDelete) ExecX(ctx context.Context) int {
+	n, err := dcd.Exec(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+func (dcd *DecisionChangeDelete) sqlExec(ctx context.Context) (int, error) {
+	_spec := sqlgraph.NewDeleteSpec(decisionchange.Table, sqlgraph.NewFieldSpec(decisionchange.FieldID, field.TypeInt))
+	if ps := dcd.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	affected, err := sqlgraph.DeleteNodes(ctx, dcd.driver, _spec)
+	if err != nil && sqlgraph.IsConstraintError(err) {
+		err = &ConstraintError{msg: err.Error(), wrap: err}
+	}
+	dcd.mutation.done = true
+	return affected, err
+}
+
+// DecisionChangeDeleteOne is the builder for deleting a single DecisionChange entity.
+type DecisionChangeDeleteOne struct {
+	dcd *DecisionChangeDelete
+}
+
+// Where appends a list predicates to the DecisionChangeDelete builder.
+func (dcdo *DecisionChangeDeleteOne) Where(ps ...predicate.DecisionChange) *DecisionChangeDeleteOne {
+	dcdo.dcd.mutation.Where(ps...)
+	return dcdo
+}
+
+// Exec executes the deletion query.
+func (dcdo *DecisionChangeDeleteOne) Exec(ctx context.Context) error {
+	n, err := dcdo.dcd.Exec(ctx)
+	switch {
+	case err != nil:
+		return err
+	case n == 0:
+		return &NotFoundError{decisionchange.Label}
+	default:
+		return nil
+	}
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (dcdo *DecisionChangeDeleteOne) ExecX(ctx context.Context) {
+	if err := dcdo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,175 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/decisionchange"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/predicate"
+)
+
+// DecisionChangeUpdate is the builder for updating DecisionChange entities.
+type DecisionChangeUpdate struct {
+	config
+	hooks    []Hook
+	mutation *DecisionChangeMutation
+}
+
+// Where appends a list predicates to the DecisionChangeUpdate builder.
+func (dcu *DecisionChangeUpdate) Where(ps ...predicate.DecisionChange) *DecisionChangeUpdate {
+	dcu.mutation.Where(ps...)
+	return dcu
+}
+
+// Mutation returns the DecisionChangeMutation object of the builder.
+func (dcu *DecisionChangeUpdate) Mutation() *DecisionChangeMutation {
+	return dcu.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (dcu *DecisionChangeUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, dcu.sqlSave, dcu.mutation, dcu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (dcu *DecisionChangeUpdate) SaveX(ctx context.Context) int {
+	affected, err := dcu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (dcu *DecisionChangeUpdate) Exec(ctx context.Context) error {
+	_, err := dcu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (dcu *DecisionChangeUpdate) ExecX(ctx context.Context) {
+	if err := dcu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+func (dcu *DecisionChangeUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	_spec := sqlgraph.NewUpdateSpec(decisionchange.Table, decisionchange.Columns, sqlgraph.NewFieldSpec(decisionchange.FieldID, field.TypeInt))
+	if ps := dcu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, dcu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{decisionchange.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	dcu.mutation.done = true
+	return n, nil
+}
+
+// DecisionChangeUpdateOne is the builder for updating a single DecisionChange entity.
+type DecisionChangeUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *DecisionChangeMutation
+}
+
+// Mutation returns the DecisionChangeMutation object of the builder.
+func (dcuo *DecisionChangeUpdateOne) Mutation() *DecisionChangeMutation {
+	return dcuo.mutation
+}
+
+// Where appends a list predicates to the DecisionChangeUpdate builder.
+func (dcuo *DecisionChangeUpdateOne) Where(ps ...predicate.DecisionChange) *DecisionChangeUpdateOne {
+	dcuo.mutation.Where(ps...)
+	return dcuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (dcuo *DecisionChangeUpdateOne) Select(field string, fields ...string) *DecisionChangeUpdateOne {
+	dcuo.fields = append([]string{field}, fields...)
+	return dcuo
+}
+
+// Save executes the query and returns the updated DecisionChange entity.
+func (dcuo *DecisionChangeUpdateOne) Save(ctx context.Context) (*DecisionChange, error) {
+	return withHooks(ctx, dcuo.sqlSave, dcuo.mutation, dcuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (dcuo *DecisionChangeUpdateOne) SaveX(ctx context.Context) *DecisionChange {
+	node, err := dcuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (dcuo *DecisionChangeUpdateOne) Exec(ctx context.Context) error {
+	_, err := dcuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (dcuo *DecisionChangeUpdateOne) ExecX(ctx context.Context) {
+	if err := dcuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+func (dcuo *DecisionChangeUpdateOne) sqlSave(ctx context.Context) (_node *DecisionChange, err error) {
+	_spec := sqlgraph.NewUpdateSpec(decisionchange.Table, decisionchange.Columns, sqlgraph.NewFieldSpec(decisionchange.FieldID, field.TypeInt))
+	id, ok := dcuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "DecisionChange.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := dcuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, decisionchange.FieldID)
+		for _, f := range fields {
+			if !decisionchange.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != decisionchange.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := dcuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	_node = &DecisionChange{config: dcuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, dcuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{decisionchange.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	dcuo.mutation.done = true
+	return _node, nil
+}
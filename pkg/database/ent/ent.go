@@ -18,11 +18,13 @@ import (
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/bouncer"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/configitem"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/decision"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/decisionchange"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/event"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/lock"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/machine"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/meta"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/metric"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/sharedbucket"
 )
 
 // ent aliases to avoid import conflicts in user's code.
@@ -83,17 +85,19 @@ var (
 func checkColumn(table, column string) error {
 	initCheck.Do(func() {
 		columnCheck = sql.NewColumnCheck(map[string]func(string) bool{
-			alert.Table:         alert.ValidColumn,
-			allowlist.Table:     allowlist.ValidColumn,
-			allowlistitem.Table: allowlistitem.ValidColumn,
-			bouncer.Table:       bouncer.ValidColumn,
-			configitem.Table:    configitem.ValidColumn,
-			decision.Table:      decision.ValidColumn,
-			event.Table:         event.ValidColumn,
-			lock.Table:          lock.ValidColumn,
-			machine.Table:       machine.ValidColumn,
-			meta.Table:          meta.ValidColumn,
-			metric.Table:        metric.ValidColumn,
+			alert.Table:          alert.ValidColumn,
+			allowlist.Table:      allowlist.ValidColumn,
+			allowlistitem.Table:  allowlistitem.ValidColumn,
+			bouncer.Table:        bouncer.ValidColumn,
+			configitem.Table:     configitem.ValidColumn,
+			decision.Table:       decision.ValidColumn,
+			decisionchange.Table: decisionchange.ValidColumn,
+			event.Table:          event.ValidColumn,
+			lock.Table:           lock.ValidColumn,
+			machine.Table:        machine.ValidColumn,
+			meta.Table:           meta.ValidColumn,
+			metric.Table:         metric.ValidColumn,
+			sharedbucket.Table:   sharedbucket.ValidColumn,
 		})
 	})
 	return columnCheck(table, column)
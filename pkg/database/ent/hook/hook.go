@@ -81,6 +81,18 @@ func (f DecisionFunc) Mutate(ctx context.Context, m ent.Mutation) (ent.Value, er
 	return nil, fmt.Errorf("unexpected mutation type %T. expect *ent.DecisionMutation", m)
 }
 
+// The DecisionChangeFunc type is an adapter to allow the use of ordinary
+// function as DecisionChange mutator.
+type DecisionChangeFunc func(context.Context, *ent.DecisionChangeMutation) (ent.Value, error)
+
+// Mutate calls f(ctx, m).
+func (f DecisionChangeFunc) Mutate(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+	if mv, ok := m.(*ent.DecisionChangeMutation); ok {
+		return f(ctx, mv)
+	}
+	return nil, fmt.Errorf("unexpected mutation type %T. expect *ent.DecisionChangeMutation", m)
+}
+
 // The EventFunc type is an adapter to allow the use of ordinary
 // function as Event mutator.
 type EventFunc func(context.Context, *ent.EventMutation) (ent.Value, error)
@@ -141,6 +153,18 @@ func (f MetricFunc) Mutate(ctx context.Context, m ent.Mutation) (ent.Value, erro
 	return nil, fmt.Errorf("unexpected mutation type %T. expect *ent.MetricMutation", m)
 }
 
+// The SharedBucketFunc type is an adapter to allow the use of ordinary
+// function as SharedBucket mutator.
+type SharedBucketFunc func(context.Context, *ent.SharedBucketMutation) (ent.Value, error)
+
+// Mutate calls f(ctx, m).
+func (f SharedBucketFunc) Mutate(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+	if mv, ok := m.(*ent.SharedBucketMutation); ok {
+		return f(ctx, mv)
+	}
+	return nil, fmt.Errorf("unexpected mutation type %T. expect *ent.SharedBucketMutation", m)
+}
+
 // Condition is a hook condition function.
 type Condition func(context.Context, ent.Mutation) bool
 
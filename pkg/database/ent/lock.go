@@ -20,7 +20,11 @@ type Lock struct {
 	// Name holds the value of the "name" field.
 	Name string `json:"name"`
 	// CreatedAt holds the value of the "created_at" field.
-	CreatedAt    time.Time `json:"created_at"`
+	CreatedAt time.Time `json:"created_at"`
+	// The LAPI instance holding the lease, empty for the other locks
+	Owner string `json:"owner,omitempty"`
+	// End of the lease, if the owner does not renew it
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
 	selectValues sql.SelectValues
 }
 
@@ -31,9 +35,9 @@ func (*Lock) scanValues(columns []string) ([]any, error) {
 		switch columns[i] {
 		case lock.FieldID:
 			values[i] = new(sql.NullInt64)
-		case lock.FieldName:
+		case lock.FieldName, lock.FieldOwner:
 			values[i] = new(sql.NullString)
-		case lock.FieldCreatedAt:
+		case lock.FieldCreatedAt, lock.FieldExpiresAt:
 			values[i] = new(sql.NullTime)
 		default:
 			values[i] = new(sql.UnknownType)
@@ -68,6 +72,19 @@ func (l *Lock) assignValues(columns []string, values []any) error {
 			} else if value.Valid {
 				l.CreatedAt = value.Time
 			}
+		case lock.FieldOwner:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field owner", values[i])
+			} else if value.Valid {
+				l.Owner = value.String
+			}
+		case lock.FieldExpiresAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field expires_at", values[i])
+			} else if value.Valid {
+				l.ExpiresAt = new(time.Time)
+				*l.ExpiresAt = value.Time
+			}
 		default:
 			l.selectValues.Set(columns[i], values[i])
 		}
@@ -109,6 +126,14 @@ func (l *Lock) String() string {
 	builder.WriteString(", ")
 	builder.WriteString("created_at=")
 	builder.WriteString(l.CreatedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	builder.WriteString("owner=")
+	builder.WriteString(l.Owner)
+	builder.WriteString(", ")
+	if v := l.ExpiresAt; v != nil {
+		builder.WriteString("expires_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
 	builder.WriteByte(')')
 	return builder.String()
 }
@@ -40,6 +40,34 @@ func (lc *LockCreate) SetNillableCreatedAt(t *time.Time) *LockCreate {
 	return lc
 }
 
+// SetOwner sets the "owner" field.
+func (lc *LockCreate) SetOwner(s string) *LockCreate {
+	lc.mutation.SetOwner(s)
+	return lc
+}
+
+// SetNillableOwner sets the "owner" field if the given value is not nil.
+func (lc *LockCreate) SetNillableOwner(s *string) *LockCreate {
+	if s != nil {
+		lc.SetOwner(*s)
+	}
+	return lc
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (lc *LockCreate) SetExpiresAt(t time.Time) *LockCreate {
+	lc.mutation.SetExpiresAt(t)
+	return lc
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (lc *LockCreate) SetNillableExpiresAt(t *time.Time) *LockCreate {
+	if t != nil {
+		lc.SetExpiresAt(*t)
+	}
+	return lc
+}
+
 // Mutation returns the LockMutation object of the builder.
 func (lc *LockCreate) Mutation() *LockMutation {
 	return lc.mutation
@@ -123,6 +151,14 @@ func (lc *LockCreate) createSpec() (*Lock, *sqlgraph.CreateSpec) {
 		_spec.SetField(lock.FieldCreatedAt, field.TypeTime, value)
 		_node.CreatedAt = value
 	}
+	if value, ok := lc.mutation.Owner(); ok {
+		_spec.SetField(lock.FieldOwner, field.TypeString, value)
+		_node.Owner = value
+	}
+	if value, ok := lc.mutation.ExpiresAt(); ok {
+		_spec.SetField(lock.FieldExpiresAt, field.TypeTime, value)
+		_node.ExpiresAt = &value
+	}
 	return _node, _spec
 }
 
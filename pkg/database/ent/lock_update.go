@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
@@ -27,6 +28,46 @@ func (lu *LockUpdate) Where(ps ...predicate.Lock) *LockUpdate {
 	return lu
 }
 
+// SetOwner sets the "owner" field.
+func (lu *LockUpdate) SetOwner(s string) *LockUpdate {
+	lu.mutation.SetOwner(s)
+	return lu
+}
+
+// SetNillableOwner sets the "owner" field if the given value is not nil.
+func (lu *LockUpdate) SetNillableOwner(s *string) *LockUpdate {
+	if s != nil {
+		lu.SetOwner(*s)
+	}
+	return lu
+}
+
+// ClearOwner clears the value of the "owner" field.
+func (lu *LockUpdate) ClearOwner() *LockUpdate {
+	lu.mutation.ClearOwner()
+	return lu
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (lu *LockUpdate) SetExpiresAt(t time.Time) *LockUpdate {
+	lu.mutation.SetExpiresAt(t)
+	return lu
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (lu *LockUpdate) SetNillableExpiresAt(t *time.Time) *LockUpdate {
+	if t != nil {
+		lu.SetExpiresAt(*t)
+	}
+	return lu
+}
+
+// ClearExpiresAt clears the value of the "expires_at" field.
+func (lu *LockUpdate) ClearExpiresAt() *LockUpdate {
+	lu.mutation.ClearExpiresAt()
+	return lu
+}
+
 // Mutation returns the LockMutation object of the builder.
 func (lu *LockUpdate) Mutation() *LockMutation {
 	return lu.mutation
@@ -68,6 +109,18 @@ func (lu *LockUpdate) sqlSave(ctx context.Context) (n int, err error) {
 			}
 		}
 	}
+	if value, ok := lu.mutation.Owner(); ok {
+		_spec.SetField(lock.FieldOwner, field.TypeString, value)
+	}
+	if lu.mutation.OwnerCleared() {
+		_spec.ClearField(lock.FieldOwner, field.TypeString)
+	}
+	if value, ok := lu.mutation.ExpiresAt(); ok {
+		_spec.SetField(lock.FieldExpiresAt, field.TypeTime, value)
+	}
+	if lu.mutation.ExpiresAtCleared() {
+		_spec.ClearField(lock.FieldExpiresAt, field.TypeTime)
+	}
 	if n, err = sqlgraph.UpdateNodes(ctx, lu.driver, _spec); err != nil {
 		if _, ok := err.(*sqlgraph.NotFoundError); ok {
 			err = &NotFoundError{lock.Label}
@@ -88,6 +141,46 @@ type LockUpdateOne struct {
 	mutation *LockMutation
 }
 
+// SetOwner sets the "owner" field.
+func (luo *LockUpdateOne) SetOwner(s string) *LockUpdateOne {
+	luo.mutation.SetOwner(s)
+	return luo
+}
+
+// SetNillableOwner sets the "owner" field if the given value is not nil.
+func (luo *LockUpdateOne) SetNillableOwner(s *string) *LockUpdateOne {
+	if s != nil {
+		luo.SetOwner(*s)
+	}
+	return luo
+}
+
+// ClearOwner clears the value of the "owner" field.
+func (luo *LockUpdateOne) ClearOwner() *LockUpdateOne {
+	luo.mutation.ClearOwner()
+	return luo
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (luo *LockUpdateOne) SetExpiresAt(t time.Time) *LockUpdateOne {
+	luo.mutation.SetExpiresAt(t)
+	return luo
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (luo *LockUpdateOne) SetNillableExpiresAt(t *time.Time) *LockUpdateOne {
+	if t != nil {
+		luo.SetExpiresAt(*t)
+	}
+	return luo
+}
+
+// ClearExpiresAt clears the value of the "expires_at" field.
+func (luo *LockUpdateOne) ClearExpiresAt() *LockUpdateOne {
+	luo.mutation.ClearExpiresAt()
+	return luo
+}
+
 // Mutation returns the LockMutation object of the builder.
 func (luo *LockUpdateOne) Mutation() *LockMutation {
 	return luo.mutation
@@ -159,6 +252,18 @@ func (luo *LockUpdateOne) sqlSave(ctx context.Context) (_node *Lock, err error)
 			}
 		}
 	}
+	if value, ok := luo.mutation.Owner(); ok {
+		_spec.SetField(lock.FieldOwner, field.TypeString, value)
+	}
+	if luo.mutation.OwnerCleared() {
+		_spec.ClearField(lock.FieldOwner, field.TypeString)
+	}
+	if value, ok := luo.mutation.ExpiresAt(); ok {
+		_spec.SetField(lock.FieldExpiresAt, field.TypeTime, value)
+	}
+	if luo.mutation.ExpiresAtCleared() {
+		_spec.ClearField(lock.FieldExpiresAt, field.TypeTime)
+	}
 	_node = &Lock{config: luo.config}
 	_spec.Assign = _node.assignValues
 	_spec.ScanValues = _node.scanValues
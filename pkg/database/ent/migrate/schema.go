@@ -212,6 +212,26 @@ var (
 			},
 		},
 	}
+	// DecisionChangesColumns holds the columns for the "decision_changes" table.
+	DecisionChangesColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "created_at", Type: field.TypeTime},
+		{Name: "type", Type: field.TypeEnum, Enums: []string{"new", "deleted"}},
+		{Name: "decision_id", Type: field.TypeInt},
+	}
+	// DecisionChangesTable holds the schema information for the "decision_changes" table.
+	DecisionChangesTable = &schema.Table{
+		Name:       "decision_changes",
+		Columns:    DecisionChangesColumns,
+		PrimaryKey: []*schema.Column{DecisionChangesColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "decisionchange_created_at",
+				Unique:  false,
+				Columns: []*schema.Column{DecisionChangesColumns[1]},
+			},
+		},
+	}
 	// EventsColumns holds the columns for the "events" table.
 	EventsColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeInt, Increment: true},
@@ -247,6 +267,8 @@ var (
 		{Name: "id", Type: field.TypeInt, Increment: true},
 		{Name: "name", Type: field.TypeString, Unique: true},
 		{Name: "created_at", Type: field.TypeTime},
+		{Name: "owner", Type: field.TypeString, Nullable: true},
+		{Name: "expires_at", Type: field.TypeTime, Nullable: true},
 	}
 	// LocksTable holds the schema information for the "locks" table.
 	LocksTable = &schema.Table{
@@ -325,6 +347,28 @@ var (
 		Columns:    MetricsColumns,
 		PrimaryKey: []*schema.Column{MetricsColumns[0]},
 	}
+	// SharedBucketsColumns holds the columns for the "shared_buckets" table.
+	SharedBucketsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "key", Type: field.TypeString, Unique: true},
+		{Name: "level", Type: field.TypeFloat64},
+		{Name: "updated_at", Type: field.TypeTime},
+		{Name: "empty_at", Type: field.TypeTime},
+		{Name: "revision", Type: field.TypeInt64, Default: 0},
+	}
+	// SharedBucketsTable holds the schema information for the "shared_buckets" table.
+	SharedBucketsTable = &schema.Table{
+		Name:       "shared_buckets",
+		Columns:    SharedBucketsColumns,
+		PrimaryKey: []*schema.Column{SharedBucketsColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "sharedbucket_empty_at",
+				Unique:  false,
+				Columns: []*schema.Column{SharedBucketsColumns[4]},
+			},
+		},
+	}
 	// AllowListAllowlistItemsColumns holds the columns for the "allow_list_allowlist_items" table.
 	AllowListAllowlistItemsColumns = []*schema.Column{
 		{Name: "allow_list_id", Type: field.TypeInt},
@@ -358,11 +402,13 @@ var (
 		BouncersTable,
 		ConfigItemsTable,
 		DecisionsTable,
+		DecisionChangesTable,
 		EventsTable,
 		LocksTable,
 		MachinesTable,
 		MetaTable,
 		MetricsTable,
+		SharedBucketsTable,
 		AllowListAllowlistItemsTable,
 	}
 )
@@ -17,6 +17,7 @@ import (
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/bouncer"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/configitem"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/decision"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/decisionchange"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/event"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/lock"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/machine"
@@ -24,6 +25,7 @@ import (
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/metric"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/predicate"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/schema"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/sharedbucket"
 )
 
 const (
@@ -35,17 +37,19 @@ const (
 	OpUpdateOne = ent.OpUpdateOne
 
 	// Node types.
-	TypeAlert         = "Alert"
-	TypeAllowList     = "AllowList"
-	TypeAllowListItem = "AllowListItem"
-	TypeBouncer       = "Bouncer"
-	TypeConfigItem    = "ConfigItem"
-	TypeDecision      = "Decision"
-	TypeEvent         = "Event"
-	TypeLock          = "Lock"
-	TypeMachine       = "Machine"
-	TypeMeta          = "Meta"
-	TypeMetric        = "Metric"
+	TypeAlert          = "Alert"
+	TypeAllowList      = "AllowList"
+	TypeAllowListItem  = "AllowListItem"
+	TypeBouncer        = "Bouncer"
+	TypeConfigItem     = "ConfigItem"
+	TypeDecision       = "Decision"
+	TypeDecisionChange = "DecisionChange"
+	TypeEvent          = "Event"
+	TypeLock           = "Lock"
+	TypeMachine        = "Machine"
+	TypeMeta           = "Meta"
+	TypeMetric         = "Metric"
+	TypeSharedBucket   = "SharedBucket"
 )
 
 // AlertMutation represents an operation that mutates the Alert nodes in the graph.
@@ -7583,35 +7587,33 @@ func (m *DecisionMutation) ResetEdge(name string) error {
 	return fmt.Errorf("unknown Decision edge %s", name)
 }
 
-// EventMutation represents an operation that mutates the Event nodes in the graph.
-type EventMutation struct {
+// DecisionChangeMutation represents an operation that mutates the DecisionChange nodes in the graph.
+type DecisionChangeMutation struct {
 	config
-	op            Op
-	typ           string
-	id            *int
-	created_at    *time.Time
-	updated_at    *time.Time
-	time          *time.Time
-	serialized    *string
-	clearedFields map[string]struct{}
-	owner         *int
-	clearedowner  bool
-	done          bool
-	oldValue      func(context.Context) (*Event, error)
-	predicates    []predicate.Event
+	op             Op
+	typ            string
+	id             *int
+	created_at     *time.Time
+	_type          *decisionchange.Type
+	decision_id    *int
+	adddecision_id *int
+	clearedFields  map[string]struct{}
+	done           bool
+	oldValue       func(context.Context) (*DecisionChange, error)
+	predicates     []predicate.DecisionChange
 }
 
-var _ ent.Mutation = (*EventMutation)(nil)
+var _ ent.Mutation = (*DecisionChangeMutation)(nil)
 
-// eventOption allows management of the mutation configuration using functional options.
-type eventOption func(*EventMutation)
+// decisionchangeOption allows management of the mutation configuration using functional options.
+type decisionchangeOption func(*DecisionChangeMutation)
 
-// newEventMutation creates new mutation for the Event entity.
-func newEventMutation(c config, op Op, opts ...eventOption) *EventMutation {
-	m := &EventMutation{
+// newDecisionChangeMutation creates new mutation for the DecisionChange entity.
+func newDecisionChangeMutation(c config, op Op, opts ...decisionchangeOption) *DecisionChangeMutation {
+	m := &DecisionChangeMutation{
 		config:        c,
 		op:            op,
-		typ:           TypeEvent,
+		typ:           TypeDecisionChange,
 		clearedFields: make(map[string]struct{}),
 	}
 	for _, opt := range opts {
@@ -7620,20 +7622,20 @@ func newEventMutation(c config, op Op, opts ...eventOption) *EventMutation {
 	return m
 }
 
-// withEventID sets the ID field of the mutation.
-func withEventID(id int) eventOption {
-	return func(m *EventMutation) {
+// withDecisionChangeID sets the ID field of the mutation.
+func withDecisionChangeID(id int) decisionchangeOption {
+	return func(m *DecisionChangeMutation) {
 		var (
 			err   error
 			once  sync.Once
-			value *Event
+			value *DecisionChange
 		)
-		m.oldValue = func(ctx context.Context) (*Event, error) {
+		m.oldValue = func(ctx context.Context) (*DecisionChange, error) {
 			once.Do(func() {
 				if m.done {
 					err = errors.New("querying old values post mutation is not allowed")
 				} else {
-					value, err = m.Client().Event.Get(ctx, id)
+					value, err = m.Client().DecisionChange.Get(ctx, id)
 				}
 			})
 			return value, err
@@ -7642,10 +7644,10 @@ func withEventID(id int) eventOption {
 	}
 }
 
-// withEvent sets the old Event of the mutation.
-func withEvent(node *Event) eventOption {
-	return func(m *EventMutation) {
-		m.oldValue = func(context.Context) (*Event, error) {
+// withDecisionChange sets the old DecisionChange of the mutation.
+func withDecisionChange(node *DecisionChange) decisionchangeOption {
+	return func(m *DecisionChangeMutation) {
+		m.oldValue = func(context.Context) (*DecisionChange, error) {
 			return node, nil
 		}
 		m.id = &node.ID
@@ -7654,7 +7656,7 @@ func withEvent(node *Event) eventOption {
 
 // Client returns a new `ent.Client` from the mutation. If the mutation was
 // executed in a transaction (ent.Tx), a transactional client is returned.
-func (m EventMutation) Client() *Client {
+func (m DecisionChangeMutation) Client() *Client {
 	client := &Client{config: m.config}
 	client.init()
 	return client
@@ -7662,7 +7664,7 @@ func (m EventMutation) Client() *Client {
 
 // Tx returns an `ent.Tx` for mutations that were executed in transactions;
 // it returns an error otherwise.
-func (m EventMutation) Tx() (*Tx, error) {
+func (m DecisionChangeMutation) Tx() (*Tx, error) {
 	if _, ok := m.driver.(*txDriver); !ok {
 		return nil, errors.New("ent: mutation is not running in a transaction")
 	}
@@ -7673,7 +7675,7 @@ func (m EventMutation) Tx() (*Tx, error) {
 
 // ID returns the ID value in the mutation. Note that the ID is only available
 // if it was provided to the builder or after it was returned from the database.
-func (m *EventMutation) ID() (id int, exists bool) {
+func (m *DecisionChangeMutation) ID() (id int, exists bool) {
 	if m.id == nil {
 		return
 	}
@@ -7684,7 +7686,7 @@ func (m *EventMutation) ID() (id int, exists bool) {
 // That means, if the mutation is applied within a transaction with an isolation level such
 // as sql.LevelSerializable, the returned ids match the ids of the rows that will be updated
 // or updated by the mutation.
-func (m *EventMutation) IDs(ctx context.Context) ([]int, error) {
+func (m *DecisionChangeMutation) IDs(ctx context.Context) ([]int, error) {
 	switch {
 	case m.op.Is(OpUpdateOne | OpDeleteOne):
 		id, exists := m.ID()
@@ -7693,19 +7695,19 @@ func (m *EventMutation) IDs(ctx context.Context) ([]int, error) {
 		}
 		fallthrough
 	case m.op.Is(OpUpdate | OpDelete):
-		return m.Client().Event.Query().Where(m.predicates...).IDs(ctx)
+		return m.Client().DecisionChange.Query().Where(m.predicates...).IDs(ctx)
 	default:
 		return nil, fmt.Errorf("IDs is not allowed on %s operations", m.op)
 	}
 }
 
 // SetCreatedAt sets the "created_at" field.
-func (m *EventMutation) SetCreatedAt(t time.Time) {
+func (m *DecisionChangeMutation) SetCreatedAt(t time.Time) {
 	m.created_at = &t
 }
 
 // CreatedAt returns the value of the "created_at" field in the mutation.
-func (m *EventMutation) CreatedAt() (r time.Time, exists bool) {
+func (m *DecisionChangeMutation) CreatedAt() (r time.Time, exists bool) {
 	v := m.created_at
 	if v == nil {
 		return
@@ -7713,10 +7715,10 @@ func (m *EventMutation) CreatedAt() (r time.Time, exists bool) {
 	return *v, true
 }
 
-// OldCreatedAt returns the old "created_at" field's value of the Event entity.
-// If the Event object wasn't provided to the builder, the object is fetched from the database.
+// OldCreatedAt returns the old "created_at" field's value of the DecisionChange entity.
+// If the DecisionChange object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *EventMutation) OldCreatedAt(ctx context.Context) (v time.Time, err error) {
+func (m *DecisionChangeMutation) OldCreatedAt(ctx context.Context) (v time.Time, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldCreatedAt is only allowed on UpdateOne operations")
 	}
@@ -7731,216 +7733,111 @@ func (m *EventMutation) OldCreatedAt(ctx context.Context) (v time.Time, err erro
 }
 
 // ResetCreatedAt resets all changes to the "created_at" field.
-func (m *EventMutation) ResetCreatedAt() {
+func (m *DecisionChangeMutation) ResetCreatedAt() {
 	m.created_at = nil
 }
 
-// SetUpdatedAt sets the "updated_at" field.
-func (m *EventMutation) SetUpdatedAt(t time.Time) {
-	m.updated_at = &t
+// SetType sets the "type" field.
+func (m *DecisionChangeMutation) SetType(d decisionchange.Type) {
+	m._type = &d
 }
 
-// UpdatedAt returns the value of the "updated_at" field in the mutation.
-func (m *EventMutation) UpdatedAt() (r time.Time, exists bool) {
-	v := m.updated_at
+// GetType returns the value of the "type" field in the mutation.
+func (m *DecisionChangeMutation) GetType() (r decisionchange.Type, exists bool) {
+	v := m._type
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldUpdatedAt returns the old "updated_at" field's value of the Event entity.
-// If the Event object wasn't provided to the builder, the object is fetched from the database.
+// OldType returns the old "type" field's value of the DecisionChange entity.
+// If the DecisionChange object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *EventMutation) OldUpdatedAt(ctx context.Context) (v time.Time, err error) {
+func (m *DecisionChangeMutation) OldType(ctx context.Context) (v decisionchange.Type, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldUpdatedAt is only allowed on UpdateOne operations")
+		return v, errors.New("OldType is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldUpdatedAt requires an ID field in the mutation")
+		return v, errors.New("OldType requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldUpdatedAt: %w", err)
+		return v, fmt.Errorf("querying old value for OldType: %w", err)
 	}
-	return oldValue.UpdatedAt, nil
+	return oldValue.Type, nil
 }
 
-// ResetUpdatedAt resets all changes to the "updated_at" field.
-func (m *EventMutation) ResetUpdatedAt() {
-	m.updated_at = nil
+// ResetType resets all changes to the "type" field.
+func (m *DecisionChangeMutation) ResetType() {
+	m._type = nil
 }
 
-// SetTime sets the "time" field.
-func (m *EventMutation) SetTime(t time.Time) {
-	m.time = &t
+// SetDecisionID sets the "decision_id" field.
+func (m *DecisionChangeMutation) SetDecisionID(i int) {
+	m.decision_id = &i
+	m.adddecision_id = nil
 }
 
-// Time returns the value of the "time" field in the mutation.
-func (m *EventMutation) Time() (r time.Time, exists bool) {
-	v := m.time
+// DecisionID returns the value of the "decision_id" field in the mutation.
+func (m *DecisionChangeMutation) DecisionID() (r int, exists bool) {
+	v := m.decision_id
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldTime returns the old "time" field's value of the Event entity.
-// If the Event object wasn't provided to the builder, the object is fetched from the database.
+// OldDecisionID returns the old "decision_id" field's value of the DecisionChange entity.
+// If the DecisionChange object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *EventMutation) OldTime(ctx context.Context) (v time.Time, err error) {
+func (m *DecisionChangeMutation) OldDecisionID(ctx context.Context) (v int, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldTime is only allowed on UpdateOne operations")
+		return v, errors.New("OldDecisionID is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldTime requires an ID field in the mutation")
+		return v, errors.New("OldDecisionID requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldTime: %w", err)
-	}
-	return oldValue.Time, nil
-}
-
-// ResetTime resets all changes to the "time" field.
-func (m *EventMutation) ResetTime() {
-	m.time = nil
-}
-
-// SetSerialized sets the "serialized" field.
-func (m *EventMutation) SetSerialized(s string) {
-	m.serialized = &s
-}
-
-// Serialized returns the value of the "serialized" field in the mutation.
-func (m *EventMutation) Serialized() (r string, exists bool) {
-	v := m.serialized
-	if v == nil {
-		return
+		return v, fmt.Errorf("querying old value for OldDecisionID: %w", err)
 	}
-	return *v, true
+	return oldValue.DecisionID, nil
 }
 
-// OldSerialized returns the old "serialized" field's value of the Event entity.
-// If the Event object wasn't provided to the builder, the object is fetched from the database.
-// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *EventMutation) OldSerialized(ctx context.Context) (v string, err error) {
-	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldSerialized is only allowed on UpdateOne operations")
-	}
-	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldSerialized requires an ID field in the mutation")
-	}
-	oldValue, err := m.oldValue(ctx)
-	if err != nil {
-		return v, fmt.Errorf("querying old value for OldSerialized: %w", err)
+// AddDecisionID adds i to the "decision_id" field.
+func (m *DecisionChangeMutation) AddDecisionID(i int) {
+	if m.adddecision_id != nil {
+		*m.adddecision_id += i
+	} else {
+		m.adddecision_id = &i
 	}
-	return oldValue.Serialized, nil
-}
-
-// ResetSerialized resets all changes to the "serialized" field.
-func (m *EventMutation) ResetSerialized() {
-	m.serialized = nil
-}
-
-// SetAlertEvents sets the "alert_events" field.
-func (m *EventMutation) SetAlertEvents(i int) {
-	m.owner = &i
 }
 
-// AlertEvents returns the value of the "alert_events" field in the mutation.
-func (m *EventMutation) AlertEvents() (r int, exists bool) {
-	v := m.owner
+// AddedDecisionID returns the value that was added to the "decision_id" field in this mutation.
+func (m *DecisionChangeMutation) AddedDecisionID() (r int, exists bool) {
+	v := m.adddecision_id
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldAlertEvents returns the old "alert_events" field's value of the Event entity.
-// If the Event object wasn't provided to the builder, the object is fetched from the database.
-// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *EventMutation) OldAlertEvents(ctx context.Context) (v int, err error) {
-	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldAlertEvents is only allowed on UpdateOne operations")
-	}
-	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldAlertEvents requires an ID field in the mutation")
-	}
-	oldValue, err := m.oldValue(ctx)
-	if err != nil {
-		return v, fmt.Errorf("querying old value for OldAlertEvents: %w", err)
-	}
-	return oldValue.AlertEvents, nil
-}
-
-// ClearAlertEvents clears the value of the "alert_events" field.
-func (m *EventMutation) ClearAlertEvents() {
-	m.owner = nil
-	m.clearedFields[event.FieldAlertEvents] = struct{}{}
-}
-
-// AlertEventsCleared returns if the "alert_events" field was cleared in this mutation.
-func (m *EventMutation) AlertEventsCleared() bool {
-	_, ok := m.clearedFields[event.FieldAlertEvents]
-	return ok
-}
-
-// ResetAlertEvents resets all changes to the "alert_events" field.
-func (m *EventMutation) ResetAlertEvents() {
-	m.owner = nil
-	delete(m.clearedFields, event.FieldAlertEvents)
-}
-
-// SetOwnerID sets the "owner" edge to the Alert entity by id.
-func (m *EventMutation) SetOwnerID(id int) {
-	m.owner = &id
-}
-
-// ClearOwner clears the "owner" edge to the Alert entity.
-func (m *EventMutation) ClearOwner() {
-	m.clearedowner = true
-	m.clearedFields[event.FieldAlertEvents] = struct{}{}
-}
-
-// OwnerCleared reports if the "owner" edge to the Alert entity was cleared.
-func (m *EventMutation) OwnerCleared() bool {
-	return m.AlertEventsCleared() || m.clearedowner
-}
-
-// OwnerID returns the "owner" edge ID in the mutation.
-func (m *EventMutation) OwnerID() (id int, exists bool) {
-	if m.owner != nil {
-		return *m.owner, true
-	}
-	return
-}
-
-// OwnerIDs returns the "owner" edge IDs in the mutation.
-// Note that IDs always returns len(IDs) <= 1 for unique edges, and you should use
-// OwnerID instead. It exists only for internal usage by the builders.
-func (m *EventMutation) OwnerIDs() (ids []int) {
-	if id := m.owner; id != nil {
-		ids = append(ids, *id)
-	}
-	return
-}
-
-// ResetOwner resets all changes to the "owner" edge.
-func (m *EventMutation) ResetOwner() {
-	m.owner = nil
-	m.clearedowner = false
+// ResetDecisionID resets all changes to the "decision_id" field.
+func (m *DecisionChangeMutation) ResetDecisionID() {
+	m.decision_id = nil
+	m.adddecision_id = nil
 }
 
-// Where appends a list predicates to the EventMutation builder.
-func (m *EventMutation) Where(ps ...predicate.Event) {
+// Where appends a list predicates to the DecisionChangeMutation builder.
+func (m *DecisionChangeMutation) Where(ps ...predicate.DecisionChange) {
 	m.predicates = append(m.predicates, ps...)
 }
 
-// WhereP appends storage-level predicates to the EventMutation builder. Using this method,
+// WhereP appends storage-level predicates to the DecisionChangeMutation builder. Using this method,
 // users can use type-assertion to append predicates that do not depend on any generated package.
-func (m *EventMutation) WhereP(ps ...func(*sql.Selector)) {
-	p := make([]predicate.Event, len(ps))
+func (m *DecisionChangeMutation) WhereP(ps ...func(*sql.Selector)) {
+	p := make([]predicate.DecisionChange, len(ps))
 	for i := range ps {
 		p[i] = ps[i]
 	}
@@ -7948,39 +7845,33 @@ func (m *EventMutation) WhereP(ps ...func(*sql.Selector)) {
 }
 
 // Op returns the operation name.
-func (m *EventMutation) Op() Op {
+func (m *DecisionChangeMutation) Op() Op {
 	return m.op
 }
 
 // SetOp allows setting the mutation operation.
-func (m *EventMutation) SetOp(op Op) {
+func (m *DecisionChangeMutation) SetOp(op Op) {
 	m.op = op
 }
 
-// Type returns the node type of this mutation (Event).
-func (m *EventMutation) Type() string {
+// Type returns the node type of this mutation (DecisionChange).
+func (m *DecisionChangeMutation) Type() string {
 	return m.typ
 }
 
 // Fields returns all fields that were changed during this mutation. Note that in
 // order to get all numeric fields that were incremented/decremented, call
 // AddedFields().
-func (m *EventMutation) Fields() []string {
-	fields := make([]string, 0, 5)
+func (m *DecisionChangeMutation) Fields() []string {
+	fields := make([]string, 0, 3)
 	if m.created_at != nil {
-		fields = append(fields, event.FieldCreatedAt)
-	}
-	if m.updated_at != nil {
-		fields = append(fields, event.FieldUpdatedAt)
-	}
-	if m.time != nil {
-		fields = append(fields, event.FieldTime)
+		fields = append(fields, decisionchange.FieldCreatedAt)
 	}
-	if m.serialized != nil {
-		fields = append(fields, event.FieldSerialized)
+	if m._type != nil {
+		fields = append(fields, decisionchange.FieldType)
 	}
-	if m.owner != nil {
-		fields = append(fields, event.FieldAlertEvents)
+	if m.decision_id != nil {
+		fields = append(fields, decisionchange.FieldDecisionID)
 	}
 	return fields
 }
@@ -7988,18 +7879,14 @@ func (m *EventMutation) Fields() []string {
 // Field returns the value of a field with the given name. The second boolean
 // return value indicates that this field was not set, or was not defined in the
 // schema.
-func (m *EventMutation) Field(name string) (ent.Value, bool) {
+func (m *DecisionChangeMutation) Field(name string) (ent.Value, bool) {
 	switch name {
-	case event.FieldCreatedAt:
+	case decisionchange.FieldCreatedAt:
 		return m.CreatedAt()
-	case event.FieldUpdatedAt:
-		return m.UpdatedAt()
-	case event.FieldTime:
-		return m.Time()
-	case event.FieldSerialized:
-		return m.Serialized()
-	case event.FieldAlertEvents:
-		return m.AlertEvents()
+	case decisionchange.FieldType:
+		return m.GetType()
+	case decisionchange.FieldDecisionID:
+		return m.DecisionID()
 	}
 	return nil, false
 }
@@ -8007,78 +7894,65 @@ func (m *EventMutation) Field(name string) (ent.Value, bool) {
 // OldField returns the old value of the field from the database. An error is
 // returned if the mutation operation is not UpdateOne, or the query to the
 // database failed.
-func (m *EventMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
+func (m *DecisionChangeMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
 	switch name {
-	case event.FieldCreatedAt:
+	case decisionchange.FieldCreatedAt:
 		return m.OldCreatedAt(ctx)
-	case event.FieldUpdatedAt:
-		return m.OldUpdatedAt(ctx)
-	case event.FieldTime:
-		return m.OldTime(ctx)
-	case event.FieldSerialized:
-		return m.OldSerialized(ctx)
-	case event.FieldAlertEvents:
-		return m.OldAlertEvents(ctx)
+	case decisionchange.FieldType:
+		return m.OldType(ctx)
+	case decisionchange.FieldDecisionID:
+		return m.OldDecisionID(ctx)
 	}
-	return nil, fmt.Errorf("unknown Event field %s", name)
+	return nil, fmt.Errorf("unknown DecisionChange field %s", name)
 }
 
 // SetField sets the value of a field with the given name. It returns an error if
 // the field is not defined in the schema, or if the type mismatched the field
 // type.
-func (m *EventMutation) SetField(name string, value ent.Value) error {
+func (m *DecisionChangeMutation) SetField(name string, value ent.Value) error {
 	switch name {
-	case event.FieldCreatedAt:
+	case decisionchange.FieldCreatedAt:
 		v, ok := value.(time.Time)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetCreatedAt(v)
 		return nil
-	case event.FieldUpdatedAt:
-		v, ok := value.(time.Time)
-		if !ok {
-			return fmt.Errorf("unexpected type %T for field %s", value, name)
-		}
-		m.SetUpdatedAt(v)
-		return nil
-	case event.FieldTime:
-		v, ok := value.(time.Time)
+	case decisionchange.FieldType:
+		v, ok := value.(decisionchange.Type)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetTime(v)
-		return nil
-	case event.FieldSerialized:
-		v, ok := value.(string)
-		if !ok {
-			return fmt.Errorf("unexpected type %T for field %s", value, name)
-		}
-		m.SetSerialized(v)
+		m.SetType(v)
 		return nil
-	case event.FieldAlertEvents:
+	case decisionchange.FieldDecisionID:
 		v, ok := value.(int)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetAlertEvents(v)
+		m.SetDecisionID(v)
 		return nil
 	}
-	return fmt.Errorf("unknown Event field %s", name)
+	return fmt.Errorf("unknown DecisionChange field %s", name)
 }
 
 // AddedFields returns all numeric fields that were incremented/decremented during
 // this mutation.
-func (m *EventMutation) AddedFields() []string {
+func (m *DecisionChangeMutation) AddedFields() []string {
 	var fields []string
+	if m.adddecision_id != nil {
+		fields = append(fields, decisionchange.FieldDecisionID)
+	}
 	return fields
 }
 
 // AddedField returns the numeric value that was incremented/decremented on a field
 // with the given name. The second boolean return value indicates that this field
 // was not set, or was not defined in the schema.
-func (m *EventMutation) AddedField(name string) (ent.Value, bool) {
+func (m *DecisionChangeMutation) AddedField(name string) (ent.Value, bool) {
 	switch name {
+	case decisionchange.FieldDecisionID:
+		return m.AddedDecisionID()
 	}
 	return nil, false
 }
@@ -8086,162 +7960,132 @@ func (m *EventMutation) AddedField(name string) (ent.Value, bool) {
 // AddField adds the value to the field with the given name. It returns an error if
 // the field is not defined in the schema, or if the type mismatched the field
 // type.
-func (m *EventMutation) AddField(name string, value ent.Value) error {
+func (m *DecisionChangeMutation) AddField(name string, value ent.Value) error {
 	switch name {
+	case decisionchange.FieldDecisionID:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddDecisionID(v)
+		return nil
 	}
-	return fmt.Errorf("unknown Event numeric field %s", name)
+	return fmt.Errorf("unknown DecisionChange numeric field %s", name)
 }
 
 // ClearedFields returns all nullable fields that were cleared during this
 // mutation.
-func (m *EventMutation) ClearedFields() []string {
-	var fields []string
-	if m.FieldCleared(event.FieldAlertEvents) {
-		fields = append(fields, event.FieldAlertEvents)
-	}
-	return fields
+func (m *DecisionChangeMutation) ClearedFields() []string {
+	return nil
 }
 
 // FieldCleared returns a boolean indicating if a field with the given name was
 // cleared in this mutation.
-func (m *EventMutation) FieldCleared(name string) bool {
+func (m *DecisionChangeMutation) FieldCleared(name string) bool {
 	_, ok := m.clearedFields[name]
 	return ok
 }
 
 // ClearField clears the value of the field with the given name. It returns an
 // error if the field is not defined in the schema.
-func (m *EventMutation) ClearField(name string) error {
-	switch name {
-	case event.FieldAlertEvents:
-		m.ClearAlertEvents()
-		return nil
-	}
-	return fmt.Errorf("unknown Event nullable field %s", name)
+func (m *DecisionChangeMutation) ClearField(name string) error {
+	return fmt.Errorf("unknown DecisionChange nullable field %s", name)
 }
 
 // ResetField resets all changes in the mutation for the field with the given name.
 // It returns an error if the field is not defined in the schema.
-func (m *EventMutation) ResetField(name string) error {
+func (m *DecisionChangeMutation) ResetField(name string) error {
 	switch name {
-	case event.FieldCreatedAt:
+	case decisionchange.FieldCreatedAt:
 		m.ResetCreatedAt()
 		return nil
-	case event.FieldUpdatedAt:
-		m.ResetUpdatedAt()
-		return nil
-	case event.FieldTime:
-		m.ResetTime()
-		return nil
-	case event.FieldSerialized:
-		m.ResetSerialized()
+	case decisionchange.FieldType:
+		m.ResetType()
 		return nil
-	case event.FieldAlertEvents:
-		m.ResetAlertEvents()
+	case decisionchange.FieldDecisionID:
+		m.ResetDecisionID()
 		return nil
 	}
-	return fmt.Errorf("unknown Event field %s", name)
+	return fmt.Errorf("unknown DecisionChange field %s", name)
 }
 
 // AddedEdges returns all edge names that were set/added in this mutation.
-func (m *EventMutation) AddedEdges() []string {
-	edges := make([]string, 0, 1)
-	if m.owner != nil {
-		edges = append(edges, event.EdgeOwner)
-	}
+func (m *DecisionChangeMutation) AddedEdges() []string {
+	edges := make([]string, 0, 0)
 	return edges
 }
 
 // AddedIDs returns all IDs (to other nodes) that were added for the given edge
 // name in this mutation.
-func (m *EventMutation) AddedIDs(name string) []ent.Value {
-	switch name {
-	case event.EdgeOwner:
-		if id := m.owner; id != nil {
-			return []ent.Value{*id}
-		}
-	}
+func (m *DecisionChangeMutation) AddedIDs(name string) []ent.Value {
 	return nil
 }
 
 // RemovedEdges returns all edge names that were removed in this mutation.
-func (m *EventMutation) RemovedEdges() []string {
-	edges := make([]string, 0, 1)
+func (m *DecisionChangeMutation) RemovedEdges() []string {
+	edges := make([]string, 0, 0)
 	return edges
 }
 
 // RemovedIDs returns all IDs (to other nodes) that were removed for the edge with
 // the given name in this mutation.
-func (m *EventMutation) RemovedIDs(name string) []ent.Value {
+func (m *DecisionChangeMutation) RemovedIDs(name string) []ent.Value {
 	return nil
 }
 
 // ClearedEdges returns all edge names that were cleared in this mutation.
-func (m *EventMutation) ClearedEdges() []string {
-	edges := make([]string, 0, 1)
-	if m.clearedowner {
-		edges = append(edges, event.EdgeOwner)
-	}
+func (m *DecisionChangeMutation) ClearedEdges() []string {
+	edges := make([]string, 0, 0)
 	return edges
 }
 
 // EdgeCleared returns a boolean which indicates if the edge with the given name
 // was cleared in this mutation.
-func (m *EventMutation) EdgeCleared(name string) bool {
-	switch name {
-	case event.EdgeOwner:
-		return m.clearedowner
-	}
+func (m *DecisionChangeMutation) EdgeCleared(name string) bool {
 	return false
 }
 
 // ClearEdge clears the value of the edge with the given name. It returns an error
 // if that edge is not defined in the schema.
-func (m *EventMutation) ClearEdge(name string) error {
-	switch name {
-	case event.EdgeOwner:
-		m.ClearOwner()
-		return nil
-	}
-	return fmt.Errorf("unknown Event unique edge %s", name)
+func (m *DecisionChangeMutation) ClearEdge(name string) error {
+	return fmt.Errorf("unknown DecisionChange unique edge %s", name)
 }
 
 // ResetEdge resets all changes to the edge with the given name in this mutation.
 // It returns an error if the edge is not defined in the schema.
-func (m *EventMutation) ResetEdge(name string) error {
-	switch name {
-	case event.EdgeOwner:
-		m.ResetOwner()
-		return nil
-	}
-	return fmt.Errorf("unknown Event edge %s", name)
+func (m *DecisionChangeMutation) ResetEdge(name string) error {
+	return fmt.Errorf("unknown DecisionChange edge %s", name)
 }
 
-// LockMutation represents an operation that mutates the Lock nodes in the graph.
-type LockMutation struct {
+// EventMutation represents an operation that mutates the Event nodes in the graph.
+type EventMutation struct {
 	config
 	op            Op
 	typ           string
 	id            *int
-	name          *string
 	created_at    *time.Time
+	updated_at    *time.Time
+	time          *time.Time
+	serialized    *string
 	clearedFields map[string]struct{}
+	owner         *int
+	clearedowner  bool
 	done          bool
-	oldValue      func(context.Context) (*Lock, error)
-	predicates    []predicate.Lock
+	oldValue      func(context.Context) (*Event, error)
+	predicates    []predicate.Event
 }
 
-var _ ent.Mutation = (*LockMutation)(nil)
+var _ ent.Mutation = (*EventMutation)(nil)
 
-// lockOption allows management of the mutation configuration using functional options.
-type lockOption func(*LockMutation)
+// eventOption allows management of the mutation configuration using functional options.
+type eventOption func(*EventMutation)
 
-// newLockMutation creates new mutation for the Lock entity.
-func newLockMutation(c config, op Op, opts ...lockOption) *LockMutation {
-	m := &LockMutation{
+// newEventMutation creates new mutation for the Event entity.
+func newEventMutation(c config, op Op, opts ...eventOption) *EventMutation {
+	m := &EventMutation{
 		config:        c,
 		op:            op,
-		typ:           TypeLock,
+		typ:           TypeEvent,
 		clearedFields: make(map[string]struct{}),
 	}
 	for _, opt := range opts {
@@ -8250,20 +8094,20 @@ func newLockMutation(c config, op Op, opts ...lockOption) *LockMutation {
 	return m
 }
 
-// withLockID sets the ID field of the mutation.
-func withLockID(id int) lockOption {
-	return func(m *LockMutation) {
+// withEventID sets the ID field of the mutation.
+func withEventID(id int) eventOption {
+	return func(m *EventMutation) {
 		var (
 			err   error
 			once  sync.Once
-			value *Lock
+			value *Event
 		)
-		m.oldValue = func(ctx context.Context) (*Lock, error) {
+		m.oldValue = func(ctx context.Context) (*Event, error) {
 			once.Do(func() {
 				if m.done {
 					err = errors.New("querying old values post mutation is not allowed")
 				} else {
-					value, err = m.Client().Lock.Get(ctx, id)
+					value, err = m.Client().Event.Get(ctx, id)
 				}
 			})
 			return value, err
@@ -8272,10 +8116,10 @@ func withLockID(id int) lockOption {
 	}
 }
 
-// withLock sets the old Lock of the mutation.
-func withLock(node *Lock) lockOption {
-	return func(m *LockMutation) {
-		m.oldValue = func(context.Context) (*Lock, error) {
+// withEvent sets the old Event of the mutation.
+func withEvent(node *Event) eventOption {
+	return func(m *EventMutation) {
+		m.oldValue = func(context.Context) (*Event, error) {
 			return node, nil
 		}
 		m.id = &node.ID
@@ -8284,7 +8128,7 @@ func withLock(node *Lock) lockOption {
 
 // Client returns a new `ent.Client` from the mutation. If the mutation was
 // executed in a transaction (ent.Tx), a transactional client is returned.
-func (m LockMutation) Client() *Client {
+func (m EventMutation) Client() *Client {
 	client := &Client{config: m.config}
 	client.init()
 	return client
@@ -8292,7 +8136,7 @@ func (m LockMutation) Client() *Client {
 
 // Tx returns an `ent.Tx` for mutations that were executed in transactions;
 // it returns an error otherwise.
-func (m LockMutation) Tx() (*Tx, error) {
+func (m EventMutation) Tx() (*Tx, error) {
 	if _, ok := m.driver.(*txDriver); !ok {
 		return nil, errors.New("ent: mutation is not running in a transaction")
 	}
@@ -8303,7 +8147,7 @@ func (m LockMutation) Tx() (*Tx, error) {
 
 // ID returns the ID value in the mutation. Note that the ID is only available
 // if it was provided to the builder or after it was returned from the database.
-func (m *LockMutation) ID() (id int, exists bool) {
+func (m *EventMutation) ID() (id int, exists bool) {
 	if m.id == nil {
 		return
 	}
@@ -8314,7 +8158,7 @@ func (m *LockMutation) ID() (id int, exists bool) {
 // That means, if the mutation is applied within a transaction with an isolation level such
 // as sql.LevelSerializable, the returned ids match the ids of the rows that will be updated
 // or updated by the mutation.
-func (m *LockMutation) IDs(ctx context.Context) ([]int, error) {
+func (m *EventMutation) IDs(ctx context.Context) ([]int, error) {
 	switch {
 	case m.op.Is(OpUpdateOne | OpDeleteOne):
 		id, exists := m.ID()
@@ -8323,1165 +8167,2820 @@ func (m *LockMutation) IDs(ctx context.Context) ([]int, error) {
 		}
 		fallthrough
 	case m.op.Is(OpUpdate | OpDelete):
-		return m.Client().Lock.Query().Where(m.predicates...).IDs(ctx)
+		return m.Client().Event.Query().Where(m.predicates...).IDs(ctx)
 	default:
 		return nil, fmt.Errorf("IDs is not allowed on %s operations", m.op)
 	}
 }
 
-// SetName sets the "name" field.
-func (m *LockMutation) SetName(s string) {
-	m.name = &s
+// SetCreatedAt sets the "created_at" field.
+func (m *EventMutation) SetCreatedAt(t time.Time) {
+	m.created_at = &t
 }
 
-// Name returns the value of the "name" field in the mutation.
-func (m *LockMutation) Name() (r string, exists bool) {
-	v := m.name
+// CreatedAt returns the value of the "created_at" field in the mutation.
+func (m *EventMutation) CreatedAt() (r time.Time, exists bool) {
+	v := m.created_at
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldName returns the old "name" field's value of the Lock entity.
-// If the Lock object wasn't provided to the builder, the object is fetched from the database.
+// OldCreatedAt returns the old "created_at" field's value of the Event entity.
+// If the Event object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *LockMutation) OldName(ctx context.Context) (v string, err error) {
+func (m *EventMutation) OldCreatedAt(ctx context.Context) (v time.Time, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldName is only allowed on UpdateOne operations")
+		return v, errors.New("OldCreatedAt is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldName requires an ID field in the mutation")
+		return v, errors.New("OldCreatedAt requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldName: %w", err)
+		return v, fmt.Errorf("querying old value for OldCreatedAt: %w", err)
 	}
-	return oldValue.Name, nil
+	return oldValue.CreatedAt, nil
 }
 
-// ResetName resets all changes to the "name" field.
-func (m *LockMutation) ResetName() {
-	m.name = nil
+// ResetCreatedAt resets all changes to the "created_at" field.
+func (m *EventMutation) ResetCreatedAt() {
+	m.created_at = nil
 }
 
-// SetCreatedAt sets the "created_at" field.
-func (m *LockMutation) SetCreatedAt(t time.Time) {
-	m.created_at = &t
+// SetUpdatedAt sets the "updated_at" field.
+func (m *EventMutation) SetUpdatedAt(t time.Time) {
+	m.updated_at = &t
 }
 
-// CreatedAt returns the value of the "created_at" field in the mutation.
-func (m *LockMutation) CreatedAt() (r time.Time, exists bool) {
-	v := m.created_at
+// UpdatedAt returns the value of the "updated_at" field in the mutation.
+func (m *EventMutation) UpdatedAt() (r time.Time, exists bool) {
+	v := m.updated_at
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldCreatedAt returns the old "created_at" field's value of the Lock entity.
-// If the Lock object wasn't provided to the builder, the object is fetched from the database.
+// OldUpdatedAt returns the old "updated_at" field's value of the Event entity.
+// If the Event object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *LockMutation) OldCreatedAt(ctx context.Context) (v time.Time, err error) {
+func (m *EventMutation) OldUpdatedAt(ctx context.Context) (v time.Time, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldCreatedAt is only allowed on UpdateOne operations")
+		return v, errors.New("OldUpdatedAt is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldCreatedAt requires an ID field in the mutation")
+		return v, errors.New("OldUpdatedAt requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldCreatedAt: %w", err)
+		return v, fmt.Errorf("querying old value for OldUpdatedAt: %w", err)
 	}
-	return oldValue.CreatedAt, nil
+	return oldValue.UpdatedAt, nil
 }
 
-// ResetCreatedAt resets all changes to the "created_at" field.
-func (m *LockMutation) ResetCreatedAt() {
-	m.created_at = nil
+// ResetUpdatedAt resets all changes to the "updated_at" field.
+func (m *EventMutation) ResetUpdatedAt() {
+	m.updated_at = nil
 }
 
-// Where appends a list predicates to the LockMutation builder.
-func (m *LockMutation) Where(ps ...predicate.Lock) {
-	m.predicates = append(m.predicates, ps...)
+// SetTime sets the "time" field.
+func (m *EventMutation) SetTime(t time.Time) {
+	m.time = &t
 }
 
-// WhereP appends storage-level predicates to the LockMutation builder. Using this method,
-// users can use type-assertion to append predicates that do not depend on any generated package.
-func (m *LockMutation) WhereP(ps ...func(*sql.Selector)) {
-	p := make([]predicate.Lock, len(ps))
-	for i := range ps {
-		p[i] = ps[i]
+// Time returns the value of the "time" field in the mutation.
+func (m *EventMutation) Time() (r time.Time, exists bool) {
+	v := m.time
+	if v == nil {
+		return
 	}
-	m.Where(p...)
-}
-
-// Op returns the operation name.
-func (m *LockMutation) Op() Op {
-	return m.op
-}
-
-// SetOp allows setting the mutation operation.
-func (m *LockMutation) SetOp(op Op) {
-	m.op = op
+	return *v, true
 }
 
-// Type returns the node type of this mutation (Lock).
-func (m *LockMutation) Type() string {
-	return m.typ
-}
-
-// Fields returns all fields that were changed during this mutation. Note that in
-// order to get all numeric fields that were incremented/decremented, call
-// AddedFields().
-func (m *LockMutation) Fields() []string {
-	fields := make([]string, 0, 2)
-	if m.name != nil {
-		fields = append(fields, lock.FieldName)
+// OldTime returns the old "time" field's value of the Event entity.
+// If the Event object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *EventMutation) OldTime(ctx context.Context) (v time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldTime is only allowed on UpdateOne operations")
 	}
-	if m.created_at != nil {
-		fields = append(fields, lock.FieldCreatedAt)
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldTime requires an ID field in the mutation")
 	}
-	return fields
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldTime: %w", err)
+	}
+	return oldValue.Time, nil
 }
 
-// Field returns the value of a field with the given name. The second boolean
-// return value indicates that this field was not set, or was not defined in the
-// schema.
-func (m *LockMutation) Field(name string) (ent.Value, bool) {
-	switch name {
-	case lock.FieldName:
-		return m.Name()
-	case lock.FieldCreatedAt:
-		return m.CreatedAt()
-	}
-	return nil, false
+// ResetTime resets all changes to the "time" field.
+func (m *EventMutation) ResetTime() {
+	m.time = nil
 }
 
-// OldField returns the old value of the field from the database. An error is
-// returned if the mutation operation is not UpdateOne, or the query to the
-// database failed.
-func (m *LockMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
-	switch name {
-	case lock.FieldName:
-		return m.OldName(ctx)
-	case lock.FieldCreatedAt:
-		return m.OldCreatedAt(ctx)
+// SetSerialized sets the "serialized" field.
+func (m *EventMutation) SetSerialized(s string) {
+	m.serialized = &s
+}
+
+// Serialized returns the value of the "serialized" field in the mutation.
+func (m *EventMutation) Serialized() (r string, exists bool) {
+	v := m.serialized
+	if v == nil {
+		return
 	}
-	return nil, fmt.Errorf("unknown Lock field %s", name)
+	return *v, true
 }
 
-// SetField sets the value of a field with the given name. It returns an error if
-// the field is not defined in the schema, or if the type mismatched the field
-// type.
-func (m *LockMutation) SetField(name string, value ent.Value) error {
-	switch name {
-	case lock.FieldName:
-		v, ok := value.(string)
-		if !ok {
-			return fmt.Errorf("unexpected type %T for field %s", value, name)
-		}
-		m.SetName(v)
-		return nil
-	case lock.FieldCreatedAt:
-		v, ok := value.(time.Time)
-		if !ok {
-			return fmt.Errorf("unexpected type %T for field %s", value, name)
-		}
-		m.SetCreatedAt(v)
-		return nil
+// OldSerialized returns the old "serialized" field's value of the Event entity.
+// If the Event object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *EventMutation) OldSerialized(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldSerialized is only allowed on UpdateOne operations")
 	}
-	return fmt.Errorf("unknown Lock field %s", name)
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldSerialized requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldSerialized: %w", err)
+	}
+	return oldValue.Serialized, nil
 }
 
-// AddedFields returns all numeric fields that were incremented/decremented during
-// this mutation.
-func (m *LockMutation) AddedFields() []string {
-	return nil
+// ResetSerialized resets all changes to the "serialized" field.
+func (m *EventMutation) ResetSerialized() {
+	m.serialized = nil
 }
 
-// AddedField returns the numeric value that was incremented/decremented on a field
-// with the given name. The second boolean return value indicates that this field
-// was not set, or was not defined in the schema.
-func (m *LockMutation) AddedField(name string) (ent.Value, bool) {
-	return nil, false
+// SetAlertEvents sets the "alert_events" field.
+func (m *EventMutation) SetAlertEvents(i int) {
+	m.owner = &i
 }
 
-// AddField adds the value to the field with the given name. It returns an error if
-// the field is not defined in the schema, or if the type mismatched the field
-// type.
-func (m *LockMutation) AddField(name string, value ent.Value) error {
-	switch name {
+// AlertEvents returns the value of the "alert_events" field in the mutation.
+func (m *EventMutation) AlertEvents() (r int, exists bool) {
+	v := m.owner
+	if v == nil {
+		return
 	}
-	return fmt.Errorf("unknown Lock numeric field %s", name)
+	return *v, true
 }
 
-// ClearedFields returns all nullable fields that were cleared during this
-// mutation.
-func (m *LockMutation) ClearedFields() []string {
-	return nil
+// OldAlertEvents returns the old "alert_events" field's value of the Event entity.
+// If the Event object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *EventMutation) OldAlertEvents(ctx context.Context) (v int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldAlertEvents is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldAlertEvents requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldAlertEvents: %w", err)
+	}
+	return oldValue.AlertEvents, nil
 }
 
-// FieldCleared returns a boolean indicating if a field with the given name was
-// cleared in this mutation.
-func (m *LockMutation) FieldCleared(name string) bool {
-	_, ok := m.clearedFields[name]
+// ClearAlertEvents clears the value of the "alert_events" field.
+func (m *EventMutation) ClearAlertEvents() {
+	m.owner = nil
+	m.clearedFields[event.FieldAlertEvents] = struct{}{}
+}
+
+// AlertEventsCleared returns if the "alert_events" field was cleared in this mutation.
+func (m *EventMutation) AlertEventsCleared() bool {
+	_, ok := m.clearedFields[event.FieldAlertEvents]
 	return ok
 }
 
-// ClearField clears the value of the field with the given name. It returns an
-// error if the field is not defined in the schema.
-func (m *LockMutation) ClearField(name string) error {
-	return fmt.Errorf("unknown Lock nullable field %s", name)
+// ResetAlertEvents resets all changes to the "alert_events" field.
+func (m *EventMutation) ResetAlertEvents() {
+	m.owner = nil
+	delete(m.clearedFields, event.FieldAlertEvents)
 }
 
-// ResetField resets all changes in the mutation for the field with the given name.
-// It returns an error if the field is not defined in the schema.
-func (m *LockMutation) ResetField(name string) error {
-	switch name {
-	case lock.FieldName:
-		m.ResetName()
-		return nil
-	case lock.FieldCreatedAt:
-		m.ResetCreatedAt()
-		return nil
-	}
-	return fmt.Errorf("unknown Lock field %s", name)
+// SetOwnerID sets the "owner" edge to the Alert entity by id.
+func (m *EventMutation) SetOwnerID(id int) {
+	m.owner = &id
 }
 
-// AddedEdges returns all edge names that were set/added in this mutation.
-func (m *LockMutation) AddedEdges() []string {
-	edges := make([]string, 0, 0)
-	return edges
+// ClearOwner clears the "owner" edge to the Alert entity.
+func (m *EventMutation) ClearOwner() {
+	m.clearedowner = true
+	m.clearedFields[event.FieldAlertEvents] = struct{}{}
 }
 
-// AddedIDs returns all IDs (to other nodes) that were added for the given edge
-// name in this mutation.
-func (m *LockMutation) AddedIDs(name string) []ent.Value {
-	return nil
+// OwnerCleared reports if the "owner" edge to the Alert entity was cleared.
+func (m *EventMutation) OwnerCleared() bool {
+	return m.AlertEventsCleared() || m.clearedowner
 }
 
-// RemovedEdges returns all edge names that were removed in this mutation.
-func (m *LockMutation) RemovedEdges() []string {
-	edges := make([]string, 0, 0)
-	return edges
+// OwnerID returns the "owner" edge ID in the mutation.
+func (m *EventMutation) OwnerID() (id int, exists bool) {
+	if m.owner != nil {
+		return *m.owner, true
+	}
+	return
 }
 
-// RemovedIDs returns all IDs (to other nodes) that were removed for the edge with
-// the given name in this mutation.
-func (m *LockMutation) RemovedIDs(name string) []ent.Value {
-	return nil
+// OwnerIDs returns the "owner" edge IDs in the mutation.
+// Note that IDs always returns len(IDs) <= 1 for unique edges, and you should use
+// OwnerID instead. It exists only for internal usage by the builders.
+func (m *EventMutation) OwnerIDs() (ids []int) {
+	if id := m.owner; id != nil {
+		ids = append(ids, *id)
+	}
+	return
 }
 
-// ClearedEdges returns all edge names that were cleared in this mutation.
-func (m *LockMutation) ClearedEdges() []string {
-	edges := make([]string, 0, 0)
-	return edges
+// ResetOwner resets all changes to the "owner" edge.
+func (m *EventMutation) ResetOwner() {
+	m.owner = nil
+	m.clearedowner = false
 }
 
-// EdgeCleared returns a boolean which indicates if the edge with the given name
-// was cleared in this mutation.
-func (m *LockMutation) EdgeCleared(name string) bool {
-	return false
+// Where appends a list predicates to the EventMutation builder.
+func (m *EventMutation) Where(ps ...predicate.Event) {
+	m.predicates = append(m.predicates, ps...)
 }
 
-// ClearEdge clears the value of the edge with the given name. It returns an error
-// if that edge is not defined in the schema.
-func (m *LockMutation) ClearEdge(name string) error {
-	return fmt.Errorf("unknown Lock unique edge %s", name)
+// WhereP appends storage-level predicates to the EventMutation builder. Using this method,
+// users can use type-assertion to append predicates that do not depend on any generated package.
+func (m *EventMutation) WhereP(ps ...func(*sql.Selector)) {
+	p := make([]predicate.Event, len(ps))
+	for i := range ps {
+		p[i] = ps[i]
+	}
+	m.Where(p...)
 }
 
-// ResetEdge resets all changes to the edge with the given name in this mutation.
-// It returns an error if the edge is not defined in the schema.
-func (m *LockMutation) ResetEdge(name string) error {
-	return fmt.Errorf("unknown Lock edge %s", name)
+// Op returns the operation name.
+func (m *EventMutation) Op() Op {
+	return m.op
 }
 
-// MachineMutation represents an operation that mutates the Machine nodes in the graph.
-type MachineMutation struct {
-	config
-	op             Op
-	typ            string
-	id             *int
-	created_at     *time.Time
-	updated_at     *time.Time
-	last_push      *time.Time
-	last_heartbeat *time.Time
-	machineId      *string
-	password       *string
-	ipAddress      *string
-	scenarios      *string
-	version        *string
-	isValidated    *bool
-	auth_type      *string
-	osname         *string
-	osversion      *string
-	featureflags   *string
-	hubstate       *map[string][]schema.ItemState
-	datasources    *map[string]int64
-	clearedFields  map[string]struct{}
-	alerts         map[int]struct{}
-	removedalerts  map[int]struct{}
-	clearedalerts  bool
-	done           bool
-	oldValue       func(context.Context) (*Machine, error)
-	predicates     []predicate.Machine
+// SetOp allows setting the mutation operation.
+func (m *EventMutation) SetOp(op Op) {
+	m.op = op
 }
 
-var _ ent.Mutation = (*MachineMutation)(nil)
-
-// machineOption allows management of the mutation configuration using functional options.
-type machineOption func(*MachineMutation)
+// Type returns the node type of this mutation (Event).
+func (m *EventMutation) Type() string {
+	return m.typ
+}
 
-// newMachineMutation creates new mutation for the Machine entity.
-func newMachineMutation(c config, op Op, opts ...machineOption) *MachineMutation {
-	m := &MachineMutation{
-		config:        c,
-		op:            op,
-		typ:           TypeMachine,
-		clearedFields: make(map[string]struct{}),
+// Fields returns all fields that were changed during this mutation. Note that in
+// order to get all numeric fields that were incremented/decremented, call
+// AddedFields().
+func (m *EventMutation) Fields() []string {
+	fields := make([]string, 0, 5)
+	if m.created_at != nil {
+		fields = append(fields, event.FieldCreatedAt)
 	}
-	for _, opt := range opts {
-		opt(m)
+	if m.updated_at != nil {
+		fields = append(fields, event.FieldUpdatedAt)
 	}
-	return m
-}
-
-// withMachineID sets the ID field of the mutation.
-func withMachineID(id int) machineOption {
-	return func(m *MachineMutation) {
-		var (
-			err   error
-			once  sync.Once
-			value *Machine
-		)
-		m.oldValue = func(ctx context.Context) (*Machine, error) {
-			once.Do(func() {
-				if m.done {
-					err = errors.New("querying old values post mutation is not allowed")
-				} else {
-					value, err = m.Client().Machine.Get(ctx, id)
-				}
-			})
-			return value, err
-		}
-		m.id = &id
+	if m.time != nil {
+		fields = append(fields, event.FieldTime)
 	}
-}
-
-// withMachine sets the old Machine of the mutation.
-func withMachine(node *Machine) machineOption {
-	return func(m *MachineMutation) {
-		m.oldValue = func(context.Context) (*Machine, error) {
-			return node, nil
-		}
-		m.id = &node.ID
+	if m.serialized != nil {
+		fields = append(fields, event.FieldSerialized)
 	}
+	if m.owner != nil {
+		fields = append(fields, event.FieldAlertEvents)
+	}
+	return fields
 }
 
-// Client returns a new `ent.Client` from the mutation. If the mutation was
-// executed in a transaction (ent.Tx), a transactional client is returned.
-func (m MachineMutation) Client() *Client {
-	client := &Client{config: m.config}
-	client.init()
-	return client
-}
-
-// Tx returns an `ent.Tx` for mutations that were executed in transactions;
-// it returns an error otherwise.
-func (m MachineMutation) Tx() (*Tx, error) {
-	if _, ok := m.driver.(*txDriver); !ok {
-		return nil, errors.New("ent: mutation is not running in a transaction")
+// Field returns the value of a field with the given name. The second boolean
+// return value indicates that this field was not set, or was not defined in the
+// schema.
+func (m *EventMutation) Field(name string) (ent.Value, bool) {
+	switch name {
+	case event.FieldCreatedAt:
+		return m.CreatedAt()
+	case event.FieldUpdatedAt:
+		return m.UpdatedAt()
+	case event.FieldTime:
+		return m.Time()
+	case event.FieldSerialized:
+		return m.Serialized()
+	case event.FieldAlertEvents:
+		return m.AlertEvents()
 	}
-	tx := &Tx{config: m.config}
-	tx.init()
-	return tx, nil
+	return nil, false
 }
 
-// ID returns the ID value in the mutation. Note that the ID is only available
-// if it was provided to the builder or after it was returned from the database.
-func (m *MachineMutation) ID() (id int, exists bool) {
-	if m.id == nil {
-		return
+// OldField returns the old value of the field from the database. An error is
+// returned if the mutation operation is not UpdateOne, or the query to the
+// database failed.
+func (m *EventMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
+	switch name {
+	case event.FieldCreatedAt:
+		return m.OldCreatedAt(ctx)
+	case event.FieldUpdatedAt:
+		return m.OldUpdatedAt(ctx)
+	case event.FieldTime:
+		return m.OldTime(ctx)
+	case event.FieldSerialized:
+		return m.OldSerialized(ctx)
+	case event.FieldAlertEvents:
+		return m.OldAlertEvents(ctx)
 	}
-	return *m.id, true
+	return nil, fmt.Errorf("unknown Event field %s", name)
 }
 
-// IDs queries the database and returns the entity ids that match the mutation's predicate.
-// That means, if the mutation is applied within a transaction with an isolation level such
-// as sql.LevelSerializable, the returned ids match the ids of the rows that will be updated
-// or updated by the mutation.
-func (m *MachineMutation) IDs(ctx context.Context) ([]int, error) {
-	switch {
-	case m.op.Is(OpUpdateOne | OpDeleteOne):
-		id, exists := m.ID()
-		if exists {
-			return []int{id}, nil
+// SetField sets the value of a field with the given name. It returns an error if
+// the field is not defined in the schema, or if the type mismatched the field
+// type.
+func (m *EventMutation) SetField(name string, value ent.Value) error {
+	switch name {
+	case event.FieldCreatedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		fallthrough
-	case m.op.Is(OpUpdate | OpDelete):
-		return m.Client().Machine.Query().Where(m.predicates...).IDs(ctx)
-	default:
-		return nil, fmt.Errorf("IDs is not allowed on %s operations", m.op)
+		m.SetCreatedAt(v)
+		return nil
+	case event.FieldUpdatedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetUpdatedAt(v)
+		return nil
+	case event.FieldTime:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetTime(v)
+		return nil
+	case event.FieldSerialized:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetSerialized(v)
+		return nil
+	case event.FieldAlertEvents:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetAlertEvents(v)
+		return nil
 	}
+	return fmt.Errorf("unknown Event field %s", name)
 }
 
-// SetCreatedAt sets the "created_at" field.
-func (m *MachineMutation) SetCreatedAt(t time.Time) {
-	m.created_at = &t
+// AddedFields returns all numeric fields that were incremented/decremented during
+// this mutation.
+func (m *EventMutation) AddedFields() []string {
+	var fields []string
+	return fields
 }
 
-// CreatedAt returns the value of the "created_at" field in the mutation.
-func (m *MachineMutation) CreatedAt() (r time.Time, exists bool) {
-	v := m.created_at
-	if v == nil {
-		return
+// AddedField returns the numeric value that was incremented/decremented on a field
+// with the given name. The second boolean return value indicates that this field
+// was not set, or was not defined in the schema.
+func (m *EventMutation) AddedField(name string) (ent.Value, bool) {
+	switch name {
 	}
-	return *v, true
+	return nil, false
 }
 
-// OldCreatedAt returns the old "created_at" field's value of the Machine entity.
-// If the Machine object wasn't provided to the builder, the object is fetched from the database.
-// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *MachineMutation) OldCreatedAt(ctx context.Context) (v time.Time, err error) {
-	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldCreatedAt is only allowed on UpdateOne operations")
-	}
-	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldCreatedAt requires an ID field in the mutation")
-	}
-	oldValue, err := m.oldValue(ctx)
-	if err != nil {
-		return v, fmt.Errorf("querying old value for OldCreatedAt: %w", err)
+// AddField adds the value to the field with the given name. It returns an error if
+// the field is not defined in the schema, or if the type mismatched the field
+// type.
+func (m *EventMutation) AddField(name string, value ent.Value) error {
+	switch name {
 	}
-	return oldValue.CreatedAt, nil
+	return fmt.Errorf("unknown Event numeric field %s", name)
 }
 
-// ResetCreatedAt resets all changes to the "created_at" field.
-func (m *MachineMutation) ResetCreatedAt() {
-	m.created_at = nil
+// ClearedFields returns all nullable fields that were cleared during this
+// mutation.
+func (m *EventMutation) ClearedFields() []string {
+	var fields []string
+	if m.FieldCleared(event.FieldAlertEvents) {
+		fields = append(fields, event.FieldAlertEvents)
+	}
+	return fields
 }
 
-// SetUpdatedAt sets the "updated_at" field.
-func (m *MachineMutation) SetUpdatedAt(t time.Time) {
-	m.updated_at = &t
+// FieldCleared returns a boolean indicating if a field with the given name was
+// cleared in this mutation.
+func (m *EventMutation) FieldCleared(name string) bool {
+	_, ok := m.clearedFields[name]
+	return ok
 }
 
-// UpdatedAt returns the value of the "updated_at" field in the mutation.
-func (m *MachineMutation) UpdatedAt() (r time.Time, exists bool) {
-	v := m.updated_at
-	if v == nil {
-		return
+// ClearField clears the value of the field with the given name. It returns an
+// error if the field is not defined in the schema.
+func (m *EventMutation) ClearField(name string) error {
+	switch name {
+	case event.FieldAlertEvents:
+		m.ClearAlertEvents()
+		return nil
 	}
-	return *v, true
+	return fmt.Errorf("unknown Event nullable field %s", name)
 }
 
-// OldUpdatedAt returns the old "updated_at" field's value of the Machine entity.
-// If the Machine object wasn't provided to the builder, the object is fetched from the database.
-// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *MachineMutation) OldUpdatedAt(ctx context.Context) (v time.Time, err error) {
-	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldUpdatedAt is only allowed on UpdateOne operations")
+// ResetField resets all changes in the mutation for the field with the given name.
+// It returns an error if the field is not defined in the schema.
+func (m *EventMutation) ResetField(name string) error {
+	switch name {
+	case event.FieldCreatedAt:
+		m.ResetCreatedAt()
+		return nil
+	case event.FieldUpdatedAt:
+		m.ResetUpdatedAt()
+		return nil
+	case event.FieldTime:
+		m.ResetTime()
+		return nil
+	case event.FieldSerialized:
+		m.ResetSerialized()
+		return nil
+	case event.FieldAlertEvents:
+		m.ResetAlertEvents()
+		return nil
 	}
-	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldUpdatedAt requires an ID field in the mutation")
+	return fmt.Errorf("unknown Event field %s", name)
+}
+
+// AddedEdges returns all edge names that were set/added in this mutation.
+func (m *EventMutation) AddedEdges() []string {
+	edges := make([]string, 0, 1)
+	if m.owner != nil {
+		edges = append(edges, event.EdgeOwner)
 	}
-	oldValue, err := m.oldValue(ctx)
-	if err != nil {
-		return v, fmt.Errorf("querying old value for OldUpdatedAt: %w", err)
+	return edges
+}
+
+// AddedIDs returns all IDs (to other nodes) that were added for the given edge
+// name in this mutation.
+func (m *EventMutation) AddedIDs(name string) []ent.Value {
+	switch name {
+	case event.EdgeOwner:
+		if id := m.owner; id != nil {
+			return []ent.Value{*id}
+		}
 	}
-	return oldValue.UpdatedAt, nil
+	return nil
 }
 
-// ResetUpdatedAt resets all changes to the "updated_at" field.
-func (m *MachineMutation) ResetUpdatedAt() {
-	m.updated_at = nil
+// RemovedEdges returns all edge names that were removed in this mutation.
+func (m *EventMutation) RemovedEdges() []string {
+	edges := make([]string, 0, 1)
+	return edges
 }
 
-// SetLastPush sets the "last_push" field.
-func (m *MachineMutation) SetLastPush(t time.Time) {
-	m.last_push = &t
+// RemovedIDs returns all IDs (to other nodes) that were removed for the edge with
+// the given name in this mutation.
+func (m *EventMutation) RemovedIDs(name string) []ent.Value {
+	return nil
 }
 
-// LastPush returns the value of the "last_push" field in the mutation.
-func (m *MachineMutation) LastPush() (r time.Time, exists bool) {
-	v := m.last_push
-	if v == nil {
-		return
+// ClearedEdges returns all edge names that were cleared in this mutation.
+func (m *EventMutation) ClearedEdges() []string {
+	edges := make([]string, 0, 1)
+	if m.clearedowner {
+		edges = append(edges, event.EdgeOwner)
 	}
-	return *v, true
+	return edges
 }
 
-// OldLastPush returns the old "last_push" field's value of the Machine entity.
-// If the Machine object wasn't provided to the builder, the object is fetched from the database.
-// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *MachineMutation) OldLastPush(ctx context.Context) (v *time.Time, err error) {
-	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldLastPush is only allowed on UpdateOne operations")
-	}
-	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldLastPush requires an ID field in the mutation")
-	}
-	oldValue, err := m.oldValue(ctx)
-	if err != nil {
-		return v, fmt.Errorf("querying old value for OldLastPush: %w", err)
+// EdgeCleared returns a boolean which indicates if the edge with the given name
+// was cleared in this mutation.
+func (m *EventMutation) EdgeCleared(name string) bool {
+	switch name {
+	case event.EdgeOwner:
+		return m.clearedowner
 	}
-	return oldValue.LastPush, nil
+	return false
 }
 
-// ClearLastPush clears the value of the "last_push" field.
-func (m *MachineMutation) ClearLastPush() {
-	m.last_push = nil
-	m.clearedFields[machine.FieldLastPush] = struct{}{}
+// ClearEdge clears the value of the edge with the given name. It returns an error
+// if that edge is not defined in the schema.
+func (m *EventMutation) ClearEdge(name string) error {
+	switch name {
+	case event.EdgeOwner:
+		m.ClearOwner()
+		return nil
+	}
+	return fmt.Errorf("unknown Event unique edge %s", name)
 }
 
-// LastPushCleared returns if the "last_push" field was cleared in this mutation.
-func (m *MachineMutation) LastPushCleared() bool {
-	_, ok := m.clearedFields[machine.FieldLastPush]
-	return ok
+// ResetEdge resets all changes to the edge with the given name in this mutation.
+// It returns an error if the edge is not defined in the schema.
+func (m *EventMutation) ResetEdge(name string) error {
+	switch name {
+	case event.EdgeOwner:
+		m.ResetOwner()
+		return nil
+	}
+	return fmt.Errorf("unknown Event edge %s", name)
 }
 
-// ResetLastPush resets all changes to the "last_push" field.
-func (m *MachineMutation) ResetLastPush() {
-	m.last_push = nil
-	delete(m.clearedFields, machine.FieldLastPush)
+// LockMutation represents an operation that mutates the Lock nodes in the graph.
+type LockMutation struct {
+	config
+	op            Op
+	typ           string
+	id            *int
+	name          *string
+	created_at    *time.Time
+	owner         *string
+	expires_at    *time.Time
+	clearedFields map[string]struct{}
+	done          bool
+	oldValue      func(context.Context) (*Lock, error)
+	predicates    []predicate.Lock
 }
 
-// SetLastHeartbeat sets the "last_heartbeat" field.
-func (m *MachineMutation) SetLastHeartbeat(t time.Time) {
-	m.last_heartbeat = &t
-}
+var _ ent.Mutation = (*LockMutation)(nil)
 
-// LastHeartbeat returns the value of the "last_heartbeat" field in the mutation.
-func (m *MachineMutation) LastHeartbeat() (r time.Time, exists bool) {
-	v := m.last_heartbeat
-	if v == nil {
-		return
-	}
-	return *v, true
-}
+// lockOption allows management of the mutation configuration using functional options.
+type lockOption func(*LockMutation)
 
-// OldLastHeartbeat returns the old "last_heartbeat" field's value of the Machine entity.
-// If the Machine object wasn't provided to the builder, the object is fetched from the database.
-// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *MachineMutation) OldLastHeartbeat(ctx context.Context) (v *time.Time, err error) {
-	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldLastHeartbeat is only allowed on UpdateOne operations")
-	}
-	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldLastHeartbeat requires an ID field in the mutation")
+// newLockMutation creates new mutation for the Lock entity.
+func newLockMutation(c config, op Op, opts ...lockOption) *LockMutation {
+	m := &LockMutation{
+		config:        c,
+		op:            op,
+		typ:           TypeLock,
+		clearedFields: make(map[string]struct{}),
 	}
-	oldValue, err := m.oldValue(ctx)
-	if err != nil {
-		return v, fmt.Errorf("querying old value for OldLastHeartbeat: %w", err)
+	for _, opt := range opts {
+		opt(m)
 	}
-	return oldValue.LastHeartbeat, nil
+	return m
 }
 
-// ClearLastHeartbeat clears the value of the "last_heartbeat" field.
-func (m *MachineMutation) ClearLastHeartbeat() {
-	m.last_heartbeat = nil
-	m.clearedFields[machine.FieldLastHeartbeat] = struct{}{}
+// withLockID sets the ID field of the mutation.
+func withLockID(id int) lockOption {
+	return func(m *LockMutation) {
+		var (
+			err   error
+			once  sync.Once
+			value *Lock
+		)
+		m.oldValue = func(ctx context.Context) (*Lock, error) {
+			once.Do(func() {
+				if m.done {
+					err = errors.New("querying old values post mutation is not allowed")
+				} else {
+					value, err = m.Client().Lock.Get(ctx, id)
+				}
+			})
+			return value, err
+		}
+		m.id = &id
+	}
 }
 
-// LastHeartbeatCleared returns if the "last_heartbeat" field was cleared in this mutation.
-func (m *MachineMutation) LastHeartbeatCleared() bool {
-	_, ok := m.clearedFields[machine.FieldLastHeartbeat]
-	return ok
+// withLock sets the old Lock of the mutation.
+func withLock(node *Lock) lockOption {
+	return func(m *LockMutation) {
+		m.oldValue = func(context.Context) (*Lock, error) {
+			return node, nil
+		}
+		m.id = &node.ID
+	}
 }
 
-// ResetLastHeartbeat resets all changes to the "last_heartbeat" field.
-func (m *MachineMutation) ResetLastHeartbeat() {
-	m.last_heartbeat = nil
-	delete(m.clearedFields, machine.FieldLastHeartbeat)
+// Client returns a new `ent.Client` from the mutation. If the mutation was
+// executed in a transaction (ent.Tx), a transactional client is returned.
+func (m LockMutation) Client() *Client {
+	client := &Client{config: m.config}
+	client.init()
+	return client
 }
 
-// SetMachineId sets the "machineId" field.
-func (m *MachineMutation) SetMachineId(s string) {
-	m.machineId = &s
+// Tx returns an `ent.Tx` for mutations that were executed in transactions;
+// it returns an error otherwise.
+func (m LockMutation) Tx() (*Tx, error) {
+	if _, ok := m.driver.(*txDriver); !ok {
+		return nil, errors.New("ent: mutation is not running in a transaction")
+	}
+	tx := &Tx{config: m.config}
+	tx.init()
+	return tx, nil
 }
 
-// MachineId returns the value of the "machineId" field in the mutation.
-func (m *MachineMutation) MachineId() (r string, exists bool) {
-	v := m.machineId
-	if v == nil {
+// ID returns the ID value in the mutation. Note that the ID is only available
+// if it was provided to the builder or after it was returned from the database.
+func (m *LockMutation) ID() (id int, exists bool) {
+	if m.id == nil {
+		return
+	}
+	return *m.id, true
+}
+
+// IDs queries the database and returns the entity ids that match the mutation's predicate.
+// That means, if the mutation is applied within a transaction with an isolation level such
+// as sql.LevelSerializable, the returned ids match the ids of the rows that will be updated
+// or updated by the mutation.
+func (m *LockMutation) IDs(ctx context.Context) ([]int, error) {
+	switch {
+	case m.op.Is(OpUpdateOne | OpDeleteOne):
+		id, exists := m.ID()
+		if exists {
+			return []int{id}, nil
+		}
+		fallthrough
+	case m.op.Is(OpUpdate | OpDelete):
+		return m.Client().Lock.Query().Where(m.predicates...).IDs(ctx)
+	default:
+		return nil, fmt.Errorf("IDs is not allowed on %s operations", m.op)
+	}
+}
+
+// SetName sets the "name" field.
+func (m *LockMutation) SetName(s string) {
+	m.name = &s
+}
+
+// Name returns the value of the "name" field in the mutation.
+func (m *LockMutation) Name() (r string, exists bool) {
+	v := m.name
+	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldMachineId returns the old "machineId" field's value of the Machine entity.
-// If the Machine object wasn't provided to the builder, the object is fetched from the database.
+// OldName returns the old "name" field's value of the Lock entity.
+// If the Lock object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *MachineMutation) OldMachineId(ctx context.Context) (v string, err error) {
+func (m *LockMutation) OldName(ctx context.Context) (v string, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldMachineId is only allowed on UpdateOne operations")
+		return v, errors.New("OldName is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldMachineId requires an ID field in the mutation")
+		return v, errors.New("OldName requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldMachineId: %w", err)
+		return v, fmt.Errorf("querying old value for OldName: %w", err)
 	}
-	return oldValue.MachineId, nil
+	return oldValue.Name, nil
 }
 
-// ResetMachineId resets all changes to the "machineId" field.
-func (m *MachineMutation) ResetMachineId() {
-	m.machineId = nil
+// ResetName resets all changes to the "name" field.
+func (m *LockMutation) ResetName() {
+	m.name = nil
 }
 
-// SetPassword sets the "password" field.
-func (m *MachineMutation) SetPassword(s string) {
-	m.password = &s
+// SetCreatedAt sets the "created_at" field.
+func (m *LockMutation) SetCreatedAt(t time.Time) {
+	m.created_at = &t
 }
 
-// Password returns the value of the "password" field in the mutation.
-func (m *MachineMutation) Password() (r string, exists bool) {
-	v := m.password
+// CreatedAt returns the value of the "created_at" field in the mutation.
+func (m *LockMutation) CreatedAt() (r time.Time, exists bool) {
+	v := m.created_at
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldPassword returns the old "password" field's value of the Machine entity.
-// If the Machine object wasn't provided to the builder, the object is fetched from the database.
+// OldCreatedAt returns the old "created_at" field's value of the Lock entity.
+// If the Lock object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *MachineMutation) OldPassword(ctx context.Context) (v string, err error) {
+func (m *LockMutation) OldCreatedAt(ctx context.Context) (v time.Time, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldPassword is only allowed on UpdateOne operations")
+		return v, errors.New("OldCreatedAt is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldPassword requires an ID field in the mutation")
+		return v, errors.New("OldCreatedAt requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldPassword: %w", err)
+		return v, fmt.Errorf("querying old value for OldCreatedAt: %w", err)
 	}
-	return oldValue.Password, nil
+	return oldValue.CreatedAt, nil
 }
 
-// ResetPassword resets all changes to the "password" field.
-func (m *MachineMutation) ResetPassword() {
-	m.password = nil
+// ResetCreatedAt resets all changes to the "created_at" field.
+func (m *LockMutation) ResetCreatedAt() {
+	m.created_at = nil
 }
 
-// SetIpAddress sets the "ipAddress" field.
-func (m *MachineMutation) SetIpAddress(s string) {
-	m.ipAddress = &s
+// SetOwner sets the "owner" field.
+func (m *LockMutation) SetOwner(s string) {
+	m.owner = &s
 }
 
-// IpAddress returns the value of the "ipAddress" field in the mutation.
-func (m *MachineMutation) IpAddress() (r string, exists bool) {
-	v := m.ipAddress
+// Owner returns the value of the "owner" field in the mutation.
+func (m *LockMutation) Owner() (r string, exists bool) {
+	v := m.owner
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldIpAddress returns the old "ipAddress" field's value of the Machine entity.
-// If the Machine object wasn't provided to the builder, the object is fetched from the database.
+// OldOwner returns the old "owner" field's value of the Lock entity.
+// If the Lock object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *MachineMutation) OldIpAddress(ctx context.Context) (v string, err error) {
+func (m *LockMutation) OldOwner(ctx context.Context) (v string, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldIpAddress is only allowed on UpdateOne operations")
+		return v, errors.New("OldOwner is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldIpAddress requires an ID field in the mutation")
+		return v, errors.New("OldOwner requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldIpAddress: %w", err)
+		return v, fmt.Errorf("querying old value for OldOwner: %w", err)
 	}
-	return oldValue.IpAddress, nil
+	return oldValue.Owner, nil
 }
 
-// ResetIpAddress resets all changes to the "ipAddress" field.
-func (m *MachineMutation) ResetIpAddress() {
-	m.ipAddress = nil
+// ClearOwner clears the value of the "owner" field.
+func (m *LockMutation) ClearOwner() {
+	m.owner = nil
+	m.clearedFields[lock.FieldOwner] = struct{}{}
 }
 
-// SetScenarios sets the "scenarios" field.
-func (m *MachineMutation) SetScenarios(s string) {
-	m.scenarios = &s
+// OwnerCleared returns if the "owner" field was cleared in this mutation.
+func (m *LockMutation) OwnerCleared() bool {
+	_, ok := m.clearedFields[lock.FieldOwner]
+	return ok
 }
 
-// Scenarios returns the value of the "scenarios" field in the mutation.
-func (m *MachineMutation) Scenarios() (r string, exists bool) {
-	v := m.scenarios
+// ResetOwner resets all changes to the "owner" field.
+func (m *LockMutation) ResetOwner() {
+	m.owner = nil
+	delete(m.clearedFields, lock.FieldOwner)
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (m *LockMutation) SetExpiresAt(t time.Time) {
+	m.expires_at = &t
+}
+
+// ExpiresAt returns the value of the "expires_at" field in the mutation.
+func (m *LockMutation) ExpiresAt() (r time.Time, exists bool) {
+	v := m.expires_at
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldScenarios returns the old "scenarios" field's value of the Machine entity.
-// If the Machine object wasn't provided to the builder, the object is fetched from the database.
+// OldExpiresAt returns the old "expires_at" field's value of the Lock entity.
+// If the Lock object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *MachineMutation) OldScenarios(ctx context.Context) (v string, err error) {
+func (m *LockMutation) OldExpiresAt(ctx context.Context) (v *time.Time, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldScenarios is only allowed on UpdateOne operations")
+		return v, errors.New("OldExpiresAt is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldScenarios requires an ID field in the mutation")
+		return v, errors.New("OldExpiresAt requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldScenarios: %w", err)
+		return v, fmt.Errorf("querying old value for OldExpiresAt: %w", err)
 	}
-	return oldValue.Scenarios, nil
+	return oldValue.ExpiresAt, nil
 }
 
-// ClearScenarios clears the value of the "scenarios" field.
-func (m *MachineMutation) ClearScenarios() {
-	m.scenarios = nil
-	m.clearedFields[machine.FieldScenarios] = struct{}{}
+// ClearExpiresAt clears the value of the "expires_at" field.
+func (m *LockMutation) ClearExpiresAt() {
+	m.expires_at = nil
+	m.clearedFields[lock.FieldExpiresAt] = struct{}{}
 }
 
-// ScenariosCleared returns if the "scenarios" field was cleared in this mutation.
-func (m *MachineMutation) ScenariosCleared() bool {
-	_, ok := m.clearedFields[machine.FieldScenarios]
+// ExpiresAtCleared returns if the "expires_at" field was cleared in this mutation.
+func (m *LockMutation) ExpiresAtCleared() bool {
+	_, ok := m.clearedFields[lock.FieldExpiresAt]
 	return ok
 }
 
-// ResetScenarios resets all changes to the "scenarios" field.
-func (m *MachineMutation) ResetScenarios() {
-	m.scenarios = nil
-	delete(m.clearedFields, machine.FieldScenarios)
+// ResetExpiresAt resets all changes to the "expires_at" field.
+func (m *LockMutation) ResetExpiresAt() {
+	m.expires_at = nil
+	delete(m.clearedFields, lock.FieldExpiresAt)
+}
+
+// Where appends a list predicates to the LockMutation builder.
+func (m *LockMutation) Where(ps ...predicate.Lock) {
+	m.predicates = append(m.predicates, ps...)
+}
+
+// WhereP appends storage-level predicates to the LockMutation builder. Using this method,
+// users can use type-assertion to append predicates that do not depend on any generated package.
+func (m *LockMutation) WhereP(ps ...func(*sql.Selector)) {
+	p := make([]predicate.Lock, len(ps))
+	for i := range ps {
+		p[i] = ps[i]
+	}
+	m.Where(p...)
+}
+
+// Op returns the operation name.
+func (m *LockMutation) Op() Op {
+	return m.op
+}
+
+// SetOp allows setting the mutation operation.
+func (m *LockMutation) SetOp(op Op) {
+	m.op = op
+}
+
+// Type returns the node type of this mutation (Lock).
+func (m *LockMutation) Type() string {
+	return m.typ
+}
+
+// Fields returns all fields that were changed during this mutation. Note that in
+// order to get all numeric fields that were incremented/decremented, call
+// AddedFields().
+func (m *LockMutation) Fields() []string {
+	fields := make([]string, 0, 4)
+	if m.name != nil {
+		fields = append(fields, lock.FieldName)
+	}
+	if m.created_at != nil {
+		fields = append(fields, lock.FieldCreatedAt)
+	}
+	if m.owner != nil {
+		fields = append(fields, lock.FieldOwner)
+	}
+	if m.expires_at != nil {
+		fields = append(fields, lock.FieldExpiresAt)
+	}
+	return fields
+}
+
+// Field returns the value of a field with the given name. The second boolean
+// return value indicates that this field was not set, or was not defined in the
+// schema.
+func (m *LockMutation) Field(name string) (ent.Value, bool) {
+	switch name {
+	case lock.FieldName:
+		return m.Name()
+	case lock.FieldCreatedAt:
+		return m.CreatedAt()
+	case lock.FieldOwner:
+		return m.Owner()
+	case lock.FieldExpiresAt:
+		return m.ExpiresAt()
+	}
+	return nil, false
+}
+
+// OldField returns the old value of the field from the database. An error is
+// returned if the mutation operation is not UpdateOne, or the query to the
+// database failed.
+func (m *LockMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
+	switch name {
+	case lock.FieldName:
+		return m.OldName(ctx)
+	case lock.FieldCreatedAt:
+		return m.OldCreatedAt(ctx)
+	case lock.FieldOwner:
+		return m.OldOwner(ctx)
+	case lock.FieldExpiresAt:
+		return m.OldExpiresAt(ctx)
+	}
+	return nil, fmt.Errorf("unknown Lock field %s", name)
+}
+
+// SetField sets the value of a field with the given name. It returns an error if
+// the field is not defined in the schema, or if the type mismatched the field
+// type.
+func (m *LockMutation) SetField(name string, value ent.Value) error {
+	switch name {
+	case lock.FieldName:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetName(v)
+		return nil
+	case lock.FieldCreatedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetCreatedAt(v)
+		return nil
+	case lock.FieldOwner:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetOwner(v)
+		return nil
+	case lock.FieldExpiresAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetExpiresAt(v)
+		return nil
+	}
+	return fmt.Errorf("unknown Lock field %s", name)
+}
+
+// AddedFields returns all numeric fields that were incremented/decremented during
+// this mutation.
+func (m *LockMutation) AddedFields() []string {
+	return nil
+}
+
+// AddedField returns the numeric value that was incremented/decremented on a field
+// with the given name. The second boolean return value indicates that this field
+// was not set, or was not defined in the schema.
+func (m *LockMutation) AddedField(name string) (ent.Value, bool) {
+	return nil, false
+}
+
+// AddField adds the value to the field with the given name. It returns an error if
+// the field is not defined in the schema, or if the type mismatched the field
+// type.
+func (m *LockMutation) AddField(name string, value ent.Value) error {
+	switch name {
+	}
+	return fmt.Errorf("unknown Lock numeric field %s", name)
+}
+
+// ClearedFields returns all nullable fields that were cleared during this
+// mutation.
+func (m *LockMutation) ClearedFields() []string {
+	var fields []string
+	if m.FieldCleared(lock.FieldOwner) {
+		fields = append(fields, lock.FieldOwner)
+	}
+	if m.FieldCleared(lock.FieldExpiresAt) {
+		fields = append(fields, lock.FieldExpiresAt)
+	}
+	return fields
+}
+
+// FieldCleared returns a boolean indicating if a field with the given name was
+// cleared in this mutation.
+func (m *LockMutation) FieldCleared(name string) bool {
+	_, ok := m.clearedFields[name]
+	return ok
+}
+
+// ClearField clears the value of the field with the given name. It returns an
+// error if the field is not defined in the schema.
+func (m *LockMutation) ClearField(name string) error {
+	switch name {
+	case lock.FieldOwner:
+		m.ClearOwner()
+		return nil
+	case lock.FieldExpiresAt:
+		m.ClearExpiresAt()
+		return nil
+	}
+	return fmt.Errorf("unknown Lock nullable field %s", name)
+}
+
+// ResetField resets all changes in the mutation for the field with the given name.
+// It returns an error if the field is not defined in the schema.
+func (m *LockMutation) ResetField(name string) error {
+	switch name {
+	case lock.FieldName:
+		m.ResetName()
+		return nil
+	case lock.FieldCreatedAt:
+		m.ResetCreatedAt()
+		return nil
+	case lock.FieldOwner:
+		m.ResetOwner()
+		return nil
+	case lock.FieldExpiresAt:
+		m.ResetExpiresAt()
+		return nil
+	}
+	return fmt.Errorf("unknown Lock field %s", name)
+}
+
+// AddedEdges returns all edge names that were set/added in this mutation.
+func (m *LockMutation) AddedEdges() []string {
+	edges := make([]string, 0, 0)
+	return edges
+}
+
+// AddedIDs returns all IDs (to other nodes) that were added for the given edge
+// name in this mutation.
+func (m *LockMutation) AddedIDs(name string) []ent.Value {
+	return nil
+}
+
+// RemovedEdges returns all edge names that were removed in this mutation.
+func (m *LockMutation) RemovedEdges() []string {
+	edges := make([]string, 0, 0)
+	return edges
+}
+
+// RemovedIDs returns all IDs (to other nodes) that were removed for the edge with
+// the given name in this mutation.
+func (m *LockMutation) RemovedIDs(name string) []ent.Value {
+	return nil
+}
+
+// ClearedEdges returns all edge names that were cleared in this mutation.
+func (m *LockMutation) ClearedEdges() []string {
+	edges := make([]string, 0, 0)
+	return edges
+}
+
+// EdgeCleared returns a boolean which indicates if the edge with the given name
+// was cleared in this mutation.
+func (m *LockMutation) EdgeCleared(name string) bool {
+	return false
+}
+
+// ClearEdge clears the value of the edge with the given name. It returns an error
+// if that edge is not defined in the schema.
+func (m *LockMutation) ClearEdge(name string) error {
+	return fmt.Errorf("unknown Lock unique edge %s", name)
+}
+
+// ResetEdge resets all changes to the edge with the given name in this mutation.
+// It returns an error if the edge is not defined in the schema.
+func (m *LockMutation) ResetEdge(name string) error {
+	return fmt.Errorf("unknown Lock edge %s", name)
+}
+
+// MachineMutation represents an operation that mutates the Machine nodes in the graph.
+type MachineMutation struct {
+	config
+	op             Op
+	typ            string
+	id             *int
+	created_at     *time.Time
+	updated_at     *time.Time
+	last_push      *time.Time
+	last_heartbeat *time.Time
+	machineId      *string
+	password       *string
+	ipAddress      *string
+	scenarios      *string
+	version        *string
+	isValidated    *bool
+	auth_type      *string
+	osname         *string
+	osversion      *string
+	featureflags   *string
+	hubstate       *map[string][]schema.ItemState
+	datasources    *map[string]int64
+	clearedFields  map[string]struct{}
+	alerts         map[int]struct{}
+	removedalerts  map[int]struct{}
+	clearedalerts  bool
+	done           bool
+	oldValue       func(context.Context) (*Machine, error)
+	predicates     []predicate.Machine
+}
+
+var _ ent.Mutation = (*MachineMutation)(nil)
+
+// machineOption allows management of the mutation configuration using functional options.
+type machineOption func(*MachineMutation)
+
+// newMachineMutation creates new mutation for the Machine entity.
+func newMachineMutation(c config, op Op, opts ...machineOption) *MachineMutation {
+	m := &MachineMutation{
+		config:        c,
+		op:            op,
+		typ:           TypeMachine,
+		clearedFields: make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// withMachineID sets the ID field of the mutation.
+func withMachineID(id int) machineOption {
+	return func(m *MachineMutation) {
+		var (
+			err   error
+			once  sync.Once
+			value *Machine
+		)
+		m.oldValue = func(ctx context.Context) (*Machine, error) {
+			once.Do(func() {
+				if m.done {
+					err = errors.New("querying old values post mutation is not allowed")
+				} else {
+					value, err = m.Client().Machine.Get(ctx, id)
+				}
+			})
+			return value, err
+		}
+		m.id = &id
+	}
+}
+
+// withMachine sets the old Machine of the mutation.
+func withMachine(node *Machine) machineOption {
+	return func(m *MachineMutation) {
+		m.oldValue = func(context.Context) (*Machine, error) {
+			return node, nil
+		}
+		m.id = &node.ID
+	}
+}
+
+// Client returns a new `ent.Client` from the mutation. If the mutation was
+// executed in a transaction (ent.Tx), a transactional client is returned.
+func (m MachineMutation) Client() *Client {
+	client := &Client{config: m.config}
+	client.init()
+	return client
+}
+
+// Tx returns an `ent.Tx` for mutations that were executed in transactions;
+// it returns an error otherwise.
+func (m MachineMutation) Tx() (*Tx, error) {
+	if _, ok := m.driver.(*txDriver); !ok {
+		return nil, errors.New("ent: mutation is not running in a transaction")
+	}
+	tx := &Tx{config: m.config}
+	tx.init()
+	return tx, nil
+}
+
+// ID returns the ID value in the mutation. Note that the ID is only available
+// if it was provided to the builder or after it was returned from the database.
+func (m *MachineMutation) ID() (id int, exists bool) {
+	if m.id == nil {
+		return
+	}
+	return *m.id, true
+}
+
+// IDs queries the database and returns the entity ids that match the mutation's predicate.
+// That means, if the mutation is applied within a transaction with an isolation level such
+// as sql.LevelSerializable, the returned ids match the ids of the rows that will be updated
+// or updated by the mutation.
+func (m *MachineMutation) IDs(ctx context.Context) ([]int, error) {
+	switch {
+	case m.op.Is(OpUpdateOne | OpDeleteOne):
+		id, exists := m.ID()
+		if exists {
+			return []int{id}, nil
+		}
+		fallthrough
+	case m.op.Is(OpUpdate | OpDelete):
+		return m.Client().Machine.Query().Where(m.predicates...).IDs(ctx)
+	default:
+		return nil, fmt.Errorf("IDs is not allowed on %s operations", m.op)
+	}
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (m *MachineMutation) SetCreatedAt(t time.Time) {
+	m.created_at = &t
+}
+
+// CreatedAt returns the value of the "created_at" field in the mutation.
+func (m *MachineMutation) CreatedAt() (r time.Time, exists bool) {
+	v := m.created_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldCreatedAt returns the old "created_at" field's value of the Machine entity.
+// If the Machine object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MachineMutation) OldCreatedAt(ctx context.Context) (v time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldCreatedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldCreatedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldCreatedAt: %w", err)
+	}
+	return oldValue.CreatedAt, nil
+}
+
+// ResetCreatedAt resets all changes to the "created_at" field.
+func (m *MachineMutation) ResetCreatedAt() {
+	m.created_at = nil
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (m *MachineMutation) SetUpdatedAt(t time.Time) {
+	m.updated_at = &t
+}
+
+// UpdatedAt returns the value of the "updated_at" field in the mutation.
+func (m *MachineMutation) UpdatedAt() (r time.Time, exists bool) {
+	v := m.updated_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldUpdatedAt returns the old "updated_at" field's value of the Machine entity.
+// If the Machine object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MachineMutation) OldUpdatedAt(ctx context.Context) (v time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldUpdatedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldUpdatedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldUpdatedAt: %w", err)
+	}
+	return oldValue.UpdatedAt, nil
+}
+
+// ResetUpdatedAt resets all changes to the "updated_at" field.
+func (m *MachineMutation) ResetUpdatedAt() {
+	m.updated_at = nil
+}
+
+// SetLastPush sets the "last_push" field.
+func (m *MachineMutation) SetLastPush(t time.Time) {
+	m.last_push = &t
+}
+
+// LastPush returns the value of the "last_push" field in the mutation.
+func (m *MachineMutation) LastPush() (r time.Time, exists bool) {
+	v := m.last_push
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldLastPush returns the old "last_push" field's value of the Machine entity.
+// If the Machine object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MachineMutation) OldLastPush(ctx context.Context) (v *time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldLastPush is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldLastPush requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldLastPush: %w", err)
+	}
+	return oldValue.LastPush, nil
+}
+
+// ClearLastPush clears the value of the "last_push" field.
+func (m *MachineMutation) ClearLastPush() {
+	m.last_push = nil
+	m.clearedFields[machine.FieldLastPush] = struct{}{}
+}
+
+// LastPushCleared returns if the "last_push" field was cleared in this mutation.
+func (m *MachineMutation) LastPushCleared() bool {
+	_, ok := m.clearedFields[machine.FieldLastPush]
+	return ok
+}
+
+// ResetLastPush resets all changes to the "last_push" field.
+func (m *MachineMutation) ResetLastPush() {
+	m.last_push = nil
+	delete(m.clearedFields, machine.FieldLastPush)
+}
+
+// SetLastHeartbeat sets the "last_heartbeat" field.
+func (m *MachineMutation) SetLastHeartbeat(t time.Time) {
+	m.last_heartbeat = &t
+}
+
+// LastHeartbeat returns the value of the "last_heartbeat" field in the mutation.
+func (m *MachineMutation) LastHeartbeat() (r time.Time, exists bool) {
+	v := m.last_heartbeat
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldLastHeartbeat returns the old "last_heartbeat" field's value of the Machine entity.
+// If the Machine object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MachineMutation) OldLastHeartbeat(ctx context.Context) (v *time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldLastHeartbeat is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldLastHeartbeat requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldLastHeartbeat: %w", err)
+	}
+	return oldValue.LastHeartbeat, nil
+}
+
+// ClearLastHeartbeat clears the value of the "last_heartbeat" field.
+func (m *MachineMutation) ClearLastHeartbeat() {
+	m.last_heartbeat = nil
+	m.clearedFields[machine.FieldLastHeartbeat] = struct{}{}
+}
+
+// LastHeartbeatCleared returns if the "last_heartbeat" field was cleared in this mutation.
+func (m *MachineMutation) LastHeartbeatCleared() bool {
+	_, ok := m.clearedFields[machine.FieldLastHeartbeat]
+	return ok
+}
+
+// ResetLastHeartbeat resets all changes to the "last_heartbeat" field.
+func (m *MachineMutation) ResetLastHeartbeat() {
+	m.last_heartbeat = nil
+	delete(m.clearedFields, machine.FieldLastHeartbeat)
+}
+
+// SetMachineId sets the "machineId" field.
+func (m *MachineMutation) SetMachineId(s string) {
+	m.machineId = &s
+}
+
+// MachineId returns the value of the "machineId" field in the mutation.
+func (m *MachineMutation) MachineId() (r string, exists bool) {
+	v := m.machineId
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldMachineId returns the old "machineId" field's value of the Machine entity.
+// If the Machine object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MachineMutation) OldMachineId(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldMachineId is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldMachineId requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldMachineId: %w", err)
+	}
+	return oldValue.MachineId, nil
+}
+
+// ResetMachineId resets all changes to the "machineId" field.
+func (m *MachineMutation) ResetMachineId() {
+	m.machineId = nil
+}
+
+// SetPassword sets the "password" field.
+func (m *MachineMutation) SetPassword(s string) {
+	m.password = &s
+}
+
+// Password returns the value of the "password" field in the mutation.
+func (m *MachineMutation) Password() (r string, exists bool) {
+	v := m.password
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldPassword returns the old "password" field's value of the Machine entity.
+// If the Machine object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MachineMutation) OldPassword(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldPassword is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldPassword requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldPassword: %w", err)
+	}
+	return oldValue.Password, nil
+}
+
+// ResetPassword resets all changes to the "password" field.
+func (m *MachineMutation) ResetPassword() {
+	m.password = nil
+}
+
+// SetIpAddress sets the "ipAddress" field.
+func (m *MachineMutation) SetIpAddress(s string) {
+	m.ipAddress = &s
+}
+
+// IpAddress returns the value of the "ipAddress" field in the mutation.
+func (m *MachineMutation) IpAddress() (r string, exists bool) {
+	v := m.ipAddress
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldIpAddress returns the old "ipAddress" field's value of the Machine entity.
+// If the Machine object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MachineMutation) OldIpAddress(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldIpAddress is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldIpAddress requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldIpAddress: %w", err)
+	}
+	return oldValue.IpAddress, nil
+}
+
+// ResetIpAddress resets all changes to the "ipAddress" field.
+func (m *MachineMutation) ResetIpAddress() {
+	m.ipAddress = nil
+}
+
+// SetScenarios sets the "scenarios" field.
+func (m *MachineMutation) SetScenarios(s string) {
+	m.scenarios = &s
+}
+
+// Scenarios returns the value of the "scenarios" field in the mutation.
+func (m *MachineMutation) Scenarios() (r string, exists bool) {
+	v := m.scenarios
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldScenarios returns the old "scenarios" field's value of the Machine entity.
+// If the Machine object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MachineMutation) OldScenarios(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldScenarios is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldScenarios requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldScenarios: %w", err)
+	}
+	return oldValue.Scenarios, nil
+}
+
+// ClearScenarios clears the value of the "scenarios" field.
+func (m *MachineMutation) ClearScenarios() {
+	m.scenarios = nil
+	m.clearedFields[machine.FieldScenarios] = struct{}{}
+}
+
+// ScenariosCleared returns if the "scenarios" field was cleared in this mutation.
+func (m *MachineMutation) ScenariosCleared() bool {
+	_, ok := m.clearedFields[machine.FieldScenarios]
+	return ok
+}
+
+// ResetScenarios resets all changes to the "scenarios" field.
+func (m *MachineMutation) ResetScenarios() {
+	m.scenarios = nil
+	delete(m.clearedFields, machine.FieldScenarios)
+}
+
+// SetVersion sets the "version" field.
+func (m *MachineMutation) SetVersion(s string) {
+	m.version = &s
+}
+
+// Version returns the value of the "version" field in the mutation.
+func (m *MachineMutation) Version() (r string, exists bool) {
+	v := m.version
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldVersion returns the old "version" field's value of the Machine entity.
+// If the Machine object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MachineMutation) OldVersion(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldVersion is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldVersion requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldVersion: %w", err)
+	}
+	return oldValue.Version, nil
+}
+
+// ClearVersion clears the value of the "version" field.
+func (m *MachineMutation) ClearVersion() {
+	m.version = nil
+	m.clearedFields[machine.FieldVersion] = struct{}{}
+}
+
+// VersionCleared returns if the "version" field was cleared in this mutation.
+func (m *MachineMutation) VersionCleared() bool {
+	_, ok := m.clearedFields[machine.FieldVersion]
+	return ok
+}
+
+// ResetVersion resets all changes to the "version" field.
+func (m *MachineMutation) ResetVersion() {
+	m.version = nil
+	delete(m.clearedFields, machine.FieldVersion)
+}
+
+// SetIsValidated sets the "isValidated" field.
+func (m *MachineMutation) SetIsValidated(b bool) {
+	m.isValidated = &b
+}
+
+// IsValidated returns the value of the "isValidated" field in the mutation.
+func (m *MachineMutation) IsValidated() (r bool, exists bool) {
+	v := m.isValidated
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldIsValidated returns the old "isValidated" field's value of the Machine entity.
+// If the Machine object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MachineMutation) OldIsValidated(ctx context.Context) (v bool, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldIsValidated is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldIsValidated requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldIsValidated: %w", err)
+	}
+	return oldValue.IsValidated, nil
+}
+
+// ResetIsValidated resets all changes to the "isValidated" field.
+func (m *MachineMutation) ResetIsValidated() {
+	m.isValidated = nil
+}
+
+// SetAuthType sets the "auth_type" field.
+func (m *MachineMutation) SetAuthType(s string) {
+	m.auth_type = &s
+}
+
+// AuthType returns the value of the "auth_type" field in the mutation.
+func (m *MachineMutation) AuthType() (r string, exists bool) {
+	v := m.auth_type
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldAuthType returns the old "auth_type" field's value of the Machine entity.
+// If the Machine object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MachineMutation) OldAuthType(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldAuthType is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldAuthType requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldAuthType: %w", err)
+	}
+	return oldValue.AuthType, nil
+}
+
+// ResetAuthType resets all changes to the "auth_type" field.
+func (m *MachineMutation) ResetAuthType() {
+	m.auth_type = nil
+}
+
+// SetOsname sets the "osname" field.
+func (m *MachineMutation) SetOsname(s string) {
+	m.osname = &s
+}
+
+// Osname returns the value of the "osname" field in the mutation.
+func (m *MachineMutation) Osname() (r string, exists bool) {
+	v := m.osname
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldOsname returns the old "osname" field's value of the Machine entity.
+// If the Machine object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MachineMutation) OldOsname(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldOsname is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldOsname requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldOsname: %w", err)
+	}
+	return oldValue.Osname, nil
+}
+
+// ClearOsname clears the value of the "osname" field.
+func (m *MachineMutation) ClearOsname() {
+	m.osname = nil
+	m.clearedFields[machine.FieldOsname] = struct{}{}
+}
+
+// OsnameCleared returns if the "osname" field was cleared in this mutation.
+func (m *MachineMutation) OsnameCleared() bool {
+	_, ok := m.clearedFields[machine.FieldOsname]
+	return ok
+}
+
+// ResetOsname resets all changes to the "osname" field.
+func (m *MachineMutation) ResetOsname() {
+	m.osname = nil
+	delete(m.clearedFields, machine.FieldOsname)
+}
+
+// SetOsversion sets the "osversion" field.
+func (m *MachineMutation) SetOsversion(s string) {
+	m.osversion = &s
+}
+
+// Osversion returns the value of the "osversion" field in the mutation.
+func (m *MachineMutation) Osversion() (r string, exists bool) {
+	v := m.osversion
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldOsversion returns the old "osversion" field's value of the Machine entity.
+// If the Machine object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MachineMutation) OldOsversion(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldOsversion is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldOsversion requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldOsversion: %w", err)
+	}
+	return oldValue.Osversion, nil
+}
+
+// ClearOsversion clears the value of the "osversion" field.
+func (m *MachineMutation) ClearOsversion() {
+	m.osversion = nil
+	m.clearedFields[machine.FieldOsversion] = struct{}{}
+}
+
+// OsversionCleared returns if the "osversion" field was cleared in this mutation.
+func (m *MachineMutation) OsversionCleared() bool {
+	_, ok := m.clearedFields[machine.FieldOsversion]
+	return ok
+}
+
+// ResetOsversion resets all changes to the "osversion" field.
+func (m *MachineMutation) ResetOsversion() {
+	m.osversion = nil
+	delete(m.clearedFields, machine.FieldOsversion)
+}
+
+// SetFeatureflags sets the "featureflags" field.
+func (m *MachineMutation) SetFeatureflags(s string) {
+	m.featureflags = &s
+}
+
+// Featureflags returns the value of the "featureflags" field in the mutation.
+func (m *MachineMutation) Featureflags() (r string, exists bool) {
+	v := m.featureflags
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldFeatureflags returns the old "featureflags" field's value of the Machine entity.
+// If the Machine object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MachineMutation) OldFeatureflags(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldFeatureflags is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldFeatureflags requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldFeatureflags: %w", err)
+	}
+	return oldValue.Featureflags, nil
+}
+
+// ClearFeatureflags clears the value of the "featureflags" field.
+func (m *MachineMutation) ClearFeatureflags() {
+	m.featureflags = nil
+	m.clearedFields[machine.FieldFeatureflags] = struct{}{}
+}
+
+// FeatureflagsCleared returns if the "featureflags" field was cleared in this mutation.
+func (m *MachineMutation) FeatureflagsCleared() bool {
+	_, ok := m.clearedFields[machine.FieldFeatureflags]
+	return ok
+}
+
+// ResetFeatureflags resets all changes to the "featureflags" field.
+func (m *MachineMutation) ResetFeatureflags() {
+	m.featureflags = nil
+	delete(m.clearedFields, machine.FieldFeatureflags)
+}
+
+// SetHubstate sets the "hubstate" field.
+func (m *MachineMutation) SetHubstate(ms map[string][]schema.ItemState) {
+	m.hubstate = &ms
+}
+
+// Hubstate returns the value of the "hubstate" field in the mutation.
+func (m *MachineMutation) Hubstate() (r map[string][]schema.ItemState, exists bool) {
+	v := m.hubstate
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldHubstate returns the old "hubstate" field's value of the Machine entity.
+// If the Machine object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MachineMutation) OldHubstate(ctx context.Context) (v map[string][]schema.ItemState, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldHubstate is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldHubstate requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldHubstate: %w", err)
+	}
+	return oldValue.Hubstate, nil
+}
+
+// ClearHubstate clears the value of the "hubstate" field.
+func (m *MachineMutation) ClearHubstate() {
+	m.hubstate = nil
+	m.clearedFields[machine.FieldHubstate] = struct{}{}
+}
+
+// HubstateCleared returns if the "hubstate" field was cleared in this mutation.
+func (m *MachineMutation) HubstateCleared() bool {
+	_, ok := m.clearedFields[machine.FieldHubstate]
+	return ok
+}
+
+// ResetHubstate resets all changes to the "hubstate" field.
+func (m *MachineMutation) ResetHubstate() {
+	m.hubstate = nil
+	delete(m.clearedFields, machine.FieldHubstate)
+}
+
+// SetDatasources sets the "datasources" field.
+func (m *MachineMutation) SetDatasources(value map[string]int64) {
+	m.datasources = &value
+}
+
+// Datasources returns the value of the "datasources" field in the mutation.
+func (m *MachineMutation) Datasources() (r map[string]int64, exists bool) {
+	v := m.datasources
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldDatasources returns the old "datasources" field's value of the Machine entity.
+// If the Machine object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MachineMutation) OldDatasources(ctx context.Context) (v map[string]int64, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldDatasources is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldDatasources requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldDatasources: %w", err)
+	}
+	return oldValue.Datasources, nil
+}
+
+// ClearDatasources clears the value of the "datasources" field.
+func (m *MachineMutation) ClearDatasources() {
+	m.datasources = nil
+	m.clearedFields[machine.FieldDatasources] = struct{}{}
+}
+
+// DatasourcesCleared returns if the "datasources" field was cleared in this mutation.
+func (m *MachineMutation) DatasourcesCleared() bool {
+	_, ok := m.clearedFields[machine.FieldDatasources]
+	return ok
+}
+
+// ResetDatasources resets all changes to the "datasources" field.
+func (m *MachineMutation) ResetDatasources() {
+	m.datasources = nil
+	delete(m.clearedFields, machine.FieldDatasources)
+}
+
+// AddAlertIDs adds the "alerts" edge to the Alert entity by ids.
+func (m *MachineMutation) AddAlertIDs(ids ...int) {
+	if m.alerts == nil {
+		m.alerts = make(map[int]struct{})
+	}
+	for i := range ids {
+		m.alerts[ids[i]] = struct{}{}
+	}
+}
+
+// ClearAlerts clears the "alerts" edge to the Alert entity.
+func (m *MachineMutation) ClearAlerts() {
+	m.clearedalerts = true
+}
+
+// AlertsCleared reports if the "alerts" edge to the Alert entity was cleared.
+func (m *MachineMutation) AlertsCleared() bool {
+	return m.clearedalerts
+}
+
+// RemoveAlertIDs removes the "alerts" edge to the Alert entity by IDs.
+func (m *MachineMutation) RemoveAlertIDs(ids ...int) {
+	if m.removedalerts == nil {
+		m.removedalerts = make(map[int]struct{})
+	}
+	for i := range ids {
+		delete(m.alerts, ids[i])
+		m.removedalerts[ids[i]] = struct{}{}
+	}
+}
+
+// RemovedAlerts returns the removed IDs of the "alerts" edge to the Alert entity.
+func (m *MachineMutation) RemovedAlertsIDs() (ids []int) {
+	for id := range m.removedalerts {
+		ids = append(ids, id)
+	}
+	return
+}
+
+// AlertsIDs returns the "alerts" edge IDs in the mutation.
+func (m *MachineMutation) AlertsIDs() (ids []int) {
+	for id := range m.alerts {
+		ids = append(ids, id)
+	}
+	return
+}
+
+// ResetAlerts resets all changes to the "alerts" edge.
+func (m *MachineMutation) ResetAlerts() {
+	m.alerts = nil
+	m.clearedalerts = false
+	m.removedalerts = nil
+}
+
+// Where appends a list predicates to the MachineMutation builder.
+func (m *MachineMutation) Where(ps ...predicate.Machine) {
+	m.predicates = append(m.predicates, ps...)
+}
+
+// WhereP appends storage-level predicates to the MachineMutation builder. Using this method,
+// users can use type-assertion to append predicates that do not depend on any generated package.
+func (m *MachineMutation) WhereP(ps ...func(*sql.Selector)) {
+	p := make([]predicate.Machine, len(ps))
+	for i := range ps {
+		p[i] = ps[i]
+	}
+	m.Where(p...)
+}
+
+// Op returns the operation name.
+func (m *MachineMutation) Op() Op {
+	return m.op
+}
+
+// SetOp allows setting the mutation operation.
+func (m *MachineMutation) SetOp(op Op) {
+	m.op = op
+}
+
+// Type returns the node type of this mutation (Machine).
+func (m *MachineMutation) Type() string {
+	return m.typ
+}
+
+// Fields returns all fields that were changed during this mutation. Note that in
+// order to get all numeric fields that were incremented/decremented, call
+// AddedFields().
+func (m *MachineMutation) Fields() []string {
+	fields := make([]string, 0, 16)
+	if m.created_at != nil {
+		fields = append(fields, machine.FieldCreatedAt)
+	}
+	if m.updated_at != nil {
+		fields = append(fields, machine.FieldUpdatedAt)
+	}
+	if m.last_push != nil {
+		fields = append(fields, machine.FieldLastPush)
+	}
+	if m.last_heartbeat != nil {
+		fields = append(fields, machine.FieldLastHeartbeat)
+	}
+	if m.machineId != nil {
+		fields = append(fields, machine.FieldMachineId)
+	}
+	if m.password != nil {
+		fields = append(fields, machine.FieldPassword)
+	}
+	if m.ipAddress != nil {
+		fields = append(fields, machine.FieldIpAddress)
+	}
+	if m.scenarios != nil {
+		fields = append(fields, machine.FieldScenarios)
+	}
+	if m.version != nil {
+		fields = append(fields, machine.FieldVersion)
+	}
+	if m.isValidated != nil {
+		fields = append(fields, machine.FieldIsValidated)
+	}
+	if m.auth_type != nil {
+		fields = append(fields, machine.FieldAuthType)
+	}
+	if m.osname != nil {
+		fields = append(fields, machine.FieldOsname)
+	}
+	if m.osversion != nil {
+		fields = append(fields, machine.FieldOsversion)
+	}
+	if m.featureflags != nil {
+		fields = append(fields, machine.FieldFeatureflags)
+	}
+	if m.hubstate != nil {
+		fields = append(fields, machine.FieldHubstate)
+	}
+	if m.datasources != nil {
+		fields = append(fields, machine.FieldDatasources)
+	}
+	return fields
+}
+
+// Field returns the value of a field with the given name. The second boolean
+// return value indicates that this field was not set, or was not defined in the
+// schema.
+func (m *MachineMutation) Field(name string) (ent.Value, bool) {
+	switch name {
+	case machine.FieldCreatedAt:
+		return m.CreatedAt()
+	case machine.FieldUpdatedAt:
+		return m.UpdatedAt()
+	case machine.FieldLastPush:
+		return m.LastPush()
+	case machine.FieldLastHeartbeat:
+		return m.LastHeartbeat()
+	case machine.FieldMachineId:
+		return m.MachineId()
+	case machine.FieldPassword:
+		return m.Password()
+	case machine.FieldIpAddress:
+		return m.IpAddress()
+	case machine.FieldScenarios:
+		return m.Scenarios()
+	case machine.FieldVersion:
+		return m.Version()
+	case machine.FieldIsValidated:
+		return m.IsValidated()
+	case machine.FieldAuthType:
+		return m.AuthType()
+	case machine.FieldOsname:
+		return m.Osname()
+	case machine.FieldOsversion:
+		return m.Osversion()
+	case machine.FieldFeatureflags:
+		return m.Featureflags()
+	case machine.FieldHubstate:
+		return m.Hubstate()
+	case machine.FieldDatasources:
+		return m.Datasources()
+	}
+	return nil, false
+}
+
+// OldField returns the old value of the field from the database. An error is
+// returned if the mutation operation is not UpdateOne, or the query to the
+// database failed.
+func (m *MachineMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
+	switch name {
+	case machine.FieldCreatedAt:
+		return m.OldCreatedAt(ctx)
+	case machine.FieldUpdatedAt:
+		return m.OldUpdatedAt(ctx)
+	case machine.FieldLastPush:
+		return m.OldLastPush(ctx)
+	case machine.FieldLastHeartbeat:
+		return m.OldLastHeartbeat(ctx)
+	case machine.FieldMachineId:
+		return m.OldMachineId(ctx)
+	case machine.FieldPassword:
+		return m.OldPassword(ctx)
+	case machine.FieldIpAddress:
+		return m.OldIpAddress(ctx)
+	case machine.FieldScenarios:
+		return m.OldScenarios(ctx)
+	case machine.FieldVersion:
+		return m.OldVersion(ctx)
+	case machine.FieldIsValidated:
+		return m.OldIsValidated(ctx)
+	case machine.FieldAuthType:
+		return m.OldAuthType(ctx)
+	case machine.FieldOsname:
+		return m.OldOsname(ctx)
+	case machine.FieldOsversion:
+		return m.OldOsversion(ctx)
+	case machine.FieldFeatureflags:
+		return m.OldFeatureflags(ctx)
+	case machine.FieldHubstate:
+		return m.OldHubstate(ctx)
+	case machine.FieldDatasources:
+		return m.OldDatasources(ctx)
+	}
+	return nil, fmt.Errorf("unknown Machine field %s", name)
+}
+
+// SetField sets the value of a field with the given name. It returns an error if
+// the field is not defined in the schema, or if the type mismatched the field
+// type.
+func (m *MachineMutation) SetField(name string, value ent.Value) error {
+	switch name {
+	case machine.FieldCreatedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetCreatedAt(v)
+		return nil
+	case machine.FieldUpdatedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetUpdatedAt(v)
+		return nil
+	case machine.FieldLastPush:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetLastPush(v)
+		return nil
+	case machine.FieldLastHeartbeat:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetLastHeartbeat(v)
+		return nil
+	case machine.FieldMachineId:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetMachineId(v)
+		return nil
+	case machine.FieldPassword:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetPassword(v)
+		return nil
+	case machine.FieldIpAddress:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetIpAddress(v)
+		return nil
+	case machine.FieldScenarios:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetScenarios(v)
+		return nil
+	case machine.FieldVersion:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetVersion(v)
+		return nil
+	case machine.FieldIsValidated:
+		v, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetIsValidated(v)
+		return nil
+	case machine.FieldAuthType:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetAuthType(v)
+		return nil
+	case machine.FieldOsname:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetOsname(v)
+		return nil
+	case machine.FieldOsversion:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetOsversion(v)
+		return nil
+	case machine.FieldFeatureflags:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetFeatureflags(v)
+		return nil
+	case machine.FieldHubstate:
+		v, ok := value.(map[string][]schema.ItemState)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetHubstate(v)
+		return nil
+	case machine.FieldDatasources:
+		v, ok := value.(map[string]int64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetDatasources(v)
+		return nil
+	}
+	return fmt.Errorf("unknown Machine field %s", name)
+}
+
+// AddedFields returns all numeric fields that were incremented/decremented during
+// this mutation.
+func (m *MachineMutation) AddedFields() []string {
+	return nil
+}
+
+// AddedField returns the numeric value that was incremented/decremented on a field
+// with the given name. The second boolean return value indicates that this field
+// was not set, or was not defined in the schema.
+func (m *MachineMutation) AddedField(name string) (ent.Value, bool) {
+	return nil, false
+}
+
+// AddField adds the value to the field with the given name. It returns an error if
+// the field is not defined in the schema, or if the type mismatched the field
+// type.
+func (m *MachineMutation) AddField(name string, value ent.Value) error {
+	switch name {
+	}
+	return fmt.Errorf("unknown Machine numeric field %s", name)
+}
+
+// ClearedFields returns all nullable fields that were cleared during this
+// mutation.
+func (m *MachineMutation) ClearedFields() []string {
+	var fields []string
+	if m.FieldCleared(machine.FieldLastPush) {
+		fields = append(fields, machine.FieldLastPush)
+	}
+	if m.FieldCleared(machine.FieldLastHeartbeat) {
+		fields = append(fields, machine.FieldLastHeartbeat)
+	}
+	if m.FieldCleared(machine.FieldScenarios) {
+		fields = append(fields, machine.FieldScenarios)
+	}
+	if m.FieldCleared(machine.FieldVersion) {
+		fields = append(fields, machine.FieldVersion)
+	}
+	if m.FieldCleared(machine.FieldOsname) {
+		fields = append(fields, machine.FieldOsname)
+	}
+	if m.FieldCleared(machine.FieldOsversion) {
+		fields = append(fields, machine.FieldOsversion)
+	}
+	if m.FieldCleared(machine.FieldFeatureflags) {
+		fields = append(fields, machine.FieldFeatureflags)
+	}
+	if m.FieldCleared(machine.FieldHubstate) {
+		fields = append(fields, machine.FieldHubstate)
+	}
+	if m.FieldCleared(machine.FieldDatasources) {
+		fields = append(fields, machine.FieldDatasources)
+	}
+	return fields
+}
+
+// FieldCleared returns a boolean indicating if a field with the given name was
+// cleared in this mutation.
+func (m *MachineMutation) FieldCleared(name string) bool {
+	_, ok := m.clearedFields[name]
+	return ok
+}
+
+// ClearField clears the value of the field with the given name. It returns an
+// error if the field is not defined in the schema.
+func (m *MachineMutation) ClearField(name string) error {
+	switch name {
+	case machine.FieldLastPush:
+		m.ClearLastPush()
+		return nil
+	case machine.FieldLastHeartbeat:
+		m.ClearLastHeartbeat()
+		return nil
+	case machine.FieldScenarios:
+		m.ClearScenarios()
+		return nil
+	case machine.FieldVersion:
+		m.ClearVersion()
+		return nil
+	case machine.FieldOsname:
+		m.ClearOsname()
+		return nil
+	case machine.FieldOsversion:
+		m.ClearOsversion()
+		return nil
+	case machine.FieldFeatureflags:
+		m.ClearFeatureflags()
+		return nil
+	case machine.FieldHubstate:
+		m.ClearHubstate()
+		return nil
+	case machine.FieldDatasources:
+		m.ClearDatasources()
+		return nil
+	}
+	return fmt.Errorf("unknown Machine nullable field %s", name)
+}
+
+// ResetField resets all changes in the mutation for the field with the given name.
+// It returns an error if the field is not defined in the schema.
+func (m *MachineMutation) ResetField(name string) error {
+	switch name {
+	case machine.FieldCreatedAt:
+		m.ResetCreatedAt()
+		return nil
+	case machine.FieldUpdatedAt:
+		m.ResetUpdatedAt()
+		return nil
+	case machine.FieldLastPush:
+		m.ResetLastPush()
+		return nil
+	case machine.FieldLastHeartbeat:
+		m.ResetLastHeartbeat()
+		return nil
+	case machine.FieldMachineId:
+		m.ResetMachineId()
+		return nil
+	case machine.FieldPassword:
+		m.ResetPassword()
+		return nil
+	case machine.FieldIpAddress:
+		m.ResetIpAddress()
+		return nil
+	case machine.FieldScenarios:
+		m.ResetScenarios()
+		return nil
+	case machine.FieldVersion:
+		m.ResetVersion()
+		return nil
+	case machine.FieldIsValidated:
+		m.ResetIsValidated()
+		return nil
+	case machine.FieldAuthType:
+		m.ResetAuthType()
+		return nil
+	case machine.FieldOsname:
+		m.ResetOsname()
+		return nil
+	case machine.FieldOsversion:
+		m.ResetOsversion()
+		return nil
+	case machine.FieldFeatureflags:
+		m.ResetFeatureflags()
+		return nil
+	case machine.FieldHubstate:
+		m.ResetHubstate()
+		return nil
+	case machine.FieldDatasources:
+		m.ResetDatasources()
+		return nil
+	}
+	return fmt.Errorf("unknown Machine field %s", name)
+}
+
+// AddedEdges returns all edge names that were set/added in this mutation.
+func (m *MachineMutation) AddedEdges() []string {
+	edges := make([]string, 0, 1)
+	if m.alerts != nil {
+		edges = append(edges, machine.EdgeAlerts)
+	}
+	return edges
+}
+
+// AddedIDs returns all IDs (to other nodes) that were added for the given edge
+// name in this mutation.
+func (m *MachineMutation) AddedIDs(name string) []ent.Value {
+	switch name {
+	case machine.EdgeAlerts:
+		ids := make([]ent.Value, 0, len(m.alerts))
+		for id := range m.alerts {
+			ids = append(ids, id)
+		}
+		return ids
+	}
+	return nil
+}
+
+// RemovedEdges returns all edge names that were removed in this mutation.
+func (m *MachineMutation) RemovedEdges() []string {
+	edges := make([]string, 0, 1)
+	if m.removedalerts != nil {
+		edges = append(edges, machine.EdgeAlerts)
+	}
+	return edges
 }
 
-// SetVersion sets the "version" field.
-func (m *MachineMutation) SetVersion(s string) {
-	m.version = &s
+// RemovedIDs returns all IDs (to other nodes) that were removed for the edge with
+// the given name in this mutation.
+func (m *MachineMutation) RemovedIDs(name string) []ent.Value {
+	switch name {
+	case machine.EdgeAlerts:
+		ids := make([]ent.Value, 0, len(m.removedalerts))
+		for id := range m.removedalerts {
+			ids = append(ids, id)
+		}
+		return ids
+	}
+	return nil
 }
 
-// Version returns the value of the "version" field in the mutation.
-func (m *MachineMutation) Version() (r string, exists bool) {
-	v := m.version
-	if v == nil {
-		return
+// ClearedEdges returns all edge names that were cleared in this mutation.
+func (m *MachineMutation) ClearedEdges() []string {
+	edges := make([]string, 0, 1)
+	if m.clearedalerts {
+		edges = append(edges, machine.EdgeAlerts)
 	}
-	return *v, true
+	return edges
 }
 
-// OldVersion returns the old "version" field's value of the Machine entity.
-// If the Machine object wasn't provided to the builder, the object is fetched from the database.
-// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *MachineMutation) OldVersion(ctx context.Context) (v string, err error) {
-	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldVersion is only allowed on UpdateOne operations")
-	}
-	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldVersion requires an ID field in the mutation")
-	}
-	oldValue, err := m.oldValue(ctx)
-	if err != nil {
-		return v, fmt.Errorf("querying old value for OldVersion: %w", err)
+// EdgeCleared returns a boolean which indicates if the edge with the given name
+// was cleared in this mutation.
+func (m *MachineMutation) EdgeCleared(name string) bool {
+	switch name {
+	case machine.EdgeAlerts:
+		return m.clearedalerts
 	}
-	return oldValue.Version, nil
+	return false
 }
 
-// ClearVersion clears the value of the "version" field.
-func (m *MachineMutation) ClearVersion() {
-	m.version = nil
-	m.clearedFields[machine.FieldVersion] = struct{}{}
+// ClearEdge clears the value of the edge with the given name. It returns an error
+// if that edge is not defined in the schema.
+func (m *MachineMutation) ClearEdge(name string) error {
+	switch name {
+	}
+	return fmt.Errorf("unknown Machine unique edge %s", name)
 }
 
-// VersionCleared returns if the "version" field was cleared in this mutation.
-func (m *MachineMutation) VersionCleared() bool {
-	_, ok := m.clearedFields[machine.FieldVersion]
-	return ok
+// ResetEdge resets all changes to the edge with the given name in this mutation.
+// It returns an error if the edge is not defined in the schema.
+func (m *MachineMutation) ResetEdge(name string) error {
+	switch name {
+	case machine.EdgeAlerts:
+		m.ResetAlerts()
+		return nil
+	}
+	return fmt.Errorf("unknown Machine edge %s", name)
 }
 
-// ResetVersion resets all changes to the "version" field.
-func (m *MachineMutation) ResetVersion() {
-	m.version = nil
-	delete(m.clearedFields, machine.FieldVersion)
+// MetaMutation represents an operation that mutates the Meta nodes in the graph.
+type MetaMutation struct {
+	config
+	op            Op
+	typ           string
+	id            *int
+	created_at    *time.Time
+	updated_at    *time.Time
+	key           *string
+	value         *string
+	clearedFields map[string]struct{}
+	owner         *int
+	clearedowner  bool
+	done          bool
+	oldValue      func(context.Context) (*Meta, error)
+	predicates    []predicate.Meta
 }
 
-// SetIsValidated sets the "isValidated" field.
-func (m *MachineMutation) SetIsValidated(b bool) {
-	m.isValidated = &b
-}
+var _ ent.Mutation = (*MetaMutation)(nil)
 
-// IsValidated returns the value of the "isValidated" field in the mutation.
-func (m *MachineMutation) IsValidated() (r bool, exists bool) {
-	v := m.isValidated
-	if v == nil {
-		return
-	}
-	return *v, true
-}
+// metaOption allows management of the mutation configuration using functional options.
+type metaOption func(*MetaMutation)
 
-// OldIsValidated returns the old "isValidated" field's value of the Machine entity.
-// If the Machine object wasn't provided to the builder, the object is fetched from the database.
-// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *MachineMutation) OldIsValidated(ctx context.Context) (v bool, err error) {
-	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldIsValidated is only allowed on UpdateOne operations")
+// newMetaMutation creates new mutation for the Meta entity.
+func newMetaMutation(c config, op Op, opts ...metaOption) *MetaMutation {
+	m := &MetaMutation{
+		config:        c,
+		op:            op,
+		typ:           TypeMeta,
+		clearedFields: make(map[string]struct{}),
 	}
-	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldIsValidated requires an ID field in the mutation")
+	for _, opt := range opts {
+		opt(m)
 	}
-	oldValue, err := m.oldValue(ctx)
-	if err != nil {
-		return v, fmt.Errorf("querying old value for OldIsValidated: %w", err)
+	return m
+}
+
+// withMetaID sets the ID field of the mutation.
+func withMetaID(id int) metaOption {
+	return func(m *MetaMutation) {
+		var (
+			err   error
+			once  sync.Once
+			value *Meta
+		)
+		m.oldValue = func(ctx context.Context) (*Meta, error) {
+			once.Do(func() {
+				if m.done {
+					err = errors.New("querying old values post mutation is not allowed")
+				} else {
+					value, err = m.Client().Meta.Get(ctx, id)
+				}
+			})
+			return value, err
+		}
+		m.id = &id
 	}
-	return oldValue.IsValidated, nil
 }
 
-// ResetIsValidated resets all changes to the "isValidated" field.
-func (m *MachineMutation) ResetIsValidated() {
-	m.isValidated = nil
+// withMeta sets the old Meta of the mutation.
+func withMeta(node *Meta) metaOption {
+	return func(m *MetaMutation) {
+		m.oldValue = func(context.Context) (*Meta, error) {
+			return node, nil
+		}
+		m.id = &node.ID
+	}
 }
 
-// SetAuthType sets the "auth_type" field.
-func (m *MachineMutation) SetAuthType(s string) {
-	m.auth_type = &s
+// Client returns a new `ent.Client` from the mutation. If the mutation was
+// executed in a transaction (ent.Tx), a transactional client is returned.
+func (m MetaMutation) Client() *Client {
+	client := &Client{config: m.config}
+	client.init()
+	return client
 }
 
-// AuthType returns the value of the "auth_type" field in the mutation.
-func (m *MachineMutation) AuthType() (r string, exists bool) {
-	v := m.auth_type
-	if v == nil {
-		return
+// Tx returns an `ent.Tx` for mutations that were executed in transactions;
+// it returns an error otherwise.
+func (m MetaMutation) Tx() (*Tx, error) {
+	if _, ok := m.driver.(*txDriver); !ok {
+		return nil, errors.New("ent: mutation is not running in a transaction")
 	}
-	return *v, true
+	tx := &Tx{config: m.config}
+	tx.init()
+	return tx, nil
 }
 
-// OldAuthType returns the old "auth_type" field's value of the Machine entity.
-// If the Machine object wasn't provided to the builder, the object is fetched from the database.
-// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *MachineMutation) OldAuthType(ctx context.Context) (v string, err error) {
-	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldAuthType is only allowed on UpdateOne operations")
-	}
-	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldAuthType requires an ID field in the mutation")
-	}
-	oldValue, err := m.oldValue(ctx)
-	if err != nil {
-		return v, fmt.Errorf("querying old value for OldAuthType: %w", err)
+// ID returns the ID value in the mutation. Note that the ID is only available
+// if it was provided to the builder or after it was returned from the database.
+func (m *MetaMutation) ID() (id int, exists bool) {
+	if m.id == nil {
+		return
 	}
-	return oldValue.AuthType, nil
+	return *m.id, true
 }
 
-// ResetAuthType resets all changes to the "auth_type" field.
-func (m *MachineMutation) ResetAuthType() {
-	m.auth_type = nil
+// IDs queries the database and returns the entity ids that match the mutation's predicate.
+// That means, if the mutation is applied within a transaction with an isolation level such
+// as sql.LevelSerializable, the returned ids match the ids of the rows that will be updated
+// or updated by the mutation.
+func (m *MetaMutation) IDs(ctx context.Context) ([]int, error) {
+	switch {
+	case m.op.Is(OpUpdateOne | OpDeleteOne):
+		id, exists := m.ID()
+		if exists {
+			return []int{id}, nil
+		}
+		fallthrough
+	case m.op.Is(OpUpdate | OpDelete):
+		return m.Client().Meta.Query().Where(m.predicates...).IDs(ctx)
+	default:
+		return nil, fmt.Errorf("IDs is not allowed on %s operations", m.op)
+	}
 }
 
-// SetOsname sets the "osname" field.
-func (m *MachineMutation) SetOsname(s string) {
-	m.osname = &s
+// SetCreatedAt sets the "created_at" field.
+func (m *MetaMutation) SetCreatedAt(t time.Time) {
+	m.created_at = &t
 }
 
-// Osname returns the value of the "osname" field in the mutation.
-func (m *MachineMutation) Osname() (r string, exists bool) {
-	v := m.osname
+// CreatedAt returns the value of the "created_at" field in the mutation.
+func (m *MetaMutation) CreatedAt() (r time.Time, exists bool) {
+	v := m.created_at
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldOsname returns the old "osname" field's value of the Machine entity.
-// If the Machine object wasn't provided to the builder, the object is fetched from the database.
+// OldCreatedAt returns the old "created_at" field's value of the Meta entity.
+// If the Meta object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *MachineMutation) OldOsname(ctx context.Context) (v string, err error) {
+func (m *MetaMutation) OldCreatedAt(ctx context.Context) (v time.Time, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldOsname is only allowed on UpdateOne operations")
+		return v, errors.New("OldCreatedAt is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldOsname requires an ID field in the mutation")
+		return v, errors.New("OldCreatedAt requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldOsname: %w", err)
+		return v, fmt.Errorf("querying old value for OldCreatedAt: %w", err)
 	}
-	return oldValue.Osname, nil
-}
-
-// ClearOsname clears the value of the "osname" field.
-func (m *MachineMutation) ClearOsname() {
-	m.osname = nil
-	m.clearedFields[machine.FieldOsname] = struct{}{}
-}
-
-// OsnameCleared returns if the "osname" field was cleared in this mutation.
-func (m *MachineMutation) OsnameCleared() bool {
-	_, ok := m.clearedFields[machine.FieldOsname]
-	return ok
+	return oldValue.CreatedAt, nil
 }
 
-// ResetOsname resets all changes to the "osname" field.
-func (m *MachineMutation) ResetOsname() {
-	m.osname = nil
-	delete(m.clearedFields, machine.FieldOsname)
+// ResetCreatedAt resets all changes to the "created_at" field.
+func (m *MetaMutation) ResetCreatedAt() {
+	m.created_at = nil
 }
 
-// SetOsversion sets the "osversion" field.
-func (m *MachineMutation) SetOsversion(s string) {
-	m.osversion = &s
+// SetUpdatedAt sets the "updated_at" field.
+func (m *MetaMutation) SetUpdatedAt(t time.Time) {
+	m.updated_at = &t
 }
 
-// Osversion returns the value of the "osversion" field in the mutation.
-func (m *MachineMutation) Osversion() (r string, exists bool) {
-	v := m.osversion
+// UpdatedAt returns the value of the "updated_at" field in the mutation.
+func (m *MetaMutation) UpdatedAt() (r time.Time, exists bool) {
+	v := m.updated_at
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldOsversion returns the old "osversion" field's value of the Machine entity.
-// If the Machine object wasn't provided to the builder, the object is fetched from the database.
+// OldUpdatedAt returns the old "updated_at" field's value of the Meta entity.
+// If the Meta object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *MachineMutation) OldOsversion(ctx context.Context) (v string, err error) {
+func (m *MetaMutation) OldUpdatedAt(ctx context.Context) (v time.Time, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldOsversion is only allowed on UpdateOne operations")
+		return v, errors.New("OldUpdatedAt is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldOsversion requires an ID field in the mutation")
+		return v, errors.New("OldUpdatedAt requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldOsversion: %w", err)
+		return v, fmt.Errorf("querying old value for OldUpdatedAt: %w", err)
 	}
-	return oldValue.Osversion, nil
-}
-
-// ClearOsversion clears the value of the "osversion" field.
-func (m *MachineMutation) ClearOsversion() {
-	m.osversion = nil
-	m.clearedFields[machine.FieldOsversion] = struct{}{}
-}
-
-// OsversionCleared returns if the "osversion" field was cleared in this mutation.
-func (m *MachineMutation) OsversionCleared() bool {
-	_, ok := m.clearedFields[machine.FieldOsversion]
-	return ok
+	return oldValue.UpdatedAt, nil
 }
 
-// ResetOsversion resets all changes to the "osversion" field.
-func (m *MachineMutation) ResetOsversion() {
-	m.osversion = nil
-	delete(m.clearedFields, machine.FieldOsversion)
+// ResetUpdatedAt resets all changes to the "updated_at" field.
+func (m *MetaMutation) ResetUpdatedAt() {
+	m.updated_at = nil
 }
 
-// SetFeatureflags sets the "featureflags" field.
-func (m *MachineMutation) SetFeatureflags(s string) {
-	m.featureflags = &s
+// SetKey sets the "key" field.
+func (m *MetaMutation) SetKey(s string) {
+	m.key = &s
 }
 
-// Featureflags returns the value of the "featureflags" field in the mutation.
-func (m *MachineMutation) Featureflags() (r string, exists bool) {
-	v := m.featureflags
+// Key returns the value of the "key" field in the mutation.
+func (m *MetaMutation) Key() (r string, exists bool) {
+	v := m.key
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldFeatureflags returns the old "featureflags" field's value of the Machine entity.
-// If the Machine object wasn't provided to the builder, the object is fetched from the database.
+// OldKey returns the old "key" field's value of the Meta entity.
+// If the Meta object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *MachineMutation) OldFeatureflags(ctx context.Context) (v string, err error) {
+func (m *MetaMutation) OldKey(ctx context.Context) (v string, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldFeatureflags is only allowed on UpdateOne operations")
+		return v, errors.New("OldKey is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldFeatureflags requires an ID field in the mutation")
+		return v, errors.New("OldKey requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldFeatureflags: %w", err)
+		return v, fmt.Errorf("querying old value for OldKey: %w", err)
 	}
-	return oldValue.Featureflags, nil
-}
-
-// ClearFeatureflags clears the value of the "featureflags" field.
-func (m *MachineMutation) ClearFeatureflags() {
-	m.featureflags = nil
-	m.clearedFields[machine.FieldFeatureflags] = struct{}{}
-}
-
-// FeatureflagsCleared returns if the "featureflags" field was cleared in this mutation.
-func (m *MachineMutation) FeatureflagsCleared() bool {
-	_, ok := m.clearedFields[machine.FieldFeatureflags]
-	return ok
+	return oldValue.Key, nil
 }
 
-// ResetFeatureflags resets all changes to the "featureflags" field.
-func (m *MachineMutation) ResetFeatureflags() {
-	m.featureflags = nil
-	delete(m.clearedFields, machine.FieldFeatureflags)
+// ResetKey resets all changes to the "key" field.
+func (m *MetaMutation) ResetKey() {
+	m.key = nil
 }
 
-// SetHubstate sets the "hubstate" field.
-func (m *MachineMutation) SetHubstate(ms map[string][]schema.ItemState) {
-	m.hubstate = &ms
+// SetValue sets the "value" field.
+func (m *MetaMutation) SetValue(s string) {
+	m.value = &s
 }
 
-// Hubstate returns the value of the "hubstate" field in the mutation.
-func (m *MachineMutation) Hubstate() (r map[string][]schema.ItemState, exists bool) {
-	v := m.hubstate
+// Value returns the value of the "value" field in the mutation.
+func (m *MetaMutation) Value() (r string, exists bool) {
+	v := m.value
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldHubstate returns the old "hubstate" field's value of the Machine entity.
-// If the Machine object wasn't provided to the builder, the object is fetched from the database.
+// OldValue returns the old "value" field's value of the Meta entity.
+// If the Meta object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *MachineMutation) OldHubstate(ctx context.Context) (v map[string][]schema.ItemState, err error) {
+func (m *MetaMutation) OldValue(ctx context.Context) (v string, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldHubstate is only allowed on UpdateOne operations")
+		return v, errors.New("OldValue is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldHubstate requires an ID field in the mutation")
+		return v, errors.New("OldValue requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldHubstate: %w", err)
+		return v, fmt.Errorf("querying old value for OldValue: %w", err)
 	}
-	return oldValue.Hubstate, nil
-}
-
-// ClearHubstate clears the value of the "hubstate" field.
-func (m *MachineMutation) ClearHubstate() {
-	m.hubstate = nil
-	m.clearedFields[machine.FieldHubstate] = struct{}{}
-}
-
-// HubstateCleared returns if the "hubstate" field was cleared in this mutation.
-func (m *MachineMutation) HubstateCleared() bool {
-	_, ok := m.clearedFields[machine.FieldHubstate]
-	return ok
+	return oldValue.Value, nil
 }
 
-// ResetHubstate resets all changes to the "hubstate" field.
-func (m *MachineMutation) ResetHubstate() {
-	m.hubstate = nil
-	delete(m.clearedFields, machine.FieldHubstate)
+// ResetValue resets all changes to the "value" field.
+func (m *MetaMutation) ResetValue() {
+	m.value = nil
 }
 
-// SetDatasources sets the "datasources" field.
-func (m *MachineMutation) SetDatasources(value map[string]int64) {
-	m.datasources = &value
+// SetAlertMetas sets the "alert_metas" field.
+func (m *MetaMutation) SetAlertMetas(i int) {
+	m.owner = &i
 }
 
-// Datasources returns the value of the "datasources" field in the mutation.
-func (m *MachineMutation) Datasources() (r map[string]int64, exists bool) {
-	v := m.datasources
+// AlertMetas returns the value of the "alert_metas" field in the mutation.
+func (m *MetaMutation) AlertMetas() (r int, exists bool) {
+	v := m.owner
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldDatasources returns the old "datasources" field's value of the Machine entity.
-// If the Machine object wasn't provided to the builder, the object is fetched from the database.
+// OldAlertMetas returns the old "alert_metas" field's value of the Meta entity.
+// If the Meta object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *MachineMutation) OldDatasources(ctx context.Context) (v map[string]int64, err error) {
+func (m *MetaMutation) OldAlertMetas(ctx context.Context) (v int, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldDatasources is only allowed on UpdateOne operations")
+		return v, errors.New("OldAlertMetas is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldDatasources requires an ID field in the mutation")
+		return v, errors.New("OldAlertMetas requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldDatasources: %w", err)
+		return v, fmt.Errorf("querying old value for OldAlertMetas: %w", err)
 	}
-	return oldValue.Datasources, nil
+	return oldValue.AlertMetas, nil
 }
 
-// ClearDatasources clears the value of the "datasources" field.
-func (m *MachineMutation) ClearDatasources() {
-	m.datasources = nil
-	m.clearedFields[machine.FieldDatasources] = struct{}{}
+// ClearAlertMetas clears the value of the "alert_metas" field.
+func (m *MetaMutation) ClearAlertMetas() {
+	m.owner = nil
+	m.clearedFields[meta.FieldAlertMetas] = struct{}{}
 }
 
-// DatasourcesCleared returns if the "datasources" field was cleared in this mutation.
-func (m *MachineMutation) DatasourcesCleared() bool {
-	_, ok := m.clearedFields[machine.FieldDatasources]
+// AlertMetasCleared returns if the "alert_metas" field was cleared in this mutation.
+func (m *MetaMutation) AlertMetasCleared() bool {
+	_, ok := m.clearedFields[meta.FieldAlertMetas]
 	return ok
 }
 
-// ResetDatasources resets all changes to the "datasources" field.
-func (m *MachineMutation) ResetDatasources() {
-	m.datasources = nil
-	delete(m.clearedFields, machine.FieldDatasources)
-}
-
-// AddAlertIDs adds the "alerts" edge to the Alert entity by ids.
-func (m *MachineMutation) AddAlertIDs(ids ...int) {
-	if m.alerts == nil {
-		m.alerts = make(map[int]struct{})
-	}
-	for i := range ids {
-		m.alerts[ids[i]] = struct{}{}
-	}
+// ResetAlertMetas resets all changes to the "alert_metas" field.
+func (m *MetaMutation) ResetAlertMetas() {
+	m.owner = nil
+	delete(m.clearedFields, meta.FieldAlertMetas)
 }
 
-// ClearAlerts clears the "alerts" edge to the Alert entity.
-func (m *MachineMutation) ClearAlerts() {
-	m.clearedalerts = true
+// SetOwnerID sets the "owner" edge to the Alert entity by id.
+func (m *MetaMutation) SetOwnerID(id int) {
+	m.owner = &id
 }
 
-// AlertsCleared reports if the "alerts" edge to the Alert entity was cleared.
-func (m *MachineMutation) AlertsCleared() bool {
-	return m.clearedalerts
+// ClearOwner clears the "owner" edge to the Alert entity.
+func (m *MetaMutation) ClearOwner() {
+	m.clearedowner = true
+	m.clearedFields[meta.FieldAlertMetas] = struct{}{}
 }
 
-// RemoveAlertIDs removes the "alerts" edge to the Alert entity by IDs.
-func (m *MachineMutation) RemoveAlertIDs(ids ...int) {
-	if m.removedalerts == nil {
-		m.removedalerts = make(map[int]struct{})
-	}
-	for i := range ids {
-		delete(m.alerts, ids[i])
-		m.removedalerts[ids[i]] = struct{}{}
-	}
+// OwnerCleared reports if the "owner" edge to the Alert entity was cleared.
+func (m *MetaMutation) OwnerCleared() bool {
+	return m.AlertMetasCleared() || m.clearedowner
 }
 
-// RemovedAlerts returns the removed IDs of the "alerts" edge to the Alert entity.
-func (m *MachineMutation) RemovedAlertsIDs() (ids []int) {
-	for id := range m.removedalerts {
-		ids = append(ids, id)
+// OwnerID returns the "owner" edge ID in the mutation.
+func (m *MetaMutation) OwnerID() (id int, exists bool) {
+	if m.owner != nil {
+		return *m.owner, true
 	}
 	return
 }
 
-// AlertsIDs returns the "alerts" edge IDs in the mutation.
-func (m *MachineMutation) AlertsIDs() (ids []int) {
-	for id := range m.alerts {
-		ids = append(ids, id)
+// OwnerIDs returns the "owner" edge IDs in the mutation.
+// Note that IDs always returns len(IDs) <= 1 for unique edges, and you should use
+// OwnerID instead. It exists only for internal usage by the builders.
+func (m *MetaMutation) OwnerIDs() (ids []int) {
+	if id := m.owner; id != nil {
+		ids = append(ids, *id)
 	}
 	return
 }
 
-// ResetAlerts resets all changes to the "alerts" edge.
-func (m *MachineMutation) ResetAlerts() {
-	m.alerts = nil
-	m.clearedalerts = false
-	m.removedalerts = nil
+// ResetOwner resets all changes to the "owner" edge.
+func (m *MetaMutation) ResetOwner() {
+	m.owner = nil
+	m.clearedowner = false
 }
 
-// Where appends a list predicates to the MachineMutation builder.
-func (m *MachineMutation) Where(ps ...predicate.Machine) {
+// Where appends a list predicates to the MetaMutation builder.
+func (m *MetaMutation) Where(ps ...predicate.Meta) {
 	m.predicates = append(m.predicates, ps...)
 }
 
-// WhereP appends storage-level predicates to the MachineMutation builder. Using this method,
+// WhereP appends storage-level predicates to the MetaMutation builder. Using this method,
 // users can use type-assertion to append predicates that do not depend on any generated package.
-func (m *MachineMutation) WhereP(ps ...func(*sql.Selector)) {
-	p := make([]predicate.Machine, len(ps))
+func (m *MetaMutation) WhereP(ps ...func(*sql.Selector)) {
+	p := make([]predicate.Meta, len(ps))
 	for i := range ps {
 		p[i] = ps[i]
 	}
@@ -9489,72 +10988,39 @@ func (m *MachineMutation) WhereP(ps ...func(*sql.Selector)) {
 }
 
 // Op returns the operation name.
-func (m *MachineMutation) Op() Op {
+func (m *MetaMutation) Op() Op {
 	return m.op
 }
 
 // SetOp allows setting the mutation operation.
-func (m *MachineMutation) SetOp(op Op) {
+func (m *MetaMutation) SetOp(op Op) {
 	m.op = op
 }
 
-// Type returns the node type of this mutation (Machine).
-func (m *MachineMutation) Type() string {
+// Type returns the node type of this mutation (Meta).
+func (m *MetaMutation) Type() string {
 	return m.typ
 }
 
 // Fields returns all fields that were changed during this mutation. Note that in
 // order to get all numeric fields that were incremented/decremented, call
 // AddedFields().
-func (m *MachineMutation) Fields() []string {
-	fields := make([]string, 0, 16)
+func (m *MetaMutation) Fields() []string {
+	fields := make([]string, 0, 5)
 	if m.created_at != nil {
-		fields = append(fields, machine.FieldCreatedAt)
+		fields = append(fields, meta.FieldCreatedAt)
 	}
 	if m.updated_at != nil {
-		fields = append(fields, machine.FieldUpdatedAt)
-	}
-	if m.last_push != nil {
-		fields = append(fields, machine.FieldLastPush)
-	}
-	if m.last_heartbeat != nil {
-		fields = append(fields, machine.FieldLastHeartbeat)
-	}
-	if m.machineId != nil {
-		fields = append(fields, machine.FieldMachineId)
-	}
-	if m.password != nil {
-		fields = append(fields, machine.FieldPassword)
-	}
-	if m.ipAddress != nil {
-		fields = append(fields, machine.FieldIpAddress)
-	}
-	if m.scenarios != nil {
-		fields = append(fields, machine.FieldScenarios)
-	}
-	if m.version != nil {
-		fields = append(fields, machine.FieldVersion)
-	}
-	if m.isValidated != nil {
-		fields = append(fields, machine.FieldIsValidated)
-	}
-	if m.auth_type != nil {
-		fields = append(fields, machine.FieldAuthType)
-	}
-	if m.osname != nil {
-		fields = append(fields, machine.FieldOsname)
-	}
-	if m.osversion != nil {
-		fields = append(fields, machine.FieldOsversion)
+		fields = append(fields, meta.FieldUpdatedAt)
 	}
-	if m.featureflags != nil {
-		fields = append(fields, machine.FieldFeatureflags)
+	if m.key != nil {
+		fields = append(fields, meta.FieldKey)
 	}
-	if m.hubstate != nil {
-		fields = append(fields, machine.FieldHubstate)
+	if m.value != nil {
+		fields = append(fields, meta.FieldValue)
 	}
-	if m.datasources != nil {
-		fields = append(fields, machine.FieldDatasources)
+	if m.owner != nil {
+		fields = append(fields, meta.FieldAlertMetas)
 	}
 	return fields
 }
@@ -9562,40 +11028,18 @@ func (m *MachineMutation) Fields() []string {
 // Field returns the value of a field with the given name. The second boolean
 // return value indicates that this field was not set, or was not defined in the
 // schema.
-func (m *MachineMutation) Field(name string) (ent.Value, bool) {
+func (m *MetaMutation) Field(name string) (ent.Value, bool) {
 	switch name {
-	case machine.FieldCreatedAt:
+	case meta.FieldCreatedAt:
 		return m.CreatedAt()
-	case machine.FieldUpdatedAt:
+	case meta.FieldUpdatedAt:
 		return m.UpdatedAt()
-	case machine.FieldLastPush:
-		return m.LastPush()
-	case machine.FieldLastHeartbeat:
-		return m.LastHeartbeat()
-	case machine.FieldMachineId:
-		return m.MachineId()
-	case machine.FieldPassword:
-		return m.Password()
-	case machine.FieldIpAddress:
-		return m.IpAddress()
-	case machine.FieldScenarios:
-		return m.Scenarios()
-	case machine.FieldVersion:
-		return m.Version()
-	case machine.FieldIsValidated:
-		return m.IsValidated()
-	case machine.FieldAuthType:
-		return m.AuthType()
-	case machine.FieldOsname:
-		return m.Osname()
-	case machine.FieldOsversion:
-		return m.Osversion()
-	case machine.FieldFeatureflags:
-		return m.Featureflags()
-	case machine.FieldHubstate:
-		return m.Hubstate()
-	case machine.FieldDatasources:
-		return m.Datasources()
+	case meta.FieldKey:
+		return m.Key()
+	case meta.FieldValue:
+		return m.Value()
+	case meta.FieldAlertMetas:
+		return m.AlertMetas()
 	}
 	return nil, false
 }
@@ -9603,432 +11047,244 @@ func (m *MachineMutation) Field(name string) (ent.Value, bool) {
 // OldField returns the old value of the field from the database. An error is
 // returned if the mutation operation is not UpdateOne, or the query to the
 // database failed.
-func (m *MachineMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
+func (m *MetaMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
 	switch name {
-	case machine.FieldCreatedAt:
+	case meta.FieldCreatedAt:
 		return m.OldCreatedAt(ctx)
-	case machine.FieldUpdatedAt:
+	case meta.FieldUpdatedAt:
 		return m.OldUpdatedAt(ctx)
-	case machine.FieldLastPush:
-		return m.OldLastPush(ctx)
-	case machine.FieldLastHeartbeat:
-		return m.OldLastHeartbeat(ctx)
-	case machine.FieldMachineId:
-		return m.OldMachineId(ctx)
-	case machine.FieldPassword:
-		return m.OldPassword(ctx)
-	case machine.FieldIpAddress:
-		return m.OldIpAddress(ctx)
-	case machine.FieldScenarios:
-		return m.OldScenarios(ctx)
-	case machine.FieldVersion:
-		return m.OldVersion(ctx)
-	case machine.FieldIsValidated:
-		return m.OldIsValidated(ctx)
-	case machine.FieldAuthType:
-		return m.OldAuthType(ctx)
-	case machine.FieldOsname:
-		return m.OldOsname(ctx)
-	case machine.FieldOsversion:
-		return m.OldOsversion(ctx)
-	case machine.FieldFeatureflags:
-		return m.OldFeatureflags(ctx)
-	case machine.FieldHubstate:
-		return m.OldHubstate(ctx)
-	case machine.FieldDatasources:
-		return m.OldDatasources(ctx)
+	case meta.FieldKey:
+		return m.OldKey(ctx)
+	case meta.FieldValue:
+		return m.OldValue(ctx)
+	case meta.FieldAlertMetas:
+		return m.OldAlertMetas(ctx)
 	}
-	return nil, fmt.Errorf("unknown Machine field %s", name)
+	return nil, fmt.Errorf("unknown Meta field %s", name)
 }
 
 // SetField sets the value of a field with the given name. It returns an error if
 // the field is not defined in the schema, or if the type mismatched the field
 // type.
-func (m *MachineMutation) SetField(name string, value ent.Value) error {
+func (m *MetaMutation) SetField(name string, value ent.Value) error {
 	switch name {
-	case machine.FieldCreatedAt:
+	case meta.FieldCreatedAt:
 		v, ok := value.(time.Time)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetCreatedAt(v)
 		return nil
-	case machine.FieldUpdatedAt:
+	case meta.FieldUpdatedAt:
 		v, ok := value.(time.Time)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetUpdatedAt(v)
 		return nil
-	case machine.FieldLastPush:
-		v, ok := value.(time.Time)
-		if !ok {
-			return fmt.Errorf("unexpected type %T for field %s", value, name)
-		}
-		m.SetLastPush(v)
-		return nil
-	case machine.FieldLastHeartbeat:
-		v, ok := value.(time.Time)
-		if !ok {
-			return fmt.Errorf("unexpected type %T for field %s", value, name)
-		}
-		m.SetLastHeartbeat(v)
-		return nil
-	case machine.FieldMachineId:
-		v, ok := value.(string)
-		if !ok {
-			return fmt.Errorf("unexpected type %T for field %s", value, name)
-		}
-		m.SetMachineId(v)
-		return nil
-	case machine.FieldPassword:
-		v, ok := value.(string)
-		if !ok {
-			return fmt.Errorf("unexpected type %T for field %s", value, name)
-		}
-		m.SetPassword(v)
-		return nil
-	case machine.FieldIpAddress:
-		v, ok := value.(string)
-		if !ok {
-			return fmt.Errorf("unexpected type %T for field %s", value, name)
-		}
-		m.SetIpAddress(v)
-		return nil
-	case machine.FieldScenarios:
-		v, ok := value.(string)
-		if !ok {
-			return fmt.Errorf("unexpected type %T for field %s", value, name)
-		}
-		m.SetScenarios(v)
-		return nil
-	case machine.FieldVersion:
-		v, ok := value.(string)
-		if !ok {
-			return fmt.Errorf("unexpected type %T for field %s", value, name)
-		}
-		m.SetVersion(v)
-		return nil
-	case machine.FieldIsValidated:
-		v, ok := value.(bool)
-		if !ok {
-			return fmt.Errorf("unexpected type %T for field %s", value, name)
-		}
-		m.SetIsValidated(v)
-		return nil
-	case machine.FieldAuthType:
-		v, ok := value.(string)
-		if !ok {
-			return fmt.Errorf("unexpected type %T for field %s", value, name)
-		}
-		m.SetAuthType(v)
-		return nil
-	case machine.FieldOsname:
-		v, ok := value.(string)
-		if !ok {
-			return fmt.Errorf("unexpected type %T for field %s", value, name)
-		}
-		m.SetOsname(v)
-		return nil
-	case machine.FieldOsversion:
+	case meta.FieldKey:
 		v, ok := value.(string)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetOsversion(v)
+		m.SetKey(v)
 		return nil
-	case machine.FieldFeatureflags:
+	case meta.FieldValue:
 		v, ok := value.(string)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetFeatureflags(v)
-		return nil
-	case machine.FieldHubstate:
-		v, ok := value.(map[string][]schema.ItemState)
-		if !ok {
-			return fmt.Errorf("unexpected type %T for field %s", value, name)
-		}
-		m.SetHubstate(v)
+		m.SetValue(v)
 		return nil
-	case machine.FieldDatasources:
-		v, ok := value.(map[string]int64)
+	case meta.FieldAlertMetas:
+		v, ok := value.(int)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetDatasources(v)
+		m.SetAlertMetas(v)
 		return nil
 	}
-	return fmt.Errorf("unknown Machine field %s", name)
+	return fmt.Errorf("unknown Meta field %s", name)
 }
 
 // AddedFields returns all numeric fields that were incremented/decremented during
 // this mutation.
-func (m *MachineMutation) AddedFields() []string {
-	return nil
+func (m *MetaMutation) AddedFields() []string {
+	var fields []string
+	return fields
 }
 
 // AddedField returns the numeric value that was incremented/decremented on a field
 // with the given name. The second boolean return value indicates that this field
 // was not set, or was not defined in the schema.
-func (m *MachineMutation) AddedField(name string) (ent.Value, bool) {
+func (m *MetaMutation) AddedField(name string) (ent.Value, bool) {
+	switch name {
+	}
 	return nil, false
 }
 
 // AddField adds the value to the field with the given name. It returns an error if
 // the field is not defined in the schema, or if the type mismatched the field
 // type.
-func (m *MachineMutation) AddField(name string, value ent.Value) error {
+func (m *MetaMutation) AddField(name string, value ent.Value) error {
 	switch name {
 	}
-	return fmt.Errorf("unknown Machine numeric field %s", name)
+	return fmt.Errorf("unknown Meta numeric field %s", name)
 }
 
 // ClearedFields returns all nullable fields that were cleared during this
 // mutation.
-func (m *MachineMutation) ClearedFields() []string {
+func (m *MetaMutation) ClearedFields() []string {
 	var fields []string
-	if m.FieldCleared(machine.FieldLastPush) {
-		fields = append(fields, machine.FieldLastPush)
-	}
-	if m.FieldCleared(machine.FieldLastHeartbeat) {
-		fields = append(fields, machine.FieldLastHeartbeat)
-	}
-	if m.FieldCleared(machine.FieldScenarios) {
-		fields = append(fields, machine.FieldScenarios)
-	}
-	if m.FieldCleared(machine.FieldVersion) {
-		fields = append(fields, machine.FieldVersion)
-	}
-	if m.FieldCleared(machine.FieldOsname) {
-		fields = append(fields, machine.FieldOsname)
-	}
-	if m.FieldCleared(machine.FieldOsversion) {
-		fields = append(fields, machine.FieldOsversion)
-	}
-	if m.FieldCleared(machine.FieldFeatureflags) {
-		fields = append(fields, machine.FieldFeatureflags)
-	}
-	if m.FieldCleared(machine.FieldHubstate) {
-		fields = append(fields, machine.FieldHubstate)
-	}
-	if m.FieldCleared(machine.FieldDatasources) {
-		fields = append(fields, machine.FieldDatasources)
+	if m.FieldCleared(meta.FieldAlertMetas) {
+		fields = append(fields, meta.FieldAlertMetas)
 	}
 	return fields
 }
 
 // FieldCleared returns a boolean indicating if a field with the given name was
 // cleared in this mutation.
-func (m *MachineMutation) FieldCleared(name string) bool {
+func (m *MetaMutation) FieldCleared(name string) bool {
 	_, ok := m.clearedFields[name]
 	return ok
 }
 
 // ClearField clears the value of the field with the given name. It returns an
 // error if the field is not defined in the schema.
-func (m *MachineMutation) ClearField(name string) error {
+func (m *MetaMutation) ClearField(name string) error {
 	switch name {
-	case machine.FieldLastPush:
-		m.ClearLastPush()
-		return nil
-	case machine.FieldLastHeartbeat:
-		m.ClearLastHeartbeat()
-		return nil
-	case machine.FieldScenarios:
-		m.ClearScenarios()
-		return nil
-	case machine.FieldVersion:
-		m.ClearVersion()
-		return nil
-	case machine.FieldOsname:
-		m.ClearOsname()
-		return nil
-	case machine.FieldOsversion:
-		m.ClearOsversion()
-		return nil
-	case machine.FieldFeatureflags:
-		m.ClearFeatureflags()
-		return nil
-	case machine.FieldHubstate:
-		m.ClearHubstate()
-		return nil
-	case machine.FieldDatasources:
-		m.ClearDatasources()
+	case meta.FieldAlertMetas:
+		m.ClearAlertMetas()
 		return nil
 	}
-	return fmt.Errorf("unknown Machine nullable field %s", name)
+	return fmt.Errorf("unknown Meta nullable field %s", name)
 }
 
 // ResetField resets all changes in the mutation for the field with the given name.
 // It returns an error if the field is not defined in the schema.
-func (m *MachineMutation) ResetField(name string) error {
+func (m *MetaMutation) ResetField(name string) error {
 	switch name {
-	case machine.FieldCreatedAt:
+	case meta.FieldCreatedAt:
 		m.ResetCreatedAt()
 		return nil
-	case machine.FieldUpdatedAt:
+	case meta.FieldUpdatedAt:
 		m.ResetUpdatedAt()
 		return nil
-	case machine.FieldLastPush:
-		m.ResetLastPush()
-		return nil
-	case machine.FieldLastHeartbeat:
-		m.ResetLastHeartbeat()
-		return nil
-	case machine.FieldMachineId:
-		m.ResetMachineId()
-		return nil
-	case machine.FieldPassword:
-		m.ResetPassword()
-		return nil
-	case machine.FieldIpAddress:
-		m.ResetIpAddress()
-		return nil
-	case machine.FieldScenarios:
-		m.ResetScenarios()
-		return nil
-	case machine.FieldVersion:
-		m.ResetVersion()
-		return nil
-	case machine.FieldIsValidated:
-		m.ResetIsValidated()
-		return nil
-	case machine.FieldAuthType:
-		m.ResetAuthType()
-		return nil
-	case machine.FieldOsname:
-		m.ResetOsname()
-		return nil
-	case machine.FieldOsversion:
-		m.ResetOsversion()
-		return nil
-	case machine.FieldFeatureflags:
-		m.ResetFeatureflags()
+	case meta.FieldKey:
+		m.ResetKey()
 		return nil
-	case machine.FieldHubstate:
-		m.ResetHubstate()
+	case meta.FieldValue:
+		m.ResetValue()
 		return nil
-	case machine.FieldDatasources:
-		m.ResetDatasources()
+	case meta.FieldAlertMetas:
+		m.ResetAlertMetas()
 		return nil
 	}
-	return fmt.Errorf("unknown Machine field %s", name)
+	return fmt.Errorf("unknown Meta field %s", name)
 }
 
 // AddedEdges returns all edge names that were set/added in this mutation.
-func (m *MachineMutation) AddedEdges() []string {
+func (m *MetaMutation) AddedEdges() []string {
 	edges := make([]string, 0, 1)
-	if m.alerts != nil {
-		edges = append(edges, machine.EdgeAlerts)
+	if m.owner != nil {
+		edges = append(edges, meta.EdgeOwner)
 	}
 	return edges
 }
 
 // AddedIDs returns all IDs (to other nodes) that were added for the given edge
 // name in this mutation.
-func (m *MachineMutation) AddedIDs(name string) []ent.Value {
+func (m *MetaMutation) AddedIDs(name string) []ent.Value {
 	switch name {
-	case machine.EdgeAlerts:
-		ids := make([]ent.Value, 0, len(m.alerts))
-		for id := range m.alerts {
-			ids = append(ids, id)
+	case meta.EdgeOwner:
+		if id := m.owner; id != nil {
+			return []ent.Value{*id}
 		}
-		return ids
 	}
 	return nil
 }
 
 // RemovedEdges returns all edge names that were removed in this mutation.
-func (m *MachineMutation) RemovedEdges() []string {
+func (m *MetaMutation) RemovedEdges() []string {
 	edges := make([]string, 0, 1)
-	if m.removedalerts != nil {
-		edges = append(edges, machine.EdgeAlerts)
-	}
 	return edges
 }
 
 // RemovedIDs returns all IDs (to other nodes) that were removed for the edge with
 // the given name in this mutation.
-func (m *MachineMutation) RemovedIDs(name string) []ent.Value {
-	switch name {
-	case machine.EdgeAlerts:
-		ids := make([]ent.Value, 0, len(m.removedalerts))
-		for id := range m.removedalerts {
-			ids = append(ids, id)
-		}
-		return ids
-	}
+func (m *MetaMutation) RemovedIDs(name string) []ent.Value {
 	return nil
 }
 
 // ClearedEdges returns all edge names that were cleared in this mutation.
-func (m *MachineMutation) ClearedEdges() []string {
+func (m *MetaMutation) ClearedEdges() []string {
 	edges := make([]string, 0, 1)
-	if m.clearedalerts {
-		edges = append(edges, machine.EdgeAlerts)
+	if m.clearedowner {
+		edges = append(edges, meta.EdgeOwner)
 	}
 	return edges
 }
 
 // EdgeCleared returns a boolean which indicates if the edge with the given name
 // was cleared in this mutation.
-func (m *MachineMutation) EdgeCleared(name string) bool {
+func (m *MetaMutation) EdgeCleared(name string) bool {
 	switch name {
-	case machine.EdgeAlerts:
-		return m.clearedalerts
+	case meta.EdgeOwner:
+		return m.clearedowner
 	}
 	return false
 }
 
 // ClearEdge clears the value of the edge with the given name. It returns an error
 // if that edge is not defined in the schema.
-func (m *MachineMutation) ClearEdge(name string) error {
+func (m *MetaMutation) ClearEdge(name string) error {
 	switch name {
+	case meta.EdgeOwner:
+		m.ClearOwner()
+		return nil
 	}
-	return fmt.Errorf("unknown Machine unique edge %s", name)
+	return fmt.Errorf("unknown Meta unique edge %s", name)
 }
 
 // ResetEdge resets all changes to the edge with the given name in this mutation.
 // It returns an error if the edge is not defined in the schema.
-func (m *MachineMutation) ResetEdge(name string) error {
+func (m *MetaMutation) ResetEdge(name string) error {
 	switch name {
-	case machine.EdgeAlerts:
-		m.ResetAlerts()
+	case meta.EdgeOwner:
+		m.ResetOwner()
 		return nil
 	}
-	return fmt.Errorf("unknown Machine edge %s", name)
+	return fmt.Errorf("unknown Meta edge %s", name)
 }
 
-// MetaMutation represents an operation that mutates the Meta nodes in the graph.
-type MetaMutation struct {
+// MetricMutation represents an operation that mutates the Metric nodes in the graph.
+type MetricMutation struct {
 	config
-	op            Op
-	typ           string
-	id            *int
-	created_at    *time.Time
-	updated_at    *time.Time
-	key           *string
-	value         *string
-	clearedFields map[string]struct{}
-	owner         *int
-	clearedowner  bool
-	done          bool
-	oldValue      func(context.Context) (*Meta, error)
-	predicates    []predicate.Meta
+	op             Op
+	typ            string
+	id             *int
+	generated_type *metric.GeneratedType
+	generated_by   *string
+	received_at    *time.Time
+	pushed_at      *time.Time
+	payload        *string
+	clearedFields  map[string]struct{}
+	done           bool
+	oldValue       func(context.Context) (*Metric, error)
+	predicates     []predicate.Metric
 }
 
-var _ ent.Mutation = (*MetaMutation)(nil)
+var _ ent.Mutation = (*MetricMutation)(nil)
 
-// metaOption allows management of the mutation configuration using functional options.
-type metaOption func(*MetaMutation)
+// metricOption allows management of the mutation configuration using functional options.
+type metricOption func(*MetricMutation)
 
-// newMetaMutation creates new mutation for the Meta entity.
-func newMetaMutation(c config, op Op, opts ...metaOption) *MetaMutation {
-	m := &MetaMutation{
+// newMetricMutation creates new mutation for the Metric entity.
+func newMetricMutation(c config, op Op, opts ...metricOption) *MetricMutation {
+	m := &MetricMutation{
 		config:        c,
 		op:            op,
-		typ:           TypeMeta,
+		typ:           TypeMetric,
 		clearedFields: make(map[string]struct{}),
 	}
 	for _, opt := range opts {
@@ -10037,20 +11293,20 @@ func newMetaMutation(c config, op Op, opts ...metaOption) *MetaMutation {
 	return m
 }
 
-// withMetaID sets the ID field of the mutation.
-func withMetaID(id int) metaOption {
-	return func(m *MetaMutation) {
+// withMetricID sets the ID field of the mutation.
+func withMetricID(id int) metricOption {
+	return func(m *MetricMutation) {
 		var (
 			err   error
 			once  sync.Once
-			value *Meta
+			value *Metric
 		)
-		m.oldValue = func(ctx context.Context) (*Meta, error) {
+		m.oldValue = func(ctx context.Context) (*Metric, error) {
 			once.Do(func() {
 				if m.done {
 					err = errors.New("querying old values post mutation is not allowed")
 				} else {
-					value, err = m.Client().Meta.Get(ctx, id)
+					value, err = m.Client().Metric.Get(ctx, id)
 				}
 			})
 			return value, err
@@ -10059,10 +11315,10 @@ func withMetaID(id int) metaOption {
 	}
 }
 
-// withMeta sets the old Meta of the mutation.
-func withMeta(node *Meta) metaOption {
-	return func(m *MetaMutation) {
-		m.oldValue = func(context.Context) (*Meta, error) {
+// withMetric sets the old Metric of the mutation.
+func withMetric(node *Metric) metricOption {
+	return func(m *MetricMutation) {
+		m.oldValue = func(context.Context) (*Metric, error) {
 			return node, nil
 		}
 		m.id = &node.ID
@@ -10071,7 +11327,7 @@ func withMeta(node *Meta) metaOption {
 
 // Client returns a new `ent.Client` from the mutation. If the mutation was
 // executed in a transaction (ent.Tx), a transactional client is returned.
-func (m MetaMutation) Client() *Client {
+func (m MetricMutation) Client() *Client {
 	client := &Client{config: m.config}
 	client.init()
 	return client
@@ -10079,7 +11335,7 @@ func (m MetaMutation) Client() *Client {
 
 // Tx returns an `ent.Tx` for mutations that were executed in transactions;
 // it returns an error otherwise.
-func (m MetaMutation) Tx() (*Tx, error) {
+func (m MetricMutation) Tx() (*Tx, error) {
 	if _, ok := m.driver.(*txDriver); !ok {
 		return nil, errors.New("ent: mutation is not running in a transaction")
 	}
@@ -10090,7 +11346,7 @@ func (m MetaMutation) Tx() (*Tx, error) {
 
 // ID returns the ID value in the mutation. Note that the ID is only available
 // if it was provided to the builder or after it was returned from the database.
-func (m *MetaMutation) ID() (id int, exists bool) {
+func (m *MetricMutation) ID() (id int, exists bool) {
 	if m.id == nil {
 		return
 	}
@@ -10101,7 +11357,7 @@ func (m *MetaMutation) ID() (id int, exists bool) {
 // That means, if the mutation is applied within a transaction with an isolation level such
 // as sql.LevelSerializable, the returned ids match the ids of the rows that will be updated
 // or updated by the mutation.
-func (m *MetaMutation) IDs(ctx context.Context) ([]int, error) {
+func (m *MetricMutation) IDs(ctx context.Context) ([]int, error) {
 	switch {
 	case m.op.Is(OpUpdateOne | OpDeleteOne):
 		id, exists := m.ID()
@@ -10110,254 +11366,214 @@ func (m *MetaMutation) IDs(ctx context.Context) ([]int, error) {
 		}
 		fallthrough
 	case m.op.Is(OpUpdate | OpDelete):
-		return m.Client().Meta.Query().Where(m.predicates...).IDs(ctx)
+		return m.Client().Metric.Query().Where(m.predicates...).IDs(ctx)
 	default:
 		return nil, fmt.Errorf("IDs is not allowed on %s operations", m.op)
 	}
 }
 
-// SetCreatedAt sets the "created_at" field.
-func (m *MetaMutation) SetCreatedAt(t time.Time) {
-	m.created_at = &t
+// SetGeneratedType sets the "generated_type" field.
+func (m *MetricMutation) SetGeneratedType(mt metric.GeneratedType) {
+	m.generated_type = &mt
 }
 
-// CreatedAt returns the value of the "created_at" field in the mutation.
-func (m *MetaMutation) CreatedAt() (r time.Time, exists bool) {
-	v := m.created_at
+// GeneratedType returns the value of the "generated_type" field in the mutation.
+func (m *MetricMutation) GeneratedType() (r metric.GeneratedType, exists bool) {
+	v := m.generated_type
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldCreatedAt returns the old "created_at" field's value of the Meta entity.
-// If the Meta object wasn't provided to the builder, the object is fetched from the database.
+// OldGeneratedType returns the old "generated_type" field's value of the Metric entity.
+// If the Metric object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *MetaMutation) OldCreatedAt(ctx context.Context) (v time.Time, err error) {
+func (m *MetricMutation) OldGeneratedType(ctx context.Context) (v metric.GeneratedType, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldCreatedAt is only allowed on UpdateOne operations")
+		return v, errors.New("OldGeneratedType is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldCreatedAt requires an ID field in the mutation")
+		return v, errors.New("OldGeneratedType requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldCreatedAt: %w", err)
+		return v, fmt.Errorf("querying old value for OldGeneratedType: %w", err)
 	}
-	return oldValue.CreatedAt, nil
+	return oldValue.GeneratedType, nil
 }
 
-// ResetCreatedAt resets all changes to the "created_at" field.
-func (m *MetaMutation) ResetCreatedAt() {
-	m.created_at = nil
+// ResetGeneratedType resets all changes to the "generated_type" field.
+func (m *MetricMutation) ResetGeneratedType() {
+	m.generated_type = nil
 }
 
-// SetUpdatedAt sets the "updated_at" field.
-func (m *MetaMutation) SetUpdatedAt(t time.Time) {
-	m.updated_at = &t
+// SetGeneratedBy sets the "generated_by" field.
+func (m *MetricMutation) SetGeneratedBy(s string) {
+	m.generated_by = &s
 }
 
-// UpdatedAt returns the value of the "updated_at" field in the mutation.
-func (m *MetaMutation) UpdatedAt() (r time.Time, exists bool) {
-	v := m.updated_at
+// GeneratedBy returns the value of the "generated_by" field in the mutation.
+func (m *MetricMutation) GeneratedBy() (r string, exists bool) {
+	v := m.generated_by
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldUpdatedAt returns the old "updated_at" field's value of the Meta entity.
-// If the Meta object wasn't provided to the builder, the object is fetched from the database.
+// OldGeneratedBy returns the old "generated_by" field's value of the Metric entity.
+// If the Metric object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *MetaMutation) OldUpdatedAt(ctx context.Context) (v time.Time, err error) {
+func (m *MetricMutation) OldGeneratedBy(ctx context.Context) (v string, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldUpdatedAt is only allowed on UpdateOne operations")
+		return v, errors.New("OldGeneratedBy is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldUpdatedAt requires an ID field in the mutation")
+		return v, errors.New("OldGeneratedBy requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldUpdatedAt: %w", err)
+		return v, fmt.Errorf("querying old value for OldGeneratedBy: %w", err)
 	}
-	return oldValue.UpdatedAt, nil
+	return oldValue.GeneratedBy, nil
 }
 
-// ResetUpdatedAt resets all changes to the "updated_at" field.
-func (m *MetaMutation) ResetUpdatedAt() {
-	m.updated_at = nil
+// ResetGeneratedBy resets all changes to the "generated_by" field.
+func (m *MetricMutation) ResetGeneratedBy() {
+	m.generated_by = nil
 }
 
-// SetKey sets the "key" field.
-func (m *MetaMutation) SetKey(s string) {
-	m.key = &s
+// SetReceivedAt sets the "received_at" field.
+func (m *MetricMutation) SetReceivedAt(t time.Time) {
+	m.received_at = &t
 }
 
-// Key returns the value of the "key" field in the mutation.
-func (m *MetaMutation) Key() (r string, exists bool) {
-	v := m.key
+// ReceivedAt returns the value of the "received_at" field in the mutation.
+func (m *MetricMutation) ReceivedAt() (r time.Time, exists bool) {
+	v := m.received_at
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldKey returns the old "key" field's value of the Meta entity.
-// If the Meta object wasn't provided to the builder, the object is fetched from the database.
+// OldReceivedAt returns the old "received_at" field's value of the Metric entity.
+// If the Metric object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *MetaMutation) OldKey(ctx context.Context) (v string, err error) {
+func (m *MetricMutation) OldReceivedAt(ctx context.Context) (v time.Time, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldKey is only allowed on UpdateOne operations")
+		return v, errors.New("OldReceivedAt is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldKey requires an ID field in the mutation")
+		return v, errors.New("OldReceivedAt requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldKey: %w", err)
+		return v, fmt.Errorf("querying old value for OldReceivedAt: %w", err)
 	}
-	return oldValue.Key, nil
+	return oldValue.ReceivedAt, nil
 }
 
-// ResetKey resets all changes to the "key" field.
-func (m *MetaMutation) ResetKey() {
-	m.key = nil
+// ResetReceivedAt resets all changes to the "received_at" field.
+func (m *MetricMutation) ResetReceivedAt() {
+	m.received_at = nil
 }
 
-// SetValue sets the "value" field.
-func (m *MetaMutation) SetValue(s string) {
-	m.value = &s
+// SetPushedAt sets the "pushed_at" field.
+func (m *MetricMutation) SetPushedAt(t time.Time) {
+	m.pushed_at = &t
 }
 
-// Value returns the value of the "value" field in the mutation.
-func (m *MetaMutation) Value() (r string, exists bool) {
-	v := m.value
+// PushedAt returns the value of the "pushed_at" field in the mutation.
+func (m *MetricMutation) PushedAt() (r time.Time, exists bool) {
+	v := m.pushed_at
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldValue returns the old "value" field's value of the Meta entity.
-// If the Meta object wasn't provided to the builder, the object is fetched from the database.
+// OldPushedAt returns the old "pushed_at" field's value of the Metric entity.
+// If the Metric object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *MetaMutation) OldValue(ctx context.Context) (v string, err error) {
+func (m *MetricMutation) OldPushedAt(ctx context.Context) (v *time.Time, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldValue is only allowed on UpdateOne operations")
+		return v, errors.New("OldPushedAt is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldValue requires an ID field in the mutation")
+		return v, errors.New("OldPushedAt requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldValue: %w", err)
+		return v, fmt.Errorf("querying old value for OldPushedAt: %w", err)
 	}
-	return oldValue.Value, nil
+	return oldValue.PushedAt, nil
 }
 
-// ResetValue resets all changes to the "value" field.
-func (m *MetaMutation) ResetValue() {
-	m.value = nil
+// ClearPushedAt clears the value of the "pushed_at" field.
+func (m *MetricMutation) ClearPushedAt() {
+	m.pushed_at = nil
+	m.clearedFields[metric.FieldPushedAt] = struct{}{}
 }
 
-// SetAlertMetas sets the "alert_metas" field.
-func (m *MetaMutation) SetAlertMetas(i int) {
-	m.owner = &i
+// PushedAtCleared returns if the "pushed_at" field was cleared in this mutation.
+func (m *MetricMutation) PushedAtCleared() bool {
+	_, ok := m.clearedFields[metric.FieldPushedAt]
+	return ok
 }
 
-// AlertMetas returns the value of the "alert_metas" field in the mutation.
-func (m *MetaMutation) AlertMetas() (r int, exists bool) {
-	v := m.owner
+// ResetPushedAt resets all changes to the "pushed_at" field.
+func (m *MetricMutation) ResetPushedAt() {
+	m.pushed_at = nil
+	delete(m.clearedFields, metric.FieldPushedAt)
+}
+
+// SetPayload sets the "payload" field.
+func (m *MetricMutation) SetPayload(s string) {
+	m.payload = &s
+}
+
+// Payload returns the value of the "payload" field in the mutation.
+func (m *MetricMutation) Payload() (r string, exists bool) {
+	v := m.payload
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldAlertMetas returns the old "alert_metas" field's value of the Meta entity.
-// If the Meta object wasn't provided to the builder, the object is fetched from the database.
+// OldPayload returns the old "payload" field's value of the Metric entity.
+// If the Metric object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *MetaMutation) OldAlertMetas(ctx context.Context) (v int, err error) {
+func (m *MetricMutation) OldPayload(ctx context.Context) (v string, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldAlertMetas is only allowed on UpdateOne operations")
+		return v, errors.New("OldPayload is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldAlertMetas requires an ID field in the mutation")
+		return v, errors.New("OldPayload requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldAlertMetas: %w", err)
-	}
-	return oldValue.AlertMetas, nil
-}
-
-// ClearAlertMetas clears the value of the "alert_metas" field.
-func (m *MetaMutation) ClearAlertMetas() {
-	m.owner = nil
-	m.clearedFields[meta.FieldAlertMetas] = struct{}{}
-}
-
-// AlertMetasCleared returns if the "alert_metas" field was cleared in this mutation.
-func (m *MetaMutation) AlertMetasCleared() bool {
-	_, ok := m.clearedFields[meta.FieldAlertMetas]
-	return ok
-}
-
-// ResetAlertMetas resets all changes to the "alert_metas" field.
-func (m *MetaMutation) ResetAlertMetas() {
-	m.owner = nil
-	delete(m.clearedFields, meta.FieldAlertMetas)
-}
-
-// SetOwnerID sets the "owner" edge to the Alert entity by id.
-func (m *MetaMutation) SetOwnerID(id int) {
-	m.owner = &id
-}
-
-// ClearOwner clears the "owner" edge to the Alert entity.
-func (m *MetaMutation) ClearOwner() {
-	m.clearedowner = true
-	m.clearedFields[meta.FieldAlertMetas] = struct{}{}
-}
-
-// OwnerCleared reports if the "owner" edge to the Alert entity was cleared.
-func (m *MetaMutation) OwnerCleared() bool {
-	return m.AlertMetasCleared() || m.clearedowner
-}
-
-// OwnerID returns the "owner" edge ID in the mutation.
-func (m *MetaMutation) OwnerID() (id int, exists bool) {
-	if m.owner != nil {
-		return *m.owner, true
-	}
-	return
-}
-
-// OwnerIDs returns the "owner" edge IDs in the mutation.
-// Note that IDs always returns len(IDs) <= 1 for unique edges, and you should use
-// OwnerID instead. It exists only for internal usage by the builders.
-func (m *MetaMutation) OwnerIDs() (ids []int) {
-	if id := m.owner; id != nil {
-		ids = append(ids, *id)
+		return v, fmt.Errorf("querying old value for OldPayload: %w", err)
 	}
-	return
+	return oldValue.Payload, nil
 }
 
-// ResetOwner resets all changes to the "owner" edge.
-func (m *MetaMutation) ResetOwner() {
-	m.owner = nil
-	m.clearedowner = false
+// ResetPayload resets all changes to the "payload" field.
+func (m *MetricMutation) ResetPayload() {
+	m.payload = nil
 }
 
-// Where appends a list predicates to the MetaMutation builder.
-func (m *MetaMutation) Where(ps ...predicate.Meta) {
+// Where appends a list predicates to the MetricMutation builder.
+func (m *MetricMutation) Where(ps ...predicate.Metric) {
 	m.predicates = append(m.predicates, ps...)
 }
 
-// WhereP appends storage-level predicates to the MetaMutation builder. Using this method,
+// WhereP appends storage-level predicates to the MetricMutation builder. Using this method,
 // users can use type-assertion to append predicates that do not depend on any generated package.
-func (m *MetaMutation) WhereP(ps ...func(*sql.Selector)) {
-	p := make([]predicate.Meta, len(ps))
+func (m *MetricMutation) WhereP(ps ...func(*sql.Selector)) {
+	p := make([]predicate.Metric, len(ps))
 	for i := range ps {
 		p[i] = ps[i]
 	}
@@ -10365,39 +11581,39 @@ func (m *MetaMutation) WhereP(ps ...func(*sql.Selector)) {
 }
 
 // Op returns the operation name.
-func (m *MetaMutation) Op() Op {
+func (m *MetricMutation) Op() Op {
 	return m.op
 }
 
 // SetOp allows setting the mutation operation.
-func (m *MetaMutation) SetOp(op Op) {
+func (m *MetricMutation) SetOp(op Op) {
 	m.op = op
 }
 
-// Type returns the node type of this mutation (Meta).
-func (m *MetaMutation) Type() string {
+// Type returns the node type of this mutation (Metric).
+func (m *MetricMutation) Type() string {
 	return m.typ
 }
 
 // Fields returns all fields that were changed during this mutation. Note that in
 // order to get all numeric fields that were incremented/decremented, call
 // AddedFields().
-func (m *MetaMutation) Fields() []string {
+func (m *MetricMutation) Fields() []string {
 	fields := make([]string, 0, 5)
-	if m.created_at != nil {
-		fields = append(fields, meta.FieldCreatedAt)
+	if m.generated_type != nil {
+		fields = append(fields, metric.FieldGeneratedType)
 	}
-	if m.updated_at != nil {
-		fields = append(fields, meta.FieldUpdatedAt)
+	if m.generated_by != nil {
+		fields = append(fields, metric.FieldGeneratedBy)
 	}
-	if m.key != nil {
-		fields = append(fields, meta.FieldKey)
+	if m.received_at != nil {
+		fields = append(fields, metric.FieldReceivedAt)
 	}
-	if m.value != nil {
-		fields = append(fields, meta.FieldValue)
+	if m.pushed_at != nil {
+		fields = append(fields, metric.FieldPushedAt)
 	}
-	if m.owner != nil {
-		fields = append(fields, meta.FieldAlertMetas)
+	if m.payload != nil {
+		fields = append(fields, metric.FieldPayload)
 	}
 	return fields
 }
@@ -10405,18 +11621,18 @@ func (m *MetaMutation) Fields() []string {
 // Field returns the value of a field with the given name. The second boolean
 // return value indicates that this field was not set, or was not defined in the
 // schema.
-func (m *MetaMutation) Field(name string) (ent.Value, bool) {
+func (m *MetricMutation) Field(name string) (ent.Value, bool) {
 	switch name {
-	case meta.FieldCreatedAt:
-		return m.CreatedAt()
-	case meta.FieldUpdatedAt:
-		return m.UpdatedAt()
-	case meta.FieldKey:
-		return m.Key()
-	case meta.FieldValue:
-		return m.Value()
-	case meta.FieldAlertMetas:
-		return m.AlertMetas()
+	case metric.FieldGeneratedType:
+		return m.GeneratedType()
+	case metric.FieldGeneratedBy:
+		return m.GeneratedBy()
+	case metric.FieldReceivedAt:
+		return m.ReceivedAt()
+	case metric.FieldPushedAt:
+		return m.PushedAt()
+	case metric.FieldPayload:
+		return m.Payload()
 	}
 	return nil, false
 }
@@ -10424,244 +11640,217 @@ func (m *MetaMutation) Field(name string) (ent.Value, bool) {
 // OldField returns the old value of the field from the database. An error is
 // returned if the mutation operation is not UpdateOne, or the query to the
 // database failed.
-func (m *MetaMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
+func (m *MetricMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
 	switch name {
-	case meta.FieldCreatedAt:
-		return m.OldCreatedAt(ctx)
-	case meta.FieldUpdatedAt:
-		return m.OldUpdatedAt(ctx)
-	case meta.FieldKey:
-		return m.OldKey(ctx)
-	case meta.FieldValue:
-		return m.OldValue(ctx)
-	case meta.FieldAlertMetas:
-		return m.OldAlertMetas(ctx)
+	case metric.FieldGeneratedType:
+		return m.OldGeneratedType(ctx)
+	case metric.FieldGeneratedBy:
+		return m.OldGeneratedBy(ctx)
+	case metric.FieldReceivedAt:
+		return m.OldReceivedAt(ctx)
+	case metric.FieldPushedAt:
+		return m.OldPushedAt(ctx)
+	case metric.FieldPayload:
+		return m.OldPayload(ctx)
 	}
-	return nil, fmt.Errorf("unknown Meta field %s", name)
+	return nil, fmt.Errorf("unknown Metric field %s", name)
 }
 
 // SetField sets the value of a field with the given name. It returns an error if
 // the field is not defined in the schema, or if the type mismatched the field
 // type.
-func (m *MetaMutation) SetField(name string, value ent.Value) error {
+func (m *MetricMutation) SetField(name string, value ent.Value) error {
 	switch name {
-	case meta.FieldCreatedAt:
-		v, ok := value.(time.Time)
+	case metric.FieldGeneratedType:
+		v, ok := value.(metric.GeneratedType)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetCreatedAt(v)
+		m.SetGeneratedType(v)
 		return nil
-	case meta.FieldUpdatedAt:
-		v, ok := value.(time.Time)
+	case metric.FieldGeneratedBy:
+		v, ok := value.(string)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetUpdatedAt(v)
+		m.SetGeneratedBy(v)
 		return nil
-	case meta.FieldKey:
-		v, ok := value.(string)
+	case metric.FieldReceivedAt:
+		v, ok := value.(time.Time)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetKey(v)
+		m.SetReceivedAt(v)
 		return nil
-	case meta.FieldValue:
-		v, ok := value.(string)
+	case metric.FieldPushedAt:
+		v, ok := value.(time.Time)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetValue(v)
+		m.SetPushedAt(v)
 		return nil
-	case meta.FieldAlertMetas:
-		v, ok := value.(int)
+	case metric.FieldPayload:
+		v, ok := value.(string)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetAlertMetas(v)
+		m.SetPayload(v)
 		return nil
 	}
-	return fmt.Errorf("unknown Meta field %s", name)
+	return fmt.Errorf("unknown Metric field %s", name)
 }
 
 // AddedFields returns all numeric fields that were incremented/decremented during
 // this mutation.
-func (m *MetaMutation) AddedFields() []string {
-	var fields []string
-	return fields
+func (m *MetricMutation) AddedFields() []string {
+	return nil
 }
 
 // AddedField returns the numeric value that was incremented/decremented on a field
 // with the given name. The second boolean return value indicates that this field
 // was not set, or was not defined in the schema.
-func (m *MetaMutation) AddedField(name string) (ent.Value, bool) {
-	switch name {
-	}
+func (m *MetricMutation) AddedField(name string) (ent.Value, bool) {
 	return nil, false
 }
 
 // AddField adds the value to the field with the given name. It returns an error if
 // the field is not defined in the schema, or if the type mismatched the field
 // type.
-func (m *MetaMutation) AddField(name string, value ent.Value) error {
+func (m *MetricMutation) AddField(name string, value ent.Value) error {
 	switch name {
 	}
-	return fmt.Errorf("unknown Meta numeric field %s", name)
+	return fmt.Errorf("unknown Metric numeric field %s", name)
 }
 
 // ClearedFields returns all nullable fields that were cleared during this
 // mutation.
-func (m *MetaMutation) ClearedFields() []string {
+func (m *MetricMutation) ClearedFields() []string {
 	var fields []string
-	if m.FieldCleared(meta.FieldAlertMetas) {
-		fields = append(fields, meta.FieldAlertMetas)
+	if m.FieldCleared(metric.FieldPushedAt) {
+		fields = append(fields, metric.FieldPushedAt)
 	}
 	return fields
 }
 
 // FieldCleared returns a boolean indicating if a field with the given name was
 // cleared in this mutation.
-func (m *MetaMutation) FieldCleared(name string) bool {
+func (m *MetricMutation) FieldCleared(name string) bool {
 	_, ok := m.clearedFields[name]
 	return ok
 }
 
 // ClearField clears the value of the field with the given name. It returns an
 // error if the field is not defined in the schema.
-func (m *MetaMutation) ClearField(name string) error {
+func (m *MetricMutation) ClearField(name string) error {
 	switch name {
-	case meta.FieldAlertMetas:
-		m.ClearAlertMetas()
+	case metric.FieldPushedAt:
+		m.ClearPushedAt()
 		return nil
 	}
-	return fmt.Errorf("unknown Meta nullable field %s", name)
+	return fmt.Errorf("unknown Metric nullable field %s", name)
 }
 
 // ResetField resets all changes in the mutation for the field with the given name.
 // It returns an error if the field is not defined in the schema.
-func (m *MetaMutation) ResetField(name string) error {
+func (m *MetricMutation) ResetField(name string) error {
 	switch name {
-	case meta.FieldCreatedAt:
-		m.ResetCreatedAt()
+	case metric.FieldGeneratedType:
+		m.ResetGeneratedType()
 		return nil
-	case meta.FieldUpdatedAt:
-		m.ResetUpdatedAt()
+	case metric.FieldGeneratedBy:
+		m.ResetGeneratedBy()
 		return nil
-	case meta.FieldKey:
-		m.ResetKey()
+	case metric.FieldReceivedAt:
+		m.ResetReceivedAt()
 		return nil
-	case meta.FieldValue:
-		m.ResetValue()
+	case metric.FieldPushedAt:
+		m.ResetPushedAt()
 		return nil
-	case meta.FieldAlertMetas:
-		m.ResetAlertMetas()
+	case metric.FieldPayload:
+		m.ResetPayload()
 		return nil
 	}
-	return fmt.Errorf("unknown Meta field %s", name)
+	return fmt.Errorf("unknown Metric field %s", name)
 }
 
 // AddedEdges returns all edge names that were set/added in this mutation.
-func (m *MetaMutation) AddedEdges() []string {
-	edges := make([]string, 0, 1)
-	if m.owner != nil {
-		edges = append(edges, meta.EdgeOwner)
-	}
+func (m *MetricMutation) AddedEdges() []string {
+	edges := make([]string, 0, 0)
 	return edges
 }
 
 // AddedIDs returns all IDs (to other nodes) that were added for the given edge
 // name in this mutation.
-func (m *MetaMutation) AddedIDs(name string) []ent.Value {
-	switch name {
-	case meta.EdgeOwner:
-		if id := m.owner; id != nil {
-			return []ent.Value{*id}
-		}
-	}
+func (m *MetricMutation) AddedIDs(name string) []ent.Value {
 	return nil
 }
 
 // RemovedEdges returns all edge names that were removed in this mutation.
-func (m *MetaMutation) RemovedEdges() []string {
-	edges := make([]string, 0, 1)
+func (m *MetricMutation) RemovedEdges() []string {
+	edges := make([]string, 0, 0)
 	return edges
 }
 
 // RemovedIDs returns all IDs (to other nodes) that were removed for the edge with
 // the given name in this mutation.
-func (m *MetaMutation) RemovedIDs(name string) []ent.Value {
+func (m *MetricMutation) RemovedIDs(name string) []ent.Value {
 	return nil
 }
 
 // ClearedEdges returns all edge names that were cleared in this mutation.
-func (m *MetaMutation) ClearedEdges() []string {
-	edges := make([]string, 0, 1)
-	if m.clearedowner {
-		edges = append(edges, meta.EdgeOwner)
-	}
+func (m *MetricMutation) ClearedEdges() []string {
+	edges := make([]string, 0, 0)
 	return edges
 }
 
 // EdgeCleared returns a boolean which indicates if the edge with the given name
 // was cleared in this mutation.
-func (m *MetaMutation) EdgeCleared(name string) bool {
-	switch name {
-	case meta.EdgeOwner:
-		return m.clearedowner
-	}
+func (m *MetricMutation) EdgeCleared(name string) bool {
 	return false
 }
 
 // ClearEdge clears the value of the edge with the given name. It returns an error
 // if that edge is not defined in the schema.
-func (m *MetaMutation) ClearEdge(name string) error {
-	switch name {
-	case meta.EdgeOwner:
-		m.ClearOwner()
-		return nil
-	}
-	return fmt.Errorf("unknown Meta unique edge %s", name)
+func (m *MetricMutation) ClearEdge(name string) error {
+	return fmt.Errorf("unknown Metric unique edge %s", name)
 }
 
 // ResetEdge resets all changes to the edge with the given name in this mutation.
 // It returns an error if the edge is not defined in the schema.
-func (m *MetaMutation) ResetEdge(name string) error {
-	switch name {
-	case meta.EdgeOwner:
-		m.ResetOwner()
-		return nil
-	}
-	return fmt.Errorf("unknown Meta edge %s", name)
+func (m *MetricMutation) ResetEdge(name string) error {
+	return fmt.Errorf("unknown Metric edge %s", name)
 }
 
-// MetricMutation represents an operation that mutates the Metric nodes in the graph.
-type MetricMutation struct {
+// SharedBucketMutation represents an operation that mutates the SharedBucket nodes in the graph.
+type SharedBucketMutation struct {
 	config
-	op             Op
-	typ            string
-	id             *int
-	generated_type *metric.GeneratedType
-	generated_by   *string
-	received_at    *time.Time
-	pushed_at      *time.Time
-	payload        *string
-	clearedFields  map[string]struct{}
-	done           bool
-	oldValue       func(context.Context) (*Metric, error)
-	predicates     []predicate.Metric
+	op            Op
+	typ           string
+	id            *int
+	key           *string
+	level         *float64
+	addlevel      *float64
+	updated_at    *time.Time
+	empty_at      *time.Time
+	revision      *int64
+	addrevision   *int64
+	clearedFields map[string]struct{}
+	done          bool
+	oldValue      func(context.Context) (*SharedBucket, error)
+	predicates    []predicate.SharedBucket
 }
 
-var _ ent.Mutation = (*MetricMutation)(nil)
+var _ ent.Mutation = (*SharedBucketMutation)(nil)
 
-// metricOption allows management of the mutation configuration using functional options.
-type metricOption func(*MetricMutation)
+// sharedbucketOption allows management of the mutation configuration using functional options.
+type sharedbucketOption func(*SharedBucketMutation)
 
-// newMetricMutation creates new mutation for the Metric entity.
-func newMetricMutation(c config, op Op, opts ...metricOption) *MetricMutation {
-	m := &MetricMutation{
+// newSharedBucketMutation creates new mutation for the SharedBucket entity.
+func newSharedBucketMutation(c config, op Op, opts ...sharedbucketOption) *SharedBucketMutation {
+	m := &SharedBucketMutation{
 		config:        c,
 		op:            op,
-		typ:           TypeMetric,
+		typ:           TypeSharedBucket,
 		clearedFields: make(map[string]struct{}),
 	}
 	for _, opt := range opts {
@@ -10670,20 +11859,20 @@ func newMetricMutation(c config, op Op, opts ...metricOption) *MetricMutation {
 	return m
 }
 
-// withMetricID sets the ID field of the mutation.
-func withMetricID(id int) metricOption {
-	return func(m *MetricMutation) {
+// withSharedBucketID sets the ID field of the mutation.
+func withSharedBucketID(id int) sharedbucketOption {
+	return func(m *SharedBucketMutation) {
 		var (
 			err   error
 			once  sync.Once
-			value *Metric
+			value *SharedBucket
 		)
-		m.oldValue = func(ctx context.Context) (*Metric, error) {
+		m.oldValue = func(ctx context.Context) (*SharedBucket, error) {
 			once.Do(func() {
 				if m.done {
 					err = errors.New("querying old values post mutation is not allowed")
 				} else {
-					value, err = m.Client().Metric.Get(ctx, id)
+					value, err = m.Client().SharedBucket.Get(ctx, id)
 				}
 			})
 			return value, err
@@ -10692,10 +11881,10 @@ func withMetricID(id int) metricOption {
 	}
 }
 
-// withMetric sets the old Metric of the mutation.
-func withMetric(node *Metric) metricOption {
-	return func(m *MetricMutation) {
-		m.oldValue = func(context.Context) (*Metric, error) {
+// withSharedBucket sets the old SharedBucket of the mutation.
+func withSharedBucket(node *SharedBucket) sharedbucketOption {
+	return func(m *SharedBucketMutation) {
+		m.oldValue = func(context.Context) (*SharedBucket, error) {
 			return node, nil
 		}
 		m.id = &node.ID
@@ -10704,7 +11893,7 @@ func withMetric(node *Metric) metricOption {
 
 // Client returns a new `ent.Client` from the mutation. If the mutation was
 // executed in a transaction (ent.Tx), a transactional client is returned.
-func (m MetricMutation) Client() *Client {
+func (m SharedBucketMutation) Client() *Client {
 	client := &Client{config: m.config}
 	client.init()
 	return client
@@ -10712,7 +11901,7 @@ func (m MetricMutation) Client() *Client {
 
 // Tx returns an `ent.Tx` for mutations that were executed in transactions;
 // it returns an error otherwise.
-func (m MetricMutation) Tx() (*Tx, error) {
+func (m SharedBucketMutation) Tx() (*Tx, error) {
 	if _, ok := m.driver.(*txDriver); !ok {
 		return nil, errors.New("ent: mutation is not running in a transaction")
 	}
@@ -10723,7 +11912,7 @@ func (m MetricMutation) Tx() (*Tx, error) {
 
 // ID returns the ID value in the mutation. Note that the ID is only available
 // if it was provided to the builder or after it was returned from the database.
-func (m *MetricMutation) ID() (id int, exists bool) {
+func (m *SharedBucketMutation) ID() (id int, exists bool) {
 	if m.id == nil {
 		return
 	}
@@ -10734,7 +11923,7 @@ func (m *MetricMutation) ID() (id int, exists bool) {
 // That means, if the mutation is applied within a transaction with an isolation level such
 // as sql.LevelSerializable, the returned ids match the ids of the rows that will be updated
 // or updated by the mutation.
-func (m *MetricMutation) IDs(ctx context.Context) ([]int, error) {
+func (m *SharedBucketMutation) IDs(ctx context.Context) ([]int, error) {
 	switch {
 	case m.op.Is(OpUpdateOne | OpDeleteOne):
 		id, exists := m.ID()
@@ -10743,214 +11932,241 @@ func (m *MetricMutation) IDs(ctx context.Context) ([]int, error) {
 		}
 		fallthrough
 	case m.op.Is(OpUpdate | OpDelete):
-		return m.Client().Metric.Query().Where(m.predicates...).IDs(ctx)
+		return m.Client().SharedBucket.Query().Where(m.predicates...).IDs(ctx)
 	default:
 		return nil, fmt.Errorf("IDs is not allowed on %s operations", m.op)
 	}
 }
 
-// SetGeneratedType sets the "generated_type" field.
-func (m *MetricMutation) SetGeneratedType(mt metric.GeneratedType) {
-	m.generated_type = &mt
+// SetKey sets the "key" field.
+func (m *SharedBucketMutation) SetKey(s string) {
+	m.key = &s
 }
 
-// GeneratedType returns the value of the "generated_type" field in the mutation.
-func (m *MetricMutation) GeneratedType() (r metric.GeneratedType, exists bool) {
-	v := m.generated_type
+// Key returns the value of the "key" field in the mutation.
+func (m *SharedBucketMutation) Key() (r string, exists bool) {
+	v := m.key
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldGeneratedType returns the old "generated_type" field's value of the Metric entity.
-// If the Metric object wasn't provided to the builder, the object is fetched from the database.
+// OldKey returns the old "key" field's value of the SharedBucket entity.
+// If the SharedBucket object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *MetricMutation) OldGeneratedType(ctx context.Context) (v metric.GeneratedType, err error) {
+func (m *SharedBucketMutation) OldKey(ctx context.Context) (v string, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldGeneratedType is only allowed on UpdateOne operations")
+		return v, errors.New("OldKey is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldGeneratedType requires an ID field in the mutation")
+		return v, errors.New("OldKey requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldGeneratedType: %w", err)
+		return v, fmt.Errorf("querying old value for OldKey: %w", err)
 	}
-	return oldValue.GeneratedType, nil
+	return oldValue.Key, nil
 }
 
-// ResetGeneratedType resets all changes to the "generated_type" field.
-func (m *MetricMutation) ResetGeneratedType() {
-	m.generated_type = nil
+// ResetKey resets all changes to the "key" field.
+func (m *SharedBucketMutation) ResetKey() {
+	m.key = nil
 }
 
-// SetGeneratedBy sets the "generated_by" field.
-func (m *MetricMutation) SetGeneratedBy(s string) {
-	m.generated_by = &s
+// SetLevel sets the "level" field.
+func (m *SharedBucketMutation) SetLevel(f float64) {
+	m.level = &f
+	m.addlevel = nil
 }
 
-// GeneratedBy returns the value of the "generated_by" field in the mutation.
-func (m *MetricMutation) GeneratedBy() (r string, exists bool) {
-	v := m.generated_by
+// Level returns the value of the "level" field in the mutation.
+func (m *SharedBucketMutation) Level() (r float64, exists bool) {
+	v := m.level
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldGeneratedBy returns the old "generated_by" field's value of the Metric entity.
-// If the Metric object wasn't provided to the builder, the object is fetched from the database.
+// OldLevel returns the old "level" field's value of the SharedBucket entity.
+// If the SharedBucket object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *MetricMutation) OldGeneratedBy(ctx context.Context) (v string, err error) {
+func (m *SharedBucketMutation) OldLevel(ctx context.Context) (v float64, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldGeneratedBy is only allowed on UpdateOne operations")
+		return v, errors.New("OldLevel is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldGeneratedBy requires an ID field in the mutation")
+		return v, errors.New("OldLevel requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldGeneratedBy: %w", err)
+		return v, fmt.Errorf("querying old value for OldLevel: %w", err)
 	}
-	return oldValue.GeneratedBy, nil
+	return oldValue.Level, nil
 }
 
-// ResetGeneratedBy resets all changes to the "generated_by" field.
-func (m *MetricMutation) ResetGeneratedBy() {
-	m.generated_by = nil
+// AddLevel adds f to the "level" field.
+func (m *SharedBucketMutation) AddLevel(f float64) {
+	if m.addlevel != nil {
+		*m.addlevel += f
+	} else {
+		m.addlevel = &f
+	}
 }
 
-// SetReceivedAt sets the "received_at" field.
-func (m *MetricMutation) SetReceivedAt(t time.Time) {
-	m.received_at = &t
+// AddedLevel returns the value that was added to the "level" field in this mutation.
+func (m *SharedBucketMutation) AddedLevel() (r float64, exists bool) {
+	v := m.addlevel
+	if v == nil {
+		return
+	}
+	return *v, true
 }
 
-// ReceivedAt returns the value of the "received_at" field in the mutation.
-func (m *MetricMutation) ReceivedAt() (r time.Time, exists bool) {
-	v := m.received_at
+// ResetLevel resets all changes to the "level" field.
+func (m *SharedBucketMutation) ResetLevel() {
+	m.level = nil
+	m.addlevel = nil
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (m *SharedBucketMutation) SetUpdatedAt(t time.Time) {
+	m.updated_at = &t
+}
+
+// UpdatedAt returns the value of the "updated_at" field in the mutation.
+func (m *SharedBucketMutation) UpdatedAt() (r time.Time, exists bool) {
+	v := m.updated_at
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldReceivedAt returns the old "received_at" field's value of the Metric entity.
-// If the Metric object wasn't provided to the builder, the object is fetched from the database.
+// OldUpdatedAt returns the old "updated_at" field's value of the SharedBucket entity.
+// If the SharedBucket object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *MetricMutation) OldReceivedAt(ctx context.Context) (v time.Time, err error) {
+func (m *SharedBucketMutation) OldUpdatedAt(ctx context.Context) (v time.Time, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldReceivedAt is only allowed on UpdateOne operations")
+		return v, errors.New("OldUpdatedAt is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldReceivedAt requires an ID field in the mutation")
+		return v, errors.New("OldUpdatedAt requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldReceivedAt: %w", err)
+		return v, fmt.Errorf("querying old value for OldUpdatedAt: %w", err)
 	}
-	return oldValue.ReceivedAt, nil
+	return oldValue.UpdatedAt, nil
 }
 
-// ResetReceivedAt resets all changes to the "received_at" field.
-func (m *MetricMutation) ResetReceivedAt() {
-	m.received_at = nil
+// ResetUpdatedAt resets all changes to the "updated_at" field.
+func (m *SharedBucketMutation) ResetUpdatedAt() {
+	m.updated_at = nil
 }
 
-// SetPushedAt sets the "pushed_at" field.
-func (m *MetricMutation) SetPushedAt(t time.Time) {
-	m.pushed_at = &t
+// SetEmptyAt sets the "empty_at" field.
+func (m *SharedBucketMutation) SetEmptyAt(t time.Time) {
+	m.empty_at = &t
 }
 
-// PushedAt returns the value of the "pushed_at" field in the mutation.
-func (m *MetricMutation) PushedAt() (r time.Time, exists bool) {
-	v := m.pushed_at
+// EmptyAt returns the value of the "empty_at" field in the mutation.
+func (m *SharedBucketMutation) EmptyAt() (r time.Time, exists bool) {
+	v := m.empty_at
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldPushedAt returns the old "pushed_at" field's value of the Metric entity.
-// If the Metric object wasn't provided to the builder, the object is fetched from the database.
+// OldEmptyAt returns the old "empty_at" field's value of the SharedBucket entity.
+// If the SharedBucket object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *MetricMutation) OldPushedAt(ctx context.Context) (v *time.Time, err error) {
+func (m *SharedBucketMutation) OldEmptyAt(ctx context.Context) (v time.Time, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldPushedAt is only allowed on UpdateOne operations")
+		return v, errors.New("OldEmptyAt is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldPushedAt requires an ID field in the mutation")
+		return v, errors.New("OldEmptyAt requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldPushedAt: %w", err)
+		return v, fmt.Errorf("querying old value for OldEmptyAt: %w", err)
 	}
-	return oldValue.PushedAt, nil
-}
-
-// ClearPushedAt clears the value of the "pushed_at" field.
-func (m *MetricMutation) ClearPushedAt() {
-	m.pushed_at = nil
-	m.clearedFields[metric.FieldPushedAt] = struct{}{}
-}
-
-// PushedAtCleared returns if the "pushed_at" field was cleared in this mutation.
-func (m *MetricMutation) PushedAtCleared() bool {
-	_, ok := m.clearedFields[metric.FieldPushedAt]
-	return ok
+	return oldValue.EmptyAt, nil
 }
 
-// ResetPushedAt resets all changes to the "pushed_at" field.
-func (m *MetricMutation) ResetPushedAt() {
-	m.pushed_at = nil
-	delete(m.clearedFields, metric.FieldPushedAt)
+// ResetEmptyAt resets all changes to the "empty_at" field.
+func (m *SharedBucketMutation) ResetEmptyAt() {
+	m.empty_at = nil
 }
 
-// SetPayload sets the "payload" field.
-func (m *MetricMutation) SetPayload(s string) {
-	m.payload = &s
+// SetRevision sets the "revision" field.
+func (m *SharedBucketMutation) SetRevision(i int64) {
+	m.revision = &i
+	m.addrevision = nil
 }
 
-// Payload returns the value of the "payload" field in the mutation.
-func (m *MetricMutation) Payload() (r string, exists bool) {
-	v := m.payload
+// Revision returns the value of the "revision" field in the mutation.
+func (m *SharedBucketMutation) Revision() (r int64, exists bool) {
+	v := m.revision
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldPayload returns the old "payload" field's value of the Metric entity.
-// If the Metric object wasn't provided to the builder, the object is fetched from the database.
+// OldRevision returns the old "revision" field's value of the SharedBucket entity.
+// If the SharedBucket object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *MetricMutation) OldPayload(ctx context.Context) (v string, err error) {
+func (m *SharedBucketMutation) OldRevision(ctx context.Context) (v int64, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldPayload is only allowed on UpdateOne operations")
+		return v, errors.New("OldRevision is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldPayload requires an ID field in the mutation")
+		return v, errors.New("OldRevision requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldPayload: %w", err)
+		return v, fmt.Errorf("querying old value for OldRevision: %w", err)
 	}
-	return oldValue.Payload, nil
+	return oldValue.Revision, nil
 }
 
-// ResetPayload resets all changes to the "payload" field.
-func (m *MetricMutation) ResetPayload() {
-	m.payload = nil
+// AddRevision adds i to the "revision" field.
+func (m *SharedBucketMutation) AddRevision(i int64) {
+	if m.addrevision != nil {
+		*m.addrevision += i
+	} else {
+		m.addrevision = &i
+	}
 }
 
-// Where appends a list predicates to the MetricMutation builder.
-func (m *MetricMutation) Where(ps ...predicate.Metric) {
+// AddedRevision returns the value that was added to the "revision" field in this mutation.
+func (m *SharedBucketMutation) AddedRevision() (r int64, exists bool) {
+	v := m.addrevision
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetRevision resets all changes to the "revision" field.
+func (m *SharedBucketMutation) ResetRevision() {
+	m.revision = nil
+	m.addrevision = nil
+}
+
+// Where appends a list predicates to the SharedBucketMutation builder.
+func (m *SharedBucketMutation) Where(ps ...predicate.SharedBucket) {
 	m.predicates = append(m.predicates, ps...)
 }
 
-// WhereP appends storage-level predicates to the MetricMutation builder. Using this method,
+// WhereP appends storage-level predicates to the SharedBucketMutation builder. Using this method,
 // users can use type-assertion to append predicates that do not depend on any generated package.
-func (m *MetricMutation) WhereP(ps ...func(*sql.Selector)) {
-	p := make([]predicate.Metric, len(ps))
+func (m *SharedBucketMutation) WhereP(ps ...func(*sql.Selector)) {
+	p := make([]predicate.SharedBucket, len(ps))
 	for i := range ps {
 		p[i] = ps[i]
 	}
@@ -10958,39 +12174,39 @@ func (m *MetricMutation) WhereP(ps ...func(*sql.Selector)) {
 }
 
 // Op returns the operation name.
-func (m *MetricMutation) Op() Op {
+func (m *SharedBucketMutation) Op() Op {
 	return m.op
 }
 
 // SetOp allows setting the mutation operation.
-func (m *MetricMutation) SetOp(op Op) {
+func (m *SharedBucketMutation) SetOp(op Op) {
 	m.op = op
 }
 
-// Type returns the node type of this mutation (Metric).
-func (m *MetricMutation) Type() string {
+// Type returns the node type of this mutation (SharedBucket).
+func (m *SharedBucketMutation) Type() string {
 	return m.typ
 }
 
 // Fields returns all fields that were changed during this mutation. Note that in
 // order to get all numeric fields that were incremented/decremented, call
 // AddedFields().
-func (m *MetricMutation) Fields() []string {
+func (m *SharedBucketMutation) Fields() []string {
 	fields := make([]string, 0, 5)
-	if m.generated_type != nil {
-		fields = append(fields, metric.FieldGeneratedType)
+	if m.key != nil {
+		fields = append(fields, sharedbucket.FieldKey)
 	}
-	if m.generated_by != nil {
-		fields = append(fields, metric.FieldGeneratedBy)
+	if m.level != nil {
+		fields = append(fields, sharedbucket.FieldLevel)
 	}
-	if m.received_at != nil {
-		fields = append(fields, metric.FieldReceivedAt)
+	if m.updated_at != nil {
+		fields = append(fields, sharedbucket.FieldUpdatedAt)
 	}
-	if m.pushed_at != nil {
-		fields = append(fields, metric.FieldPushedAt)
+	if m.empty_at != nil {
+		fields = append(fields, sharedbucket.FieldEmptyAt)
 	}
-	if m.payload != nil {
-		fields = append(fields, metric.FieldPayload)
+	if m.revision != nil {
+		fields = append(fields, sharedbucket.FieldRevision)
 	}
 	return fields
 }
@@ -10998,18 +12214,18 @@ func (m *MetricMutation) Fields() []string {
 // Field returns the value of a field with the given name. The second boolean
 // return value indicates that this field was not set, or was not defined in the
 // schema.
-func (m *MetricMutation) Field(name string) (ent.Value, bool) {
+func (m *SharedBucketMutation) Field(name string) (ent.Value, bool) {
 	switch name {
-	case metric.FieldGeneratedType:
-		return m.GeneratedType()
-	case metric.FieldGeneratedBy:
-		return m.GeneratedBy()
-	case metric.FieldReceivedAt:
-		return m.ReceivedAt()
-	case metric.FieldPushedAt:
-		return m.PushedAt()
-	case metric.FieldPayload:
-		return m.Payload()
+	case sharedbucket.FieldKey:
+		return m.Key()
+	case sharedbucket.FieldLevel:
+		return m.Level()
+	case sharedbucket.FieldUpdatedAt:
+		return m.UpdatedAt()
+	case sharedbucket.FieldEmptyAt:
+		return m.EmptyAt()
+	case sharedbucket.FieldRevision:
+		return m.Revision()
 	}
 	return nil, false
 }
@@ -11017,183 +12233,201 @@ func (m *MetricMutation) Field(name string) (ent.Value, bool) {
 // OldField returns the old value of the field from the database. An error is
 // returned if the mutation operation is not UpdateOne, or the query to the
 // database failed.
-func (m *MetricMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
+func (m *SharedBucketMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
 	switch name {
-	case metric.FieldGeneratedType:
-		return m.OldGeneratedType(ctx)
-	case metric.FieldGeneratedBy:
-		return m.OldGeneratedBy(ctx)
-	case metric.FieldReceivedAt:
-		return m.OldReceivedAt(ctx)
-	case metric.FieldPushedAt:
-		return m.OldPushedAt(ctx)
-	case metric.FieldPayload:
-		return m.OldPayload(ctx)
+	case sharedbucket.FieldKey:
+		return m.OldKey(ctx)
+	case sharedbucket.FieldLevel:
+		return m.OldLevel(ctx)
+	case sharedbucket.FieldUpdatedAt:
+		return m.OldUpdatedAt(ctx)
+	case sharedbucket.FieldEmptyAt:
+		return m.OldEmptyAt(ctx)
+	case sharedbucket.FieldRevision:
+		return m.OldRevision(ctx)
 	}
-	return nil, fmt.Errorf("unknown Metric field %s", name)
+	return nil, fmt.Errorf("unknown SharedBucket field %s", name)
 }
 
 // SetField sets the value of a field with the given name. It returns an error if
 // the field is not defined in the schema, or if the type mismatched the field
 // type.
-func (m *MetricMutation) SetField(name string, value ent.Value) error {
+func (m *SharedBucketMutation) SetField(name string, value ent.Value) error {
 	switch name {
-	case metric.FieldGeneratedType:
-		v, ok := value.(metric.GeneratedType)
+	case sharedbucket.FieldKey:
+		v, ok := value.(string)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetGeneratedType(v)
+		m.SetKey(v)
 		return nil
-	case metric.FieldGeneratedBy:
-		v, ok := value.(string)
+	case sharedbucket.FieldLevel:
+		v, ok := value.(float64)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetGeneratedBy(v)
+		m.SetLevel(v)
 		return nil
-	case metric.FieldReceivedAt:
+	case sharedbucket.FieldUpdatedAt:
 		v, ok := value.(time.Time)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetReceivedAt(v)
+		m.SetUpdatedAt(v)
 		return nil
-	case metric.FieldPushedAt:
+	case sharedbucket.FieldEmptyAt:
 		v, ok := value.(time.Time)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetPushedAt(v)
+		m.SetEmptyAt(v)
 		return nil
-	case metric.FieldPayload:
-		v, ok := value.(string)
+	case sharedbucket.FieldRevision:
+		v, ok := value.(int64)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetPayload(v)
+		m.SetRevision(v)
 		return nil
 	}
-	return fmt.Errorf("unknown Metric field %s", name)
+	return fmt.Errorf("unknown SharedBucket field %s", name)
 }
 
 // AddedFields returns all numeric fields that were incremented/decremented during
 // this mutation.
-func (m *MetricMutation) AddedFields() []string {
-	return nil
+func (m *SharedBucketMutation) AddedFields() []string {
+	var fields []string
+	if m.addlevel != nil {
+		fields = append(fields, sharedbucket.FieldLevel)
+	}
+	if m.addrevision != nil {
+		fields = append(fields, sharedbucket.FieldRevision)
+	}
+	return fields
 }
 
 // AddedField returns the numeric value that was incremented/decremented on a field
 // with the given name. The second boolean return value indicates that this field
 // was not set, or was not defined in the schema.
-func (m *MetricMutation) AddedField(name string) (ent.Value, bool) {
+func (m *SharedBucketMutation) AddedField(name string) (ent.Value, bool) {
+	switch name {
+	case sharedbucket.FieldLevel:
+		return m.AddedLevel()
+	case sharedbucket.FieldRevision:
+		return m.AddedRevision()
+	}
 	return nil, false
 }
 
 // AddField adds the value to the field with the given name. It returns an error if
 // the field is not defined in the schema, or if the type mismatched the field
 // type.
-func (m *MetricMutation) AddField(name string, value ent.Value) error {
+func (m *SharedBucketMutation) AddField(name string, value ent.Value) error {
 	switch name {
+	case sharedbucket.FieldLevel:
+		v, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddLevel(v)
+		return nil
+	case sharedbucket.FieldRevision:
+		v, ok := value.(int64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddRevision(v)
+		return nil
 	}
-	return fmt.Errorf("unknown Metric numeric field %s", name)
+	return fmt.Errorf("unknown SharedBucket numeric field %s", name)
 }
 
 // ClearedFields returns all nullable fields that were cleared during this
 // mutation.
-func (m *MetricMutation) ClearedFields() []string {
-	var fields []string
-	if m.FieldCleared(metric.FieldPushedAt) {
-		fields = append(fields, metric.FieldPushedAt)
-	}
-	return fields
+func (m *SharedBucketMutation) ClearedFields() []string {
+	return nil
 }
 
 // FieldCleared returns a boolean indicating if a field with the given name was
 // cleared in this mutation.
-func (m *MetricMutation) FieldCleared(name string) bool {
+func (m *SharedBucketMutation) FieldCleared(name string) bool {
 	_, ok := m.clearedFields[name]
 	return ok
 }
 
 // ClearField clears the value of the field with the given name. It returns an
 // error if the field is not defined in the schema.
-func (m *MetricMutation) ClearField(name string) error {
-	switch name {
-	case metric.FieldPushedAt:
-		m.ClearPushedAt()
-		return nil
-	}
-	return fmt.Errorf("unknown Metric nullable field %s", name)
+func (m *SharedBucketMutation) ClearField(name string) error {
+	return fmt.Errorf("unknown SharedBucket nullable field %s", name)
 }
 
 // ResetField resets all changes in the mutation for the field with the given name.
 // It returns an error if the field is not defined in the schema.
-func (m *MetricMutation) ResetField(name string) error {
+func (m *SharedBucketMutation) ResetField(name string) error {
 	switch name {
-	case metric.FieldGeneratedType:
-		m.ResetGeneratedType()
+	case sharedbucket.FieldKey:
+		m.ResetKey()
 		return nil
-	case metric.FieldGeneratedBy:
-		m.ResetGeneratedBy()
+	case sharedbucket.FieldLevel:
+		m.ResetLevel()
 		return nil
-	case metric.FieldReceivedAt:
-		m.ResetReceivedAt()
+	case sharedbucket.FieldUpdatedAt:
+		m.ResetUpdatedAt()
 		return nil
-	case metric.FieldPushedAt:
-		m.ResetPushedAt()
+	case sharedbucket.FieldEmptyAt:
+		m.ResetEmptyAt()
 		return nil
-	case metric.FieldPayload:
-		m.ResetPayload()
+	case sharedbucket.FieldRevision:
+		m.ResetRevision()
 		return nil
 	}
-	return fmt.Errorf("unknown Metric field %s", name)
+	return fmt.Errorf("unknown SharedBucket field %s", name)
 }
 
 // AddedEdges returns all edge names that were set/added in this mutation.
-func (m *MetricMutation) AddedEdges() []string {
+func (m *SharedBucketMutation) AddedEdges() []string {
 	edges := make([]string, 0, 0)
 	return edges
 }
 
 // AddedIDs returns all IDs (to other nodes) that were added for the given edge
 // name in this mutation.
-func (m *MetricMutation) AddedIDs(name string) []ent.Value {
+func (m *SharedBucketMutation) AddedIDs(name string) []ent.Value {
 	return nil
 }
 
 // RemovedEdges returns all edge names that were removed in this mutation.
-func (m *MetricMutation) RemovedEdges() []string {
+func (m *SharedBucketMutation) RemovedEdges() []string {
 	edges := make([]string, 0, 0)
 	return edges
 }
 
 // RemovedIDs returns all IDs (to other nodes) that were removed for the edge with
 // the given name in this mutation.
-func (m *MetricMutation) RemovedIDs(name string) []ent.Value {
+func (m *SharedBucketMutation) RemovedIDs(name string) []ent.Value {
 	return nil
 }
 
 // ClearedEdges returns all edge names that were cleared in this mutation.
-func (m *MetricMutation) ClearedEdges() []string {
+func (m *SharedBucketMutation) ClearedEdges() []string {
 	edges := make([]string, 0, 0)
 	return edges
 }
 
 // EdgeCleared returns a boolean which indicates if the edge with the given name
 // was cleared in this mutation.
-func (m *MetricMutation) EdgeCleared(name string) bool {
+func (m *SharedBucketMutation) EdgeCleared(name string) bool {
 	return false
 }
 
 // ClearEdge clears the value of the edge with the given name. It returns an error
 // if that edge is not defined in the schema.
-func (m *MetricMutation) ClearEdge(name string) error {
-	return fmt.Errorf("unknown Metric unique edge %s", name)
+func (m *SharedBucketMutation) ClearEdge(name string) error {
+	return fmt.Errorf("unknown SharedBucket unique edge %s", name)
 }
 
 // ResetEdge resets all changes to the edge with the given name in this mutation.
 // It returns an error if the edge is not defined in the schema.
-func (m *MetricMutation) ResetEdge(name string) error {
-	return fmt.Errorf("unknown Metric edge %s", name)
+func (m *SharedBucketMutation) ResetEdge(name string) error {
+	return fmt.Errorf("unknown SharedBucket edge %s", name)
 }
@@ -24,6 +24,9 @@ type ConfigItem func(*sql.Selector)
 // Decision is the predicate function for decision builders.
 type Decision func(*sql.Selector)
 
+// DecisionChange is the predicate function for decisionchange builders.
+type DecisionChange func(*sql.Selector)
+
 // Event is the predicate function for event builders.
 type Event func(*sql.Selector)
 
@@ -38,3 +41,6 @@ type Meta func(*sql.Selector)
 
 // Metric is the predicate function for metric builders.
 type Metric func(*sql.Selector)
+
+// SharedBucket is the predicate function for sharedbucket builders.
+type SharedBucket func(*sql.Selector)
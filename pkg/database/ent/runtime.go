@@ -11,11 +11,13 @@ import (
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/bouncer"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/configitem"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/decision"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/decisionchange"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/event"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/lock"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/machine"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/meta"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/schema"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/sharedbucket"
 )
 
 // The init function reads all schema descriptors with runtime code
@@ -134,6 +136,12 @@ func init() {
 	decisionDescSimulated := decisionFields[13].Descriptor()
 	// decision.DefaultSimulated holds the default value on creation for the simulated field.
 	decision.DefaultSimulated = decisionDescSimulated.Default.(bool)
+	decisionchangeFields := schema.DecisionChange{}.Fields()
+	_ = decisionchangeFields
+	// decisionchangeDescCreatedAt is the schema descriptor for created_at field.
+	decisionchangeDescCreatedAt := decisionchangeFields[0].Descriptor()
+	// decisionchange.DefaultCreatedAt holds the default value on creation for the created_at field.
+	decisionchange.DefaultCreatedAt = decisionchangeDescCreatedAt.Default.(func() time.Time)
 	eventFields := schema.Event{}.Fields()
 	_ = eventFields
 	// eventDescCreatedAt is the schema descriptor for created_at field.
@@ -200,4 +208,10 @@ func init() {
 	metaDescValue := metaFields[3].Descriptor()
 	// meta.ValueValidator is a validator for the "value" field. It is called by the builders before save.
 	meta.ValueValidator = metaDescValue.Validators[0].(func(string) error)
+	sharedbucketFields := schema.SharedBucket{}.Fields()
+	_ = sharedbucketFields
+	// sharedbucketDescRevision is the schema descriptor for revision field.
+	sharedbucketDescRevision := sharedbucketFields[4].Descriptor()
+	// sharedbucket.DefaultRevision holds the default value on creation for the revision field.
+	sharedbucket.DefaultRevision = sharedbucketDescRevision.Default.(int64)
 }
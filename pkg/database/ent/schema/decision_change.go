@@ -0,0 +1,40 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+// DecisionChange is the change log of the decisions: its ID is the revision
+// that the bouncers pass to get the decisions added or deleted since their last pull.
+type DecisionChange struct {
+	ent.Schema
+}
+
+func (DecisionChange) Fields() []ent.Field {
+	return []ent.Field{
+		field.Time("created_at").
+			Default(types.UtcNow).
+			Immutable(),
+		field.Enum("type").
+			Values("new", "deleted").
+			Immutable().
+			Comment("new: the decision was added, deleted: the decision was expired before its end"),
+		field.Int("decision_id").
+			Immutable().
+			Comment("Not an edge, the decision can be deleted while the change is kept"),
+	}
+}
+
+func (DecisionChange) Edges() []ent.Edge {
+	return nil
+}
+
+func (DecisionChange) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("created_at"),
+	}
+}
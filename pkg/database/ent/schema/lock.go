@@ -15,6 +15,10 @@ func (Lock) Fields() []ent.Field {
 	return []ent.Field{
 		field.String("name").Unique().Immutable().StructTag(`json:"name"`),
 		field.Time("created_at").Default(types.UtcNow).StructTag(`json:"created_at"`).Immutable(),
+		field.String("owner").Optional().StructTag(`json:"owner,omitempty"`).
+			Comment("The LAPI instance holding the lease, empty for the other locks"),
+		field.Time("expires_at").Optional().Nillable().StructTag(`json:"expires_at,omitempty"`).
+			Comment("End of the lease, if the owner does not renew it"),
 	}
 }
 
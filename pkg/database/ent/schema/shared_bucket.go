@@ -0,0 +1,36 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// SharedBucket holds the level of a bucket shared by the log processors, so that
+// all the LAPI instances using the same database aggregate the same pours.
+type SharedBucket struct {
+	ent.Schema
+}
+
+func (SharedBucket) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("key").Unique().Immutable().StructTag(`json:"key"`),
+		field.Float("level").StructTag(`json:"level"`).
+			Comment("Level of the bucket at updated_at, before leaking"),
+		field.Time("updated_at").StructTag(`json:"updated_at"`),
+		field.Time("empty_at").StructTag(`json:"empty_at"`).
+			Comment("Time at which the bucket is fully leaked and can be deleted"),
+		field.Int64("revision").Default(0).StructTag(`json:"revision"`).
+			Comment("Incremented on every pour, to detect concurrent updates"),
+	}
+}
+
+func (SharedBucket) Edges() []ent.Edge {
+	return nil
+}
+
+func (SharedBucket) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("empty_at"),
+	}
+}
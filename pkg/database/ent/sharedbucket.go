@@ -0,0 +1,152 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/sharedbucket"
+)
+
+// SharedBucket is the model entity for the SharedBucket schema.
+type SharedBucket struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int `json:"id,omitempty"`
+	// Key holds the value of the "key" field.
+	Key string `json:"key"`
+	// Level of the bucket at updated_at, before leaking
+	Level float64 `json:"level"`
+	// UpdatedAt holds the value of the "updated_at" field.
+	UpdatedAt time.Time `json:"updated_at"`
+	// Time at which the bucket is fully leaked and can be deleted
+	EmptyAt time.Time `json:"empty_at"`
+	// Incremented on every pour, to detect concurrent updates
+	Revision     int64 `json:"revision"`
+	selectValues sql.SelectValues
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*SharedBucket) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case sharedbucket.FieldLevel:
+			values[i] = new(sql.NullFloat64)
+		case sharedbucket.FieldID, sharedbucket.FieldRevision:
+			values[i] = new(sql.NullInt64)
+		case sharedbucket.FieldKey:
+			values[i] = new(sql.NullString)
+		case sharedbucket.FieldUpdatedAt, sharedbucket.FieldEmptyAt:
+			values[i] = new(sql.NullTime)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the SharedBucket fields.
+func (sb *SharedBucket) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case sharedbucket.FieldID:
+			value, ok := values[i].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			sb.ID = int(value.Int64)
+		case sharedbucket.FieldKey:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field key", values[i])
+			} else if value.Valid {
+				sb.Key = value.String
+			}
+		case sharedbucket.FieldLevel:
+			if value, ok := values[i].(*sql.NullFloat64); !ok {
+				return fmt.Errorf("unexpected type %T for field level", values[i])
+			} else if value.Valid {
+				sb.Level = value.Float64
+			}
+		case sharedbucket.FieldUpdatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field updated_at", values[i])
+			} else if value.Valid {
+				sb.UpdatedAt = value.Time
+			}
+		case sharedbucket.FieldEmptyAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field empty_at", values[i])
+			} else if value.Valid {
+				sb.EmptyAt = value.Time
+			}
+		case sharedbucket.FieldRevision:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field revision", values[i])
+			} else if value.Valid {
+				sb.Revision = value.Int64
+			}
+		default:
+			sb.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the SharedBucket.
+// This includes values selected through modifiers, order, etc.
+func (sb *SharedBucket) Value(name string) (ent.Value, error) {
+	return sb.selectValues.Get(name)
+}
+
+// Update returns a builder for updating this SharedBucket.
+// Note that you need to call SharedBucket.Unwrap() before calling this method if this SharedBucket
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (sb *SharedBucket) Update() *SharedBucketUpdateOne {
+	return NewSharedBucketClient(sb.config).UpdateOne(sb)
+}
+
+// Unwrap unwraps the SharedBucket entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (sb *SharedBucket) Unwrap() *SharedBucket {
+	_tx, ok := sb.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: SharedBucket is not a transactional entity")
+	}
+	sb.config.driver = _tx.drv
+	return sb
+}
+
+// String implements the fmt.Stringer.
+func (sb *SharedBucket) String() string {
+	var builder strings.Builder
+	builder.WriteString("SharedBucket(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", sb.ID))
+	builder.WriteString("key=")
+	builder.WriteString(sb.Key)
+	builder.WriteString(", ")
+	builder.WriteString("level=")
+	builder.WriteString(fmt.Sprintf("%v", sb.Level))
+	builder.WriteString(", ")
+	builder.WriteString("updated_at=")
+	builder.WriteString(sb.UpdatedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	builder.WriteString("empty_at=")
+	builder.WriteString(sb.EmptyAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	builder.WriteString("revision=")
+	builder.WriteString(fmt.Sprintf("%v", sb.Revision))
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// SharedBuckets is a parsable slice of SharedBucket.
+type SharedBuckets []*SharedBucket
@@ -0,0 +1,320 @@
+// Code generated by ent, DO NOT EDIT.
+
+package sharedbucket
+
+import (
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/predicate"
+)
+
+// ID filters vertices based on their ID field.
+func ID(id int) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldEQ(FieldID, id))
+}
+
+// IDEQ applies the EQ predicate on the ID field.
+func IDEQ(id int) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldEQ(FieldID, id))
+}
+
+// IDNEQ applies the NEQ predicate on the ID field.
+func IDNEQ(id int) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldNEQ(FieldID, id))
+}
+
+// IDIn applies the In predicate on the ID field.
+func IDIn(ids ...int) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldIn(FieldID, ids...))
+}
+
+// IDNotIn applies the NotIn predicate on the ID field.
+func IDNotIn(ids ...int) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldNotIn(FieldID, ids...))
+}
+
+// IDGT applies the GT predicate on the ID field.
+func IDGT(id int) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldGT(FieldID, id))
+}
+
+// IDGTE applies the GTE predicate on the ID field.
+func IDGTE(id int) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldGTE(FieldID, id))
+}
+
+// IDLT applies the LT predicate on the ID field.
+func IDLT(id int) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldLT(FieldID, id))
+}
+
+// IDLTE applies the LTE predicate on the ID field.
+func IDLTE(id int) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldLTE(FieldID, id))
+}
+
+// Key applies equality check predicate on the "key" field. It's identical to KeyEQ.
+func Key(v string) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldEQ(FieldKey, v))
+}
+
+// Level applies equality check predicate on the "level" field. It's identical to LevelEQ.
+func Level(v float64) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldEQ(FieldLevel, v))
+}
+
+// UpdatedAt applies equality check predicate on the "updated_at" field. It's identical to UpdatedAtEQ.
+func UpdatedAt(v time.Time) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldEQ(FieldUpdatedAt, v))
+}
+
+// EmptyAt applies equality check predicate on the "empty_at" field. It's identical to EmptyAtEQ.
+func EmptyAt(v time.Time) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldEQ(FieldEmptyAt, v))
+}
+
+// Revision applies equality check predicate on the "revision" field. It's identical to RevisionEQ.
+func Revision(v int64) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldEQ(FieldRevision, v))
+}
+
+// KeyEQ applies the EQ predicate on the "key" field.
+func KeyEQ(v string) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldEQ(FieldKey, v))
+}
+
+// KeyNEQ applies the NEQ predicate on the "key" field.
+func KeyNEQ(v string) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldNEQ(FieldKey, v))
+}
+
+// KeyIn applies the In predicate on the "key" field.
+func KeyIn(vs ...string) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldIn(FieldKey, vs...))
+}
+
+// KeyNotIn applies the NotIn predicate on the "key" field.
+func KeyNotIn(vs ...string) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldNotIn(FieldKey, vs...))
+}
+
+// KeyGT applies the GT predicate on the "key" field.
+func KeyGT(v string) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldGT(FieldKey, v))
+}
+
+// KeyGTE applies the GTE predicate on the "key" field.
+func KeyGTE(v string) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldGTE(FieldKey, v))
+}
+
+// KeyLT applies the LT predicate on the "key" field.
+func KeyLT(v string) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldLT(FieldKey, v))
+}
+
+// KeyLTE applies the LTE predicate on the "key" field.
+func KeyLTE(v string) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldLTE(FieldKey, v))
+}
+
+// KeyContains applies the Contains predicate on the "key" field.
+func KeyContains(v string) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldContains(FieldKey, v))
+}
+
+// KeyHasPrefix applies the HasPrefix predicate on the "key" field.
+func KeyHasPrefix(v string) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldHasPrefix(FieldKey, v))
+}
+
+// KeyHasSuffix applies the HasSuffix predicate on the "key" field.
+func KeyHasSuffix(v string) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldHasSuffix(FieldKey, v))
+}
+
+// KeyEqualFold applies the EqualFold predicate on the "key" field.
+func KeyEqualFold(v string) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldEqualFold(FieldKey, v))
+}
+
+// KeyContainsFold applies the ContainsFold predicate on the "key" field.
+func KeyContainsFold(v string) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldContainsFold(FieldKey, v))
+}
+
+// LevelEQ applies the EQ predicate on the "level" field.
+func LevelEQ(v float64) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldEQ(FieldLevel, v))
+}
+
+// LevelNEQ applies the NEQ predicate on the "level" field.
+func LevelNEQ(v float64) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldNEQ(FieldLevel, v))
+}
+
+// LevelIn applies the In predicate on the "level" field.
+func LevelIn(vs ...float64) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldIn(FieldLevel, vs...))
+}
+
+// LevelNotIn applies the NotIn predicate on the "level" field.
+func LevelNotIn(vs ...float64) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldNotIn(FieldLevel, vs...))
+}
+
+// LevelGT applies the GT predicate on the "level" field.
+func LevelGT(v float64) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldGT(FieldLevel, v))
+}
+
+// LevelGTE applies the GTE predicate on the "level" field.
+func LevelGTE(v float64) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldGTE(FieldLevel, v))
+}
+
+// LevelLT applies the LT predicate on the "level" field.
+func LevelLT(v float64) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldLT(FieldLevel, v))
+}
+
+// LevelLTE applies the LTE predicate on the "level" field.
+func LevelLTE(v float64) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldLTE(FieldLevel, v))
+}
+
+// UpdatedAtEQ applies the EQ predicate on the "updated_at" field.
+func UpdatedAtEQ(v time.Time) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldEQ(FieldUpdatedAt, v))
+}
+
+// UpdatedAtNEQ applies the NEQ predicate on the "updated_at" field.
+func UpdatedAtNEQ(v time.Time) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldNEQ(FieldUpdatedAt, v))
+}
+
+// UpdatedAtIn applies the In predicate on the "updated_at" field.
+func UpdatedAtIn(vs ...time.Time) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldIn(FieldUpdatedAt, vs...))
+}
+
+// UpdatedAtNotIn applies the NotIn predicate on the "updated_at" field.
+func UpdatedAtNotIn(vs ...time.Time) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldNotIn(FieldUpdatedAt, vs...))
+}
+
+// UpdatedAtGT applies the GT predicate on the "updated_at" field.
+func UpdatedAtGT(v time.Time) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldGT(FieldUpdatedAt, v))
+}
+
+// UpdatedAtGTE applies the GTE predicate on the "updated_at" field.
+func UpdatedAtGTE(v time.Time) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldGTE(FieldUpdatedAt, v))
+}
+
+// UpdatedAtLT applies the LT predicate on the "updated_at" field.
+func UpdatedAtLT(v time.Time) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldLT(FieldUpdatedAt, v))
+}
+
+// UpdatedAtLTE applies the LTE predicate on the "updated_at" field.
+func UpdatedAtLTE(v time.Time) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldLTE(FieldUpdatedAt, v))
+}
+
+// EmptyAtEQ applies the EQ predicate on the "empty_at" field.
+func EmptyAtEQ(v time.Time) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldEQ(FieldEmptyAt, v))
+}
+
+// EmptyAtNEQ applies the NEQ predicate on the "empty_at" field.
+func EmptyAtNEQ(v time.Time) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldNEQ(FieldEmptyAt, v))
+}
+
+// EmptyAtIn applies the In predicate on the "empty_at" field.
+func EmptyAtIn(vs ...time.Time) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldIn(FieldEmptyAt, vs...))
+}
+
+// EmptyAtNotIn applies the NotIn predicate on the "empty_at" field.
+func EmptyAtNotIn(vs ...time.Time) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldNotIn(FieldEmptyAt, vs...))
+}
+
+// EmptyAtGT applies the GT predicate on the "empty_at" field.
+func EmptyAtGT(v time.Time) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldGT(FieldEmptyAt, v))
+}
+
+// EmptyAtGTE applies the GTE predicate on the "empty_at" field.
+func EmptyAtGTE(v time.Time) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldGTE(FieldEmptyAt, v))
+}
+
+// EmptyAtLT applies the LT predicate on the "empty_at" field.
+func EmptyAtLT(v time.Time) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldLT(FieldEmptyAt, v))
+}
+
+// EmptyAtLTE applies the LTE predicate on the "empty_at" field.
+func EmptyAtLTE(v time.Time) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldLTE(FieldEmptyAt, v))
+}
+
+// RevisionEQ applies the EQ predicate on the "revision" field.
+func RevisionEQ(v int64) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldEQ(FieldRevision, v))
+}
+
+// RevisionNEQ applies the NEQ predicate on the "revision" field.
+func RevisionNEQ(v int64) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldNEQ(FieldRevision, v))
+}
+
+// RevisionIn applies the In predicate on the "revision" field.
+func RevisionIn(vs ...int64) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldIn(FieldRevision, vs...))
+}
+
+// RevisionNotIn applies the NotIn predicate on the "revision" field.
+func RevisionNotIn(vs ...int64) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldNotIn(FieldRevision, vs...))
+}
+
+// RevisionGT applies the GT predicate on the "revision" field.
+func RevisionGT(v int64) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldGT(FieldRevision, v))
+}
+
+// RevisionGTE applies the GTE predicate on the "revision" field.
+func RevisionGTE(v int64) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldGTE(FieldRevision, v))
+}
+
+// RevisionLT applies the LT predicate on the "revision" field.
+func RevisionLT(v int64) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldLT(FieldRevision, v))
+}
+
+// RevisionLTE applies the LTE predicate on the "revision" field.
+func RevisionLTE(v int64) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.FieldLTE(FieldRevision, v))
+}
+
+// And groups predicates with the AND operator between them.
+func And(predicates ...predicate.SharedBucket) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.AndPredicates(predicates...))
+}
+
+// Or groups predicates with the OR operator between them.
+func Or(predicates ...predicate.SharedBucket) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.OrPredicates(predicates...))
+}
+
+// Not applies the not operator on the given predicate.
+func Not(p predicate.SharedBucket) predicate.SharedBucket {
+	return predicate.SharedBucket(sql.NotPredicates(p))
+}
@@ -0,0 +1,254 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/sharedbucket"
+)
+
+// SharedBucketCreate is the builder for creating a SharedBucket entity.
+type SharedBucketCreate struct {
+	config
+	mutation *SharedBucketMutation
+	hooks    []Hook
+}
+
+// SetKey sets the "key" field.
+func (sbc *SharedBucketCreate) SetKey(s string) *SharedBucketCreate {
+	sbc.mutation.SetKey(s)
+	return sbc
+}
+
+// SetLevel sets the "level" field.
+func (sbc *SharedBucketCreate) SetLevel(f float64) *SharedBucketCreate {
+	sbc.mutation.SetLevel(f)
+	return sbc
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (sbc *SharedBucketCreate) SetUpdatedAt(t time.Time) *SharedBucketCreate {
+	sbc.mutation.SetUpdatedAt(t)
+	return sbc
+}
+
+// SetEmptyAt sets the "empty_at" field.
+func (sbc *SharedBucketCreate) SetEmptyAt(t time.Time) *SharedBucketCreate {
+	sbc.mutation.SetEmptyAt(t)
+	return sbc
+}
+
+// SetRevision sets the "revision" field.
+func (sbc *SharedBucketCreate) SetRevision(i int64) *SharedBucketCreate {
+	sbc.mutation.SetRevision(i)
+	return sbc
+}
+
+// SetNillableRevision sets the "revision" field if the given value is not nil.
+func (sbc *SharedBucketCreate) SetNillableRevision(i *int64) *SharedBucketCreate {
+	if i != nil {
+		sbc.SetRevision(*i)
+	}
+	return sbc
+}
+
+// Mutation returns the SharedBucketMutation object of the builder.
+func (sbc *SharedBucketCreate) Mutation() *SharedBucketMutation {
+	return sbc.mutation
+}
+
+// Save creates the SharedBucket in the database.
+func (sbc *SharedBucketCreate) Save(ctx context.Context) (*SharedBucket, error) {
+	sbc.defaults()
+	return withHooks(ctx, sbc.sqlSave, sbc.mutation, sbc.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (sbc *SharedBucketCreate) SaveX(ctx context.Context) *SharedBucket {
+	v, err := sbc.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (sbc *SharedBucketCreate) Exec(ctx context.Context) error {
+	_, err := sbc.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (sbc *SharedBucketCreate) ExecX(ctx context.Context) {
+	if err := sbc.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (sbc *SharedBucketCreate) defaults() {
+	if _, ok := sbc.mutation.Revision(); !ok {
+		v := sharedbucket.DefaultRevision
+		sbc.mutation.SetRevision(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (sbc *SharedBucketCreate) check() error {
+	if _, ok := sbc.mutation.Key(); !ok {
+		return &ValidationError{Name: "key", err: errors.New(`ent: missing required field "SharedBucket.key"`)}
+	}
+	if _, ok := sbc.mutation.Level(); !ok {
+		return &ValidationError{Name: "level", err: errors.New(`ent: missing required field "SharedBucket.level"`)}
+	}
+	if _, ok := sbc.mutation.UpdatedAt(); !ok {
+		return &ValidationError{Name: "updated_at", err: errors.New(`ent: missing required field "SharedBucket.updated_at"`)}
+	}
+	if _, ok := sbc.mutation.EmptyAt(); !ok {
+		return &ValidationError{Name: "empty_at", err: errors.New(`ent: missing required field "SharedBucket.empty_at"`)}
+	}
+	if _, ok := sbc.mutation.Revision(); !ok {
+		return &ValidationError{Name: "revision", err: errors.New(`ent: missing required field "SharedBucket.revision"`)}
+	}
+	return nil
+}
+
+func (sbc *SharedBucketCreate) sqlSave(ctx context.Context) (*SharedBucket, error) {
+	if err := sbc.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := sbc.createSpec()
+	if err := sqlgraph.CreateNode(ctx, sbc.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	sbc.mutation.id = &_node.ID
+	sbc.mutation.done = true
+	return _node, nil
+}
+
+func (sbc *SharedBucketCreate) createSpec() (*SharedBucket, *sqlgraph.CreateSpec) {
+	var (
+		_node = &SharedBucket{config: sbc.config}
+		_spec = sqlgraph.NewCreateSpec(sharedbucket.Table, sqlgraph.NewFieldSpec(sharedbucket.FieldID, field.TypeInt))
+	)
+	if value, ok := sbc.mutation.Key(); ok {
+		_spec.SetField(sharedbucket.FieldKey, field.TypeString, value)
+		_node.Key = value
+	}
+	if value, ok := sbc.mutation.Level(); ok {
+		_spec.SetField(sharedbucket.FieldLevel, field.TypeFloat64, value)
+		_node.Level = value
+	}
+	if value, ok := sbc.mutation.UpdatedAt(); ok {
+		_spec.SetField(sharedbucket.FieldUpdatedAt, field.TypeTime, value)
+		_node.UpdatedAt = value
+	}
+	if value, ok := sbc.mutation.EmptyAt(); ok {
+		_spec.SetField(sharedbucket.FieldEmptyAt, field.TypeTime, value)
+		_node.EmptyAt = value
+	}
+	if value, ok := sbc.mutation.Revision(); ok {
+		_spec.SetField(sharedbucket.FieldRevision, field.TypeInt64, value)
+		_node.Revision = value
+	}
+	return _node, _spec
+}
+
+// SharedBucketCreateBulk is the builder for creating many SharedBucket entities in bulk.
+type SharedBucketCreateBulk struct {
+	config
+	err      error
+	builders []*SharedBucketCreate
+}
+
+// Save creates the SharedBucket entities in the database.
+func (sbcb *SharedBucketCreateBulk) Save(ctx context.Context) ([]*SharedBucket, error) {
+	if sbcb.err != nil {
+		return nil, sbcb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(sbcb.builders))
+	nodes := make([]*SharedBucket, len(sbcb.builders))
+	mutators := make([]Mutator, len(sbcb.builders))
+	for i := range sbcb.builders {
+		func(i int, root context.Context) {
+			builder := sbcb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*SharedBucketMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, sbcb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, sbcb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, sbcb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (sbcb *SharedBucketCreateBulk) SaveX(ctx context.Context) []*SharedBucket {
+	v, err := sbcb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (sbcb *SharedBucketCreateBulk) Exec(ctx context.Context) error {
+	_, err := sbcb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (sbcb *SharedBucketCreateBulk) ExecX(ctx context.Context) {
+	if err := sbcb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
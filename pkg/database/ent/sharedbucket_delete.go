@@ -0,0 +1,88 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/predicate"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/sharedbucket"
+)
+
+// SharedBucketDelete is the builder for deleting a SharedBucket entity.
+type SharedBucketDelete struct {
+	config
+	hooks    []Hook
+	mutation *SharedBucketMutation
+}
+
+// Where appends a list predicates to the SharedBucketDelete builder.
+func (sbd *SharedBucketDelete) Where(ps ...predicate.SharedBucket) *SharedBucketDelete {
+	sbd.mutation.Where(ps...)
+	return sbd
+}
+
+// Exec executes the deletion query and returns how many vertices were deleted.
+func (sbd *SharedBucketDelete) Exec(ctx context.Context) (int, error) {
+	return withHooks(ctx, sbd.sqlExec, sbd.mutation, sbd.hooks)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (sbd *SharedBucketDelete) ExecX(ctx context.Context) int {
+	n, err := sbd.Exec(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+func (sbd *SharedBucketDelete) sqlExec(ctx context.Context) (int, error) {
+	_spec := sqlgraph.NewDeleteSpec(sharedbucket.Table, sqlgraph.NewFieldSpec(sharedbucket.FieldID, field.TypeInt))
+	if ps := sbd.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	affected, err := sqlgraph.DeleteNodes(ctx, sbd.driver, _spec)
+	if err != nil && sqlgraph.IsConstraintError(err) {
+		err = &ConstraintError{msg: err.Error(), wrap: err}
+	}
+	sbd.mutation.done = true
+	return affected, err
+}
+
+// SharedBucketDeleteOne is the builder for deleting a single SharedBucket entity.
+type SharedBucketDeleteOne struct {
+	sbd *SharedBucketDelete
+}
+
+// Where appends a list predicates to the SharedBucketDelete builder.
+func (sbdo *SharedBucketDeleteOne) Where(ps ...predicate.SharedBucket) *SharedBucketDeleteOne {
+	sbdo.sbd.mutation.Where(ps...)
+	return sbdo
+}
+
+// Exec executes the deletion query.
+func (sbdo *SharedBucketDeleteOne) Exec(ctx context.Context) error {
+	n, err := sbdo.sbd.Exec(ctx)
+	switch {
+	case err != nil:
+		return err
+	case n == 0:
+		return &NotFoundError{sharedbucket.Label}
+	default:
+		return nil
+	}
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (sbdo *SharedBucketDeleteOne) ExecX(ctx context.Context) {
+	if err := sbdo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,352 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/predicate"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/sharedbucket"
+)
+
+// SharedBucketUpdate is the builder for updating SharedBucket entities.
+type SharedBucketUpdate struct {
+	config
+	hooks    []Hook
+	mutation *SharedBucketMutation
+}
+
+// Where appends a list predicates to the SharedBucketUpdate builder.
+func (sbu *SharedBucketUpdate) Where(ps ...predicate.SharedBucket) *SharedBucketUpdate {
+	sbu.mutation.Where(ps...)
+	return sbu
+}
+
+// SetLevel sets the "level" field.
+func (sbu *SharedBucketUpdate) SetLevel(f float64) *SharedBucketUpdate {
+	sbu.mutation.ResetLevel()
+	sbu.mutation.SetLevel(f)
+	return sbu
+}
+
+// SetNillableLevel sets the "level" field if the given value is not nil.
+func (sbu *SharedBucketUpdate) SetNillableLevel(f *float64) *SharedBucketUpdate {
+	if f != nil {
+		sbu.SetLevel(*f)
+	}
+	return sbu
+}
+
+// AddLevel adds f to the "level" field.
+func (sbu *SharedBucketUpdate) AddLevel(f float64) *SharedBucketUpdate {
+	sbu.mutation.AddLevel(f)
+	return sbu
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (sbu *SharedBucketUpdate) SetUpdatedAt(t time.Time) *SharedBucketUpdate {
+	sbu.mutation.SetUpdatedAt(t)
+	return sbu
+}
+
+// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
+func (sbu *SharedBucketUpdate) SetNillableUpdatedAt(t *time.Time) *SharedBucketUpdate {
+	if t != nil {
+		sbu.SetUpdatedAt(*t)
+	}
+	return sbu
+}
+
+// SetEmptyAt sets the "empty_at" field.
+func (sbu *SharedBucketUpdate) SetEmptyAt(t time.Time) *SharedBucketUpdate {
+	sbu.mutation.SetEmptyAt(t)
+	return sbu
+}
+
+// SetNillableEmptyAt sets the "empty_at" field if the given value is not nil.
+func (sbu *SharedBucketUpdate) SetNillableEmptyAt(t *time.Time) *SharedBucketUpdate {
+	if t != nil {
+		sbu.SetEmptyAt(*t)
+	}
+	return sbu
+}
+
+// SetRevision sets the "revision" field.
+func (sbu *SharedBucketUpdate) SetRevision(i int64) *SharedBucketUpdate {
+	sbu.mutation.ResetRevision()
+	sbu.mutation.SetRevision(i)
+	return sbu
+}
+
+// SetNillableRevision sets the "revision" field if the given value is not nil.
+func (sbu *SharedBucketUpdate) SetNillableRevision(i *int64) *SharedBucketUpdate {
+	if i != nil {
+		sbu.SetRevision(*i)
+	}
+	return sbu
+}
+
+// AddRevision adds i to the "revision" field.
+func (sbu *SharedBucketUpdate) AddRevision(i int64) *SharedBucketUpdate {
+	sbu.mutation.AddRevision(i)
+	return sbu
+}
+
+// Mutation returns the SharedBucketMutation object of the builder.
+func (sbu *SharedBucketUpdate) Mutation() *SharedBucketMutation {
+	return sbu.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (sbu *SharedBucketUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, sbu.sqlSave, sbu.mutation, sbu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (sbu *SharedBucketUpdate) SaveX(ctx context.Context) int {
+	affected, err := sbu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (sbu *SharedBucketUpdate) Exec(ctx context.Context) error {
+	_, err := sbu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (sbu *SharedBucketUpdate) ExecX(ctx context.Context) {
+	if err := sbu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+func (sbu *SharedBucketUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	_spec := sqlgraph.NewUpdateSpec(sharedbucket.Table, sharedbucket.Columns, sqlgraph.NewFieldSpec(sharedbucket.FieldID, field.TypeInt))
+	if ps := sbu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := sbu.mutation.Level(); ok {
+		_spec.SetField(sharedbucket.FieldLevel, field.TypeFloat64, value)
+	}
+	if value, ok := sbu.mutation.AddedLevel(); ok {
+		_spec.AddField(sharedbucket.FieldLevel, field.TypeFloat64, value)
+	}
+	if value, ok := sbu.mutation.UpdatedAt(); ok {
+		_spec.SetField(sharedbucket.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if value, ok := sbu.mutation.EmptyAt(); ok {
+		_spec.SetField(sharedbucket.FieldEmptyAt, field.TypeTime, value)
+	}
+	if value, ok := sbu.mutation.Revision(); ok {
+		_spec.SetField(sharedbucket.FieldRevision, field.TypeInt64, value)
+	}
+	if value, ok := sbu.mutation.AddedRevision(); ok {
+		_spec.AddField(sharedbucket.FieldRevision, field.TypeInt64, value)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, sbu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{sharedbucket.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	sbu.mutation.done = true
+	return n, nil
+}
+
+// SharedBucketUpdateOne is the builder for updating a single SharedBucket entity.
+type SharedBucketUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *SharedBucketMutation
+}
+
+// SetLevel sets the "level" field.
+func (sbuo *SharedBucketUpdateOne) SetLevel(f float64) *SharedBucketUpdateOne {
+	sbuo.mutation.ResetLevel()
+	sbuo.mutation.SetLevel(f)
+	return sbuo
+}
+
+// SetNillableLevel sets the "level" field if the given value is not nil.
+func (sbuo *SharedBucketUpdateOne) SetNillableLevel(f *float64) *SharedBucketUpdateOne {
+	if f != nil {
+		sbuo.SetLevel(*f)
+	}
+	return sbuo
+}
+
+// AddLevel adds f to the "level" field.
+func (sbuo *SharedBucketUpdateOne) AddLevel(f float64) *SharedBucketUpdateOne {
+	sbuo.mutation.AddLevel(f)
+	return sbuo
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (sbuo *SharedBucketUpdateOne) SetUpdatedAt(t time.Time) *SharedBucketUpdateOne {
+	sbuo.mutation.SetUpdatedAt(t)
+	return sbuo
+}
+
+// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
+func (sbuo *SharedBucketUpdateOne) SetNillableUpdatedAt(t *time.Time) *SharedBucketUpdateOne {
+	if t != nil {
+		sbuo.SetUpdatedAt(*t)
+	}
+	return sbuo
+}
+
+// SetEmptyAt sets the "empty_at" field.
+func (sbuo *SharedBucketUpdateOne) SetEmptyAt(t time.Time) *SharedBucketUpdateOne {
+	sbuo.mutation.SetEmptyAt(t)
+	return sbuo
+}
+
+// SetNillableEmptyAt sets the "empty_at" field if the given value is not nil.
+func (sbuo *SharedBucketUpdateOne) SetNillableEmptyAt(t *time.Time) *SharedBucketUpdateOne {
+	if t != nil {
+		sbuo.SetEmptyAt(*t)
+	}
+	return sbuo
+}
+
+// SetRevision sets the "revision" field.
+func (sbuo *SharedBucketUpdateOne) SetRevision(i int64) *SharedBucketUpdateOne {
+	sbuo.mutation.ResetRevision()
+	sbuo.mutation.SetRevision(i)
+	return sbuo
+}
+
+// SetNillableRevision sets the "revision" field if the given value is not nil.
+func (sbuo *SharedBucketUpdateOne) SetNillableRevision(i *int64) *SharedBucketUpdateOne {
+	if i != nil {
+		sbuo.SetRevision(*i)
+	}
+	return sbuo
+}
+
+// AddRevision adds i to the "revision" field.
+func (sbuo *SharedBucketUpdateOne) AddRevision(i int64) *SharedBucketUpdateOne {
+	sbuo.mutation.AddRevision(i)
+	return sbuo
+}
+
+// Mutation returns the SharedBucketMutation object of the builder.
+func (sbuo *SharedBucketUpdateOne) Mutation() *SharedBucketMutation {
+	return sbuo.mutation
+}
+
+// Where appends a list predicates to the SharedBucketUpdate builder.
+func (sbuo *SharedBucketUpdateOne) Where(ps ...predicate.SharedBucket) *SharedBucketUpdateOne {
+	sbuo.mutation.Where(ps...)
+	return sbuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (sbuo *SharedBucketUpdateOne) Select(field string, fields ...string) *SharedBucketUpdateOne {
+	sbuo.fields = append([]string{field}, fields...)
+	return sbuo
+}
+
+// Save executes the query and returns the updated SharedBucket entity.
+func (sbuo *SharedBucketUpdateOne) Save(ctx context.Context) (*SharedBucket, error) {
+	return withHooks(ctx, sbuo.sqlSave, sbuo.mutation, sbuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (sbuo *SharedBucketUpdateOne) SaveX(ctx context.Context) *SharedBucket {
+	node, err := sbuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (sbuo *SharedBucketUpdateOne) Exec(ctx context.Context) error {
+	_, err := sbuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (sbuo *SharedBucketUpdateOne) ExecX(ctx context.Context) {
+	if err := sbuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+func (sbuo *SharedBucketUpdateOne) sqlSave(ctx context.Context) (_node *SharedBucket, err error) {
+	_spec := sqlgraph.NewUpdateSpec(sharedbucket.Table, sharedbucket.Columns, sqlgraph.NewFieldSpec(sharedbucket.FieldID, field.TypeInt))
+	id, ok := sbuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "SharedBucket.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := sbuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, sharedbucket.FieldID)
+		for _, f := range fields {
+			if !sharedbucket.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != sharedbucket.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := sbuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := sbuo.mutation.Level(); ok {
+		_spec.SetField(sharedbucket.FieldLevel, field.TypeFloat64, value)
+	}
+	if value, ok := sbuo.mutation.AddedLevel(); ok {
+		_spec.AddField(sharedbucket.FieldLevel, field.TypeFloat64, value)
+	}
+	if value, ok := sbuo.mutation.UpdatedAt(); ok {
+		_spec.SetField(sharedbucket.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if value, ok := sbuo.mutation.EmptyAt(); ok {
+		_spec.SetField(sharedbucket.FieldEmptyAt, field.TypeTime, value)
+	}
+	if value, ok := sbuo.mutation.Revision(); ok {
+		_spec.SetField(sharedbucket.FieldRevision, field.TypeInt64, value)
+	}
+	if value, ok := sbuo.mutation.AddedRevision(); ok {
+		_spec.AddField(sharedbucket.FieldRevision, field.TypeInt64, value)
+	}
+	_node = &SharedBucket{config: sbuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, sbuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{sharedbucket.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	sbuo.mutation.done = true
+	return _node, nil
+}
@@ -24,6 +24,8 @@ type Tx struct {
 	ConfigItem *ConfigItemClient
 	// Decision is the client for interacting with the Decision builders.
 	Decision *DecisionClient
+	// DecisionChange is the client for interacting with the DecisionChange builders.
+	DecisionChange *DecisionChangeClient
 	// Event is the client for interacting with the Event builders.
 	Event *EventClient
 	// Lock is the client for interacting with the Lock builders.
@@ -34,6 +36,8 @@ type Tx struct {
 	Meta *MetaClient
 	// Metric is the client for interacting with the Metric builders.
 	Metric *MetricClient
+	// SharedBucket is the client for interacting with the SharedBucket builders.
+	SharedBucket *SharedBucketClient
 
 	// lazily loaded.
 	client     *Client
@@ -171,11 +175,13 @@ func (tx *Tx) init() {
 	tx.Bouncer = NewBouncerClient(tx.config)
 	tx.ConfigItem = NewConfigItemClient(tx.config)
 	tx.Decision = NewDecisionClient(tx.config)
+	tx.DecisionChange = NewDecisionChangeClient(tx.config)
 	tx.Event = NewEventClient(tx.config)
 	tx.Lock = NewLockClient(tx.config)
 	tx.Machine = NewMachineClient(tx.config)
 	tx.Meta = NewMetaClient(tx.config)
 	tx.Metric = NewMetricClient(tx.config)
+	tx.SharedBucket = NewSharedBucketClient(tx.config)
 }
 
 // txDriver wraps the given dialect.Tx with a nop dialect.Driver implementation.
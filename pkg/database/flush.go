@@ -16,6 +16,7 @@ import (
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/allowlistitem"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/bouncer"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/decision"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/decisionchange"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/event"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/machine"
 	"github.com/crowdsecurity/crowdsec/pkg/database/ent/metric"
@@ -25,10 +26,40 @@ import (
 const (
 	// how long to keep metrics in the local database
 	defaultMetricsMaxAge = 7 * 24 * time.Hour
-	flushInterval        = 1 * time.Minute
+	// how long to keep the decision change log
+	defaultDecisionChangesMaxAge = 7 * 24 * time.Hour
+	flushInterval                = 1 * time.Minute
+	// how often the decisions that reached their end are added to the change log
+	expiredDecisionsInterval = 10 * time.Second
 )
 
 func (c *Client) StartFlushScheduler(ctx context.Context, config *csconfig.FlushDBCfg) (*gocron.Scheduler, error) {
+	scheduler, err := c.NewFlushScheduler(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduler.StartAsync()
+
+	return scheduler, nil
+}
+
+// NewFlushScheduler prepares the flush jobs, the scheduler is started by the caller.
+// Without a flush configuration, the only job records the expired decisions in the change log.
+func (c *Client) NewFlushScheduler(ctx context.Context, config *csconfig.FlushDBCfg) (*gocron.Scheduler, error) {
+	scheduler := gocron.NewScheduler(time.UTC)
+
+	expiredJob, err := scheduler.Every(expiredDecisionsInterval).Do(c.recordExpiredDecisions, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("while starting recordExpiredDecisions scheduler: %w", err)
+	}
+
+	expiredJob.SingletonMode()
+
+	if config == nil {
+		return scheduler, nil
+	}
+
 	maxItems := 0
 	maxAge := ""
 
@@ -45,8 +76,6 @@ func (c *Client) StartFlushScheduler(ctx context.Context, config *csconfig.Flush
 	}
 
 	// Init & Start cronjob every minute for alerts
-	scheduler := gocron.NewScheduler(time.UTC)
-
 	job, err := scheduler.Every(1).Minute().Do(c.FlushAlerts, ctx, maxAge, maxItems)
 	if err != nil {
 		return nil, fmt.Errorf("while starting FlushAlerts scheduler: %w", err)
@@ -116,6 +145,13 @@ func (c *Client) StartFlushScheduler(ctx context.Context, config *csconfig.Flush
 
 	metricsJob.SingletonMode()
 
+	decisionChangesJob, err := scheduler.Every(flushInterval).Do(c.flushDecisionChanges, ctx, config.DecisionChangesMaxAge)
+	if err != nil {
+		return nil, fmt.Errorf("while starting flushDecisionChanges scheduler: %w", err)
+	}
+
+	decisionChangesJob.SingletonMode()
+
 	allowlistsJob, err := scheduler.Every(flushInterval).Do(c.flushAllowlists, ctx)
 	if err != nil {
 		return nil, fmt.Errorf("while starting FlushAllowlists scheduler: %w", err)
@@ -123,7 +159,12 @@ func (c *Client) StartFlushScheduler(ctx context.Context, config *csconfig.Flush
 
 	allowlistsJob.SingletonMode()
 
-	scheduler.StartAsync()
+	sharedBucketsJob, err := scheduler.Every(flushInterval).Do(c.flushSharedBuckets, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("while starting flushSharedBuckets scheduler: %w", err)
+	}
+
+	sharedBucketsJob.SingletonMode()
 
 	return scheduler, nil
 }
@@ -149,6 +190,34 @@ func (c *Client) flushMetrics(ctx context.Context, maxAge *time.Duration) {
 	}
 }
 
+// flushDecisionChanges deletes the changes older than maxAge. The last one is kept, it's the current revision.
+func (c *Client) flushDecisionChanges(ctx context.Context, maxAge *time.Duration) {
+	if maxAge == nil {
+		maxAge = ptr.Of(defaultDecisionChangesMaxAge)
+	}
+
+	last, err := c.DecisionRevision(ctx)
+	if err != nil {
+		c.Log.Errorf("while flushing decision changes: %s", err)
+		return
+	}
+
+	c.Log.Debugf("flushing decision changes older than %s", maxAge)
+
+	deleted, err := c.Ent.DecisionChange.Delete().Where(
+		decisionchange.CreatedAtLTE(time.Now().UTC().Add(-*maxAge)),
+		decisionchange.IDLT(last),
+	).Exec(ctx)
+	if err != nil {
+		c.Log.Errorf("while flushing decision changes: %s", err)
+		return
+	}
+
+	if deleted > 0 {
+		c.Log.Debugf("flushed %d decision changes", deleted)
+	}
+}
+
 func (c *Client) FlushOrphans(ctx context.Context) {
 	/* While it has only been linked to some very corner-case bug : https://github.com/crowdsecurity/crowdsec/issues/778 */
 	/* We want to take care of orphaned events for which the parent alert/decision has been deleted */
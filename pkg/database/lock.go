@@ -85,3 +85,76 @@ func (c *Client) ReleasePullCAPILock(ctx context.Context) error {
 
 	return nil
 }
+
+// AcquireLease takes the named lease for the owner, or renews it if the owner already holds it.
+// It returns false if another owner holds a lease that has not expired.
+func (c *Client) AcquireLease(ctx context.Context, name string, owner string, duration time.Duration) (bool, error) {
+	now := time.Now().UTC()
+
+	renewed, err := c.Ent.Lock.Update().Where(
+		lock.NameEQ(name),
+		lock.OwnerEQ(owner),
+	).SetExpiresAt(now.Add(duration)).Save(ctx)
+	if err != nil {
+		return false, errors.Wrapf(UpdateFail, "renew lease %s: %s", name, err)
+	}
+
+	if renewed > 0 {
+		return true, nil
+	}
+
+	// the previous owner is gone, or did not renew in time
+	_, err = c.Ent.Lock.Delete().Where(
+		lock.NameEQ(name),
+		lock.ExpiresAtLT(now),
+	).Exec(ctx)
+	if err != nil {
+		return false, errors.Wrapf(DeleteFail, "delete expired lease %s: %s", name, err)
+	}
+
+	err = c.Ent.Lock.Create().
+		SetName(name).
+		SetOwner(owner).
+		SetCreatedAt(now).
+		SetExpiresAt(now.Add(duration)).
+		Exec(ctx)
+
+	switch {
+	case c.IsLocked(err):
+		return false, nil
+	case err != nil:
+		return false, errors.Wrapf(InsertFail, "insert lease %s: %s", name, err)
+	}
+
+	log.Debugf("lease %s acquired by %s", name, owner)
+
+	return true, nil
+}
+
+// ReleaseLease gives up the named lease, if it's held by the owner
+func (c *Client) ReleaseLease(ctx context.Context, name string, owner string) error {
+	log.Debugf("releasing lease %s", name)
+
+	_, err := c.Ent.Lock.Delete().Where(
+		lock.NameEQ(name),
+		lock.OwnerEQ(owner),
+	).Exec(ctx)
+	if err != nil {
+		return errors.Wrapf(DeleteFail, "delete lease %s: %s", name, err)
+	}
+
+	return nil
+}
+
+// ListLeases returns the leases that have not expired
+func (c *Client) ListLeases(ctx context.Context) ([]*ent.Lock, error) {
+	leases, err := c.Ent.Lock.Query().Where(
+		lock.OwnerNEQ(""),
+		lock.ExpiresAtGTE(time.Now().UTC()),
+	).Order(ent.Asc(lock.FieldName)).All(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(QueryFail, "listing leases: %s", err)
+	}
+
+	return leases, nil
+}
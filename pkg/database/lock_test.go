@@ -0,0 +1,72 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquireLease(t *testing.T) {
+	ctx := t.Context()
+	dbClient := getDBClient(t, ctx)
+
+	leader, err := dbClient.AcquireLease(ctx, "flush", "lapi-1", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, leader)
+
+	// renewed by the owner
+	leader, err = dbClient.AcquireLease(ctx, "flush", "lapi-1", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, leader)
+
+	leader, err = dbClient.AcquireLease(ctx, "flush", "lapi-2", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, leader)
+
+	// the leases don't get in the way of the other locks
+	require.NoError(t, dbClient.AcquirePullCAPILock(ctx))
+
+	leader, err = dbClient.AcquireLease(ctx, "capi_pull", "lapi-2", time.Millisecond)
+	require.NoError(t, err)
+	assert.True(t, leader)
+
+	time.Sleep(10 * time.Millisecond)
+
+	leases, err := dbClient.ListLeases(ctx)
+	require.NoError(t, err)
+	require.Len(t, leases, 1)
+	assert.Equal(t, "flush", leases[0].Name)
+	assert.Equal(t, "lapi-1", leases[0].Owner)
+
+	// the expired lease is taken over
+	leader, err = dbClient.AcquireLease(ctx, "capi_pull", "lapi-1", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, leader)
+
+	leader, err = dbClient.AcquireLease(ctx, "capi_pull", "lapi-2", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, leader)
+
+	// only the owner can release the lease
+	require.NoError(t, dbClient.ReleaseLease(ctx, "flush", "lapi-2"))
+
+	leader, err = dbClient.AcquireLease(ctx, "flush", "lapi-2", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, leader)
+
+	require.NoError(t, dbClient.ReleaseLease(ctx, "flush", "lapi-1"))
+
+	leader, err = dbClient.AcquireLease(ctx, "flush", "lapi-2", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, leader)
+
+	leases, err = dbClient.ListLeases(ctx)
+	require.NoError(t, err)
+	require.Len(t, leases, 2)
+	assert.Equal(t, "capi_pull", leases[0].Name)
+	assert.Equal(t, "lapi-1", leases[0].Owner)
+	assert.Equal(t, "flush", leases[1].Name)
+	assert.Equal(t, "lapi-2", leases[1].Owner)
+}
@@ -0,0 +1,114 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent"
+	"github.com/crowdsecurity/crowdsec/pkg/database/ent/sharedbucket"
+)
+
+// how many times a pour is attempted when other LAPI instances update the same bucket
+const sharedBucketPourAttempts = 5
+
+// leakSharedBucket returns the level of a bucket at the given time, once leaked
+func leakSharedBucket(level float64, since time.Time, now time.Time, leakspeed time.Duration) float64 {
+	if leakspeed <= 0 || !now.After(since) {
+		return level
+	}
+
+	return math.Max(0, level-float64(now.Sub(since))/float64(leakspeed))
+}
+
+// sharedBucketEmptyAt returns the time at which a bucket with the given level is fully leaked
+func sharedBucketEmptyAt(level float64, now time.Time, leakspeed time.Duration) time.Time {
+	if leakspeed <= 0 {
+		// the bucket does not leak, keep it until it's reset
+		return now.Add(100 * 365 * 24 * time.Hour)
+	}
+
+	return now.Add(time.Duration(level * float64(leakspeed)))
+}
+
+// PourSharedBucket adds count events to the shared bucket identified by key, after leaking
+// one event per leakspeed since the previous pour, and returns the new level. The update
+// is retried if another LAPI instance poured into the same bucket in the meantime.
+func (c *Client) PourSharedBucket(ctx context.Context, key string, count int, leakspeed time.Duration) (float64, error) {
+	for range sharedBucketPourAttempts {
+		now := time.Now().UTC()
+
+		current, err := c.Ent.SharedBucket.Query().Where(sharedbucket.KeyEQ(key)).Only(ctx)
+
+		switch {
+		case ent.IsNotFound(err):
+			level := float64(count)
+
+			_, err = c.Ent.SharedBucket.Create().
+				SetKey(key).
+				SetLevel(level).
+				SetUpdatedAt(now).
+				SetEmptyAt(sharedBucketEmptyAt(level, now, leakspeed)).
+				Save(ctx)
+			if ent.IsConstraintError(err) {
+				// created by another instance, pour into it
+				continue
+			}
+
+			if err != nil {
+				return 0, errors.Wrapf(InsertFail, "shared bucket %s: %s", key, err)
+			}
+
+			return level, nil
+		case err != nil:
+			return 0, errors.Wrapf(QueryFail, "shared bucket %s: %s", key, err)
+		}
+
+		level := leakSharedBucket(current.Level, current.UpdatedAt, now, leakspeed) + float64(count)
+
+		updated, err := c.Ent.SharedBucket.Update().
+			Where(sharedbucket.IDEQ(current.ID), sharedbucket.RevisionEQ(current.Revision)).
+			SetLevel(level).
+			SetUpdatedAt(now).
+			SetEmptyAt(sharedBucketEmptyAt(level, now, leakspeed)).
+			AddRevision(1).
+			Save(ctx)
+		if err != nil {
+			return 0, errors.Wrapf(UpdateFail, "shared bucket %s: %s", key, err)
+		}
+
+		if updated == 1 {
+			return level, nil
+		}
+	}
+
+	return 0, fmt.Errorf("shared bucket %s: too many concurrent updates", key)
+}
+
+// ResetSharedBucket empties the shared bucket identified by key
+func (c *Client) ResetSharedBucket(ctx context.Context, key string) error {
+	_, err := c.Ent.SharedBucket.Delete().Where(sharedbucket.KeyEQ(key)).Exec(ctx)
+	if err != nil {
+		return errors.Wrapf(DeleteFail, "shared bucket %s: %s", key, err)
+	}
+
+	return nil
+}
+
+// flushSharedBuckets deletes the shared buckets that are fully leaked
+func (c *Client) flushSharedBuckets(ctx context.Context) {
+	deleted, err := c.Ent.SharedBucket.Delete().Where(
+		sharedbucket.EmptyAtLT(time.Now().UTC()),
+	).Exec(ctx)
+	if err != nil {
+		c.Log.Errorf("while flushing shared buckets: %s", err)
+		return
+	}
+
+	if deleted > 0 {
+		c.Log.Debugf("flushed %d shared buckets", deleted)
+	}
+}
@@ -0,0 +1,46 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPourSharedBucket(t *testing.T) {
+	ctx := t.Context()
+	dbClient := getDBClient(t, ctx)
+
+	level, err := dbClient.PourSharedBucket(ctx, "abcd", 2, time.Hour)
+	require.NoError(t, err)
+	assert.InDelta(t, 2, level, 0.01)
+
+	level, err = dbClient.PourSharedBucket(ctx, "abcd", 3, time.Hour)
+	require.NoError(t, err)
+	assert.InDelta(t, 5, level, 0.01)
+
+	require.NoError(t, dbClient.ResetSharedBucket(ctx, "abcd"))
+
+	level, err = dbClient.PourSharedBucket(ctx, "abcd", 1, time.Hour)
+	require.NoError(t, err)
+	assert.InDelta(t, 1, level, 0.01)
+
+	// leaks one event every 10ms
+	level, err = dbClient.PourSharedBucket(ctx, "fast", 2, 10*time.Millisecond)
+	require.NoError(t, err)
+	assert.InDelta(t, 2, level, 0.01)
+
+	time.Sleep(50 * time.Millisecond)
+
+	// fully leaked buckets are deleted by the flush
+	dbClient.flushSharedBuckets(ctx)
+
+	count, err := dbClient.Ent.SharedBucket.Query().Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	level, err = dbClient.PourSharedBucket(ctx, "fast", 1, 10*time.Millisecond)
+	require.NoError(t, err)
+	assert.InDelta(t, 1, level, 0.01)
+}
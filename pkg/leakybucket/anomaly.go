@@ -0,0 +1,271 @@
+package leakybucket
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+const (
+	defaultAnomalyAlpha  = 0.1
+	defaultAnomalyWarmup = 10
+	// when a long quiet period is folded into a baseline, we stop once it has converged
+	maxAnomalyQuietIntervals = 1000
+	// how often we forget the baselines that went quiet for good
+	anomalySweepInterval = time.Minute
+	// number of events kept in the queue of an anomaly bucket, to be sent with the alert
+	anomalyQueueSize = 100
+)
+
+// AnomalyCfg configures the `anomaly` buckets, which learn the usual rate of events of
+// each partition and overflow when the current rate is too far above it
+type AnomalyCfg struct {
+	Interval  string  `yaml:"interval"`             // Interval is the period over which events are counted, the rate is a number of events per interval
+	Sigma     float64 `yaml:"sigma"`                // Sigma is the number of standard deviations above the mean that triggers an overflow
+	Alpha     float64 `yaml:"alpha,omitempty"`      // Alpha is the smoothing factor of the moving average, a higher value forgets the past faster
+	Warmup    int     `yaml:"warmup,omitempty"`     // Warmup is the number of intervals to learn before the bucket can overflow
+	MinEvents int     `yaml:"min_events,omitempty"` // MinEvents is the minimum number of events in an interval for it to be considered anomalous
+	interval  time.Duration
+}
+
+// AnomalyBaseline is the learned rate of a partition of an anomaly bucket, along with
+// the count of the interval in progress. It outlives the buckets, which die when idle.
+type AnomalyBaseline struct {
+	Name       string        `json:"name"`     // name of the scenario
+	Interval   time.Duration `json:"interval"` // the baseline is dropped if the interval of the scenario changes
+	Mean       float64       `json:"mean"`
+	Variance   float64       `json:"variance"`
+	Samples    int           `json:"samples"` // number of intervals learned so far
+	Start      time.Time     `json:"start"`   // start of the current interval
+	Count      int           `json:"count"`   // events in the current interval
+	Overflowed bool          `json:"overflowed"`
+}
+
+// fold adds the count of a finished interval to the moving average and variance
+func (b *AnomalyBaseline) fold(count int, alpha float64) {
+	if b.Samples == 0 {
+		b.Mean = float64(count)
+		b.Variance = 0
+		b.Samples = 1
+
+		return
+	}
+
+	diff := float64(count) - b.Mean
+	incr := alpha * diff
+	b.Mean += incr
+	b.Variance = (1 - alpha) * (b.Variance + diff*incr)
+	b.Samples++
+}
+
+// advance moves the baseline to the interval that contains ts, learning from the intervals that ended
+func (b *AnomalyBaseline) advance(ts time.Time, alpha float64) {
+	start := ts.Truncate(b.Interval)
+
+	if b.Start.IsZero() {
+		b.Start = start
+		return
+	}
+
+	// events poured out of order (time-machine) are counted in the current interval
+	if !start.After(b.Start) {
+		return
+	}
+
+	b.fold(b.Count, alpha)
+
+	// the intervals without any event are part of the baseline too
+	quiet := int(start.Sub(b.Start)/b.Interval) - 1
+	for range min(quiet, maxAnomalyQuietIntervals) {
+		b.fold(0, alpha)
+	}
+
+	b.Start = start
+	b.Count = 0
+	b.Overflowed = false
+}
+
+// threshold is the number of events in an interval above which the rate is anomalous
+func (b *AnomalyBaseline) threshold(sigma float64) float64 {
+	// a perfectly steady baseline would overflow on a single extra event
+	stddev := math.Max(math.Sqrt(b.Variance), 1)
+
+	return b.Mean + sigma*stddev
+}
+
+// AnomalyBaselines holds the baselines of all the partitions of the anomaly buckets, by bucket key
+type AnomalyBaselines struct {
+	mu        sync.Mutex
+	baselines map[string]*AnomalyBaseline
+	lastSweep time.Time
+}
+
+func NewAnomalyBaselines() *AnomalyBaselines {
+	return &AnomalyBaselines{
+		baselines: make(map[string]*AnomalyBaseline),
+	}
+}
+
+// Snapshot returns a copy of the baselines, to be saved with the buckets state
+func (a *AnomalyBaselines) Snapshot() map[string]AnomalyBaseline {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	ret := make(map[string]AnomalyBaseline, len(a.baselines))
+	for key, baseline := range a.baselines {
+		ret[key] = *baseline
+	}
+
+	return ret
+}
+
+// Restore loads saved baselines, unless a baseline already exists for the same key
+func (a *AnomalyBaselines) Restore(key string, baseline AnomalyBaseline) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.baselines[key]; ok {
+		return false
+	}
+
+	a.baselines[key] = &baseline
+
+	return true
+}
+
+// sweep removes the baselines that didn't see any event for so long that they
+// converged to zero, so that scenarios grouping by IP don't grow forever.
+// Must be called with the lock held.
+func (a *AnomalyBaselines) sweep(now time.Time) {
+	if now.Sub(a.lastSweep) < anomalySweepInterval {
+		return
+	}
+
+	for key, baseline := range a.baselines {
+		if now.Sub(baseline.Start) > baseline.Interval*maxAnomalyQuietIntervals {
+			delete(a.baselines, key)
+		}
+	}
+
+	a.lastSweep = now
+}
+
+func validateAnomalyType(bucketFactory *BucketFactory) error {
+	cfg := bucketFactory.Anomaly
+
+	if cfg == nil {
+		return errors.New("anomaly bucket must have an anomaly configuration")
+	}
+
+	if bucketFactory.Capacity != -1 {
+		return errors.New("anomaly bucket must have capacity -1")
+	}
+
+	if cfg.Interval == "" {
+		return errors.New("interval can't be empty for anomaly bucket")
+	}
+
+	if cfg.interval <= 0 {
+		return fmt.Errorf("bad interval for anomaly bucket '%s'", cfg.Interval)
+	}
+
+	if cfg.Sigma <= 0 {
+		return fmt.Errorf("bad sigma for anomaly bucket '%f'", cfg.Sigma)
+	}
+
+	if cfg.Alpha <= 0 || cfg.Alpha > 1 {
+		return fmt.Errorf("bad alpha for anomaly bucket '%f', must be in ]0, 1]", cfg.Alpha)
+	}
+
+	if cfg.Warmup < 0 {
+		return fmt.Errorf("bad warmup for anomaly bucket '%d'", cfg.Warmup)
+	}
+
+	return nil
+}
+
+// compileAnomaly parses the interval and sets the defaults of an anomaly bucket
+func compileAnomaly(bucketFactory *BucketFactory) error {
+	var err error
+
+	cfg := bucketFactory.Anomaly
+	if cfg == nil {
+		return nil
+	}
+
+	if cfg.Interval != "" {
+		if cfg.interval, err = time.ParseDuration(cfg.Interval); err != nil {
+			return fmt.Errorf("invalid anomaly interval '%s': %w", cfg.Interval, err)
+		}
+	}
+
+	if cfg.Alpha == 0 {
+		cfg.Alpha = defaultAnomalyAlpha
+	}
+
+	if cfg.Warmup == 0 {
+		cfg.Warmup = defaultAnomalyWarmup
+	}
+
+	if bucketFactory.baselines == nil {
+		bucketFactory.baselines = NewAnomalyBaselines()
+	}
+
+	return nil
+}
+
+// AnomalyBucket is the processor of the `anomaly` buckets. The count of events of the
+// current interval is compared to the baseline (an exponentially weighted moving average
+// and standard deviation of the previous intervals), and the bucket overflows when it goes
+// above mean + sigma * stddev. Once an interval has overflowed, it can't overflow again.
+type AnomalyBucket struct {
+	DumbProcessor
+}
+
+func (a *AnomalyBucket) AfterBucketPour(bucketFactory *BucketFactory) func(types.Event, *Leaky) *types.Event {
+	return func(msg types.Event, leaky *Leaky) *types.Event {
+		cfg := bucketFactory.Anomaly
+		store := bucketFactory.baselines
+
+		store.mu.Lock()
+
+		store.sweep(leaky.Last_ts)
+
+		baseline, ok := store.baselines[leaky.Mapkey]
+		if !ok || baseline.Interval != cfg.interval {
+			baseline = &AnomalyBaseline{Name: bucketFactory.Name, Interval: cfg.interval}
+			store.baselines[leaky.Mapkey] = baseline
+		}
+
+		// Last_ts is the time of the event in time-machine mode, and the pour time in live mode
+		baseline.advance(leaky.Last_ts, cfg.Alpha)
+		baseline.Count++
+
+		count := baseline.Count
+		samples := baseline.Samples
+		threshold := baseline.threshold(cfg.Sigma)
+		overflow := !baseline.Overflowed && samples >= cfg.Warmup && count >= cfg.MinEvents && float64(count) > threshold
+
+		if overflow {
+			baseline.Overflowed = true
+		}
+
+		store.mu.Unlock()
+
+		leaky.logger.Tracef("anomaly count: %d, threshold: %.2f (%d intervals learned)", count, threshold, samples)
+
+		if !overflow {
+			return &msg
+		}
+
+		leaky.logger.Debugf("Anomaly bucket overflow (%d events, threshold %.2f)", count, threshold)
+		leaky.Ovflw_ts = leaky.Last_ts
+		leaky.Out <- leaky.Queue
+
+		return nil
+	}
+}
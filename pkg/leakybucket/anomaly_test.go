@@ -0,0 +1,115 @@
+package leakybucket
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/tomb.v2"
+
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+func TestAnomalyBaseline(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	baseline := AnomalyBaseline{Interval: time.Minute}
+
+	// 10 events per minute, for 10 minutes
+	for minute := range 10 {
+		baseline.advance(start.Add(time.Duration(minute)*time.Minute), 0.5)
+		baseline.Count += 10
+	}
+
+	baseline.advance(start.Add(10*time.Minute), 0.5)
+	assert.Equal(t, 10, baseline.Samples)
+	assert.InDelta(t, 10, baseline.Mean, 0.001)
+	assert.InDelta(t, 0, baseline.Variance, 0.001)
+	// the deviation is never lower than one event
+	assert.InDelta(t, 13, baseline.threshold(3), 0.001)
+
+	// out of order events are counted in the current interval
+	baseline.advance(start.Add(9*time.Minute), 0.5)
+	assert.Equal(t, 10, baseline.Samples)
+
+	// two minutes without events are learned too
+	baseline.advance(start.Add(13*time.Minute), 0.5)
+	assert.Equal(t, 13, baseline.Samples)
+	assert.InDelta(t, 1.25, baseline.Mean, 0.001)
+	assert.Greater(t, baseline.Variance, 1.0)
+	assert.Equal(t, start.Add(13*time.Minute), baseline.Start)
+}
+
+func TestAnomalyBaselinesSweep(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	baselines := NewAnomalyBaselines()
+	baselines.Restore("old", AnomalyBaseline{Interval: time.Second, Start: now.Add(-time.Hour)})
+	baselines.Restore("recent", AnomalyBaseline{Interval: time.Second, Start: now.Add(-time.Minute)})
+
+	baselines.sweep(now)
+
+	assert.NotContains(t, baselines.baselines, "old")
+	assert.Contains(t, baselines.baselines, "recent")
+}
+
+func newAnomalyTestHolders(t *testing.T, buckets *Buckets, tomb *tomb.Tomb, interval string) []BucketFactory {
+	t.Helper()
+
+	holders := []BucketFactory{
+		{
+			Name:        "test/anomaly",
+			Description: "test/anomaly",
+			Type:        "anomaly",
+			Capacity:    -1,
+			Filter:      "true",
+			GroupBy:     "evt.Meta.source_ip",
+			Anomaly:     &AnomalyCfg{Interval: interval, Sigma: 3},
+			baselines:   buckets.Baselines,
+			wgDumpState: buckets.wgDumpState,
+			wgPour:      buckets.wgPour,
+			ret:         make(chan types.Event, 10),
+		},
+	}
+
+	require.NoError(t, LoadBucket(&holders[0], tomb))
+
+	return holders
+}
+
+func TestAnomalyBaselinesPersistence(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "buckets_state.json")
+
+	buckets := NewBuckets()
+	tmb := &tomb.Tomb{}
+	defer tmb.Kill(nil)
+
+	holders := newAnomalyTestHolders(t, buckets, tmb, "1h")
+
+	key := GetKey(holders[0], "1.2.3.4")
+	buckets.Baselines.Restore(key, AnomalyBaseline{
+		Name:     "test/anomaly",
+		Interval: time.Hour,
+		Mean:     42,
+		Variance: 4,
+		Samples:  100,
+		Start:    time.Now().UTC().Truncate(time.Hour),
+	})
+
+	require.NoError(t, SaveBucketsState(stateFile, buckets))
+
+	// same interval: the baseline is restored
+	restored := NewBuckets()
+	require.NoError(t, RestoreBucketsState(stateFile, restored, newAnomalyTestHolders(t, restored, tmb, "1h")))
+
+	snapshot := restored.Baselines.Snapshot()
+	require.Contains(t, snapshot, key)
+	assert.InDelta(t, 42, snapshot[key].Mean, 0.001)
+	assert.Equal(t, 100, snapshot[key].Samples)
+
+	// the interval changed: the baseline doesn't make sense anymore
+	changed := NewBuckets()
+	require.NoError(t, RestoreBucketsState(stateFile, changed, newAnomalyTestHolders(t, changed, tmb, "1m")))
+	assert.Empty(t, changed.Baselines.Snapshot())
+}
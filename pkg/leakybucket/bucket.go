@@ -1,6 +1,7 @@
 package leakybucket
 
 import (
+	"encoding/json"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -70,8 +71,16 @@ type Leaky struct {
 	tomb                *tomb.Tomb
 	wgPour              *sync.WaitGroup
 	wgDumpState         *sync.WaitGroup
-	mutex               *sync.Mutex //used only for TIMEMACHINE mode to allow garbage collection without races
+	mutex               *sync.Mutex //held by the leak routine while it pours an event, to read the state of the bucket from outside without races
 	orderEvent          bool
+	restored            bool                       //set when the bucket was restored from a saved state
+	processors          []Processor                //the copy of the processors used by the leak routine, protected by mutex
+	processorsState     map[string]json.RawMessage //the saved state of the processors of a restored bucket
+	created             time.Time
+	pours               int64 //copy of Total_count that can be read by the eviction, outside of the leak routine
+	counted             bool  //protected by the limits mutex
+	evict               chan bool
+	limits              *BucketsLimits
 }
 
 var BucketsPour = prometheus.NewCounterVec(
@@ -146,7 +155,16 @@ func FromFactory(bucketFactory BucketFactory) *Leaky {
 			Qsize = bucketFactory.CacheSize
 		}
 	}
-	if bucketFactory.Capacity == -1 {
+	//anomaly and sequence buckets can live forever under a steady flow of events, don't keep all of them
+	if Qsize == -1 {
+		switch bucketFactory.Type {
+		case "anomaly":
+			Qsize = anomalyQueueSize
+		case "sequence":
+			Qsize = sequenceQueueSize
+		}
+	}
+	if bucketFactory.Capacity == -1 || bucketFactory.Type == "window" {
 		//In this case we allow all events to pass.
 		//maybe in the future we could avoid using a limiter
 		limiter = &rate.AlwaysFull{}
@@ -181,24 +199,54 @@ func FromFactory(bucketFactory BucketFactory) *Leaky {
 		wgDumpState:     bucketFactory.wgDumpState,
 		mutex:           &sync.Mutex{},
 		orderEvent:      bucketFactory.orderEvent,
+		created:         time.Now().UTC(),
+		evict:           make(chan bool, 1),
 	}
-	if l.BucketConfig.Capacity > 0 && l.BucketConfig.leakspeed != time.Duration(0) {
-		l.Duration = time.Duration(l.BucketConfig.Capacity+1) * l.BucketConfig.leakspeed
-	}
-	if l.BucketConfig.duration != time.Duration(0) {
-		l.Duration = l.BucketConfig.duration
-		l.timedOverflow = true
-	}
+	l.Duration, l.timedOverflow = bucketLifetime(l.BucketConfig)
 
 	if l.BucketConfig.Type == "conditional" {
 		l.conditionalOverflow = true
-		l.Duration = l.BucketConfig.leakspeed
+	}
+	return l
+}
+
+// bucketLifetime returns how long a bucket lives without receiving events, and whether
+// this lifetime is counted from the first event (timed overflow) rather than the last one.
+func bucketLifetime(bucketFactory *BucketFactory) (time.Duration, bool) {
+	var (
+		duration      time.Duration
+		timedOverflow bool
+	)
+
+	if bucketFactory.Capacity > 0 && bucketFactory.leakspeed != time.Duration(0) {
+		duration = time.Duration(bucketFactory.Capacity+1) * bucketFactory.leakspeed
+	}
+	if bucketFactory.duration != time.Duration(0) {
+		duration = bucketFactory.duration
+		timedOverflow = true
 	}
 
-	if l.BucketConfig.Type == "bayesian" {
-		l.Duration = l.BucketConfig.leakspeed
+	if bucketFactory.Type == "conditional" || bucketFactory.Type == "bayesian" {
+		duration = bucketFactory.leakspeed
 	}
-	return l
+
+	//a window bucket dies when all its events have left the window
+	if bucketFactory.Type == "window" {
+		timedOverflow = false
+	}
+
+	//an anomaly bucket dies after an interval without events, its baseline is kept aside
+	if bucketFactory.Type == "anomaly" && bucketFactory.Anomaly != nil {
+		duration = bucketFactory.Anomaly.interval
+		timedOverflow = false
+	}
+
+	//a sequence bucket dies when its current step can't be completed anymore
+	if bucketFactory.Type == "sequence" {
+		duration = sequenceLifetime(bucketFactory)
+		timedOverflow = false
+	}
+	return duration, timedOverflow
 }
 
 /* for now mimic a leak routine */
@@ -215,6 +263,11 @@ func LeakRoutine(leaky *Leaky) error {
 	BucketsCurrentCount.With(prometheus.Labels{"name": leaky.Name}).Inc()
 	defer BucketsCurrentCount.With(prometheus.Labels{"name": leaky.Name}).Dec()
 
+	if leaky.limits != nil {
+		leaky.limits.started(leaky)
+		defer leaky.limits.stopped(leaky)
+	}
+
 	/*todo : we create a logger at runtime while we want leakroutine to be up asap, might not be a good idea*/
 	leaky.logger = leaky.BucketConfig.logger.WithFields(log.Fields{"partition": leaky.Mapkey, "bucket_id": leaky.Uuid})
 
@@ -237,37 +290,41 @@ func LeakRoutine(leaky *Leaky) error {
 		}
 	}
 
+	if leaky.restored {
+		leaky.loadProcessorsState(processors)
+	}
+
+	leaky.mutex.Lock()
+	leaky.processors = processors
+	leaky.mutex.Unlock()
+
+	//a live bucket restored from a saved state has already been poured into: arm the ticker with
+	//what is left of its lifetime, otherwise it would only start (and the bucket die) on the next pour
+	if leaky.restored && leaky.Mode == types.LIVE {
+		deadline := leaky.Last_ts.Add(leaky.Duration)
+		if leaky.timedOverflow {
+			deadline = leaky.First_ts.Add(leaky.Duration)
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			remaining = time.Millisecond
+		}
+		durationTicker = time.NewTicker(remaining)
+		durationTickerChan = durationTicker.C
+		defer durationTicker.Stop()
+		firstEvent = false
+	}
+
 	leaky.logger.Debugf("Leaky routine starting, lifetime : %s", leaky.Duration)
 	for {
 		select {
 		/*receiving an event*/
 		case msg := <-leaky.In:
-			/*the msg var use is confusing and is redeclared in a different type :/*/
-			for _, processor := range processors {
-				msg = processor.OnBucketPour(leaky.BucketConfig)(*msg, leaky)
-				// if &msg == nil we stop processing
-				if msg == nil {
-					if leaky.orderEvent {
-						orderEvent[leaky.Mapkey].Done()
-					}
-					goto End
-				}
-			}
-			if leaky.logger.Level >= log.TraceLevel {
-				leaky.logger.Tracef("Pour event: %s", spew.Sdump(msg))
-			}
-			BucketsPour.With(prometheus.Labels{"name": leaky.Name, "source": msg.Line.Src, "type": msg.Line.Module}).Inc()
-
-			leaky.Pour(leaky, *msg) // glue for now
-
-			for _, processor := range processors {
-				msg = processor.AfterBucketPour(leaky.BucketConfig)(*msg, leaky)
-				if msg == nil {
-					if leaky.orderEvent {
-						orderEvent[leaky.Mapkey].Done()
-					}
-					goto End
+			if !leaky.pourEvent(processors, msg) {
+				if leaky.orderEvent {
+					orderEvent[leaky.Mapkey].Done()
 				}
+				goto End
 			}
 
 			//Clear cache on behalf of pour
@@ -300,13 +357,21 @@ func LeakRoutine(leaky *Leaky) error {
 			leaky.AllOut <- types.Event{Type: types.OVFLW, Overflow: types.RuntimeAlert{Mapkey: leaky.Mapkey}}
 			leaky.logger.Tracef("Returning from leaky routine.")
 			return nil
+		/*a limit was reached, and we were chosen to make room for new buckets*/
+		case <-leaky.evict:
+			close(leaky.Signal)
+			leaky.logger.Debugf("Bucket evicted")
+			leaky.AllOut <- types.Event{Type: types.OVFLW, Overflow: types.RuntimeAlert{Mapkey: leaky.Mapkey}}
+			return nil
 		/*we underflow or reach bucket deadline (timers)*/
 		case <-durationTickerChan:
 			var (
 				alert types.RuntimeAlert
 				err   error
 			)
+			leaky.mutex.Lock()
 			leaky.Ovflw_ts = time.Now().UTC()
+			leaky.mutex.Unlock()
 			close(leaky.Signal)
 			ofw := leaky.Queue
 			alert = types.RuntimeAlert{Mapkey: leaky.Mapkey}
@@ -353,11 +418,51 @@ func LeakRoutine(leaky *Leaky) error {
 	}
 }
 
-func Pour(leaky *Leaky, msg types.Event) {
+// pourEvent runs the event through the processors and pours it. It returns false if a processor
+// stopped the event. The state of the bucket can't be dumped while an event is being poured.
+func (leaky *Leaky) pourEvent(processors []Processor, msg *types.Event) bool {
 	leaky.wgDumpState.Wait()
 	leaky.wgPour.Add(1)
 	defer leaky.wgPour.Done()
 
+	leaky.mutex.Lock()
+	defer leaky.mutex.Unlock()
+
+	/*the msg var use is confusing and is redeclared in a different type :/*/
+	for _, processor := range processors {
+		msg = processor.OnBucketPour(leaky.BucketConfig)(*msg, leaky)
+		// if &msg == nil we stop processing
+		if msg == nil {
+			return false
+		}
+	}
+	if leaky.logger.Level >= log.TraceLevel {
+		leaky.logger.Tracef("Pour event: %s", spew.Sdump(msg))
+	}
+	BucketsPour.With(prometheus.Labels{"name": leaky.Name, "source": msg.Line.Src, "type": msg.Line.Module}).Inc()
+
+	leaky.Pour(leaky, *msg) // glue for now
+	atomic.StoreInt64(&leaky.pours, int64(leaky.Total_count))
+
+	for _, processor := range processors {
+		msg = processor.AfterBucketPour(leaky.BucketConfig)(*msg, leaky)
+		if msg == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// overflowTs returns the time of the overflow, it is zero while the bucket has not overflowed
+func (leaky *Leaky) overflowTs() time.Time {
+	leaky.mutex.Lock()
+	defer leaky.mutex.Unlock()
+
+	return leaky.Ovflw_ts
+}
+
+func Pour(leaky *Leaky, msg types.Event) {
 	leaky.Total_count += 1
 	if leaky.First_ts.IsZero() {
 		leaky.First_ts = time.Now().UTC()
@@ -4,6 +4,8 @@ import (
 	"crypto/sha1"
 	"fmt"
 	"sync"
+
+	"gopkg.in/tomb.v2"
 )
 
 // Buckets is the struct used to hold buckets in the context of
@@ -12,6 +14,15 @@ type Buckets struct {
 	wgDumpState *sync.WaitGroup
 	wgPour      *sync.WaitGroup
 	Bucket_map  *sync.Map
+	// SharedState holds the level of the scenarios with `shared: true`
+	SharedState SharedStateBackend
+	sharedPours *sharedStatePourer
+	// Baselines holds the rates learned by the anomaly buckets
+	Baselines *AnomalyBaselines
+	// Limits caps the number of live buckets
+	Limits *BucketsLimits
+	// Shadows compares the overflows of the scenarios with their shadow versions
+	Shadows *ShadowTracker
 }
 
 // NewBuckets create the Buckets struct
@@ -20,9 +31,25 @@ func NewBuckets() *Buckets {
 		wgDumpState: &sync.WaitGroup{},
 		wgPour:      &sync.WaitGroup{},
 		Bucket_map:  &sync.Map{},
+		SharedState: NewMemoryStateBackend(),
+		Baselines:   NewAnomalyBaselines(),
+		Limits:      NewBucketsLimits(),
+		Shadows:     NewShadowTracker(),
 	}
 }
 
+// sharedStatePourer returns the batcher of the pours in the shared state, it is started on first use
+func (b *Buckets) sharedStatePourer(t *tomb.Tomb) *sharedStatePourer {
+	if b.sharedPours == nil {
+		b.sharedPours = newSharedStatePourer(b.SharedState)
+		t.Go(func() error {
+			return b.sharedPours.run(t)
+		})
+	}
+
+	return b.sharedPours
+}
+
 func GetKey(bucketCfg BucketFactory, stackkey string) string {
 	return fmt.Sprintf("%x", sha1.Sum([]byte(bucketCfg.Filter+stackkey+bucketCfg.Name)))
 }
@@ -0,0 +1,282 @@
+package leakybucket
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// EvictionOldest evicts the buckets that were created first
+	EvictionOldest = "oldest"
+	// EvictionLeastFilled evicts the buckets that received the fewest events
+	EvictionLeastFilled = "least-filled"
+)
+
+// Finding the buckets to evict means going through all of them, so when a limit is
+// reached, we make room for several new buckets at once (a fraction of the limit).
+const evictionBatchRatio = 0.05
+
+var BucketsEvicted = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "cs_bucket_evicted_total",
+		Help: "Total buckets evicted because a limit was reached.",
+	},
+	[]string{"name"},
+)
+
+var BucketsLimit = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "cs_buckets_limit",
+		Help: "Maximum number of buckets per scenario (the global limit has an empty name).",
+	},
+	[]string{"name"},
+)
+
+func validateEvictionPolicy(policy string) error {
+	switch policy {
+	case "", EvictionOldest, EvictionLeastFilled:
+		return nil
+	default:
+		return fmt.Errorf("unknown eviction policy '%s', must be '%s' or '%s'", policy, EvictionOldest, EvictionLeastFilled)
+	}
+}
+
+// BucketsLimits caps the number of live buckets, globally and per scenario, so that
+// a scenario with a high-cardinality groupby can't exhaust the memory of the agent.
+// When a limit is reached, existing buckets are evicted to make room for the new ones.
+type BucketsLimits struct {
+	maxBuckets     int    // global limit, 0 means unlimited
+	maxPerScenario int    // default limit of the scenarios that don't have max_buckets
+	evictionPolicy string // default eviction policy
+	mu             sync.Mutex
+	evictMu        sync.Mutex     // only one eviction pass at a time
+	count          map[string]int // live buckets per scenario
+	total          int
+}
+
+func NewBucketsLimits() *BucketsLimits {
+	return &BucketsLimits{
+		count: make(map[string]int),
+	}
+}
+
+// SetLimits configures the global limit, the default per-scenario limit and the default
+// eviction policy. A limit of 0 means unlimited.
+func (b *BucketsLimits) SetLimits(maxBuckets int, maxPerScenario int, policy string) error {
+	if maxBuckets < 0 || maxPerScenario < 0 {
+		return fmt.Errorf("bucket limits can't be negative (%d, %d)", maxBuckets, maxPerScenario)
+	}
+
+	if err := validateEvictionPolicy(policy); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.maxBuckets = maxBuckets
+	b.maxPerScenario = maxPerScenario
+	b.evictionPolicy = policy
+
+	if maxBuckets > 0 {
+		BucketsLimit.With(prometheus.Labels{"name": ""}).Set(float64(maxBuckets))
+	}
+
+	return nil
+}
+
+// scenarioLimit returns the maximum number of buckets of a scenario, and how to evict them
+func (b *BucketsLimits) scenarioLimit(holder *BucketFactory) (int, string) {
+	limit := holder.MaxBuckets
+	if limit == 0 {
+		limit = b.maxPerScenario
+	}
+
+	return limit, b.policy(holder.EvictionPolicy)
+}
+
+// exposeLimit sets the gauge of the limit of a scenario, so that the pressure can be monitored
+func (b *BucketsLimits) exposeLimit(holder *BucketFactory) {
+	if limit, _ := b.scenarioLimit(holder); limit > 0 {
+		BucketsLimit.With(prometheus.Labels{"name": holder.Name}).Set(float64(limit))
+	}
+}
+
+func (b *BucketsLimits) policy(policy string) string {
+	if policy == "" {
+		policy = b.evictionPolicy
+	}
+
+	if policy == "" {
+		policy = EvictionOldest
+	}
+
+	return policy
+}
+
+// Count returns the number of live buckets of a scenario
+func (b *BucketsLimits) Count(name string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.count[name]
+}
+
+// Total returns the number of live buckets
+func (b *BucketsLimits) Total() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.total
+}
+
+// started is called when the leak routine of a bucket starts
+func (b *BucketsLimits) started(leaky *Leaky) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.count[leaky.Name]++
+	b.total++
+	leaky.counted = true
+}
+
+// stopped is called when the leak routine of a bucket returns
+func (b *BucketsLimits) stopped(leaky *Leaky) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.forget(leaky)
+}
+
+// forget stops counting a bucket, either because it's dead or because it was evicted,
+// and returns false if it wasn't counted anymore. Must be called with the lock held.
+func (b *BucketsLimits) forget(leaky *Leaky) bool {
+	if !leaky.counted {
+		return false
+	}
+
+	leaky.counted = false
+
+	b.count[leaky.Name]--
+	if b.count[leaky.Name] <= 0 {
+		delete(b.count, leaky.Name)
+	}
+
+	b.total--
+
+	return true
+}
+
+// makeRoom is called before creating a new bucket for a scenario, and evicts
+// buckets if the scenario or the agent reached its limit
+func (b *BucketsLimits) makeRoom(buckets *Buckets, holder *BucketFactory) {
+	limit, policy := b.scenarioLimit(holder)
+
+	if limit <= 0 && b.maxBuckets <= 0 {
+		return
+	}
+
+	b.evictMu.Lock()
+	defer b.evictMu.Unlock()
+
+	// the limit may have been enforced by another routine while we were waiting
+	b.mu.Lock()
+	scenarioFull := limit > 0 && b.count[holder.Name] >= limit
+	globalFull := b.maxBuckets > 0 && b.total >= b.maxBuckets
+	b.mu.Unlock()
+
+	if scenarioFull {
+		evicted := b.evict(buckets, holder.Name, batchSize(limit), policy)
+		holder.logger.Warningf("scenario reached its limit of %d buckets, evicted %d buckets (%s)", limit, evicted, policy)
+	}
+
+	if globalFull {
+		evicted := b.evict(buckets, "", batchSize(b.maxBuckets), b.policy(""))
+		log.Warningf("reached the global limit of %d buckets, evicted %d buckets (%s)", b.maxBuckets, evicted, b.policy(""))
+	}
+}
+
+func batchSize(limit int) int {
+	return max(1, int(float64(limit)*evictionBatchRatio))
+}
+
+type evictionCandidate struct {
+	key     string
+	leaky   *Leaky
+	created time.Time
+	pours   int64
+}
+
+// evict removes up to n buckets of the scenario (or of all the scenarios if name is empty),
+// chosen according to the policy, and returns how many were actually evicted
+func (b *BucketsLimits) evict(buckets *Buckets, name string, n int, policy string) int {
+	candidates := []evictionCandidate{}
+
+	buckets.Bucket_map.Range(func(rkey, rvalue interface{}) bool {
+		leaky := rvalue.(*Leaky)
+
+		if name != "" && leaky.Name != name {
+			return true
+		}
+
+		candidates = append(candidates, evictionCandidate{
+			key:     rkey.(string),
+			leaky:   leaky,
+			created: leaky.created,
+			pours:   atomic.LoadInt64(&leaky.pours),
+		})
+
+		return true
+	})
+
+	switch policy {
+	case EvictionLeastFilled:
+		slices.SortFunc(candidates, func(a, b evictionCandidate) int {
+			return cmp.Or(cmp.Compare(a.pours, b.pours), a.created.Compare(b.created))
+		})
+	default:
+		slices.SortFunc(candidates, func(a, b evictionCandidate) int {
+			return a.created.Compare(b.created)
+		})
+	}
+
+	evicted := 0
+
+	for _, candidate := range candidates {
+		if evicted >= n {
+			break
+		}
+
+		// the bucket may have died, and been replaced, in the meantime
+		if !buckets.Bucket_map.CompareAndDelete(candidate.key, candidate.leaky) {
+			continue
+		}
+
+		b.mu.Lock()
+		alive := b.forget(candidate.leaky)
+		b.mu.Unlock()
+
+		// the leak routine already returned, we only cleaned up the map
+		if !alive {
+			continue
+		}
+
+		select {
+		case candidate.leaky.evict <- true:
+		default:
+		}
+
+		BucketsEvicted.With(prometheus.Labels{"name": candidate.leaky.Name}).Inc()
+
+		evicted++
+	}
+
+	return evicted
+}
@@ -0,0 +1,129 @@
+package leakybucket
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/tomb.v2"
+
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+func newLimitsTestHolder(t *testing.T, buckets *Buckets, tomb *tomb.Tomb, name string, maxBuckets int, policy string) BucketFactory {
+	t.Helper()
+
+	holder := BucketFactory{
+		Name:           name,
+		Description:    name,
+		Type:           "leaky",
+		Capacity:       100,
+		LeakSpeed:      "10m",
+		Filter:         "true",
+		GroupBy:        "evt.Meta.source_ip",
+		MaxBuckets:     maxBuckets,
+		EvictionPolicy: policy,
+		wgDumpState:    buckets.wgDumpState,
+		wgPour:         buckets.wgPour,
+		ret:            make(chan types.Event, 100),
+	}
+
+	require.NoError(t, LoadBucket(&holder, tomb))
+
+	return holder
+}
+
+func pourFrom(t *testing.T, holders []BucketFactory, buckets *Buckets, ips ...string) {
+	t.Helper()
+
+	for _, ip := range ips {
+		ok, err := PourItemToHolders(types.Event{Meta: map[string]string{"source_ip": ip}}, holders, buckets)
+		require.NoError(t, err)
+		require.True(t, ok)
+		// make sure the creation times are distinct
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestMaxBucketsOldest(t *testing.T) {
+	buckets := NewBuckets()
+	tmb := &tomb.Tomb{}
+	defer tmb.Kill(nil)
+
+	holders := []BucketFactory{newLimitsTestHolder(t, buckets, tmb, "test/limited", 3, "")}
+
+	pourFrom(t, holders, buckets, "1.1.1.1", "2.2.2.2", "3.3.3.3", "1.1.1.1", "4.4.4.4")
+
+	assert.Equal(t, 3, buckets.Limits.Count("test/limited"))
+	require.NoError(t, expectBucketCount(buckets, 3))
+
+	_, ok := buckets.Bucket_map.Load(GetKey(holders[0], "1.1.1.1"))
+	assert.False(t, ok, "the oldest bucket should have been evicted")
+
+	for _, ip := range []string{"2.2.2.2", "3.3.3.3", "4.4.4.4"} {
+		_, ok := buckets.Bucket_map.Load(GetKey(holders[0], ip))
+		assert.True(t, ok, ip)
+	}
+}
+
+func TestMaxBucketsLeastFilled(t *testing.T) {
+	buckets := NewBuckets()
+	tmb := &tomb.Tomb{}
+	defer tmb.Kill(nil)
+
+	holders := []BucketFactory{newLimitsTestHolder(t, buckets, tmb, "test/limited", 3, EvictionLeastFilled)}
+
+	pourFrom(t, holders, buckets, "1.1.1.1", "1.1.1.1", "2.2.2.2", "3.3.3.3", "3.3.3.3", "4.4.4.4")
+
+	assert.Equal(t, 3, buckets.Limits.Count("test/limited"))
+
+	_, ok := buckets.Bucket_map.Load(GetKey(holders[0], "2.2.2.2"))
+	assert.False(t, ok, "the least filled bucket should have been evicted")
+
+	_, ok = buckets.Bucket_map.Load(GetKey(holders[0], "1.1.1.1"))
+	assert.True(t, ok)
+}
+
+func TestGlobalMaxBuckets(t *testing.T) {
+	buckets := NewBuckets()
+	require.NoError(t, buckets.Limits.SetLimits(20, 0, EvictionOldest))
+
+	tmb := &tomb.Tomb{}
+	defer tmb.Kill(nil)
+
+	holders := []BucketFactory{
+		newLimitsTestHolder(t, buckets, tmb, "test/noisy", 0, ""),
+		newLimitsTestHolder(t, buckets, tmb, "test/quiet", 0, ""),
+	}
+
+	for i := range 30 {
+		pourFrom(t, holders, buckets, fmt.Sprintf("10.0.0.%d", i))
+	}
+
+	assert.LessOrEqual(t, buckets.Limits.Total(), 20)
+	assert.Equal(t, buckets.Limits.Total(), buckets.Limits.Count("test/noisy")+buckets.Limits.Count("test/quiet"))
+
+	// the evicted buckets are recreated on the next event
+	pourFrom(t, holders, buckets, "10.0.0.0")
+
+	_, ok := buckets.Bucket_map.Load(GetKey(holders[0], "10.0.0.0"))
+	assert.True(t, ok)
+}
+
+func TestBucketsLimitsConfig(t *testing.T) {
+	limits := NewBucketsLimits()
+
+	require.Error(t, limits.SetLimits(-1, 0, ""))
+	require.Error(t, limits.SetLimits(0, 0, "random"))
+	require.NoError(t, limits.SetLimits(10, 5, EvictionLeastFilled))
+
+	limit, policy := limits.scenarioLimit(&BucketFactory{})
+	assert.Equal(t, 5, limit)
+	assert.Equal(t, EvictionLeastFilled, policy)
+
+	limit, policy = limits.scenarioLimit(&BucketFactory{MaxBuckets: 2, EvictionPolicy: EvictionOldest})
+	assert.Equal(t, 2, limit)
+	assert.Equal(t, EvictionOldest, policy)
+}
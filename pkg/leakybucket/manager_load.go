@@ -34,11 +34,11 @@ type BucketFactory struct {
 	Author              string                 `yaml:"author"`
 	Description         string                 `yaml:"description"`
 	References          []string               `yaml:"references"`
-	Type                string                 `yaml:"type"`                // Type can be : leaky, counter, trigger. It determines the main bucket characteristics
+	Type                string                 `yaml:"type"`                // Type can be : leaky, counter, trigger, conditional, bayesian, window, sequence, anomaly. It determines the main bucket characteristics
 	Name                string                 `yaml:"name"`                // Name of the bucket, used later in log and user-messages. Should be unique
 	Capacity            int                    `yaml:"capacity"`            // Capacity is applicable to leaky buckets and determines the "burst" capacity
 	LeakSpeed           string                 `yaml:"leakspeed"`           // Leakspeed is a float representing how many events per second leak out of the bucket
-	Duration            string                 `yaml:"duration"`            // Duration allows 'counter' buckets to have a fixed life-time
+	Duration            string                 `yaml:"duration"`            // Duration allows 'counter' buckets to have a fixed life-time, and is the size of the sliding window of 'window' buckets
 	Filter              string                 `yaml:"filter"`              // Filter is an expr that determines if an event is elligible for said bucket. Filter is evaluated against the Event struct
 	GroupBy             string                 `yaml:"groupby,omitempty"`   // groupy is an expr that allows to determine the partitions of the bucket. A common example is the source_ip
 	Distinct            string                 `yaml:"distinct"`            // Distinct, when present, adds a `Pour()` processor that will only pour uniq items (based on distinct expr result)
@@ -61,9 +61,17 @@ type BucketFactory struct {
 	RunTimeGroupBy      *vm.Program            `json:"-"`
 	Data                []*types.DataSource    `yaml:"data,omitempty"`
 	DataDir             string                 `yaml:"-"`
-	CancelOnFilter      string                 `yaml:"cancel_on,omitempty"` // a filter that, if matched, kills the bucket
+	CancelOnFilter      string                 `yaml:"cancel_on,omitempty"`       // a filter that, if matched, kills the bucket
+	Shared              bool                   `yaml:"shared,omitempty"`          // Shared, if true, aggregates the level of the bucket with the other agents using the same shared state
+	Sequence            []SequenceStep         `yaml:"sequence,omitempty"`        // Sequence is the ordered list of steps of a 'sequence' bucket
+	Anomaly             *AnomalyCfg            `yaml:"anomaly,omitempty"`         // Anomaly configures how an 'anomaly' bucket learns its baseline
+	MaxBuckets          int                    `yaml:"max_buckets,omitempty"`     // MaxBuckets, if > 0, limits the number of live buckets (partitions) of the scenario
+	EvictionPolicy      string                 `yaml:"eviction_policy,omitempty"` // EvictionPolicy picks the buckets to evict when MaxBuckets is reached: oldest (default) or least-filled
+	ShadowOf            string                 `yaml:"shadow_of,omitempty"`       // ShadowOf, if present, is the name of the scenario this one is a candidate version of: its overflows are compared with the ones of ShadowOf, and never sent
+	ShadowWindow        string                 `yaml:"shadow_window,omitempty"`   // ShadowWindow is how long an overflow waits for the other version of the scenario to overflow for the same source
 	leakspeed           time.Duration          // internal representation of `Leakspeed`
 	duration            time.Duration          // internal representation of `Duration`
+	shadowWindow        time.Duration          // internal representation of `ShadowWindow`
 	ret                 chan types.Event       // the bucket-specific output chan for overflows
 	processors          []Processor            // processors is the list of hooks for pour/overflow/create (cf. uniq, blackhole etc.)
 	output              bool                   // ??
@@ -73,6 +81,9 @@ type BucketFactory struct {
 	tomb                *tomb.Tomb
 	wgPour              *sync.WaitGroup
 	wgDumpState         *sync.WaitGroup
+	sharedState         *sharedStatePourer
+	baselines           *AnomalyBaselines
+	shadows             *ShadowTracker
 	orderEvent          bool
 }
 
@@ -167,6 +178,26 @@ func validateBayesianType(bucketFactory *BucketFactory) error {
 	return nil
 }
 
+func validateWindowType(bucketFactory *BucketFactory) error {
+	if bucketFactory.Capacity <= 0 {
+		return fmt.Errorf("bad capacity for window bucket '%d'", bucketFactory.Capacity)
+	}
+
+	if bucketFactory.Duration == "" {
+		return errors.New("duration can't be empty for window bucket")
+	}
+
+	if bucketFactory.duration <= 0 {
+		return fmt.Errorf("bad duration for window bucket '%s'", bucketFactory.Duration)
+	}
+
+	if bucketFactory.LeakSpeed != "" {
+		return errors.New("window bucket can't have a leakspeed")
+	}
+
+	return nil
+}
+
 func ValidateFactory(bucketFactory *BucketFactory) error {
 	if bucketFactory.Name == "" {
 		return errors.New("bucket must have name")
@@ -176,6 +207,22 @@ func ValidateFactory(bucketFactory *BucketFactory) error {
 		return errors.New("description is mandatory")
 	}
 
+	if bucketFactory.MaxBuckets < 0 {
+		return fmt.Errorf("bad max_buckets '%d'", bucketFactory.MaxBuckets)
+	}
+
+	if err := validateEvictionPolicy(bucketFactory.EvictionPolicy); err != nil {
+		return err
+	}
+
+	if err := validateShadow(bucketFactory); err != nil {
+		return err
+	}
+
+	if bucketFactory.Shared && bucketFactory.Type != "leaky" {
+		return fmt.Errorf("shared is only supported by leaky buckets, not '%s'", bucketFactory.Type)
+	}
+
 	switch bucketFactory.Type {
 	case "leaky":
 		if err := validateLeakyType(bucketFactory); err != nil {
@@ -197,6 +244,18 @@ func ValidateFactory(bucketFactory *BucketFactory) error {
 		if err := validateBayesianType(bucketFactory); err != nil {
 			return err
 		}
+	case "window":
+		if err := validateWindowType(bucketFactory); err != nil {
+			return err
+		}
+	case "sequence":
+		if err := validateSequenceType(bucketFactory); err != nil {
+			return err
+		}
+	case "anomaly":
+		if err := validateAnomalyType(bucketFactory); err != nil {
+			return err
+		}
 	default:
 		return fmt.Errorf("unknown bucket type '%s'", bucketFactory.Type)
 	}
@@ -301,6 +360,11 @@ func loadBucketFactoriesFromFile(item *cwhub.Item, hub *cwhub.Hub, buckets *Buck
 
 		bucketFactory.wgDumpState = buckets.wgDumpState
 		bucketFactory.wgPour = buckets.wgPour
+		if bucketFactory.Shared {
+			bucketFactory.sharedState = buckets.sharedStatePourer(tomb)
+		}
+		bucketFactory.baselines = buckets.Baselines
+		bucketFactory.shadows = buckets.Shadows
 
 		err = LoadBucket(&bucketFactory, tomb)
 		if err != nil {
@@ -310,6 +374,10 @@ func loadBucketFactoriesFromFile(item *cwhub.Item, hub *cwhub.Hub, buckets *Buck
 
 		bucketFactory.orderEvent = orderEvent
 
+		if buckets.Limits != nil {
+			buckets.Limits.exposeLimit(&bucketFactory)
+		}
+
 		factories = append(factories, bucketFactory)
 	}
 
@@ -331,6 +399,10 @@ func LoadBuckets(cscfg *csconfig.CrowdsecServiceCfg, hub *cwhub.Hub, scenarios [
 		allFactories = append(allFactories, factories...)
 	}
 
+	if buckets.Shadows != nil {
+		linkShadows(allFactories, buckets.Shadows)
+	}
+
 	if err := alertcontext.NewAlertContext(cscfg.ContextToSend, cscfg.ConsoleContextValueLength); err != nil {
 		return nil, nil, fmt.Errorf("unable to load alert context: %w", err)
 	}
@@ -376,6 +448,14 @@ func LoadBucket(bucketFactory *BucketFactory, tomb *tomb.Tomb) error {
 		}
 	}
 
+	if bucketFactory.ShadowWindow != "" {
+		if bucketFactory.shadowWindow, err = time.ParseDuration(bucketFactory.ShadowWindow); err != nil {
+			return fmt.Errorf("invalid shadow_window '%s' in %s: %w", bucketFactory.ShadowWindow, bucketFactory.Filename, err)
+		}
+	} else if bucketFactory.ShadowOf != "" {
+		bucketFactory.shadowWindow = defaultShadowWindow
+	}
+
 	if bucketFactory.Filter == "" {
 		bucketFactory.logger.Warning("Bucket without filter, abort.")
 		return errors.New("bucket without filter directive")
@@ -407,13 +487,30 @@ func LoadBucket(bucketFactory *BucketFactory, tomb *tomb.Tomb) error {
 		bucketFactory.processors = append(bucketFactory.processors, &DumbProcessor{})
 	case "bayesian":
 		bucketFactory.processors = append(bucketFactory.processors, &DumbProcessor{})
+	case "window":
+		bucketFactory.processors = append(bucketFactory.processors, &WindowBucket{})
+	case "sequence":
+		if err = compileSequence(bucketFactory); err != nil {
+			return fmt.Errorf("invalid sequence in %s: %w", bucketFactory.Filename, err)
+		}
+
+		bucketFactory.processors = append(bucketFactory.processors, &SequenceBucket{})
+	case "anomaly":
+		if err = compileAnomaly(bucketFactory); err != nil {
+			return fmt.Errorf("invalid anomaly in %s: %w", bucketFactory.Filename, err)
+		}
+
+		bucketFactory.processors = append(bucketFactory.processors, &AnomalyBucket{})
 	default:
 		return fmt.Errorf("invalid type '%s' in %s: %w", bucketFactory.Type, bucketFactory.Filename, err)
 	}
 
 	if bucketFactory.Distinct != "" {
-		bucketFactory.logger.Tracef("Adding a non duplicate filter")
-		bucketFactory.processors = append(bucketFactory.processors, &Uniq{})
+		// window buckets count distinct values themselves, as they have to forget them when they leave the window
+		if bucketFactory.Type != "window" {
+			bucketFactory.logger.Tracef("Adding a non duplicate filter")
+			bucketFactory.processors = append(bucketFactory.processors, &Uniq{})
+		}
 		// we're compiling and discarding the expression to be able to detect it during loading
 		_, err = expr.Compile(bucketFactory.Distinct, exprhelpers.GetExprOptions(map[string]interface{}{"evt": &types.Event{}})...)
 		if err != nil {
@@ -465,11 +562,22 @@ func LoadBucket(bucketFactory *BucketFactory, tomb *tomb.Tomb) error {
 		}
 	}
 
+	if bucketFactory.Shared {
+		bucketFactory.logger.Tracef("Adding shared bucket processor")
+		bucketFactory.processors = append(bucketFactory.processors, &SharedBucket{})
+	}
+
 	if bucketFactory.BayesianThreshold != 0 {
 		bucketFactory.logger.Tracef("Adding bayesian processor")
 		bucketFactory.processors = append(bucketFactory.processors, &BayesianBucket{})
 	}
 
+	// must be the last overflow processor, to only record the overflows that would have been sent
+	if bucketFactory.ShadowOf != "" {
+		bucketFactory.logger.Tracef("Adding shadow processor")
+		bucketFactory.processors = append(bucketFactory.processors, &ShadowScenario{})
+	}
+
 	for _, data := range bucketFactory.Data {
 		if data.DestPath == "" {
 			bucketFactory.logger.Errorf("no dest_file provided for '%s'", bucketFactory.Name)
@@ -511,8 +619,6 @@ func LoadBucketsState(file string, buckets *Buckets, bucketFactories []BucketFac
 	}
 
 	for k := range state {
-		var tbucket *Leaky
-
 		log.Debugf("Reloading bucket %s", k)
 
 		val, ok := buckets.Bucket_map.Load(k)
@@ -528,30 +634,21 @@ func LoadBucketsState(file string, buckets *Buckets, bucketFactories []BucketFac
 			}
 
 			log.Debugf("found factory %s/%s -> %s", h.Author, h.Name, h.Description)
-			// check in which mode the bucket was
-			if state[k].Mode == types.TIMEMACHINE {
-				tbucket = NewTimeMachine(h)
-			} else if state[k].Mode == types.LIVE {
-				tbucket = NewLeaky(h)
-			} else {
-				log.Errorf("Unknown bucket type : %d", state[k].Mode)
+
+			bs := BucketState{
+				Name:        state[k].Name,
+				Mode:        state[k].Mode,
+				Limiter:     state[k].SerializedState,
+				Queue:       state[k].Queue,
+				First_ts:    state[k].First_ts,
+				Last_ts:     state[k].Last_ts,
+				Ovflw_ts:    state[k].Ovflw_ts,
+				Total_count: state[k].Total_count,
+			}
+
+			if err := restoreBucket(k, bs, h, buckets); err != nil {
+				return err
 			}
-			/*Trying to restore queue state*/
-			tbucket.Queue = state[k].Queue
-			/*Trying to set the limiter to the saved values*/
-			tbucket.Limiter.Load(state[k].SerializedState)
-			tbucket.In = make(chan *types.Event)
-			tbucket.Mapkey = k
-			tbucket.Signal = make(chan bool, 1)
-			tbucket.First_ts = state[k].First_ts
-			tbucket.Last_ts = state[k].Last_ts
-			tbucket.Ovflw_ts = state[k].Ovflw_ts
-			tbucket.Total_count = state[k].Total_count
-			buckets.Bucket_map.Store(k, tbucket)
-			h.tomb.Go(func() error {
-				return LeakRoutine(tbucket)
-			})
-			<-tbucket.Signal
 
 			found = true
 
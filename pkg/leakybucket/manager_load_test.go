@@ -148,3 +148,92 @@ func TestBayesianBucketsConfig(t *testing.T) {
 		t.Fatalf("%s", err)
 	}
 }
+
+func TestWindowBucketsConfig(t *testing.T) {
+	CfgTests := []cfgTest{
+		// basic valid window
+		{BucketFactory{Name: "test", Description: "test1", Type: "window", Capacity: 2, Duration: "10s", Filter: "true"}, true, true},
+		// valid window with distinct
+		{BucketFactory{Name: "test", Description: "test1", Type: "window", Capacity: 2, Duration: "10s", Filter: "true", Distinct: "evt.Meta.foobar"}, true, true},
+		// missing duration
+		{BucketFactory{Name: "test", Description: "test1", Type: "window", Capacity: 2, Filter: "true"}, false, false},
+		// bad duration
+		{BucketFactory{Name: "test", Description: "test1", Type: "window", Capacity: 2, Duration: "abc", Filter: "true"}, false, false},
+		// bad capacity
+		{BucketFactory{Name: "test", Description: "test1", Type: "window", Capacity: 0, Duration: "10s", Filter: "true"}, false, false},
+		// leakspeed doesn't make sense
+		{BucketFactory{Name: "test", Description: "test1", Type: "window", Capacity: 2, Duration: "10s", LeakSpeed: "1s", Filter: "true"}, false, false},
+	}
+	if err := runTest(CfgTests); err != nil {
+		t.Fatalf("%s", err)
+	}
+}
+
+func TestSequenceBucketsConfig(t *testing.T) {
+	steps := func(timeout string) []SequenceStep {
+		return []SequenceStep{
+			{Filter: "evt.Meta.log_type == 'auth_failed'", Count: 3},
+			{Filter: "evt.Meta.log_type == 'auth_success'", Timeout: timeout},
+		}
+	}
+
+	CfgTests := []cfgTest{
+		// basic valid sequence
+		{BucketFactory{Name: "test", Description: "test1", Type: "sequence", Capacity: -1, Filter: "true", Sequence: steps("10s")}, true, true},
+		// missing steps
+		{BucketFactory{Name: "test", Description: "test1", Type: "sequence", Capacity: -1, Filter: "true"}, false, false},
+		// single step
+		{BucketFactory{Name: "test", Description: "test1", Type: "sequence", Capacity: -1, Filter: "true", Sequence: steps("10s")[:1]}, false, false},
+		// missing timeout
+		{BucketFactory{Name: "test", Description: "test1", Type: "sequence", Capacity: -1, Filter: "true", Sequence: steps("")}, false, false},
+		// bad timeout
+		{BucketFactory{Name: "test", Description: "test1", Type: "sequence", Capacity: -1, Filter: "true", Sequence: steps("abc")}, false, false},
+		// bad step filter
+		{BucketFactory{Name: "test", Description: "test1", Type: "sequence", Capacity: -1, Filter: "true", Sequence: []SequenceStep{{Filter: "xu"}, {Filter: "true", Timeout: "10s"}}}, false, false},
+		// capacity must be -1
+		{BucketFactory{Name: "test", Description: "test1", Type: "sequence", Capacity: 1, Filter: "true", Sequence: steps("10s")}, false, false},
+	}
+	if err := runTest(CfgTests); err != nil {
+		t.Fatalf("%s", err)
+	}
+}
+
+func TestAnomalyBucketsConfig(t *testing.T) {
+	CfgTests := []cfgTest{
+		// basic valid anomaly
+		{BucketFactory{Name: "test", Description: "test1", Type: "anomaly", Capacity: -1, Filter: "true", Anomaly: &AnomalyCfg{Interval: "1m", Sigma: 3}}, true, true},
+		// missing anomaly configuration
+		{BucketFactory{Name: "test", Description: "test1", Type: "anomaly", Capacity: -1, Filter: "true"}, false, false},
+		// missing interval
+		{BucketFactory{Name: "test", Description: "test1", Type: "anomaly", Capacity: -1, Filter: "true", Anomaly: &AnomalyCfg{Sigma: 3}}, false, false},
+		// bad interval
+		{BucketFactory{Name: "test", Description: "test1", Type: "anomaly", Capacity: -1, Filter: "true", Anomaly: &AnomalyCfg{Interval: "abc", Sigma: 3}}, false, false},
+		// missing sigma
+		{BucketFactory{Name: "test", Description: "test1", Type: "anomaly", Capacity: -1, Filter: "true", Anomaly: &AnomalyCfg{Interval: "1m"}}, false, false},
+		// bad alpha
+		{BucketFactory{Name: "test", Description: "test1", Type: "anomaly", Capacity: -1, Filter: "true", Anomaly: &AnomalyCfg{Interval: "1m", Sigma: 3, Alpha: 1.5}}, false, false},
+		// capacity must be -1
+		{BucketFactory{Name: "test", Description: "test1", Type: "anomaly", Capacity: 5, Filter: "true", Anomaly: &AnomalyCfg{Interval: "1m", Sigma: 3}}, false, false},
+	}
+	if err := runTest(CfgTests); err != nil {
+		t.Fatalf("%s", err)
+	}
+}
+
+func TestShadowBucketsConfig(t *testing.T) {
+	CfgTests := []cfgTest{
+		// basic valid shadow
+		{BucketFactory{Name: "test-candidate", Description: "test1", Type: "leaky", Capacity: 1, LeakSpeed: "1s", Filter: "true", ShadowOf: "test"}, true, true},
+		// custom window
+		{BucketFactory{Name: "test-candidate", Description: "test1", Type: "leaky", Capacity: 1, LeakSpeed: "1s", Filter: "true", ShadowOf: "test", ShadowWindow: "1m"}, true, true},
+		// bad window
+		{BucketFactory{Name: "test-candidate", Description: "test1", Type: "leaky", Capacity: 1, LeakSpeed: "1s", Filter: "true", ShadowOf: "test", ShadowWindow: "abc"}, false, false},
+		// window without shadow_of
+		{BucketFactory{Name: "test-candidate", Description: "test1", Type: "leaky", Capacity: 1, LeakSpeed: "1s", Filter: "true", ShadowWindow: "1m"}, false, false},
+		// can't shadow itself
+		{BucketFactory{Name: "test", Description: "test1", Type: "leaky", Capacity: 1, LeakSpeed: "1s", Filter: "true", ShadowOf: "test"}, false, false},
+	}
+	if err := runTest(CfgTests); err != nil {
+		t.Fatalf("%s", err)
+	}
+}
@@ -41,13 +41,27 @@ func GarbageCollectBuckets(deadline time.Time, buckets *Buckets) error {
 		val := rvalue.(*Leaky)
 		total += 1
 		//bucket already overflowed, we can kill it
-		if !val.Ovflw_ts.IsZero() {
+		if ovflwTs := val.overflowTs(); !ovflwTs.IsZero() {
 			discard += 1
-			val.logger.Debugf("overflowed at %s.", val.Ovflw_ts)
+			val.logger.Debugf("overflowed at %s.", ovflwTs)
 			toflush = append(toflush, key)
 			val.tomb.Kill(nil)
 			return true
 		}
+		//window, sequence and anomaly buckets don't leak, they are dead once all their events have left the window,
+		//when the current step of the sequence can't be completed anymore, or after an interval without events
+		if val.BucketConfig.Type == "window" || val.BucketConfig.Type == "sequence" || val.BucketConfig.Type == "anomaly" {
+			val.mutex.Lock()
+			lastTs := val.Last_ts
+			val.mutex.Unlock()
+			if !lastTs.Add(val.Duration).After(deadline) {
+				BucketsUnderflow.With(prometheus.Labels{"name": val.Name}).Inc()
+				val.logger.Debugf("UNDERFLOW : last_ts:%s window:%s", lastTs, val.Duration)
+				toflush = append(toflush, key)
+				val.tomb.Kill(nil)
+			}
+			return true
+		}
 		/*FIXME : sometimes the gettokenscountat has some rounding issues when we try to
 		match it with bucket capacity, even if the bucket has long due underflow. Round to 2 decimals*/
 		tokat := val.Limiter.GetTokensCountAt(deadline)
@@ -76,6 +90,9 @@ func GarbageCollectBuckets(deadline time.Time, buckets *Buckets) error {
 	for _, flushkey := range toflush {
 		buckets.Bucket_map.Delete(flushkey)
 	}
+	if buckets.Shadows != nil {
+		buckets.Shadows.Expire(deadline)
+	}
 	return nil
 }
 
@@ -103,9 +120,9 @@ func DumpBucketsStateAt(deadline time.Time, outputdir string, buckets *Buckets)
 		key := rkey.(string)
 		val := rvalue.(*Leaky)
 		total += 1
-		if !val.Ovflw_ts.IsZero() {
+		if ovflwTs := val.overflowTs(); !ovflwTs.IsZero() {
 			discard += 1
-			val.logger.Debugf("overflowed at %s.", val.Ovflw_ts)
+			val.logger.Debugf("overflowed at %s.", ovflwTs)
 			return true
 		}
 		/*FIXME : sometimes the gettokenscountat has some rounding issues when we try to
@@ -180,7 +197,7 @@ func PourItemToBucket(bucket *Leaky, holder BucketFactory, buckets *Buckets, par
 			if !ok {
 				//the bucket was found and dead, get a new one and continue
 				bucket.logger.Tracef("Bucket %s found dead, cleanup the body", buckey)
-				buckets.Bucket_map.Delete(buckey)
+				buckets.Bucket_map.CompareAndDelete(buckey, bucket)
 				sigclosed += 1
 				bucket, err = LoadOrStoreBucketFromHolder(buckey, buckets, holder, parsed.ExpectMode)
 				if err != nil {
@@ -209,7 +226,7 @@ func PourItemToBucket(bucket *Leaky, holder BucketFactory, buckets *Buckets, par
 				}
 				if d.After(lastTs.Add(bucket.Duration)) {
 					bucket.logger.Tracef("bucket is expired (curr event: %s, bucket deadline: %s), kill", d, lastTs.Add(bucket.Duration))
-					buckets.Bucket_map.Delete(buckey)
+					buckets.Bucket_map.CompareAndDelete(buckey, bucket)
 					//not sure about this, should we create a new one ?
 					sigclosed += 1
 					bucket, err = LoadOrStoreBucketFromHolder(buckey, buckets, holder, parsed.ExpectMode)
@@ -251,6 +268,10 @@ func LoadOrStoreBucketFromHolder(partitionKey string, buckets *Buckets, holder B
 	if !ok {
 		var fresh_bucket *Leaky
 
+		if buckets.Limits != nil {
+			buckets.Limits.makeRoom(buckets, &holder)
+		}
+
 		switch expectMode {
 		case types.TIMEMACHINE:
 			fresh_bucket = NewTimeMachine(holder)
@@ -264,6 +285,7 @@ func LoadOrStoreBucketFromHolder(partitionKey string, buckets *Buckets, holder B
 		fresh_bucket.In = make(chan *types.Event)
 		fresh_bucket.Mapkey = partitionKey
 		fresh_bucket.Signal = make(chan bool, 1)
+		fresh_bucket.limits = buckets.Limits
 		actual, stored := buckets.Bucket_map.LoadOrStore(partitionKey, fresh_bucket)
 		if !stored {
 			holder.tomb.Go(func() error {
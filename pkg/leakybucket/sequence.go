@@ -0,0 +1,251 @@
+package leakybucket
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+
+	"github.com/crowdsecurity/crowdsec/pkg/exprhelpers"
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+// number of events kept in the queue of a sequence bucket: the events of the overflow are the ones matched
+// by the steps, the queue only keeps the last events poured
+const sequenceQueueSize = 10
+
+// SequenceStepMetaKey is added to the meta of the events of a sequence overflow, to tell which step they matched
+const SequenceStepMetaKey = "sequence_step"
+
+// SequenceStep is one of the ordered steps of a `sequence` bucket
+type SequenceStep struct {
+	Name          string `yaml:"name,omitempty"`    // Name is used to tag the events of the step in the overflow, defaults to the index of the step
+	Filter        string `yaml:"filter"`            // Filter is an expr that determines if an event belongs to the step
+	Count         int    `yaml:"count,omitempty"`   // Count is the number of events needed to complete the step, defaults to 1
+	Timeout       string `yaml:"timeout,omitempty"` // Timeout is the time allowed to complete the step, counted from the end of the previous step (or from the first event of the first step)
+	timeout       time.Duration
+	RunTimeFilter *vm.Program `yaml:"-" json:"-"`
+}
+
+func (s *SequenceStep) label(idx int) string {
+	if s.Name != "" {
+		return s.Name
+	}
+
+	return strconv.Itoa(idx)
+}
+
+// compileSequence parses the timeouts and compiles the filters of the steps of a sequence bucket
+func compileSequence(bucketFactory *BucketFactory) error {
+	var err error
+
+	for idx := range bucketFactory.Sequence {
+		step := &bucketFactory.Sequence[idx]
+
+		if step.Filter == "" {
+			return fmt.Errorf("sequence step %d has no filter", idx)
+		}
+
+		step.RunTimeFilter, err = expr.Compile(step.Filter, exprhelpers.GetExprOptions(map[string]interface{}{"evt": &types.Event{}})...)
+		if err != nil {
+			return fmt.Errorf("invalid filter '%s' for sequence step %d: %w", step.Filter, idx, err)
+		}
+
+		if step.Count == 0 {
+			step.Count = 1
+		}
+
+		if step.Timeout != "" {
+			if step.timeout, err = time.ParseDuration(step.Timeout); err != nil {
+				return fmt.Errorf("invalid timeout '%s' for sequence step %d: %w", step.Timeout, idx, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateSequenceType(bucketFactory *BucketFactory) error {
+	if len(bucketFactory.Sequence) < 2 {
+		return errors.New("sequence bucket must have at least 2 steps")
+	}
+
+	if bucketFactory.Capacity != -1 {
+		return errors.New("sequence bucket must have capacity -1")
+	}
+
+	for idx, step := range bucketFactory.Sequence {
+		if step.RunTimeFilter == nil {
+			return fmt.Errorf("sequence step %d has no filter", idx)
+		}
+
+		if step.Count < 0 {
+			return fmt.Errorf("bad count for sequence step %d '%d'", idx, step.Count)
+		}
+
+		if step.timeout < 0 {
+			return fmt.Errorf("bad timeout for sequence step %d '%s'", idx, step.Timeout)
+		}
+
+		// without a timeout, a sequence could be completed days after it started
+		if idx > 0 && step.timeout == 0 {
+			return fmt.Errorf("timeout can't be empty for sequence step %d", idx)
+		}
+	}
+
+	return nil
+}
+
+// sequenceLifetime is the longest step timeout: a bucket that didn't receive any event for
+// that long can't complete its current step anymore
+func sequenceLifetime(bucketFactory *BucketFactory) time.Duration {
+	var lifetime time.Duration
+
+	for _, step := range bucketFactory.Sequence {
+		lifetime = max(lifetime, step.timeout)
+	}
+
+	return lifetime
+}
+
+// SequenceBucket is the processor of the `sequence` buckets: it overflows when the events
+// poured in the bucket match each of the steps, in order, and each step is completed
+// within its timeout. When a step times out, the sequence starts over.
+type SequenceBucket struct {
+	step    int             // index of the step we're waiting for
+	matched int             // number of events matched by the current step
+	since   time.Time       // start of the current step
+	events  [][]types.Event // events matched by each step
+	DumbProcessor
+}
+
+func (s *SequenceBucket) OnBucketInit(bucketFactory *BucketFactory) error {
+	s.reset(len(bucketFactory.Sequence))
+	return nil
+}
+
+func (s *SequenceBucket) reset(steps int) {
+	s.step = 0
+	s.matched = 0
+	s.since = time.Time{}
+	s.events = make([][]types.Event, steps)
+}
+
+func (s *SequenceBucket) AfterBucketPour(bucketFactory *BucketFactory) func(types.Event, *Leaky) *types.Event {
+	return func(msg types.Event, leaky *Leaky) *types.Event {
+		// Last_ts is the time of the event in time-machine mode, and the pour time in live mode
+		now := leaky.Last_ts
+
+		step := &bucketFactory.Sequence[s.step]
+
+		if (s.step > 0 || s.matched > 0) && step.timeout > 0 && now.Sub(s.since) > step.timeout {
+			leaky.logger.Debugf("sequence step %s timed out, starting over", step.label(s.step))
+			s.reset(len(bucketFactory.Sequence))
+			step = &bucketFactory.Sequence[0]
+		}
+
+		ret, err := exprhelpers.Run(step.RunTimeFilter, map[string]interface{}{"evt": &msg}, leaky.logger, bucketFactory.Debug)
+		if err != nil {
+			leaky.logger.Errorf("sequence step %s filter exec failed : %v", step.label(s.step), err)
+			return &msg
+		}
+
+		match, ok := ret.(bool)
+		if !ok {
+			leaky.logger.Errorf("sequence step %s filter unexpected non-bool return : %T", step.label(s.step), ret)
+			return &msg
+		}
+
+		if !match {
+			return &msg
+		}
+
+		if s.step == 0 && s.matched == 0 {
+			s.since = now
+		}
+
+		s.events[s.step] = append(s.events[s.step], tagSequenceEvent(msg, step.label(s.step)))
+		s.matched++
+
+		leaky.logger.Tracef("sequence step %s: %d/%d", step.label(s.step), s.matched, step.Count)
+
+		if s.matched < step.Count {
+			return &msg
+		}
+
+		s.step++
+		s.matched = 0
+		s.since = now
+
+		if s.step < len(bucketFactory.Sequence) {
+			return &msg
+		}
+
+		leaky.logger.Debugf("Sequence bucket overflow")
+
+		queue := types.NewQueue(-1)
+
+		for _, events := range s.events {
+			for _, evt := range events {
+				queue.Add(evt)
+			}
+		}
+
+		leaky.Ovflw_ts = leaky.Last_ts
+		leaky.Out <- queue
+
+		return nil
+	}
+}
+
+// tagSequenceEvent returns a copy of the event with the step it matched in its meta
+func tagSequenceEvent(evt types.Event, step string) types.Event {
+	meta := make(map[string]string, len(evt.Meta)+1)
+	for k, v := range evt.Meta {
+		meta[k] = v
+	}
+
+	meta[SequenceStepMetaKey] = step
+	evt.Meta = meta
+
+	return evt
+}
+
+type sequenceState struct {
+	Step    int             `json:"step"`
+	Matched int             `json:"matched"`
+	Since   time.Time       `json:"since"`
+	Events  [][]types.Event `json:"events"`
+}
+
+func (s *SequenceBucket) dumpState() (json.RawMessage, error) {
+	return json.Marshal(sequenceState{
+		Step:    s.step,
+		Matched: s.matched,
+		Since:   s.since,
+		Events:  s.events,
+	})
+}
+
+func (s *SequenceBucket) loadState(state json.RawMessage) error {
+	var st sequenceState
+
+	if err := json.Unmarshal(state, &st); err != nil {
+		return err
+	}
+
+	if len(st.Events) != len(s.events) || st.Step < 0 || st.Step >= len(s.events) {
+		return fmt.Errorf("state for %d steps at step %d, the sequence has %d steps", len(st.Events), st.Step, len(s.events))
+	}
+
+	s.step = st.Step
+	s.matched = st.Matched
+	s.since = st.Since
+	s.events = st.Events
+
+	return nil
+}
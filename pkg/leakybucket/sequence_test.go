@@ -0,0 +1,140 @@
+package leakybucket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/tomb.v2"
+
+	"github.com/crowdsecurity/crowdsec/pkg/models"
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+func TestSequenceOverflowEvents(t *testing.T) {
+	tmb := &tomb.Tomb{}
+	defer tmb.Kill(nil)
+
+	buckets := NewBuckets()
+	out := make(chan types.Event, 10)
+
+	holders := []BucketFactory{
+		{
+			Name:        "test/sequence",
+			Description: "test/sequence",
+			Type:        "sequence",
+			Capacity:    -1,
+			Filter:      "true",
+			GroupBy:     "evt.Meta.source_ip",
+			Sequence: []SequenceStep{
+				{Name: "recon", Filter: "evt.Meta.log_type == 'scan'", Count: 2, Timeout: "1m"},
+				{Name: "exploit", Filter: "evt.Meta.log_type == 'exploit'", Timeout: "1m"},
+			},
+			ret:         out,
+			wgDumpState: buckets.wgDumpState,
+			wgPour:      buckets.wgPour,
+		},
+	}
+
+	require.NoError(t, LoadBucket(&holders[0], tmb))
+
+	pour := func(logType string) {
+		now, err := time.Now().UTC().MarshalText()
+		require.NoError(t, err)
+
+		evt := types.Event{Meta: map[string]string{"source_ip": "1.2.3.4", "log_type": logType}, MarshaledTime: string(now)}
+
+		ok, err := PourItemToHolders(evt, holders, buckets)
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+
+	// out of order: the exploit doesn't count before the recon is done
+	pour("exploit")
+	pour("scan")
+	pour("noise")
+	pour("scan")
+	pour("exploit")
+
+	var alert *models.Alert
+
+	select {
+	case evt := <-out:
+		alert = evt.Overflow.Alert
+	case <-time.After(2 * time.Second):
+		t.Fatal("no overflow")
+	}
+
+	require.NotNil(t, alert)
+	assert.Equal(t, int32(5), *alert.EventsCount)
+
+	steps := []string{}
+
+	for _, evt := range alert.Events {
+		for _, meta := range evt.Meta {
+			if meta.Key == SequenceStepMetaKey {
+				steps = append(steps, meta.Value)
+			}
+		}
+	}
+
+	assert.Equal(t, []string{"recon", "recon", "exploit"}, steps)
+}
+
+func TestSequenceQueueSize(t *testing.T) {
+	tmb := &tomb.Tomb{}
+	defer tmb.Kill(nil)
+
+	buckets := NewBuckets()
+
+	holders := []BucketFactory{
+		{
+			Name:        "test/sequence",
+			Description: "test/sequence",
+			Type:        "sequence",
+			Capacity:    -1,
+			Filter:      "true",
+			GroupBy:     "evt.Meta.source_ip",
+			Sequence: []SequenceStep{
+				{Name: "recon", Filter: "evt.Meta.log_type == 'scan'", Timeout: "1m"},
+				{Name: "exploit", Filter: "evt.Meta.log_type == 'exploit'", Timeout: "1m"},
+			},
+			ret:         make(chan types.Event, 10),
+			wgDumpState: buckets.wgDumpState,
+			wgPour:      buckets.wgPour,
+		},
+	}
+
+	require.NoError(t, LoadBucket(&holders[0], tmb))
+
+	// a steady flow of events that don't complete the sequence
+	for range 5 * sequenceQueueSize {
+		now, err := time.Now().UTC().MarshalText()
+		require.NoError(t, err)
+
+		evt := types.Event{Meta: map[string]string{"source_ip": "1.2.3.4", "log_type": "noise"}, MarshaledTime: string(now)}
+
+		ok, err := PourItemToHolders(evt, holders, buckets)
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+
+	value, ok := buckets.Bucket_map.Load(GetKey(holders[0], "1.2.3.4"))
+	require.True(t, ok)
+
+	leaky, ok := value.(*Leaky)
+	require.True(t, ok)
+
+	require.Eventually(t, func() bool {
+		leaky.mutex.Lock()
+		defer leaky.mutex.Unlock()
+
+		return leaky.Total_count == 5*sequenceQueueSize
+	}, 2*time.Second, 10*time.Millisecond)
+
+	leaky.mutex.Lock()
+	defer leaky.mutex.Unlock()
+
+	assert.LessOrEqual(t, len(leaky.Queue.GetQueue()), sequenceQueueSize+1)
+}
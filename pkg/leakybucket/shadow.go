@@ -0,0 +1,258 @@
+package leakybucket
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+// how long an overflow waits for the same source to overflow in the other version of the scenario
+const defaultShadowWindow = 5 * time.Minute
+
+const (
+	// ShadowBoth counts the sources that overflowed in both versions of the scenario
+	ShadowBoth = "both"
+	// ShadowOnlyActive counts the sources that only overflowed in the active scenario
+	ShadowOnlyActive = "only_active"
+	// ShadowOnlyShadow counts the sources that only overflowed in the shadow scenario
+	ShadowOnlyShadow = "only_shadow"
+)
+
+var ScenarioShadowOverflows = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "cs_scenario_shadow_total",
+		Help: "Overflows of the scenarios running in shadow mode, compared with the active version.",
+	},
+	[]string{"name", "shadow", "result"},
+)
+
+type shadowPair struct {
+	active string
+	shadow string
+	window time.Duration
+}
+
+type shadowKey struct {
+	active string
+	shadow string
+	source string
+}
+
+// shadowOverflow is an overflow waiting for its counterpart in the other version of the scenario
+type shadowOverflow struct {
+	fromShadow bool
+	expiry     time.Time
+}
+
+// ShadowTracker compares the overflows of the scenarios with the ones of their shadow
+// versions (scenarios with `shadow_of`), source by source. An overflow that is not matched
+// by the other version of the scenario within the window is counted as a divergence.
+type ShadowTracker struct {
+	mu      sync.Mutex
+	pairs   map[string][]shadowPair // by name of the active scenario
+	shadows map[string]shadowPair   // by name of the shadow scenario
+	pending map[shadowKey][]shadowOverflow
+}
+
+func NewShadowTracker() *ShadowTracker {
+	return &ShadowTracker{
+		pairs:   make(map[string][]shadowPair),
+		shadows: make(map[string]shadowPair),
+		pending: make(map[shadowKey][]shadowOverflow),
+	}
+}
+
+// Enabled returns true if at least one scenario runs in shadow mode
+func (s *ShadowTracker) Enabled() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.shadows) > 0
+}
+
+func (s *ShadowTracker) link(active string, shadow string, window time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pair := shadowPair{active: active, shadow: shadow, window: window}
+	s.shadows[shadow] = pair
+	s.pairs[active] = append(s.pairs[active], pair)
+}
+
+// record adds the overflow of a scenario (or of its shadow) for the given sources, and
+// matches it against the pending overflows of the other version
+func (s *ShadowTracker) record(name string, fromShadow bool, sources []string, ts time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire(ts)
+
+	var pairs []shadowPair
+
+	if fromShadow {
+		pair, ok := s.shadows[name]
+		if !ok {
+			return
+		}
+
+		pairs = []shadowPair{pair}
+	} else {
+		pairs = s.pairs[name]
+	}
+
+	for _, pair := range pairs {
+		for _, source := range sources {
+			key := shadowKey{active: pair.active, shadow: pair.shadow, source: source}
+
+			idx := slices.IndexFunc(s.pending[key], func(o shadowOverflow) bool {
+				return o.fromShadow != fromShadow
+			})
+
+			if idx >= 0 {
+				s.pending[key] = slices.Delete(s.pending[key], idx, idx+1)
+				if len(s.pending[key]) == 0 {
+					delete(s.pending, key)
+				}
+
+				ScenarioShadowOverflows.With(prometheus.Labels{"name": pair.active, "shadow": pair.shadow, "result": ShadowBoth}).Inc()
+
+				continue
+			}
+
+			s.pending[key] = append(s.pending[key], shadowOverflow{fromShadow: fromShadow, expiry: ts.Add(pair.window)})
+		}
+	}
+}
+
+// Expire counts the overflows that were not matched before the end of their window as divergences
+func (s *ShadowTracker) Expire(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire(now)
+}
+
+// expire must be called with the lock held
+func (s *ShadowTracker) expire(now time.Time) {
+	for key, overflows := range s.pending {
+		kept := overflows[:0]
+
+		for _, o := range overflows {
+			if o.expiry.After(now) {
+				kept = append(kept, o)
+				continue
+			}
+
+			result := ShadowOnlyActive
+			if o.fromShadow {
+				result = ShadowOnlyShadow
+			}
+
+			log.Debugf("shadow: %s overflowed %s for %s", key.source, result, key.shadow)
+			ScenarioShadowOverflows.With(prometheus.Labels{"name": key.active, "shadow": key.shadow, "result": result}).Inc()
+		}
+
+		if len(kept) == 0 {
+			delete(s.pending, key)
+			continue
+		}
+
+		s.pending[key] = kept
+	}
+}
+
+func validateShadow(bucketFactory *BucketFactory) error {
+	if bucketFactory.ShadowOf == "" {
+		if bucketFactory.ShadowWindow != "" {
+			return errors.New("shadow_window requires shadow_of")
+		}
+
+		return nil
+	}
+
+	if bucketFactory.ShadowOf == bucketFactory.Name {
+		return errors.New("a scenario can't be its own shadow")
+	}
+
+	if bucketFactory.shadowWindow <= 0 {
+		return fmt.Errorf("bad shadow_window '%s'", bucketFactory.ShadowWindow)
+	}
+
+	return nil
+}
+
+// linkShadows registers the scenarios running in shadow mode, and adds a processor to
+// the scenarios they shadow, so that their overflows can be compared
+func linkShadows(factories []BucketFactory, tracker *ShadowTracker) {
+	for _, shadow := range factories {
+		if shadow.ShadowOf == "" {
+			continue
+		}
+
+		found := false
+
+		for idx := range factories {
+			active := &factories[idx]
+			if active.Name != shadow.ShadowOf {
+				continue
+			}
+
+			found = true
+
+			if !slices.ContainsFunc(active.processors, func(p Processor) bool {
+				_, ok := p.(*ShadowedScenario)
+				return ok
+			}) {
+				active.processors = append(active.processors, &ShadowedScenario{})
+			}
+		}
+
+		if !found {
+			shadow.logger.Warningf("scenario %s is not loaded, all the overflows of its shadow will diverge", shadow.ShadowOf)
+		}
+
+		tracker.link(shadow.ShadowOf, shadow.Name, shadow.shadowWindow)
+	}
+}
+
+// ShadowScenario is the processor of the scenarios running in shadow mode: their
+// overflows are recorded to be compared with the active scenario, but never sent.
+type ShadowScenario struct {
+	DumbProcessor
+}
+
+func (s *ShadowScenario) OnBucketOverflow(bucketFactory *BucketFactory) func(*Leaky, types.RuntimeAlert, *types.Queue) (types.RuntimeAlert, *types.Queue) {
+	return func(leaky *Leaky, alert types.RuntimeAlert, queue *types.Queue) (types.RuntimeAlert, *types.Queue) {
+		if bucketFactory.shadows != nil {
+			bucketFactory.shadows.record(bucketFactory.Name, true, alert.GetSources(), leaky.Ovflw_ts)
+		}
+
+		leaky.logger.Infof("Shadow overflow of %s for %v, discarded", bucketFactory.ShadowOf, alert.GetSources())
+
+		return types.RuntimeAlert{
+			Mapkey: leaky.Mapkey,
+		}, nil
+	}
+}
+
+// ShadowedScenario is added to the scenarios that have a shadow version, to record their overflows
+type ShadowedScenario struct {
+	DumbProcessor
+}
+
+func (s *ShadowedScenario) OnBucketOverflow(bucketFactory *BucketFactory) func(*Leaky, types.RuntimeAlert, *types.Queue) (types.RuntimeAlert, *types.Queue) {
+	return func(leaky *Leaky, alert types.RuntimeAlert, queue *types.Queue) (types.RuntimeAlert, *types.Queue) {
+		if bucketFactory.shadows != nil {
+			bucketFactory.shadows.record(bucketFactory.Name, false, alert.GetSources(), leaky.Ovflw_ts)
+		}
+
+		return alert, queue
+	}
+}
@@ -0,0 +1,80 @@
+package leakybucket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func shadowCount(active, shadow, result string) int {
+	return int(testutil.ToFloat64(ScenarioShadowOverflows.With(prometheus.Labels{"name": active, "shadow": shadow, "result": result})))
+}
+
+func TestShadowTracker(t *testing.T) {
+	tracker := NewShadowTracker()
+	assert.False(t, tracker.Enabled())
+
+	tracker.link("test/shadowed", "test/shadowed-v2", time.Minute)
+	assert.True(t, tracker.Enabled())
+
+	before := map[string]int{}
+	for _, result := range []string{ShadowBoth, ShadowOnlyActive, ShadowOnlyShadow} {
+		before[result] = shadowCount("test/shadowed", "test/shadowed-v2", result)
+	}
+
+	delta := func(result string) int {
+		return shadowCount("test/shadowed", "test/shadowed-v2", result) - before[result]
+	}
+
+	start := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	// both versions overflow for the same source, in any order
+	tracker.record("test/shadowed", false, []string{"1.2.3.4"}, start)
+	tracker.record("test/shadowed-v2", true, []string{"1.2.3.4"}, start.Add(30*time.Second))
+	tracker.record("test/shadowed-v2", true, []string{"2.2.3.4"}, start.Add(30*time.Second))
+	tracker.record("test/shadowed", false, []string{"2.2.3.4"}, start.Add(40*time.Second))
+
+	// only one version overflows
+	tracker.record("test/shadowed", false, []string{"3.3.3.3"}, start)
+	tracker.record("test/shadowed-v2", true, []string{"4.4.4.4"}, start)
+
+	// too late to match
+	tracker.record("test/shadowed", false, []string{"5.5.5.5"}, start)
+	tracker.record("test/shadowed-v2", true, []string{"5.5.5.5"}, start.Add(2*time.Minute))
+
+	// unknown scenarios are ignored
+	tracker.record("test/other", false, []string{"1.2.3.4"}, start)
+	tracker.record("test/other-v2", true, []string{"1.2.3.4"}, start)
+
+	assert.Equal(t, 2, delta(ShadowBoth))
+	// 3.3.3.3 and 5.5.5.5 (from the active scenario) expired when the last overflow was recorded
+	assert.Equal(t, 2, delta(ShadowOnlyActive))
+	assert.Equal(t, 1, delta(ShadowOnlyShadow))
+
+	tracker.Expire(start.Add(time.Hour))
+
+	assert.Equal(t, 2, delta(ShadowBoth))
+	assert.Equal(t, 2, delta(ShadowOnlyActive))
+	assert.Equal(t, 2, delta(ShadowOnlyShadow))
+	assert.Empty(t, tracker.pending)
+}
+
+func TestLinkShadows(t *testing.T) {
+	factories := []BucketFactory{
+		{Name: "test/active", processors: []Processor{&DumbProcessor{}}},
+		{Name: "test/candidate-1", ShadowOf: "test/active", shadowWindow: time.Minute},
+		{Name: "test/candidate-2", ShadowOf: "test/active", shadowWindow: time.Minute},
+	}
+
+	tracker := NewShadowTracker()
+	linkShadows(factories, tracker)
+
+	// the active scenario records its overflows once, whatever the number of shadows
+	assert.Len(t, factories[0].processors, 2)
+	assert.IsType(t, &ShadowedScenario{}, factories[0].processors[1])
+	assert.Len(t, tracker.pairs["test/active"], 2)
+	assert.Len(t, tracker.shadows, 2)
+}
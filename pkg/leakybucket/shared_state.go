@@ -0,0 +1,280 @@
+package leakybucket
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/tomb.v2"
+
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+// SharedStateBackend holds the level of buckets that are shared between several agents.
+// Each agent pouring into a shared bucket also pours into the backend, so that a
+// partition (ie. a source_ip) overflows when the sum of the events seen by all the
+// agents exceeds the capacity, even if no single agent saw enough of them.
+type SharedStateBackend interface {
+	// Pour adds count events to the shared bucket identified by key, after leaking
+	// one event per leakspeed since the previous pour, and returns the new level.
+	Pour(ctx context.Context, key string, count int, leakspeed time.Duration) (float64, error)
+	// Reset empties the shared bucket identified by key.
+	Reset(ctx context.Context, key string) error
+}
+
+// how often the memory backend forgets about the buckets that are fully leaked
+const sharedLevelSweepInterval = time.Minute
+
+type sharedLevel struct {
+	level     float64
+	last      time.Time
+	leakspeed time.Duration
+}
+
+// levelAt returns the level of the bucket at the given time, once leaked
+func (s *sharedLevel) levelAt(now time.Time) float64 {
+	if s.leakspeed <= 0 || !now.After(s.last) {
+		return s.level
+	}
+
+	leaked := float64(now.Sub(s.last)) / float64(s.leakspeed)
+
+	return math.Max(0, s.level-leaked)
+}
+
+// MemoryStateBackend is the default SharedStateBackend. When used by a single
+// agent, it only aggregates local events. The agents configured with the "lapi"
+// shared state pour into the database of the LAPI instead, through the backend set by crowdsec.
+type MemoryStateBackend struct {
+	mu        sync.Mutex
+	levels    map[string]*sharedLevel
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryStateBackend() *MemoryStateBackend {
+	return &MemoryStateBackend{
+		levels: make(map[string]*sharedLevel),
+		now:    time.Now,
+	}
+}
+
+func (m *MemoryStateBackend) Pour(_ context.Context, key string, count int, leakspeed time.Duration) (float64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now().UTC()
+
+	if now.Sub(m.lastSweep) > sharedLevelSweepInterval {
+		m.sweep(now)
+	}
+
+	shared, ok := m.levels[key]
+	if !ok {
+		shared = &sharedLevel{}
+		m.levels[key] = shared
+	}
+
+	shared.level = shared.levelAt(now) + float64(count)
+	shared.last = now
+	shared.leakspeed = leakspeed
+
+	return shared.level, nil
+}
+
+func (m *MemoryStateBackend) Reset(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.levels, key)
+
+	return nil
+}
+
+// sweep removes the buckets that are empty, must be called with the lock held
+func (m *MemoryStateBackend) sweep(now time.Time) {
+	for key, shared := range m.levels {
+		if shared.levelAt(now) <= 0 {
+			delete(m.levels, key)
+		}
+	}
+
+	m.lastSweep = now
+}
+
+// how often the pours in the shared buckets are sent to the backend
+const sharedStateFlushInterval = time.Second
+
+// sharedStatePourer batches the pours in the shared buckets. The events are counted locally
+// and sent to the backend on a ticker, so that a slow or unreachable backend never holds the
+// leak routines. The overflow is decided on the last level returned by the backend, leaked
+// since, plus the local events that were not sent yet: the pours of the other agents are
+// only taken into account after the next flush.
+type sharedStatePourer struct {
+	backend  SharedStateBackend
+	interval time.Duration
+	mu       sync.Mutex
+	pending  map[string]*sharedLevel // events poured locally since the last flush, in level
+	inflight map[string]*sharedLevel // events being sent by the current flush
+	known    map[string]*sharedLevel // last level returned by the backend
+	resets   map[string]bool         // buckets that overflowed since the last flush
+	now      func() time.Time
+}
+
+func newSharedStatePourer(backend SharedStateBackend) *sharedStatePourer {
+	return &sharedStatePourer{
+		backend:  backend,
+		interval: sharedStateFlushInterval,
+		pending:  make(map[string]*sharedLevel),
+		known:    make(map[string]*sharedLevel),
+		resets:   make(map[string]bool),
+		now:      time.Now,
+	}
+}
+
+// pour counts one event in the shared bucket and returns its estimated level
+func (p *sharedStatePourer) pour(key string, leakspeed time.Duration) float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now().UTC()
+
+	pending, ok := p.pending[key]
+	if !ok {
+		pending = &sharedLevel{}
+		p.pending[key] = pending
+	}
+
+	pending.level++
+	pending.leakspeed = leakspeed
+
+	level := pending.level
+
+	if inflight, ok := p.inflight[key]; ok {
+		level += inflight.level
+	}
+
+	if known, ok := p.known[key]; ok {
+		level += known.levelAt(now)
+	}
+
+	return level
+}
+
+// overflowed forgets about the bucket, and resets it on the backend with the next flush
+func (p *sharedStatePourer) overflowed(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.pending, key)
+	delete(p.inflight, key)
+	delete(p.known, key)
+	p.resets[key] = true
+}
+
+// flush sends the resets and the pending pours to the backend
+func (p *sharedStatePourer) flush(ctx context.Context) {
+	p.mu.Lock()
+	pending := p.pending
+	resets := p.resets
+	p.inflight = pending
+	p.pending = make(map[string]*sharedLevel)
+	p.resets = make(map[string]bool)
+	p.mu.Unlock()
+
+	for key := range resets {
+		if err := p.backend.Reset(ctx, key); err != nil {
+			log.Warningf("unable to reset shared bucket %s: %s", key, err)
+		}
+	}
+
+	for key, pour := range pending {
+		level, err := p.backend.Pour(ctx, key, int(pour.level), pour.leakspeed)
+
+		p.mu.Lock()
+
+		_, sent := p.inflight[key]
+		delete(p.inflight, key)
+
+		switch {
+		case err != nil:
+			// the events are only counted in the local bucket
+			log.Warningf("unable to pour in shared bucket %s: %s", key, err)
+		case sent:
+			p.known[key] = &sharedLevel{level: level, last: p.now().UTC(), leakspeed: pour.leakspeed}
+		default:
+			// the bucket overflowed while we were pouring
+		}
+
+		p.mu.Unlock()
+	}
+
+	p.mu.Lock()
+	p.inflight = nil
+	now := p.now().UTC()
+
+	for key, known := range p.known {
+		if known.levelAt(now) <= 0 {
+			delete(p.known, key)
+		}
+	}
+	p.mu.Unlock()
+}
+
+// run flushes the pours until the tomb dies
+func (p *sharedStatePourer) run(t *tomb.Tomb) error {
+	ctx := t.Context(context.Background())
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.flush(ctx)
+		case <-t.Dying():
+			return nil
+		}
+	}
+}
+
+// SharedBucket is the processor of the scenarios that have `shared: true`. On top
+// of the local leaky bucket, it pours every event into the shared state backend
+// and makes the bucket overflow when the shared level exceeds the capacity.
+type SharedBucket struct {
+	DumbProcessor
+}
+
+func (s *SharedBucket) AfterBucketPour(b *BucketFactory) func(types.Event, *Leaky) *types.Event {
+	return func(msg types.Event, l *Leaky) *types.Event {
+		// the shared level is computed on the backend clock, it doesn't make sense in time-machine mode
+		if l.Mode != types.LIVE || b.sharedState == nil {
+			return &msg
+		}
+
+		// the bucket already overflowed locally
+		if !l.Ovflw_ts.IsZero() {
+			return &msg
+		}
+
+		level := b.sharedState.pour(l.Mapkey, b.leakspeed)
+
+		l.logger.Tracef("shared bucket level: %f/%d", level, b.Capacity)
+
+		if level <= float64(b.Capacity) {
+			return &msg
+		}
+
+		l.logger.Debugf("Shared bucket overflow (level %.2f)", level)
+
+		// the overflow is ours, don't let the other agents raise the same one
+		b.sharedState.overflowed(l.Mapkey)
+
+		l.Ovflw_ts = l.Last_ts
+		l.Out <- l.Queue
+
+		return nil
+	}
+}
@@ -0,0 +1,221 @@
+package leakybucket
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/tomb.v2"
+
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+type testAgent struct {
+	buckets *Buckets
+	holders []BucketFactory
+	out     chan types.Event
+}
+
+// newTestAgent loads the same shared scenario as every other agent of the test, as if it was running on another host
+func newTestAgent(t *testing.T, tomb *tomb.Tomb, shared SharedStateBackend) *testAgent {
+	t.Helper()
+
+	agent := &testAgent{
+		buckets: NewBuckets(),
+		out:     make(chan types.Event, 10),
+	}
+	agent.buckets.SharedState = shared
+
+	// flush often, so that the agents learn quickly about the pours of the others
+	agent.buckets.sharedPours = newSharedStatePourer(shared)
+	agent.buckets.sharedPours.interval = 10 * time.Millisecond
+	tomb.Go(func() error {
+		return agent.buckets.sharedPours.run(tomb)
+	})
+
+	agent.holders = []BucketFactory{
+		{
+			Name:        "test/shared-leaky",
+			Description: "test/shared-leaky",
+			Type:        "leaky",
+			Capacity:    3,
+			LeakSpeed:   "10m",
+			Filter:      "true",
+			GroupBy:     "evt.Meta.source_ip",
+			Shared:      true,
+			ret:         agent.out,
+			sharedState: agent.buckets.sharedStatePourer(tomb),
+			wgDumpState: agent.buckets.wgDumpState,
+			wgPour:      agent.buckets.wgPour,
+		},
+	}
+
+	require.NoError(t, LoadBucket(&agent.holders[0], tomb))
+
+	return agent
+}
+
+func (a *testAgent) pour(t *testing.T, ip string) {
+	t.Helper()
+
+	now, err := time.Now().UTC().MarshalText()
+	require.NoError(t, err)
+
+	evt := types.Event{Meta: map[string]string{"source_ip": ip}, MarshaledTime: string(now)}
+
+	ok, err := PourItemToHolders(evt, a.holders, a.buckets)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+// overflows returns the alerts raised by the agent (not the bucket deaths)
+func (a *testAgent) overflows() int {
+	count := 0
+
+	for {
+		select {
+		case evt := <-a.out:
+			if evt.Overflow.Alert != nil {
+				count++
+			}
+		default:
+			return count
+		}
+	}
+}
+
+func TestSharedBucketAcrossAgents(t *testing.T) {
+	tmb := &tomb.Tomb{}
+	defer tmb.Kill(nil)
+
+	shared := NewMemoryStateBackend()
+	agents := []*testAgent{
+		newTestAgent(t, tmb, shared),
+		newTestAgent(t, tmb, shared),
+		newTestAgent(t, tmb, shared),
+	}
+
+	// 4 events from the same IP spread over three agents: none of them
+	// overflows locally, but the shared bucket does. The pours are flushed
+	// in the background, the last agent knows about the first three events.
+	for _, agent := range []*testAgent{agents[0], agents[1], agents[2], agents[2]} {
+		agent.pour(t, "1.2.3.4")
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	// another IP stays under the capacity
+	agents[1].pour(t, "5.6.7.8")
+	agents[2].pour(t, "5.6.7.8")
+
+	time.Sleep(500 * time.Millisecond)
+
+	total := 0
+	for _, agent := range agents {
+		total += agent.overflows()
+	}
+
+	assert.Equal(t, 1, total)
+}
+
+func TestUnsharedBucketAcrossAgents(t *testing.T) {
+	tmb := &tomb.Tomb{}
+	defer tmb.Kill(nil)
+
+	agents := []*testAgent{
+		newTestAgent(t, tmb, NewMemoryStateBackend()),
+		newTestAgent(t, tmb, NewMemoryStateBackend()),
+	}
+
+	agents[0].pour(t, "1.2.3.4")
+	agents[1].pour(t, "1.2.3.4")
+	agents[0].pour(t, "1.2.3.4")
+	agents[1].pour(t, "1.2.3.4")
+
+	time.Sleep(500 * time.Millisecond)
+
+	assert.Equal(t, 0, agents[0].overflows()+agents[1].overflows())
+}
+
+func TestMemoryStateBackendLeak(t *testing.T) {
+	ctx := t.Context()
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	backend := NewMemoryStateBackend()
+	backend.now = func() time.Time { return now }
+
+	level, err := backend.Pour(ctx, "key", 3, 10*time.Second)
+	require.NoError(t, err)
+	assert.InDelta(t, 3, level, 0.001)
+
+	// 20s later, two events leaked
+	now = now.Add(20 * time.Second)
+
+	level, err = backend.Pour(ctx, "key", 1, 10*time.Second)
+	require.NoError(t, err)
+	assert.InDelta(t, 2, level, 0.001)
+
+	require.NoError(t, backend.Reset(ctx, "key"))
+
+	level, err = backend.Pour(ctx, "key", 1, 10*time.Second)
+	require.NoError(t, err)
+	assert.InDelta(t, 1, level, 0.001)
+
+	// fully leaked buckets are forgotten
+	now = now.Add(time.Hour)
+
+	_, err = backend.Pour(ctx, "other", 1, 10*time.Second)
+	require.NoError(t, err)
+	assert.NotContains(t, backend.levels, "key")
+}
+
+// blockingBackend never answers, as a LAPI that is down or too slow
+type blockingBackend struct {
+	release chan struct{}
+}
+
+func (b *blockingBackend) Pour(ctx context.Context, _ string, _ int, _ time.Duration) (float64, error) {
+	select {
+	case <-b.release:
+	case <-ctx.Done():
+	}
+
+	return 0, errors.New("backend unavailable")
+}
+
+func (b *blockingBackend) Reset(_ context.Context, _ string) error {
+	return nil
+}
+
+func TestSharedStatePourerSlowBackend(t *testing.T) {
+	tmb := &tomb.Tomb{}
+
+	backend := &blockingBackend{release: make(chan struct{})}
+	pourer := newSharedStatePourer(backend)
+	pourer.interval = 10 * time.Millisecond
+
+	tmb.Go(func() error {
+		return pourer.run(tmb)
+	})
+
+	// the pours are counted locally while the flush is stuck on the backend
+	start := time.Now()
+
+	for i := range 5 {
+		assert.InDelta(t, float64(i+1), pourer.pour("key", time.Minute), 0.001)
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	assert.Less(t, time.Since(start), time.Second)
+
+	// the failed flush is forgotten, the events stay in the local bucket
+	close(backend.release)
+	time.Sleep(50 * time.Millisecond)
+	assert.InDelta(t, 1, pourer.pour("key", time.Minute), 0.001)
+
+	// the pourer stops with the buckets
+	tmb.Kill(nil)
+	require.NoError(t, tmb.Wait())
+}
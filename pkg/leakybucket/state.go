@@ -0,0 +1,372 @@
+package leakybucket
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/crowdsecurity/crowdsec/pkg/time/rate"
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+// BucketsStateVersion is the version of the buckets state file format.
+// State files written with a different version are ignored at restore time.
+const BucketsStateVersion = 1
+
+// BucketsState is the on-disk snapshot of all the live buckets
+type BucketsState struct {
+	Version   int                    `json:"version"`
+	Timestamp time.Time              `json:"timestamp"`
+	Buckets   map[string]BucketState `json:"buckets"`
+	// Baselines are the rates learned by the anomaly buckets, they outlive the buckets
+	Baselines map[string]AnomalyBaseline `json:"baselines,omitempty"`
+}
+
+// BucketState holds what is needed to resurrect a single bucket. The scenario
+// hash and version are kept so that buckets belonging to a scenario that has
+// changed since the snapshot are dropped instead of being restored wrongly.
+type BucketState struct {
+	Name            string       `json:"name"`
+	ScenarioHash    string       `json:"scenario_hash"`
+	ScenarioVersion string       `json:"scenario_version"`
+	Mode            int          `json:"mode"`
+	Limiter         rate.Lstate  `json:"limiter"`
+	Queue           *types.Queue `json:"queue"`
+	First_ts        time.Time    `json:"first_ts"`
+	Last_ts         time.Time    `json:"last_ts"`
+	Ovflw_ts        time.Time    `json:"ovflw_ts"`
+	Total_count     int          `json:"total_count"`
+	// Processors is the state of the processors that keep track of the events on their own, by processor type
+	Processors map[string]json.RawMessage `json:"processors,omitempty"`
+}
+
+// statefulProcessor is implemented by the processors whose state must be saved with the bucket
+// (the entries of a window, the progress of a sequence). The other processors, such as the
+// uniq cache or the bayesian guillotines, start over when the bucket is restored.
+type statefulProcessor interface {
+	dumpState() (json.RawMessage, error)
+	loadState(state json.RawMessage) error
+}
+
+// processorKey identifies the processor a saved state belongs to
+func processorKey(p Processor) string {
+	return fmt.Sprintf("%T", p)
+}
+
+// dumpProcessorsState returns the state of the stateful processors, the caller holds the bucket mutex
+func (leaky *Leaky) dumpProcessorsState() map[string]json.RawMessage {
+	var ret map[string]json.RawMessage
+
+	for _, p := range leaky.processors {
+		sp, ok := p.(statefulProcessor)
+		if !ok {
+			continue
+		}
+
+		state, err := sp.dumpState()
+		if err != nil {
+			leaky.logger.Warningf("failed to save the state of %s: %s", processorKey(p), err)
+			continue
+		}
+
+		if ret == nil {
+			ret = make(map[string]json.RawMessage)
+		}
+
+		ret[processorKey(p)] = state
+	}
+
+	return ret
+}
+
+// loadProcessorsState gives back to the processors of a restored bucket the state they had when it was saved
+func (leaky *Leaky) loadProcessorsState(processors []Processor) {
+	for _, p := range processors {
+		sp, ok := p.(statefulProcessor)
+		if !ok {
+			continue
+		}
+
+		state, ok := leaky.processorsState[processorKey(p)]
+		if !ok {
+			continue
+		}
+
+		if err := sp.loadState(state); err != nil {
+			leaky.logger.Warningf("failed to restore the state of %s, starting over: %s", processorKey(p), err)
+		}
+	}
+}
+
+// deadline returns the time at which a bucket with this state would die, mirroring the LeakRoutine timers
+func (s BucketState) deadline(duration time.Duration, timedOverflow bool) time.Time {
+	if timedOverflow {
+		return s.First_ts.Add(duration)
+	}
+
+	return s.Last_ts.Add(duration)
+}
+
+// SnapshotBucketsState returns the state of every bucket that is still alive at the given time
+func SnapshotBucketsState(now time.Time, buckets *Buckets) *BucketsState {
+	// synchronize with PourItemToHolders
+	buckets.wgPour.Wait()
+	buckets.wgDumpState.Add(1)
+	defer buckets.wgDumpState.Done()
+
+	state := &BucketsState{
+		Version:   BucketsStateVersion,
+		Timestamp: now,
+		Buckets:   make(map[string]BucketState),
+	}
+
+	buckets.Bucket_map.Range(func(rkey, rvalue interface{}) bool {
+		key := rkey.(string)
+		val := rvalue.(*Leaky)
+
+		val.mutex.Lock()
+
+		if ovflwTs := val.Ovflw_ts; !ovflwTs.IsZero() {
+			val.mutex.Unlock()
+			val.logger.Debugf("overflowed at %s, not saving", ovflwTs)
+
+			return true
+		}
+
+		bs := BucketState{
+			Name:            val.Name,
+			ScenarioHash:    val.hash,
+			ScenarioVersion: val.scenarioVersion,
+			Mode:            val.Mode,
+			Limiter:         val.Limiter.Dump(),
+			Queue:           &types.Queue{Queue: append([]types.Event(nil), val.Queue.Queue...), L: val.Queue.L},
+			First_ts:        val.First_ts,
+			Last_ts:         val.Last_ts,
+			Ovflw_ts:        val.Ovflw_ts,
+			Total_count:     val.Total_count,
+			Processors:      val.dumpProcessorsState(),
+		}
+		val.mutex.Unlock()
+
+		// nothing was poured yet, there is nothing worth saving
+		if bs.Last_ts.IsZero() {
+			return true
+		}
+
+		if bs.Mode == types.LIVE && !bs.deadline(val.Duration, val.timedOverflow).After(now) {
+			val.logger.Debugf("bucket is past its deadline, not saving")
+			return true
+		}
+
+		state.Buckets[key] = bs
+
+		return true
+	})
+
+	if buckets.Baselines != nil {
+		state.Baselines = buckets.Baselines.Snapshot()
+	}
+
+	return state
+}
+
+// SaveBucketsState writes a snapshot of the live buckets to path. The file is
+// written to a temporary location first and renamed, so that a crash while
+// saving never leaves a truncated state behind.
+func SaveBucketsState(path string, buckets *Buckets) error {
+	state := SnapshotBucketsState(time.Now().UTC(), buckets)
+
+	body, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to serialize buckets state: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("while creating directory %s: %w", dir, err)
+	}
+
+	tmpFd, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+
+	tmpFileName := tmpFd.Name()
+
+	if _, err := tmpFd.Write(body); err != nil {
+		tmpFd.Close()
+		os.Remove(tmpFileName)
+
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	if err := tmpFd.Close(); err != nil {
+		os.Remove(tmpFileName)
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+
+	if err := os.Rename(tmpFileName, path); err != nil {
+		os.Remove(tmpFileName)
+		return fmt.Errorf("failed to rename %s to %s: %w", tmpFileName, path, err)
+	}
+
+	log.Debugf("Saved %d buckets to %s", len(state.Buckets), path)
+
+	return nil
+}
+
+// RestoreBucketsState loads a state file written by SaveBucketsState. A missing
+// file is not an error. Buckets whose scenario is gone or has changed, and buckets
+// that would have died while the agent was not running, are dropped.
+func RestoreBucketsState(path string, buckets *Buckets, bucketFactories []BucketFactory) error {
+	var state BucketsState
+
+	body, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			log.Debugf("no buckets state in %s", path)
+			return nil
+		}
+
+		return fmt.Errorf("can't read state file %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(body, &state); err != nil {
+		return fmt.Errorf("can't parse state file %s: %w", path, err)
+	}
+
+	if state.Version != BucketsStateVersion {
+		log.Warningf("ignoring buckets state %s: format version %d, expected %d", path, state.Version, BucketsStateVersion)
+		return nil
+	}
+
+	factories := make(map[string]BucketFactory, len(bucketFactories))
+	for _, h := range bucketFactories {
+		factories[h.Name] = h
+	}
+
+	now := time.Now().UTC()
+	restored := 0
+	dropped := 0
+
+	for key, bs := range state.Buckets {
+		h, ok := factories[bs.Name]
+		if !ok {
+			log.Debugf("scenario %s is not loaded anymore, dropping bucket %s", bs.Name, key)
+			dropped++
+
+			continue
+		}
+
+		if h.hash != bs.ScenarioHash || h.ScenarioVersion != bs.ScenarioVersion {
+			log.Debugf("scenario %s has changed (%s -> %s), dropping bucket %s", bs.Name, bs.ScenarioVersion, h.ScenarioVersion, key)
+			dropped++
+
+			continue
+		}
+
+		if _, ok := buckets.Bucket_map.Load(key); ok {
+			log.Debugf("bucket %s already exists, not restoring", key)
+			dropped++
+
+			continue
+		}
+
+		if bs.Mode == types.LIVE {
+			duration, timedOverflow := bucketLifetime(&h)
+			if !bs.deadline(duration, timedOverflow).After(now) {
+				log.Debugf("bucket %s (%s) expired while we were down", key, bs.Name)
+				dropped++
+
+				continue
+			}
+		}
+
+		if err := restoreBucket(key, bs, h, buckets); err != nil {
+			return err
+		}
+
+		restored++
+	}
+
+	log.Infof("Restored %d buckets from %s (%d dropped)", restored, path, dropped)
+
+	restoreBaselines(state.Baselines, buckets, factories)
+
+	return nil
+}
+
+// restoreBaselines loads the rates learned by the anomaly buckets. Unlike the buckets, they
+// are kept when the scenario changes, as long as the rate is measured over the same interval.
+func restoreBaselines(baselines map[string]AnomalyBaseline, buckets *Buckets, factories map[string]BucketFactory) {
+	if buckets.Baselines == nil || len(baselines) == 0 {
+		return
+	}
+
+	restored := 0
+
+	for key, baseline := range baselines {
+		h, ok := factories[baseline.Name]
+		if !ok || h.Anomaly == nil {
+			log.Debugf("scenario %s is not loaded anymore, dropping baseline %s", baseline.Name, key)
+			continue
+		}
+
+		if h.Anomaly.interval != baseline.Interval {
+			log.Debugf("scenario %s interval has changed (%s -> %s), dropping baseline %s", baseline.Name, baseline.Interval, h.Anomaly.interval, key)
+			continue
+		}
+
+		if buckets.Baselines.Restore(key, baseline) {
+			restored++
+		}
+	}
+
+	log.Infof("Restored %d anomaly baselines", restored)
+}
+
+// restoreBucket instantiates a bucket from its saved state and starts its leak routine
+func restoreBucket(key string, bs BucketState, h BucketFactory, buckets *Buckets) error {
+	var tbucket *Leaky
+
+	switch bs.Mode {
+	case types.TIMEMACHINE:
+		tbucket = NewTimeMachine(h)
+	case types.LIVE:
+		tbucket = NewLeaky(h)
+	default:
+		return fmt.Errorf("unknown bucket mode for %s: %d", key, bs.Mode)
+	}
+
+	if bs.Queue != nil {
+		tbucket.Queue = bs.Queue
+	}
+
+	tbucket.Limiter.Load(bs.Limiter)
+	tbucket.In = make(chan *types.Event)
+	tbucket.Mapkey = key
+	tbucket.Signal = make(chan bool, 1)
+	tbucket.First_ts = bs.First_ts
+	tbucket.Last_ts = bs.Last_ts
+	tbucket.Ovflw_ts = bs.Ovflw_ts
+	tbucket.Total_count = bs.Total_count
+	tbucket.pours = int64(bs.Total_count)
+	tbucket.restored = true
+	tbucket.processorsState = bs.Processors
+	tbucket.limits = buckets.Limits
+
+	buckets.Bucket_map.Store(key, tbucket)
+	h.tomb.Go(func() error {
+		return LeakRoutine(tbucket)
+	})
+	<-tbucket.Signal
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package leakybucket
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/tomb.v2"
+
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+func newStateTestHolders(t *testing.T, buckets *Buckets, tomb *tomb.Tomb, hash string) []BucketFactory {
+	t.Helper()
+
+	holders := []BucketFactory{
+		{
+			Name:        "test_leaky_slow",
+			Description: "test_leaky_slow",
+			Type:        "leaky",
+			Capacity:    5,
+			LeakSpeed:   "10m",
+			Filter:      "true",
+			GroupBy:     "evt.Meta.source_ip",
+			hash:        hash,
+			wgDumpState: buckets.wgDumpState,
+			wgPour:      buckets.wgPour,
+			ret:         make(chan types.Event, 10),
+		},
+	}
+
+	for idx := range holders {
+		require.NoError(t, LoadBucket(&holders[idx], tomb))
+	}
+
+	return holders
+}
+
+func TestSaveAndRestoreBucketsState(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "buckets_state.json")
+
+	buckets := NewBuckets()
+	tmb := &tomb.Tomb{}
+	holders := newStateTestHolders(t, buckets, tmb, "hash1")
+
+	for _, ip := range []string{"1.2.3.4", "1.2.3.4", "5.6.7.8"} {
+		in := types.Event{Meta: map[string]string{"source_ip": ip}}
+		ok, err := PourItemToHolders(in, holders, buckets)
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+
+	time.Sleep(500 * time.Millisecond)
+	require.NoError(t, expectBucketCount(buckets, 2))
+	require.NoError(t, SaveBucketsState(stateFile, buckets))
+
+	body, err := os.ReadFile(stateFile)
+	require.NoError(t, err)
+
+	var state BucketsState
+	require.NoError(t, json.Unmarshal(body, &state))
+	assert.Equal(t, BucketsStateVersion, state.Version)
+	assert.Len(t, state.Buckets, 2)
+
+	// restore into a fresh agent, same scenario
+	restored := NewBuckets()
+	restoredTomb := &tomb.Tomb{}
+	require.NoError(t, RestoreBucketsState(stateFile, restored, newStateTestHolders(t, restored, restoredTomb, "hash1")))
+	require.NoError(t, expectBucketCount(restored, 2))
+
+	key := GetKey(holders[0], "1.2.3.4")
+	val, ok := restored.Bucket_map.Load(key)
+	require.True(t, ok)
+
+	bucket := val.(*Leaky)
+	assert.Equal(t, 2, bucket.Total_count)
+	assert.Len(t, bucket.Queue.GetQueue(), 2)
+	assert.False(t, bucket.First_ts.IsZero())
+	assert.InDelta(t, 3, bucket.Limiter.GetTokensCount(), 0.1)
+
+	// the scenario has changed, buckets must be dropped
+	changed := NewBuckets()
+	changedTomb := &tomb.Tomb{}
+	require.NoError(t, RestoreBucketsState(stateFile, changed, newStateTestHolders(t, changed, changedTomb, "hash2")))
+	require.NoError(t, expectBucketCount(changed, 0))
+
+	for _, tb := range []*tomb.Tomb{tmb, restoredTomb, changedTomb} {
+		tb.Kill(nil)
+	}
+}
+
+func TestRestoreBucketsStateIgnored(t *testing.T) {
+	dir := t.TempDir()
+	buckets := NewBuckets()
+	tmb := &tomb.Tomb{}
+	holders := newStateTestHolders(t, buckets, tmb, "")
+
+	// missing file is not an error
+	require.NoError(t, RestoreBucketsState(filepath.Join(dir, "missing.json"), buckets, holders))
+
+	// unknown format version
+	future := filepath.Join(dir, "future.json")
+	require.NoError(t, os.WriteFile(future, []byte(`{"version": 9999, "buckets": {"x": {"name": "test_leaky_slow"}}}`), 0o600))
+	require.NoError(t, RestoreBucketsState(future, buckets, holders))
+
+	// expired while the agent was down
+	expired := filepath.Join(dir, "expired.json")
+	old := time.Now().UTC().Add(-24 * time.Hour)
+	state := BucketsState{
+		Version: BucketsStateVersion,
+		Buckets: map[string]BucketState{
+			"x": {Name: "test_leaky_slow", Mode: types.LIVE, First_ts: old, Last_ts: old, Total_count: 1},
+		},
+	}
+	body, err := json.Marshal(state)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(expired, body, 0o600))
+	require.NoError(t, RestoreBucketsState(expired, buckets, holders))
+
+	require.NoError(t, expectBucketCount(buckets, 0))
+
+	// corrupted file
+	broken := filepath.Join(dir, "broken.json")
+	require.NoError(t, os.WriteFile(broken, []byte(`{`), 0o600))
+	require.Error(t, RestoreBucketsState(broken, buckets, holders))
+
+	tmb.Kill(nil)
+}
+
+func TestRestoreWindowState(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "buckets_state.json")
+
+	newHolders := func(buckets *Buckets, tmb *tomb.Tomb) []BucketFactory {
+		holders := []BucketFactory{
+			{
+				Name:        "test_window",
+				Description: "test_window",
+				Type:        "window",
+				Capacity:    2,
+				Duration:    "1h",
+				Filter:      "true",
+				GroupBy:     "evt.Meta.source_ip",
+				wgDumpState: buckets.wgDumpState,
+				wgPour:      buckets.wgPour,
+				ret:         make(chan types.Event, 10),
+			},
+		}
+
+		require.NoError(t, LoadBucket(&holders[0], tmb))
+
+		return holders
+	}
+
+	in := types.Event{Meta: map[string]string{"source_ip": "1.2.3.4"}}
+
+	buckets := NewBuckets()
+	tmb := &tomb.Tomb{}
+	holders := newHolders(buckets, tmb)
+
+	for range 2 {
+		ok, err := PourItemToHolders(in, holders, buckets)
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+
+	time.Sleep(500 * time.Millisecond)
+	require.NoError(t, SaveBucketsState(stateFile, buckets))
+
+	// the third event overflows the restored bucket, it still knows about the first two
+	restored := NewBuckets()
+	restoredTomb := &tomb.Tomb{}
+	restoredHolders := newHolders(restored, restoredTomb)
+	require.NoError(t, RestoreBucketsState(stateFile, restored, restoredHolders))
+
+	ok, err := PourItemToHolders(in, restoredHolders, restored)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	select {
+	case evt := <-restoredHolders[0].ret:
+		assert.Equal(t, types.OVFLW, evt.Type)
+		assert.Equal(t, GetKey(restoredHolders[0], "1.2.3.4"), evt.Overflow.Mapkey)
+	case <-time.After(2 * time.Second):
+		t.Fatal("the restored window bucket did not overflow")
+	}
+
+	tmb.Kill(nil)
+	restoredTomb.Kill(nil)
+}
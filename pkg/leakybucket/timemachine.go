@@ -29,13 +29,11 @@ func TimeMachinePour(l *Leaky, msg types.Event) {
 	}
 
 	l.Total_count += 1
-	l.mutex.Lock()
 	if l.First_ts.IsZero() {
 		l.logger.Debugf("First event, bucket creation time : %s", d)
 		l.First_ts = d
 	}
 	l.Last_ts = d
-	l.mutex.Unlock()
 	if l.Limiter.AllowN(d, 1) || l.conditionalOverflow {
 		l.logger.Tracef("Time-Pouring event %s (tokens:%f)", d, l.Limiter.GetTokensCount())
 		l.Queue.Add(msg)
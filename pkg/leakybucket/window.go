@@ -0,0 +1,154 @@
+package leakybucket
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+
+	"github.com/crowdsecurity/crowdsec/pkg/exprhelpers"
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+// WindowBucket is the processor of the `window` buckets: it keeps the timestamp of every
+// event (or distinct value) poured within the last `duration`, and overflows as soon as
+// there are more than `capacity` of them. Unlike the leaky bucket, there is no smoothing:
+// an event is counted for exactly `duration`, then forgotten.
+type WindowBucket struct {
+	DistinctCompiled *vm.Program
+	entries          []windowEntry  // sorted by timestamp
+	counts           map[string]int // number of entries per distinct value
+	DumbProcessor
+}
+
+type windowEntry struct {
+	ts  time.Time
+	key string
+}
+
+func (w *WindowBucket) OnBucketInit(bucketFactory *BucketFactory) error {
+	w.entries = []windowEntry{}
+	w.counts = make(map[string]int)
+
+	if bucketFactory.Distinct == "" {
+		return nil
+	}
+
+	compiled, err := expr.Compile(bucketFactory.Distinct, exprhelpers.GetExprOptions(map[string]interface{}{"evt": &types.Event{}})...)
+	if err != nil {
+		return fmt.Errorf("window distinct compile error: %w", err)
+	}
+
+	w.DistinctCompiled = compiled
+
+	return nil
+}
+
+func (w *WindowBucket) AfterBucketPour(bucketFactory *BucketFactory) func(types.Event, *Leaky) *types.Event {
+	return func(msg types.Event, leaky *Leaky) *types.Event {
+		var key string
+
+		if w.DistinctCompiled != nil {
+			element, err := getElement(msg, w.DistinctCompiled)
+			if err != nil {
+				leaky.logger.Errorf("window distinct exec failed : %v", err)
+				return &msg
+			}
+
+			key = element
+		}
+
+		// Last_ts is the time of the event in time-machine mode, and the pour time in live mode
+		w.add(leaky.Last_ts, key)
+
+		// events poured out of order (time-machine) can't move the window backward
+		now := w.entries[len(w.entries)-1].ts
+		w.expire(now.Add(-bucketFactory.duration))
+
+		count := w.count()
+		leaky.logger.Tracef("window count: %d/%d", count, bucketFactory.Capacity)
+
+		if count <= bucketFactory.Capacity {
+			return &msg
+		}
+
+		leaky.logger.Debugf("Window bucket overflow (%d events in %s)", count, bucketFactory.duration)
+		leaky.Ovflw_ts = leaky.Last_ts
+		leaky.Out <- leaky.Queue
+
+		return nil
+	}
+}
+
+// add inserts an entry, keeping them sorted by timestamp
+func (w *WindowBucket) add(ts time.Time, key string) {
+	idx := sort.Search(len(w.entries), func(i int) bool {
+		return w.entries[i].ts.After(ts)
+	})
+
+	w.entries = append(w.entries, windowEntry{})
+	copy(w.entries[idx+1:], w.entries[idx:])
+	w.entries[idx] = windowEntry{ts: ts, key: key}
+	w.counts[key]++
+}
+
+// expire forgets the entries that are not after the start of the window
+func (w *WindowBucket) expire(start time.Time) {
+	idx := 0
+
+	for idx < len(w.entries) && !w.entries[idx].ts.After(start) {
+		key := w.entries[idx].key
+
+		w.counts[key]--
+		if w.counts[key] <= 0 {
+			delete(w.counts, key)
+		}
+
+		idx++
+	}
+
+	w.entries = w.entries[idx:]
+}
+
+// count returns the number of events, or of distinct values, in the window
+func (w *WindowBucket) count() int {
+	if w.DistinctCompiled != nil {
+		return len(w.counts)
+	}
+
+	return len(w.entries)
+}
+
+type windowEntryState struct {
+	Ts  time.Time `json:"ts"`
+	Key string    `json:"key,omitempty"`
+}
+
+func (w *WindowBucket) dumpState() (json.RawMessage, error) {
+	entries := make([]windowEntryState, 0, len(w.entries))
+	for _, e := range w.entries {
+		entries = append(entries, windowEntryState{Ts: e.ts, Key: e.key})
+	}
+
+	return json.Marshal(entries)
+}
+
+func (w *WindowBucket) loadState(state json.RawMessage) error {
+	entries := []windowEntryState{}
+
+	if err := json.Unmarshal(state, &entries); err != nil {
+		return err
+	}
+
+	w.entries = []windowEntry{}
+	w.counts = make(map[string]int)
+
+	for _, e := range entries {
+		w.add(e.Ts, e.Key)
+	}
+
+	return nil
+}
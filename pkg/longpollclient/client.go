@@ -106,7 +106,6 @@ func (c *LongPollClient) poll(ctx context.Context) error {
 		select {
 		case <-c.t.Dying():
 			logger.Debugf("dying")
-			close(c.c)
 			return nil
 		default:
 			var pollResp pollResponse
@@ -148,11 +147,16 @@ func (c *LongPollClient) poll(ctx context.Context) error {
 }
 
 func (c *LongPollClient) pollEvents(ctx context.Context) error {
+	defer close(c.c)
+
 	for {
 		select {
 		case <-c.t.Dying():
 			c.logger.Debug("dying")
 			return nil
+		case <-ctx.Done():
+			c.logger.Debug("context canceled")
+			return nil
 		default:
 			c.logger.Debug("Polling PAPI")
 			err := c.poll(ctx)
@@ -160,7 +164,6 @@ func (c *LongPollClient) pollEvents(ctx context.Context) error {
 				c.logger.Errorf("failed to poll: %s", err)
 				if errors.Is(err, errUnauthorized) {
 					c.t.Kill(err)
-					close(c.c)
 					return err
 				}
 				continue
@@ -171,6 +174,8 @@ func (c *LongPollClient) pollEvents(ctx context.Context) error {
 
 func (c *LongPollClient) Start(ctx context.Context, since time.Time) chan Event {
 	c.logger.Infof("starting polling client")
+	// the client can be started again after being stopped
+	c.t = tomb.Tomb{}
 	c.c = make(chan Event)
 	c.since = since.Unix() * 1000
 	c.timeout = "45"
@@ -0,0 +1,347 @@
+package parser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Decoder extracts the fields of a structured log
+type Decoder func(string) (map[string]string, error)
+
+var decoders = map[string]Decoder{
+	"json":   decodeJSON,
+	"logfmt": decodeLogfmt,
+	"cef":    decodeCEF,
+	"leef":   decodeLEEF,
+}
+
+func getDecoder(format string) (Decoder, error) {
+	decoder, ok := decoders[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown decode format '%s', must be one of json, logfmt, cef, leef", format)
+	}
+
+	return decoder, nil
+}
+
+// decodeJSON flattens a JSON object: nested objects are joined with dots, and arrays are kept as JSON
+func decodeJSON(s string) (map[string]string, error) {
+	var doc map[string]any
+
+	dec := json.NewDecoder(strings.NewReader(s))
+	// keep the numbers as they are written, instead of converting them to float
+	dec.UseNumber()
+
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	if doc == nil {
+		return nil, errors.New("not a JSON object")
+	}
+
+	ret := make(map[string]string, len(doc))
+	flattenJSON("", doc, ret)
+
+	return ret, nil
+}
+
+func flattenJSON(prefix string, value any, ret map[string]string) {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, sub := range v {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+
+			flattenJSON(key, sub, ret)
+		}
+	case []any:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return
+		}
+
+		ret[prefix] = string(b)
+	case string:
+		ret[prefix] = v
+	case json.Number:
+		ret[prefix] = v.String()
+	case bool:
+		ret[prefix] = strconv.FormatBool(v)
+	case nil:
+		ret[prefix] = ""
+	}
+}
+
+// decodeLogfmt parses `key=value key2="quoted value" flag` lines
+func decodeLogfmt(s string) (map[string]string, error) {
+	ret := make(map[string]string)
+	pairs := 0
+
+	isSpace := func(c byte) bool { return c == ' ' || c == '\t' }
+
+	for i := 0; i < len(s); {
+		for i < len(s) && isSpace(s[i]) {
+			i++
+		}
+
+		if i >= len(s) {
+			break
+		}
+
+		start := i
+		for i < len(s) && s[i] != '=' && !isSpace(s[i]) {
+			if s[i] == '"' {
+				return nil, fmt.Errorf("unexpected '\"' at position %d", i)
+			}
+
+			i++
+		}
+
+		key := s[start:i]
+		if key == "" {
+			return nil, fmt.Errorf("missing key at position %d", i)
+		}
+
+		// a key without value is a flag
+		if i >= len(s) || s[i] != '=' {
+			ret[key] = ""
+			continue
+		}
+
+		i++
+		pairs++
+
+		if i < len(s) && s[i] == '"' {
+			value, n, err := unquoteLogfmt(s[i:])
+			if err != nil {
+				return nil, fmt.Errorf("bad value for key '%s': %w", key, err)
+			}
+
+			ret[key] = value
+			i += n
+
+			continue
+		}
+
+		start = i
+		for i < len(s) && !isSpace(s[i]) {
+			i++
+		}
+
+		ret[key] = s[start:i]
+	}
+
+	if pairs == 0 {
+		return nil, errors.New("no key=value pair")
+	}
+
+	return ret, nil
+}
+
+// unquoteLogfmt returns the content of the quoted string at the start of s, and its length in s
+func unquoteLogfmt(s string) (string, int, error) {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			value, err := strconv.Unquote(s[:i+1])
+			if err != nil {
+				return "", 0, err
+			}
+
+			return value, i + 1, nil
+		}
+	}
+
+	return "", 0, errors.New("unterminated quoted value")
+}
+
+var cefHeader = []string{"cef_version", "device_vendor", "device_product", "device_version", "signature_id", "name", "severity"}
+
+// decodeCEF parses ArcSight Common Event Format logs, optionally prefixed by a syslog header:
+// `CEF:Version|Device Vendor|Device Product|Device Version|Signature ID|Name|Severity|Extension`
+func decodeCEF(s string) (map[string]string, error) {
+	idx := strings.Index(s, "CEF:")
+	if idx < 0 {
+		return nil, errors.New("no CEF header")
+	}
+
+	header, extension, err := splitCEFHeader(s[idx+len("CEF:"):], len(cefHeader))
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make(map[string]string)
+
+	for i, key := range cefHeader {
+		ret[key] = header[i]
+	}
+
+	parseCEFExtension(extension, ret)
+
+	return ret, nil
+}
+
+// splitCEFHeader splits the n fields of the header, separated by unescaped pipes, from the extension
+func splitCEFHeader(s string, n int) ([]string, string, error) {
+	fields := make([]string, 0, n)
+
+	var field strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && (s[i+1] == '|' || s[i+1] == '\\'):
+			i++
+			field.WriteByte(s[i])
+		case s[i] == '|':
+			fields = append(fields, field.String())
+			field.Reset()
+
+			if len(fields) == n {
+				return fields, s[i+1:], nil
+			}
+		default:
+			field.WriteByte(s[i])
+		}
+	}
+
+	return nil, "", fmt.Errorf("CEF header has %d fields instead of %d", len(fields), n)
+}
+
+func isCEFKey(key string) bool {
+	if key == "" {
+		return false
+	}
+
+	for _, c := range key {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.' || c == '-' || c == '[' || c == ']') {
+			return false
+		}
+	}
+
+	return true
+}
+
+var cefValueReplacer = strings.NewReplacer(`\=`, `=`, `\\`, `\`, `\n`, "\n", `\r`, "\r")
+
+// parseCEFExtension parses the space separated key=value pairs of the extension. The values can contain
+// spaces, so a value ends where the next key starts.
+func parseCEFExtension(s string, ret map[string]string) {
+	type pair struct {
+		keyStart int
+		eq       int
+	}
+
+	pairs := []pair{}
+
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+
+		if s[i] != '=' {
+			continue
+		}
+
+		keyStart := strings.LastIndexByte(s[:i], ' ') + 1
+		if len(pairs) > 0 && keyStart <= pairs[len(pairs)-1].eq {
+			// an unescaped '=' in a value
+			continue
+		}
+
+		if !isCEFKey(s[keyStart:i]) {
+			continue
+		}
+
+		pairs = append(pairs, pair{keyStart: keyStart, eq: i})
+	}
+
+	for idx, p := range pairs {
+		end := len(s)
+		if idx+1 < len(pairs) {
+			end = pairs[idx+1].keyStart
+		}
+
+		value := strings.TrimRight(s[p.eq+1:end], " ")
+		ret[s[p.keyStart:p.eq]] = cefValueReplacer.Replace(value)
+	}
+}
+
+var leefHeader = []string{"leef_version", "vendor", "product", "product_version", "event_id"}
+
+// decodeLEEF parses IBM QRadar Log Event Extended Format logs, optionally prefixed by a syslog header:
+// `LEEF:1.0|Vendor|Product|Version|EventID|key=value<tab>key=value`, LEEF 2.0 adds the delimiter of the
+// attributes to the header: `LEEF:2.0|Vendor|Product|Version|EventID|^|key=value^key=value`
+func decodeLEEF(s string) (map[string]string, error) {
+	idx := strings.Index(s, "LEEF:")
+	if idx < 0 {
+		return nil, errors.New("no LEEF header")
+	}
+
+	parts := strings.SplitN(s[idx+len("LEEF:"):], "|", len(leefHeader)+1)
+	if len(parts) <= len(leefHeader) {
+		return nil, fmt.Errorf("LEEF header has %d fields instead of %d", len(parts)-1, len(leefHeader))
+	}
+
+	ret := make(map[string]string)
+
+	for i, key := range leefHeader {
+		ret[key] = parts[i]
+	}
+
+	attributes := parts[len(leefHeader)]
+	delim := "\t"
+
+	if strings.HasPrefix(ret["leef_version"], "2") {
+		spec, rest, ok := strings.Cut(attributes, "|")
+		if !ok {
+			return nil, errors.New("LEEF 2.0 header has no delimiter")
+		}
+
+		var err error
+
+		if delim, err = leefDelimiter(spec); err != nil {
+			return nil, err
+		}
+
+		attributes = rest
+	}
+
+	for _, attribute := range strings.Split(attributes, delim) {
+		key, value, ok := strings.Cut(attribute, "=")
+		if !ok || key == "" {
+			continue
+		}
+
+		ret[key] = value
+	}
+
+	return ret, nil
+}
+
+// leefDelimiter returns the delimiter of a LEEF 2.0 header: a character, or its code in hexadecimal (x09 or 0x09)
+func leefDelimiter(spec string) (string, error) {
+	switch {
+	case spec == "":
+		return "\t", nil
+	case len(spec) == 1:
+		return spec, nil
+	case strings.HasPrefix(spec, "x") || strings.HasPrefix(spec, "0x"):
+		code, err := strconv.ParseUint(strings.TrimPrefix(strings.TrimPrefix(spec, "0"), "x"), 16, 8)
+		if err != nil {
+			return "", fmt.Errorf("bad LEEF delimiter '%s': %w", spec, err)
+		}
+
+		return string(rune(code)), nil
+	default:
+		return "", fmt.Errorf("bad LEEF delimiter '%s'", spec)
+	}
+}
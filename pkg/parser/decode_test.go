@@ -0,0 +1,194 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/crowdsecurity/go-cs-lib/cstest"
+)
+
+func TestDecoders(t *testing.T) {
+	tests := []struct {
+		name        string
+		format      string
+		input       string
+		expected    map[string]string
+		expectedErr string
+	}{
+		{
+			name:   "json",
+			format: "json",
+			input:  `{"ts": "2024-01-01T10:00:00Z", "status": 403, "ratio": 0.5, "ok": false, "user": null, "http": {"method": "GET", "headers": {"host": "example.com"}}, "tags": ["a", 1]}`,
+			expected: map[string]string{
+				"ts":                "2024-01-01T10:00:00Z",
+				"status":            "403",
+				"ratio":             "0.5",
+				"ok":                "false",
+				"user":              "",
+				"http.method":       "GET",
+				"http.headers.host": "example.com",
+				"tags":              `["a",1]`,
+			},
+		},
+		{
+			name:        "json not an object",
+			format:      "json",
+			input:       `["a", "b"]`,
+			expectedErr: "cannot unmarshal array",
+		},
+		{
+			name:        "json broken",
+			format:      "json",
+			input:       `{"a": `,
+			expectedErr: "unexpected EOF",
+		},
+		{
+			name:   "logfmt",
+			format: "logfmt",
+			input:  `level=info msg="user \"bob\" logged in" src=1.2.3.4 debug  duration=12ms empty=`,
+			expected: map[string]string{
+				"level":    "info",
+				"msg":      `user "bob" logged in`,
+				"src":      "1.2.3.4",
+				"debug":    "",
+				"duration": "12ms",
+				"empty":    "",
+			},
+		},
+		{
+			name:        "logfmt without pairs",
+			format:      "logfmt",
+			input:       "this is not logfmt",
+			expectedErr: "no key=value pair",
+		},
+		{
+			name:        "logfmt unterminated",
+			format:      "logfmt",
+			input:       `msg="oops`,
+			expectedErr: "bad value for key 'msg': unterminated quoted value",
+		},
+		{
+			name:   "cef",
+			format: "cef",
+			input:  `Sep 19 08:26:10 host CEF:0|Security|threat\|manager|1.0|100|worm successfully stopped|10|src=10.0.0.1 dst=2.1.2.2 spt=1232 msg=Detected a threat. No action needed request=http://example.com/?a\=1 cs1=C:\\dir`,
+			expected: map[string]string{
+				"cef_version":    "0",
+				"device_vendor":  "Security",
+				"device_product": "threat|manager",
+				"device_version": "1.0",
+				"signature_id":   "100",
+				"name":           "worm successfully stopped",
+				"severity":       "10",
+				"src":            "10.0.0.1",
+				"dst":            "2.1.2.2",
+				"spt":            "1232",
+				"msg":            "Detected a threat. No action needed",
+				"request":        "http://example.com/?a=1",
+				"cs1":            `C:\dir`,
+			},
+		},
+		{
+			name:   "cef unescaped equal sign in value",
+			format: "cef",
+			input:  `CEF:0|v|p|1|2|n|3|request=http://example.com/?a=1&b=2 src=1.2.3.4`,
+			expected: map[string]string{
+				"cef_version":    "0",
+				"device_vendor":  "v",
+				"device_product": "p",
+				"device_version": "1",
+				"signature_id":   "2",
+				"name":           "n",
+				"severity":       "3",
+				"request":        "http://example.com/?a=1&b=2",
+				"src":            "1.2.3.4",
+			},
+		},
+		{
+			name:        "cef short header",
+			format:      "cef",
+			input:       `CEF:0|Security|threatmanager`,
+			expectedErr: "CEF header has 2 fields instead of 7",
+		},
+		{
+			name:        "not cef",
+			format:      "cef",
+			input:       `hello world`,
+			expectedErr: "no CEF header",
+		},
+		{
+			name:   "leef 1.0",
+			format: "leef",
+			input:  "LEEF:1.0|Microsoft|MSExchange|4.0 SP1|15345|src=192.0.2.0\tdst=172.50.123.1\tsev=5\tmsg=a message with spaces",
+			expected: map[string]string{
+				"leef_version":    "1.0",
+				"vendor":          "Microsoft",
+				"product":         "MSExchange",
+				"product_version": "4.0 SP1",
+				"event_id":        "15345",
+				"src":             "192.0.2.0",
+				"dst":             "172.50.123.1",
+				"sev":             "5",
+				"msg":             "a message with spaces",
+			},
+		},
+		{
+			name:   "leef 2.0 with delimiter",
+			format: "leef",
+			input:  "<13>Jan 18 11:07:53 host LEEF:2.0|Lancope|StealthWatch|1.0|41|^|src=10.0.1.8^dst=10.0.0.5^proto=tcp",
+			expected: map[string]string{
+				"leef_version":    "2.0",
+				"vendor":          "Lancope",
+				"product":         "StealthWatch",
+				"product_version": "1.0",
+				"event_id":        "41",
+				"src":             "10.0.1.8",
+				"dst":             "10.0.0.5",
+				"proto":           "tcp",
+			},
+		},
+		{
+			name:   "leef 2.0 with hex delimiter",
+			format: "leef",
+			input:  "LEEF:2.0|Vendor|Product|1.0|42|0x7c|src=10.0.1.8|dst=10.0.0.5",
+			expected: map[string]string{
+				"leef_version":    "2.0",
+				"vendor":          "Vendor",
+				"product":         "Product",
+				"product_version": "1.0",
+				"event_id":        "42",
+				"src":             "10.0.1.8",
+				"dst":             "10.0.0.5",
+			},
+		},
+		{
+			name:        "leef 2.0 bad delimiter",
+			format:      "leef",
+			input:       "LEEF:2.0|Vendor|Product|1.0|42|xyz|src=10.0.1.8",
+			expectedErr: "bad LEEF delimiter 'xyz'",
+		},
+		{
+			name:        "leef short header",
+			format:      "leef",
+			input:       "LEEF:1.0|Vendor|Product",
+			expectedErr: "LEEF header has 2 fields instead of 5",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			decoder, err := getDecoder(tc.format)
+			cstest.RequireErrorContains(t, err, "")
+
+			fields, err := decoder(tc.input)
+			cstest.RequireErrorContains(t, err, tc.expectedErr)
+
+			assert.Equal(t, tc.expected, fields)
+		})
+	}
+}
+
+func TestUnknownDecoder(t *testing.T) {
+	_, err := getDecoder("xml")
+	cstest.RequireErrorContains(t, err, "unknown decode format 'xml'")
+}
@@ -0,0 +1,146 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// dissectField is a field of a dissect pattern, and the literal that ends it
+type dissectField struct {
+	name string // empty if the field is skipped: %{} or %{?name}
+	// the field is followed by a variable number of delimiters: %{name->}
+	padded bool
+	// the delimiter that ends the field, empty for the last field, which takes the rest of the string
+	delim string
+}
+
+// Dissector is a compiled dissect pattern. It's anchored at the start of the string: the text before the
+// first field must match, then each field takes everything up to the next occurrence of its delimiter.
+// Unlike grok, the content of the fields is not checked.
+type Dissector struct {
+	pattern string
+	prefix  string
+	fields  []dissectField
+	names   []string
+}
+
+// CompileDissect parses a dissect pattern such as `%{remote_addr} - %{remote_user} [%{time_local}] "%{request}"`.
+// %{} and %{?name} skip a field, and %{name->} skips the repeated delimiters after the field.
+func CompileDissect(pattern string) (*Dissector, error) {
+	d := &Dissector{pattern: pattern}
+
+	rest := pattern
+	seen := make(map[string]bool)
+
+	for {
+		start := strings.Index(rest, "%{")
+		if start < 0 {
+			break
+		}
+
+		literal := rest[:start]
+
+		end := strings.Index(rest[start:], "}")
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated field at '%s'", rest[start:])
+		}
+
+		key := rest[start+2 : start+end]
+		rest = rest[start+end+1:]
+
+		if len(d.fields) == 0 {
+			d.prefix = literal
+		} else {
+			if literal == "" {
+				return nil, fmt.Errorf("field '%s' must be separated from the previous one by a delimiter", key)
+			}
+
+			d.fields[len(d.fields)-1].delim = literal
+		}
+
+		field := dissectField{}
+
+		if strings.HasSuffix(key, "->") {
+			field.padded = true
+			key = strings.TrimSuffix(key, "->")
+		}
+
+		if key != "" && !strings.HasPrefix(key, "?") {
+			if strings.ContainsAny(key, "%{} ") {
+				return nil, fmt.Errorf("invalid field name '%s'", key)
+			}
+
+			if seen[key] {
+				return nil, fmt.Errorf("duplicate field '%s'", key)
+			}
+
+			seen[key] = true
+			field.name = key
+			d.names = append(d.names, key)
+		}
+
+		d.fields = append(d.fields, field)
+	}
+
+	if len(d.fields) == 0 {
+		return nil, errors.New("pattern has no field")
+	}
+
+	// the text after the last field must be found too
+	d.fields[len(d.fields)-1].delim = rest
+
+	return d, nil
+}
+
+// Parse returns the fields extracted from s, or nil if s doesn't match the pattern
+func (d *Dissector) Parse(s string) map[string]string {
+	if !strings.HasPrefix(s, d.prefix) {
+		return nil
+	}
+
+	s = s[len(d.prefix):]
+
+	values := make([]string, 0, len(d.names))
+
+	for _, field := range d.fields {
+		var value string
+
+		if field.delim == "" {
+			value, s = s, ""
+		} else {
+			idx := strings.Index(s, field.delim)
+			if idx < 0 {
+				return nil
+			}
+
+			value, s = s[:idx], s[idx+len(field.delim):]
+
+			if field.padded {
+				for strings.HasPrefix(s, field.delim) {
+					s = s[len(field.delim):]
+				}
+			}
+		}
+
+		if field.name != "" {
+			values = append(values, value)
+		}
+	}
+
+	ret := make(map[string]string, len(d.names))
+	for idx, name := range d.names {
+		ret[name] = values[idx]
+	}
+
+	return ret
+}
+
+// Names returns the names of the fields captured by the pattern
+func (d *Dissector) Names() []string {
+	return d.names
+}
+
+func (d *Dissector) String() string {
+	return d.pattern
+}
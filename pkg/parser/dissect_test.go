@@ -0,0 +1,158 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/crowdsecurity/go-cs-lib/cstest"
+)
+
+func TestCompileDissect(t *testing.T) {
+	tests := []struct {
+		name        string
+		pattern     string
+		names       []string
+		expectedErr string
+	}{
+		{name: "simple", pattern: "%{a} %{b}", names: []string{"a", "b"}},
+		{name: "prefix and suffix", pattern: "[%{a}] %{b}!", names: []string{"a", "b"}},
+		{name: "skipped fields", pattern: "%{a} %{} %{?c} %{d}", names: []string{"a", "d"}},
+		{name: "padding", pattern: "%{a->} %{b}", names: []string{"a", "b"}},
+		{name: "no field", pattern: "nothing to see", expectedErr: "pattern has no field"},
+		{name: "unterminated", pattern: "%{a} %{b", expectedErr: "unterminated field at '%{b'"},
+		{name: "no delimiter", pattern: "%{a}%{b}", expectedErr: "field 'b' must be separated from the previous one by a delimiter"},
+		{name: "duplicate", pattern: "%{a} %{a}", expectedErr: "duplicate field 'a'"},
+		{name: "bad name", pattern: "%{a b} %{c}", expectedErr: "invalid field name 'a b'"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := CompileDissect(tc.pattern)
+			cstest.RequireErrorContains(t, err, tc.expectedErr)
+
+			if tc.expectedErr != "" {
+				return
+			}
+
+			assert.Equal(t, tc.names, d.Names())
+			assert.Equal(t, tc.pattern, d.String())
+		})
+	}
+}
+
+func TestDissectParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		input    string
+		expected map[string]string
+	}{
+		{
+			name:    "nginx",
+			pattern: `%{remote_addr} - %{remote_user} [%{time_local}] "%{method} %{request} HTTP/%{http_version}" %{status} %{body_bytes_sent} "%{http_referer}" "%{http_user_agent}"`,
+			input:   `192.168.1.1 - - [10/Oct/2023:13:55:36 +0000] "GET /index.html HTTP/1.1" 200 612 "-" "curl/8.0.1"`,
+			expected: map[string]string{
+				"remote_addr":     "192.168.1.1",
+				"remote_user":     "-",
+				"time_local":      "10/Oct/2023:13:55:36 +0000",
+				"method":          "GET",
+				"request":         "/index.html",
+				"http_version":    "1.1",
+				"status":          "200",
+				"body_bytes_sent": "612",
+				"http_referer":    "-",
+				"http_user_agent": "curl/8.0.1",
+			},
+		},
+		{
+			name:     "last field takes the rest",
+			pattern:  "%{a} %{b}",
+			input:    "one two three",
+			expected: map[string]string{"a": "one", "b": "two three"},
+		},
+		{
+			name:     "text after the suffix is ignored",
+			pattern:  "%{a} [%{b}]",
+			input:    "one [two] three",
+			expected: map[string]string{"a": "one", "b": "two"},
+		},
+		{
+			name:     "empty field",
+			pattern:  "%{a}|%{b}|%{c}",
+			input:    "one||three",
+			expected: map[string]string{"a": "one", "b": "", "c": "three"},
+		},
+		{
+			name:     "skipped fields",
+			pattern:  "%{a} %{} %{?c} %{d}",
+			input:    "one two three four",
+			expected: map[string]string{"a": "one", "d": "four"},
+		},
+		{
+			name:     "padding",
+			pattern:  "%{month->} %{day} %{time}",
+			input:    "Oct   1 13:55:36",
+			expected: map[string]string{"month": "Oct", "day": "1", "time": "13:55:36"},
+		},
+		{
+			name:    "prefix mismatch",
+			pattern: "[%{a}] %{b}",
+			input:   "one [two]",
+		},
+		{
+			name:    "missing delimiter",
+			pattern: "%{a} - %{b}",
+			input:   "one two",
+		},
+		{
+			name:    "missing suffix",
+			pattern: "%{a} [%{b}]",
+			input:   "one [two",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := CompileDissect(tc.pattern)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, d.Parse(tc.input))
+		})
+	}
+}
+
+const benchNginxLine = `192.168.1.1 - - [10/Oct/2023:13:55:36 +0000] "GET /index.html?page=2&sort=asc HTTP/1.1" 200 612 "https://example.com/" "Mozilla/5.0 (X11; Linux x86_64; rv:109.0) Gecko/20100101 Firefox/115.0"`
+
+func BenchmarkDissectNginx(b *testing.B) {
+	d, err := CompileDissect(`%{remote_addr} - %{remote_user} [%{time_local}] "%{method} %{request} HTTP/%{http_version}" %{status} %{body_bytes_sent} "%{http_referer}" "%{http_user_agent}"`)
+	require.NoError(b, err)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if d.Parse(benchNginxLine) == nil {
+			b.Fatal("dissect failed")
+		}
+	}
+}
+
+// the same format, with the grok pattern of the nginx parser from the hub
+func BenchmarkGrokNginx(b *testing.B) {
+	pctx, err := Init(map[string]interface{}{"patterns": "../../config/patterns/", "data": "./tests/"})
+	require.NoError(b, err)
+
+	g, err := pctx.Grok.Get("NGINXACCESS")
+	require.NoError(b, err)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if len(g.Parse(benchNginxLine)) == 0 {
+			b.Fatal("grok failed")
+		}
+	}
+}
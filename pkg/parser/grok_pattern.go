@@ -43,6 +43,53 @@ type GrokPattern struct {
 	Statics []ExtraField `yaml:"statics,omitempty"`
 }
 
+// DissectPattern splits a string on the literal delimiters of a pattern, which is much cheaper than
+// a grok pattern for simple delimited formats
+type DissectPattern struct {
+	//the field to which the pattern is going to apply
+	TargetField string `yaml:"apply_on,omitempty"`
+	//a dissect pattern, ie. `%{remote_addr} - %{remote_user} [%{time_local}]`
+	Pattern string `yaml:"pattern,omitempty"`
+	//the runtime form of pattern
+	RunTimeDissect *Dissector `json:"-"`
+	//the output of the expression is going to be the source for the pattern
+	ExpValue     string      `yaml:"expression,omitempty"`
+	RunTimeValue *vm.Program `json:"-"` //the actual compiled filter
+	//a dissect can contain statics that apply if pattern is successful
+	Statics []ExtraField `yaml:"statics,omitempty"`
+}
+
+// DecodeField maps a field of a structured log to Parsed or Meta
+type DecodeField struct {
+	//the name of the field in the log, nested JSON fields are joined with dots (ie. `http.request.method`)
+	Key string `yaml:"key"`
+	//if the target field is in Parsed map
+	Parsed string `yaml:"parsed,omitempty"`
+	//if the target field is in Meta map
+	Meta string `yaml:"meta,omitempty"`
+	//the node fails if the field is missing
+	Required bool `yaml:"required,omitempty"`
+}
+
+// DecodePattern decodes a structured log (json, logfmt, cef or leef), instead of matching it with a grok pattern
+type DecodePattern struct {
+	//the format of the log : json, logfmt, cef or leef
+	Format string `yaml:"format,omitempty"`
+	//the field which is going to be decoded
+	TargetField string `yaml:"apply_on,omitempty"`
+	//the output of the expression is going to be decoded
+	ExpValue     string      `yaml:"expression,omitempty"`
+	RunTimeValue *vm.Program `json:"-"` //the actual compiled filter
+	//the fields to copy to Parsed or Meta, if empty all the fields are copied to Parsed
+	Fields []DecodeField `yaml:"fields,omitempty"`
+	//copy all the fields to Parsed, even when fields are mapped
+	KeepAll bool `yaml:"keep_all,omitempty"`
+	//the runtime form of format
+	RunTimeDecoder Decoder `json:"-"`
+	//a decode can contain statics that apply if decoding is successful
+	Statics []ExtraField `yaml:"statics,omitempty"`
+}
+
 type DataCapture struct {
 	Name            string        `yaml:"name,omitempty"`
 	Key             string        `yaml:"key,omitempty"`
@@ -51,12 +51,16 @@ type Node struct {
 	// Flag used to describe when to 'break' or return an 'error'
 	EnrichFunctions EnricherCtx
 
-	/* If the node is actually a leaf, it can have : grok, enrich, statics */
+	/* If the node is actually a leaf, it can have : grok, dissect or decode, enrich, statics */
 	// pattern_syntax are named grok patterns that are re-utilized over several grok patterns
 	SubGroks yaml.MapSlice `yaml:"pattern_syntax,omitempty"`
 
 	// Holds a grok pattern
 	Grok GrokPattern `yaml:"grok,omitempty"`
+	// Holds a dissect pattern, a faster alternative to grok for delimited formats
+	Dissect DissectPattern `yaml:"dissect,omitempty"`
+	// Holds the format and field mapping of a structured log (json, logfmt, cef, leef)
+	Decode DecodePattern `yaml:"decode,omitempty"`
 	// Statics can be present in any type of node and is executed last
 	Statics []ExtraField `yaml:"statics,omitempty"`
 	// Stash allows to capture data from the log line and store it in an accessible cache
@@ -64,6 +68,8 @@ type Node struct {
 	// Whitelists
 	Whitelist Whitelist           `yaml:"whitelist,omitempty"`
 	Data      []*types.DataSource `yaml:"data,omitempty"`
+	// observers of the time spent in each step, nil unless timing is enabled
+	timers *nodeTimers
 }
 
 func (n *Node) validate(ectx EnricherCtx) error {
@@ -91,6 +97,48 @@ func (n *Node) validate(ectx EnricherCtx) error {
 		}
 	}
 
+	if n.Dissect.RunTimeDissect != nil || n.Dissect.TargetField != "" {
+		if n.Dissect.TargetField == "" && n.Dissect.ExpValue == "" {
+			return errors.New("dissect requires 'expression' or 'apply_on'")
+		}
+
+		if n.Dissect.Pattern == "" {
+			return errors.New("dissect needs 'pattern'")
+		}
+	}
+
+	if n.Decode.RunTimeDecoder != nil || n.Decode.TargetField != "" {
+		if n.Decode.TargetField == "" && n.Decode.ExpValue == "" {
+			return errors.New("decode requires 'expression' or 'apply_on'")
+		}
+
+		if n.Decode.Format == "" {
+			return errors.New("decode needs 'format'")
+		}
+
+		for idx, field := range n.Decode.Fields {
+			if field.Key == "" {
+				return fmt.Errorf("decode field %d : key must be set", idx)
+			}
+
+			if field.Parsed == "" && field.Meta == "" {
+				return fmt.Errorf("decode field %s : at least one of parsed/meta must be set", field.Key)
+			}
+		}
+	}
+
+	extractors := 0
+
+	for _, present := range []bool{n.Grok.RunTimeRegexp != nil, n.Dissect.RunTimeDissect != nil, n.Decode.RunTimeDecoder != nil} {
+		if present {
+			extractors++
+		}
+	}
+
+	if extractors > 1 {
+		return errors.New("a node can only have one of grok, dissect or decode")
+	}
+
 	for idx, static := range n.Statics {
 		if static.Method != "" {
 			if static.ExpValue == "" {
@@ -148,7 +196,9 @@ func (n *Node) processFilter(cachedExprEnv map[string]interface{}) (bool, error)
 	}
 
 	// Evaluate node's filter
+	start := n.startTimer()
 	output, err := exprhelpers.Run(n.RunTimeFilter, cachedExprEnv, clog, n.Debug)
+	n.observe(StepExpr, start)
 	if err != nil {
 		clog.Warningf("failed to run filter : %v", err)
 		clog.Debugf("Event leaving node : ko")
@@ -175,11 +225,16 @@ func (n *Node) processFilter(cachedExprEnv map[string]interface{}) (bool, error)
 func (n *Node) processWhitelist(cachedExprEnv map[string]interface{}, p *types.Event) (bool, error) {
 	var exprErr error
 
+	start := n.startTimer()
 	isWhitelisted := n.CheckIPsWL(p)
 	if !isWhitelisted {
 		isWhitelisted, exprErr = n.CheckExprWL(cachedExprEnv, p)
 	}
 
+	if n.ContainsWLs() {
+		n.observe(StepExpr, start)
+	}
+
 	if exprErr != nil {
 		// Previous code returned nil if there was an error, so we keep this behavior
 		return false, nil //nolint:nilerr
@@ -204,34 +259,27 @@ func (n *Node) processWhitelist(cachedExprEnv map[string]interface{}, p *types.E
 	return isWhitelisted, nil
 }
 
-func (n *Node) processGrok(p *types.Event, cachedExprEnv map[string]any) (bool, bool, error) {
-	// Process grok if present, should be exclusive with nodes :)
+// patternInput returns the string a grok or dissect pattern applies to: a field, or the result of an expression
+func (n *Node) patternInput(p *types.Event, targetField string, runTimeValue *vm.Program, cachedExprEnv map[string]any) (string, bool) {
 	clog := n.Logger
-	var NodeHasOKGrok bool
 	gstr := ""
 
-	if n.Grok.RunTimeRegexp == nil {
-		clog.Tracef("! No grok pattern : %p", n.Grok.RunTimeRegexp)
-		return true, false, nil
-	}
-
-	clog.Tracef("Processing grok pattern : %s : %p", n.Grok.RegexpName, n.Grok.RunTimeRegexp)
 	// for unparsed, parsed etc. set sensible defaults to reduce user hassle
-	if n.Grok.TargetField != "" {
+	if targetField != "" {
 		// it's a hack to avoid using real reflect
-		if n.Grok.TargetField == "Line.Raw" {
+		if targetField == "Line.Raw" {
 			gstr = p.Line.Raw
-		} else if val, ok := p.Parsed[n.Grok.TargetField]; ok {
+		} else if val, ok := p.Parsed[targetField]; ok {
 			gstr = val
 		} else {
-			clog.Debugf("(%s) target field '%s' doesn't exist in %v", n.rn, n.Grok.TargetField, p.Parsed)
-			return false, false, nil
+			clog.Debugf("(%s) target field '%s' doesn't exist in %v", n.rn, targetField, p.Parsed)
+			return "", false
 		}
-	} else if n.Grok.RunTimeValue != nil {
-		output, err := exprhelpers.Run(n.Grok.RunTimeValue, cachedExprEnv, clog, n.Debug)
+	} else if runTimeValue != nil {
+		output, err := exprhelpers.Run(runTimeValue, cachedExprEnv, clog, n.Debug)
 		if err != nil {
 			clog.Warningf("failed to run RunTimeValue : %v", err)
-			return false, false, nil
+			return "", false
 		}
 
 		switch out := output.(type) {
@@ -246,6 +294,34 @@ func (n *Node) processGrok(p *types.Event, cachedExprEnv map[string]any) (bool,
 		}
 	}
 
+	return gstr, true
+}
+
+func (n *Node) processGrok(p *types.Event, cachedExprEnv map[string]any) (bool, bool, error) {
+	// Process grok if present, should be exclusive with nodes :)
+	clog := n.Logger
+	var NodeHasOKGrok bool
+
+	if n.Dissect.RunTimeDissect != nil {
+		return n.processDissect(p, cachedExprEnv)
+	}
+
+	if n.Decode.RunTimeDecoder != nil {
+		return n.processDecode(p, cachedExprEnv)
+	}
+
+	if n.Grok.RunTimeRegexp == nil {
+		clog.Tracef("! No grok pattern : %p", n.Grok.RunTimeRegexp)
+		return true, false, nil
+	}
+
+	clog.Tracef("Processing grok pattern : %s : %p", n.Grok.RegexpName, n.Grok.RunTimeRegexp)
+
+	gstr, ok := n.patternInput(p, n.Grok.TargetField, n.Grok.RunTimeValue, cachedExprEnv)
+	if !ok {
+		return false, false, nil
+	}
+
 	var groklabel string
 	if n.Grok.RegexpName == "" {
 		groklabel = fmt.Sprintf("%5.5s...", n.Grok.RegexpValue)
@@ -253,7 +329,9 @@ func (n *Node) processGrok(p *types.Event, cachedExprEnv map[string]any) (bool,
 		groklabel = n.Grok.RegexpName
 	}
 
+	start := n.startTimer()
 	grok := n.Grok.RunTimeRegexp.Parse(gstr)
+	n.observe(StepGrok, start)
 
 	if len(grok) == 0 {
 		// grok failed, node failed
@@ -280,6 +358,101 @@ func (n *Node) processGrok(p *types.Event, cachedExprEnv map[string]any) (bool,
 	return true, NodeHasOKGrok, nil
 }
 
+// processDissect behaves like processGrok, a successful dissect pattern counts as a successful grok
+func (n *Node) processDissect(p *types.Event, cachedExprEnv map[string]any) (bool, bool, error) {
+	clog := n.Logger
+
+	clog.Tracef("Processing dissect pattern : %s", n.Dissect.Pattern)
+
+	gstr, ok := n.patternInput(p, n.Dissect.TargetField, n.Dissect.RunTimeValue, cachedExprEnv)
+	if !ok {
+		return false, false, nil
+	}
+
+	start := n.startTimer()
+	fields := n.Dissect.RunTimeDissect.Parse(gstr)
+	n.observe(StepGrok, start)
+	if fields == nil {
+		clog.Debugf("+ Dissect '%5.5s...' didn't match '%s'", n.Dissect.Pattern, gstr)
+		return false, false, nil
+	}
+
+	clog.Debugf("+ Dissect '%5.5s...' returned %d entries to merge in Parsed", n.Dissect.Pattern, len(fields))
+
+	for k, v := range fields {
+		clog.Debugf("\t.Parsed['%s'] = '%s'", k, v)
+		p.Parsed[k] = v
+	}
+
+	if err := n.ProcessStatics(n.Dissect.Statics, p); err != nil {
+		clog.Errorf("(%s) Failed to process statics : %v", n.rn, err)
+		return false, false, err
+	}
+
+	return true, true, nil
+}
+
+// processDecode behaves like processGrok: a log that can't be decoded, or that lacks a required field, fails the node
+func (n *Node) processDecode(p *types.Event, cachedExprEnv map[string]any) (bool, bool, error) {
+	clog := n.Logger
+
+	clog.Tracef("Processing %s decoder", n.Decode.Format)
+
+	gstr, ok := n.patternInput(p, n.Decode.TargetField, n.Decode.RunTimeValue, cachedExprEnv)
+	if !ok {
+		return false, false, nil
+	}
+
+	start := n.startTimer()
+	fields, err := n.Decode.RunTimeDecoder(gstr)
+	n.observe(StepGrok, start)
+	if err != nil {
+		clog.Debugf("+ Unable to decode '%s' as %s : %v", gstr, n.Decode.Format, err)
+		return false, false, nil
+	}
+
+	// check the required fields first, not to populate Parsed if the node fails
+	for _, field := range n.Decode.Fields {
+		if _, ok := fields[field.Key]; !ok && field.Required {
+			clog.Debugf("+ Required field '%s' is missing from %s log", field.Key, n.Decode.Format)
+			return false, false, nil
+		}
+	}
+
+	clog.Debugf("+ Decoded %d fields from %s log", len(fields), n.Decode.Format)
+
+	if len(n.Decode.Fields) == 0 || n.Decode.KeepAll {
+		for k, v := range fields {
+			clog.Debugf("\t.Parsed['%s'] = '%s'", k, v)
+			p.Parsed[k] = v
+		}
+	}
+
+	for _, field := range n.Decode.Fields {
+		v, ok := fields[field.Key]
+		if !ok {
+			continue
+		}
+
+		if field.Parsed != "" {
+			clog.Debugf("\t.Parsed['%s'] = '%s'", field.Parsed, v)
+			p.Parsed[field.Parsed] = v
+		}
+
+		if field.Meta != "" {
+			clog.Debugf("\t.Meta['%s'] = '%s'", field.Meta, v)
+			p.Meta[field.Meta] = v
+		}
+	}
+
+	if err := n.ProcessStatics(n.Decode.Statics, p); err != nil {
+		clog.Errorf("(%s) Failed to process statics : %v", n.rn, err)
+		return false, false, err
+	}
+
+	return true, true, nil
+}
+
 func (n *Node) process(p *types.Event, ctx UnixParserCtx, expressionEnv map[string]interface{}) (bool, error) {
 	clog := n.Logger
 
@@ -311,7 +484,7 @@ func (n *Node) process(p *types.Event, ctx UnixParserCtx, expressionEnv map[stri
 	}
 
 	// Process the stash (data collection) if : a grok was present and succeeded, or if there is no grok
-	if NodeHasOKGrok || n.Grok.RunTimeRegexp == nil {
+	if NodeHasOKGrok || (n.Grok.RunTimeRegexp == nil && n.Dissect.RunTimeDissect == nil && n.Decode.RunTimeDecoder == nil) {
 		for idx, stash := range n.Stash {
 			var (
 				key   string
@@ -548,6 +721,62 @@ func (n *Node) compile(pctx *UnixParserCtx, ectx EnricherCtx) error {
 		}
 	}
 
+	/* compile dissect pattern and its expression */
+	if n.Dissect.Pattern != "" {
+		n.Dissect.RunTimeDissect, err = CompileDissect(n.Dissect.Pattern)
+		if err != nil {
+			return fmt.Errorf("failed to compile dissect '%s': %w", n.Dissect.Pattern, err)
+		}
+
+		valid = true
+	}
+
+	if n.Dissect.ExpValue != "" {
+		n.Dissect.RunTimeValue, err = expr.Compile(n.Dissect.ExpValue,
+			exprhelpers.GetExprOptions(map[string]interface{}{"evt": &types.Event{}})...)
+		if err != nil {
+			return fmt.Errorf("while compiling dissect's expression: %w", err)
+		}
+	}
+
+	for idx := range n.Dissect.Statics {
+		if n.Dissect.Statics[idx].ExpValue != "" {
+			n.Dissect.Statics[idx].RunTimeValue, err = expr.Compile(n.Dissect.Statics[idx].ExpValue,
+				exprhelpers.GetExprOptions(map[string]interface{}{"evt": &types.Event{}})...)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	/* load decoder, its expression and statics */
+	if n.Decode.Format != "" {
+		n.Decode.RunTimeDecoder, err = getDecoder(n.Decode.Format)
+		if err != nil {
+			return err
+		}
+
+		valid = true
+	}
+
+	if n.Decode.ExpValue != "" {
+		n.Decode.RunTimeValue, err = expr.Compile(n.Decode.ExpValue,
+			exprhelpers.GetExprOptions(map[string]interface{}{"evt": &types.Event{}})...)
+		if err != nil {
+			return fmt.Errorf("while compiling decode's expression: %w", err)
+		}
+	}
+
+	for idx := range n.Decode.Statics {
+		if n.Decode.Statics[idx].ExpValue != "" {
+			n.Decode.Statics[idx].RunTimeValue, err = expr.Compile(n.Decode.Statics[idx].ExpValue,
+				exprhelpers.GetExprOptions(map[string]interface{}{"evt": &types.Event{}})...)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	/* load grok statics */
 	// compile expr statics if present
 	for idx := range n.Grok.Statics {
@@ -46,6 +46,22 @@ func TestParserConfigs(t *testing.T) {
 			{Key: string("SUBGROKBIS"), Value: string("[a-z]%{MYGROKBIS}")},
 			{Key: string("MYGROKBIS"), Value: string("[a-z]")},
 		}, Grok: GrokPattern{RegexpValue: "^x%{MYGROKBIS:extr}$", TargetField: "t"}}, false, true},
+		//valid node with dissect pattern
+		{&Node{Debug: true, Stage: "s00", Dissect: DissectPattern{Pattern: "%{a} - %{b}", TargetField: "t"}}, true, true},
+		//dissect pattern without target
+		{&Node{Debug: true, Stage: "s00", Dissect: DissectPattern{Pattern: "%{a} - %{b}"}}, false, false},
+		//broken dissect pattern
+		{&Node{Debug: true, Stage: "s00", Dissect: DissectPattern{Pattern: "%{a}%{b}", TargetField: "t"}}, false, true},
+		//valid node with decode
+		{&Node{Debug: true, Stage: "s00", Decode: DecodePattern{Format: "json", TargetField: "t", Fields: []DecodeField{{Key: "a.b", Parsed: "b"}}}}, true, true},
+		//unknown decode format
+		{&Node{Debug: true, Stage: "s00", Decode: DecodePattern{Format: "xml", TargetField: "t"}}, false, true},
+		//decode field without target
+		{&Node{Debug: true, Stage: "s00", Decode: DecodePattern{Format: "logfmt", TargetField: "t", Fields: []DecodeField{{Key: "a"}}}}, false, false},
+		//both dissect and decode
+		{&Node{Debug: true, Stage: "s00", Dissect: DissectPattern{Pattern: "%{a} - %{b}", TargetField: "t"}, Decode: DecodePattern{Format: "cef", TargetField: "t"}}, false, false},
+		//both grok and dissect
+		{&Node{Debug: true, Stage: "s00", Grok: GrokPattern{RegexpValue: "^x%{DATA:extr}$", TargetField: "t"}, Dissect: DissectPattern{Pattern: "%{a} - %{b}", TargetField: "t"}}, false, false},
 	}
 	for idx := range CfgTests {
 		err := CfgTests[idx].NodeCfg.compile(pctx, EnricherCtx{})
@@ -0,0 +1,113 @@
+package parser
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// the steps of a node that are timed when profiling is enabled
+const (
+	// StepGrok is the time spent matching the grok, dissect or decode pattern
+	StepGrok = "grok"
+	// StepExpr is the time spent evaluating the filter, whitelists and static expressions
+	StepExpr = "expr"
+	// StepEnrich is the time spent in the enrichment methods
+	StepEnrich = "enrich"
+)
+
+// most steps take a few microseconds, a slow grok pattern or a dns lookup a few milliseconds
+var timingBuckets = []float64{0.00001, 0.000025, 0.00005, 0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.05}
+
+var NodesTiming = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "cs_node_step_seconds",
+		Help:    "Time spent by a node in each step: grok, expr or enrich.",
+		Buckets: timingBuckets,
+	},
+	[]string{"name", "step"},
+)
+
+var StagesTiming = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "cs_stage_seconds",
+		Help:    "Time spent processing an event in each parser stage.",
+		Buckets: timingBuckets,
+	},
+	[]string{"stage"},
+)
+
+// nodeTimers holds the observers of a node, to avoid looking up the labels for each event
+type nodeTimers struct {
+	grok   prometheus.Observer
+	expr   prometheus.Observer
+	enrich prometheus.Observer
+}
+
+func newNodeTimers(name string) *nodeTimers {
+	return &nodeTimers{
+		grok:   NodesTiming.With(prometheus.Labels{"name": name, "step": StepGrok}),
+		expr:   NodesTiming.With(prometheus.Labels{"name": name, "step": StepExpr}),
+		enrich: NodesTiming.With(prometheus.Labels{"name": name, "step": StepEnrich}),
+	}
+}
+
+// since returns the time spent since start, in seconds
+func since(start time.Time) float64 {
+	return time.Since(start).Seconds()
+}
+
+// startTimer returns the current time if the node is timed, to avoid reading the clock for nothing
+func (n *Node) startTimer() time.Time {
+	if n.timers == nil {
+		return time.Time{}
+	}
+
+	return time.Now()
+}
+
+// observe records the time spent since start in one of the steps of the node, if timing is enabled
+func (n *Node) observe(step string, start time.Time) {
+	if n.timers == nil {
+		return
+	}
+
+	switch step {
+	case StepGrok:
+		n.timers.grok.Observe(since(start))
+	case StepExpr:
+		n.timers.expr.Observe(since(start))
+	case StepEnrich:
+		n.timers.enrich.Observe(since(start))
+	}
+}
+
+// EnableTiming turns on the timing of the nodes and of their children.
+// Nodes without a name are labeled after their stage or their parent, and their position.
+func EnableTiming(nodes []Node) {
+	for idx := range nodes {
+		label := nodes[idx].Name
+		if label == "" {
+			label = fmt.Sprintf("%s/%d", nodes[idx].Stage, idx)
+		}
+
+		enableTiming(&nodes[idx], label)
+	}
+}
+
+func enableTiming(n *Node, label string) {
+	n.timers = newNodeTimers(label)
+
+	for idx := range n.LeavesNodes {
+		leaf := &n.LeavesNodes[idx]
+
+		leafLabel := leaf.Name
+		// compile() names all the anonymous children of a node the same way
+		if leafLabel == "" || leafLabel == "child-"+n.Name {
+			leafLabel = fmt.Sprintf("%s/%d", label, idx)
+		}
+
+		enableTiming(leaf, leafLabel)
+	}
+}
@@ -0,0 +1,89 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/crowdsecurity/crowdsec/pkg/types"
+)
+
+func sampleCount(t *testing.T, observer prometheus.Observer) uint64 {
+	t.Helper()
+
+	m := &dto.Metric{}
+	require.NoError(t, observer.(prometheus.Metric).Write(m))
+
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestNodeTiming(t *testing.T) {
+	pctx, ectx := prepTests(t)
+
+	nodes := []Node{
+		{
+			Name:      "tests/timing",
+			Stage:     "s00-raw",
+			Filter:    "evt.Line.Labels.type == 'testlog'",
+			OnSuccess: "next_stage",
+			LeavesNodes: []Node{
+				{
+					Dissect: DissectPattern{
+						TargetField: "Line.Raw",
+						Pattern:     "%{date} %{message}",
+					},
+					Statics: []ExtraField{
+						{Method: "ParseDate", ExpValue: "evt.Parsed.date"},
+						{Meta: "message", ExpValue: "evt.Parsed.message"},
+					},
+				},
+			},
+		},
+	}
+
+	for idx := range nodes {
+		require.NoError(t, nodes[idx].compile(pctx, ectx))
+	}
+
+	// nothing is timed until it's enabled
+	assert.Nil(t, nodes[0].timers)
+	assert.Nil(t, nodes[0].LeavesNodes[0].timers)
+
+	ctx := *pctx
+	ctx.Stages = []string{"s00-raw"}
+	ctx.Timing = true
+
+	EnableTiming(nodes)
+	require.NotNil(t, nodes[0].LeavesNodes[0].timers)
+
+	stage := StagesTiming.With(prometheus.Labels{"stage": "s00-raw"})
+	stagesBefore := sampleCount(t, stage)
+
+	lines := []string{"2023-10-10T13:55:36Z hello", "2023-10-10T13:55:37Z world"}
+	for _, line := range lines {
+		evt, err := Parse(ctx, types.Event{Line: types.Line{Raw: line, Labels: map[string]string{"type": "testlog"}}}, nodes)
+		require.NoError(t, err)
+		require.True(t, evt.Process)
+	}
+
+	parent := nodes[0].timers
+	child := nodes[0].LeavesNodes[0].timers
+
+	// the anonymous child is labeled after its parent and its position
+	assert.Equal(t, child.grok, NodesTiming.With(prometheus.Labels{"name": "tests/timing/0", "step": StepGrok}))
+
+	// the parent only has a filter
+	assert.Equal(t, uint64(2), sampleCount(t, parent.expr))
+	assert.Equal(t, uint64(0), sampleCount(t, parent.grok))
+	assert.Equal(t, uint64(0), sampleCount(t, parent.enrich))
+
+	// the child has no filter, but a pattern, a static expression and an enrichment method
+	assert.Equal(t, uint64(2), sampleCount(t, child.grok))
+	assert.Equal(t, uint64(4), sampleCount(t, child.expr))
+	assert.Equal(t, uint64(2), sampleCount(t, child.enrich))
+
+	assert.Equal(t, stagesBefore+2, sampleCount(t, stage))
+}
@@ -127,7 +127,9 @@ func (n *Node) ProcessStatics(statics []ExtraField, event *types.Event) error {
 		if static.Value != "" {
 			value = static.Value
 		} else if static.RunTimeValue != nil {
+			start := n.startTimer()
 			output, err := exprhelpers.Run(static.RunTimeValue, map[string]interface{}{"evt": event}, clog, n.Debug)
+			n.observe(StepExpr, start)
 			if err != nil {
 				clog.Warningf("failed to run RunTimeValue : %v", err)
 				continue
@@ -164,7 +166,9 @@ func (n *Node) ProcessStatics(statics []ExtraField, event *types.Event) error {
 			/*still way too hackish, but : inject all the results in enriched, and */
 			if enricherPlugin, ok := n.EnrichFunctions.Registered[static.Method]; ok {
 				clog.Tracef("Found method '%s'", static.Method)
+				start := n.startTimer()
 				ret, err := enricherPlugin.EnrichFunc(value, event, n.Logger.WithField("method", static.Method))
+				n.observe(StepEnrich, start)
 				if err != nil {
 					clog.Errorf("method '%s' returned an error : %v", static.Method, err)
 				}
@@ -329,6 +333,11 @@ func Parse(ctx UnixParserCtx, xp types.Event, nodes []Node) (types.Event, error)
 			return event, nil
 		}
 
+		var stageStart time.Time
+		if ctx.Timing {
+			stageStart = time.Now()
+		}
+
 		isStageOK := false
 		for idx := range nodes {
 			//Only process current stage's nodes
@@ -379,6 +388,9 @@ func Parse(ctx UnixParserCtx, xp types.Event, nodes []Node) (types.Event, error)
 				break
 			}
 		}
+		if ctx.Timing {
+			StagesTiming.With(prometheus.Labels{"stage": stage}).Observe(since(stageStart))
+		}
 		if !isStageOK {
 			log.Debugf("Log didn't finish stage %s", event.Stage)
 			event.Process = false
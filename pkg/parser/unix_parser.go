@@ -20,6 +20,7 @@ type UnixParserCtx struct {
 	Grok       grokky.Host
 	Stages     []string
 	Profiling  bool
+	Timing     bool
 	DataFolder string
 }
 
@@ -152,6 +153,13 @@ func LoadParsers(cConfig *csconfig.Config, parsers *Parsers) (*Parsers, error) {
 		parsers.Ctx.Profiling = true
 		parsers.Povfwctx.Profiling = true
 	}
+
+	if cConfig.Prometheus != nil && cConfig.Prometheus.Enabled && cConfig.Prometheus.ParserTiming {
+		parsers.Ctx.Timing = true
+		parsers.Povfwctx.Timing = true
+		EnableTiming(parsers.Nodes)
+		EnableTiming(parsers.Povfwnodes)
+	}
 	/*
 		Reset CTX grok to reduce memory footprint after we compile all the patterns
 	*/
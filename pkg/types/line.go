@@ -9,4 +9,7 @@ type Line struct {
 	Labels  map[string]string `yaml:"Labels,omitempty"`
 	Process bool
 	Module  string `yaml:"Module,omitempty"`
+	// Ack is set by the datasources that acknowledge their messages (nats jetstream, redis, rabbitmq,
+	// file bookmarks): it's called once the line went through the parsers
+	Ack func() `yaml:"-" json:"-"`
 }